@@ -0,0 +1,40 @@
+package asn1
+
+type DumpRequest struct {
+	Input string `json:"input" binding:"required"`
+	// Block 输入包含多个 PEM 块时选择第几个（从 0 开始）
+	Block int `json:"block"`
+}
+
+type Node struct {
+	Offset       int    `json:"offset"`
+	HeaderLength int    `json:"headerLength"`
+	Length       int    `json:"length"`
+	Indefinite   bool   `json:"indefinite,omitempty"`
+	Depth        int    `json:"depth"`
+	Class        string `json:"class"`
+	Tag          int    `json:"tag"`
+	Constructed  bool   `json:"constructed"`
+	Type         string `json:"type"`
+	Value        string `json:"value,omitempty"`
+	OIDName      string `json:"oidName,omitempty"`
+	Time         string `json:"time,omitempty"`
+	Hex          string `json:"hex,omitempty"`
+	Truncated    bool   `json:"truncated,omitempty"`
+	Encapsulated bool   `json:"encapsulated,omitempty"`
+	Children     []Node `json:"children,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type DumpResponse struct {
+	// Format 识别出的输入格式：pem / hex / base64
+	Format    string `json:"format"`
+	PEMType   string `json:"pemType,omitempty"`
+	PEMBlocks int    `json:"pemBlocks,omitempty"`
+	Length    int    `json:"length"`
+	Nodes     []Node `json:"nodes"`
+	// Text openssl asn1parse 风格的文本输出
+	Text string `json:"text"`
+	// Error 数据不完整或有误时的第一个错误，已解析的部分仍在 Nodes 中
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,29 @@
+package asn1
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpapi "my-tools/internal/api/http"
+)
+
+func Register(r *gin.RouterGroup) {
+	svc := NewService()
+	g := r.Group("/asn1")
+	g.POST("/dump", func(c *gin.Context) {
+		var req DumpRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Dump(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+}
@@ -0,0 +1,54 @@
+package asn1
+
+import "my-tools/internal/domain/asn1dump"
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Dump(req DumpRequest) (DumpResponse, error) {
+	res, err := asn1dump.Dump(req.Input, asn1dump.Options{Block: req.Block})
+	if err != nil {
+		return DumpResponse{}, err
+	}
+	return DumpResponse{
+		Format:    res.Format,
+		PEMType:   res.PEMType,
+		PEMBlocks: res.PEMBlocks,
+		Length:    res.Length,
+		Nodes:     toNodes(res.Nodes),
+		Text:      res.Text,
+		Error:     res.Error,
+	}, nil
+}
+
+func toNodes(nodes []*asn1dump.Node) []Node {
+	if len(nodes) == 0 {
+		return nil
+	}
+	out := make([]Node, len(nodes))
+	for i, n := range nodes {
+		out[i] = Node{
+			Offset:       n.Offset,
+			HeaderLength: n.HeaderLength,
+			Length:       n.Length,
+			Indefinite:   n.Indefinite,
+			Depth:        n.Depth,
+			Class:        n.Class,
+			Tag:          n.Tag,
+			Constructed:  n.Constructed,
+			Type:         n.Type,
+			Value:        n.Value,
+			OIDName:      n.OIDName,
+			Time:         n.Time,
+			Hex:          n.Hex,
+			Truncated:    n.Truncated,
+			Encapsulated: n.Encapsulated,
+			Children:     toNodes(n.Children),
+			Error:        n.Error,
+		}
+	}
+	return out
+}
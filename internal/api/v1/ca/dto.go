@@ -0,0 +1,120 @@
+package ca
+
+import "time"
+
+type InitRequest struct {
+	// Name 根证书 / 中间证书 CN 的前缀
+	Name                     string `json:"name"`
+	Organization             string `json:"organization"`
+	KeySpec                  string `json:"keySpec"`
+	RootValidityDays         int    `json:"rootValidityDays"`
+	IntermediateValidityDays int    `json:"intermediateValidityDays"`
+	// Force 已有 CA 时重新创建，原有签发日志会被清空
+	Force bool `json:"force"`
+}
+
+type StatusResponse struct {
+	Initialized  bool        `json:"initialized"`
+	CreatedAt    *time.Time  `json:"createdAt,omitempty"`
+	Root         *CertDetail `json:"root,omitempty"`
+	Intermediate *CertDetail `json:"intermediate,omitempty"`
+	Issued       int         `json:"issued"`
+	Revoked      int         `json:"revoked"`
+	CRLNumber    int64       `json:"crlNumber"`
+}
+
+type CertDetail struct {
+	PEM          string    `json:"pem"`
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	SerialNumber string    `json:"serialNumber"`
+	Version      int       `json:"version"`
+	IsCA         bool      `json:"isCA"`
+
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	URIs           []string `json:"uris"`
+
+	KeyUsage    []string `json:"keyUsage"`
+	ExtKeyUsage []string `json:"extKeyUsage"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+
+	FingerprintSHA1   string `json:"fingerprintSha1"`
+	FingerprintSHA256 string `json:"fingerprintSha256"`
+	SubjectKeyID      string `json:"subjectKeyId,omitempty"`
+	AuthorityKeyID    string `json:"authorityKeyId,omitempty"`
+
+	CRLDistributionPoints []string `json:"crlDistributionPoints"`
+	OCSPServers           []string `json:"ocspServers"`
+	IssuingCertificateURL []string `json:"issuingCertificateUrl"`
+
+	PolicyOIDs        []string `json:"policyOids"`
+	ValidationLevel   string   `json:"validationLevel,omitempty"`
+	PathLenConstraint *int     `json:"pathLenConstraint,omitempty"`
+
+	SCTs           []SCT  `json:"scts"`
+	SCTError       string `json:"sctError,omitempty"`
+	Precertificate bool   `json:"precertificate"`
+}
+
+type SCT struct {
+	Version            int       `json:"version"`
+	LogID              string    `json:"logId"`
+	LogName            string    `json:"logName,omitempty"`
+	LogOperator        string    `json:"logOperator,omitempty"`
+	Timestamp          time.Time `json:"timestamp"`
+	HashAlgorithm      string    `json:"hashAlgorithm"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	Signature          string    `json:"signature"`
+	Extensions         string    `json:"extensions,omitempty"`
+}
+
+type SignRequest struct {
+	CSR          string `json:"csr" binding:"required"`
+	ValidityDays int    `json:"validityDays"`
+	// DNSNames / IPAddresses / EmailAddresses 任一不为空时替换 CSR 中的 SAN
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	ExtKeyUsages   []string `json:"extKeyUsages"`
+}
+
+// SignResponse 与 /cert/split 结构一致，certs 依次为 终端证书、中间证书、根证书
+type SignResponse struct {
+	Certs    []CertDetail `json:"certs"`
+	Count    int          `json:"count"`
+	ChainPEM string       `json:"chainPem"`
+}
+
+type IssuedCert struct {
+	SerialNumber     string     `json:"serialNumber"`
+	SerialHex        string     `json:"serialHex"`
+	Subject          string     `json:"subject"`
+	SANs             []string   `json:"sans"`
+	NotBefore        time.Time  `json:"notBefore"`
+	NotAfter         time.Time  `json:"notAfter"`
+	IssuedAt         time.Time  `json:"issuedAt"`
+	Revoked          bool       `json:"revoked"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+	PEM              string     `json:"pem"`
+}
+
+type IssuedResponse struct {
+	Items []IssuedCert `json:"items"`
+	Count int          `json:"count"`
+}
+
+type RevokeRequest struct {
+	// SerialNumber 十进制，或带冒号 / 0x 前缀的十六进制
+	SerialNumber string `json:"serialNumber" binding:"required"`
+	// Reason RFC 5280 CRLReason 名称，例如 keyCompromise / superseded
+	Reason string `json:"reason"`
+}
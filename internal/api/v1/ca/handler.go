@@ -0,0 +1,116 @@
+package ca
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpapi "my-tools/internal/api/http"
+	"my-tools/internal/infra/castore"
+)
+
+func Register(r *gin.RouterGroup) {
+	svc := NewService(castore.NewStore())
+	g := r.Group("/ca")
+	g.GET("", func(c *gin.Context) {
+		res, err := svc.Status()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, httpapi.Fail("internal", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/init", func(c *gin.Context) {
+		var req InitRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Init(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/sign", func(c *gin.Context) {
+		var req SignRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Sign(req)
+		if err != nil {
+			writeError(c, err, "invalid_csr")
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.GET("/issued", func(c *gin.Context) {
+		res, err := svc.Issued()
+		if err != nil {
+			writeError(c, err, "internal")
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/revoke", func(c *gin.Context) {
+		var req RevokeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Revoke(req)
+		if err != nil {
+			writeError(c, err, "invalid_input")
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.GET("/crl", func(c *gin.Context) {
+		var (
+			data []byte
+			err  error
+		)
+		name := "test_ca.crl"
+		if c.Query("format") == "pem" {
+			data, err = svc.CRLPEM()
+			name = "test_ca.crl.pem"
+		} else {
+			data, err = svc.CRL()
+		}
+		if err != nil {
+			writeError(c, err, "internal")
+			return
+		}
+
+		c.Header("Content-Disposition", "attachment; filename=\""+name+"\"")
+		c.Data(http.StatusOK, "application/pkix-crl", data)
+	})
+}
+
+// writeError CA 未创建或证书不存在时返回 404，其余错误使用 code
+func writeError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, ErrNotInitialized) || errors.Is(err, ErrNotIssued):
+		c.JSON(http.StatusNotFound, httpapi.Fail("not_found", err.Error()))
+	case code == "internal":
+		c.JSON(http.StatusInternalServerError, httpapi.Fail(code, err.Error()))
+	default:
+		c.JSON(http.StatusBadRequest, httpapi.Fail(code, err.Error()))
+	}
+}
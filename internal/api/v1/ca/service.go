@@ -0,0 +1,372 @@
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	domainca "my-tools/internal/domain/ca"
+	domaincert "my-tools/internal/domain/cert"
+	"my-tools/internal/infra/castore"
+)
+
+var (
+	ErrNotInitialized = castore.ErrNotInitialized
+	ErrExists         = errors.New("test ca already exists, set force to recreate it")
+	ErrNotIssued      = errors.New("certificate was not issued by the test ca")
+)
+
+type Service struct {
+	store *castore.Store
+}
+
+func NewService(store *castore.Store) *Service {
+	return &Service{store: store}
+}
+
+func (s *Service) Status() (StatusResponse, error) {
+	st, err := s.store.Load()
+	if err != nil {
+		return StatusResponse{}, err
+	}
+	if st == nil {
+		return StatusResponse{}, nil
+	}
+
+	certs, err := domaincert.SplitCertChainWithInfo(st.RootCert + st.IntermediateCert)
+	if err != nil {
+		return StatusResponse{}, err
+	}
+	details := toCertDetails(certs)
+	createdAt := st.CreatedAt
+	res := StatusResponse{
+		Initialized:  true,
+		CreatedAt:    &createdAt,
+		Root:         &details[0],
+		Intermediate: &details[1],
+		Issued:       len(st.Issued),
+		CRLNumber:    st.CRLNumber,
+	}
+	for _, it := range st.Issued {
+		if it.RevokedAt != nil {
+			res.Revoked++
+		}
+	}
+	return res, nil
+}
+
+func (s *Service) Init(req InitRequest) (StatusResponse, error) {
+	st, err := s.store.Load()
+	if err != nil {
+		return StatusResponse{}, err
+	}
+	if st != nil && !req.Force {
+		return StatusResponse{}, ErrExists
+	}
+
+	now := time.Now()
+	a, err := domainca.NewAuthority(domainca.InitOptions{
+		Name:                     req.Name,
+		Organization:             req.Organization,
+		KeySpec:                  req.KeySpec,
+		RootValidityDays:         req.RootValidityDays,
+		IntermediateValidityDays: req.IntermediateValidityDays,
+	}, now)
+	if err != nil {
+		return StatusResponse{}, err
+	}
+	p, err := a.PEM()
+	if err != nil {
+		return StatusResponse{}, err
+	}
+
+	if err := s.store.Save(castore.State{
+		RootCert:         p.RootCert,
+		RootKey:          p.RootKey,
+		IntermediateCert: p.IntermediateCert,
+		IntermediateKey:  p.IntermediateKey,
+		CreatedAt:        now,
+		Issued:           []castore.Issued{},
+	}); err != nil {
+		return StatusResponse{}, err
+	}
+	return s.Status()
+}
+
+func (s *Service) Sign(req SignRequest) (SignResponse, error) {
+	var leaf *x509.Certificate
+	var chainPEM string
+	err := s.store.Update(func(st *castore.State) error {
+		a, err := loadAuthority(st)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		leaf, err = a.SignCSR(req.CSR, domainca.SignOptions{
+			ValidityDays:   req.ValidityDays,
+			DNSNames:       req.DNSNames,
+			IPAddresses:    req.IPAddresses,
+			EmailAddresses: req.EmailAddresses,
+			ExtKeyUsages:   req.ExtKeyUsages,
+		}, now)
+		if err != nil {
+			return err
+		}
+
+		leafPEM := domainca.EncodeCertPEM(leaf)
+		chainPEM = leafPEM + st.IntermediateCert + st.RootCert
+		st.Issued = append(st.Issued, castore.Issued{
+			SerialNumber: leaf.SerialNumber.String(),
+			SerialHex:    domaincert.SerialHex(leaf.SerialNumber),
+			Subject:      leaf.Subject.String(),
+			SANs:         certSANs(leaf),
+			NotBefore:    leaf.NotBefore,
+			NotAfter:     leaf.NotAfter,
+			IssuedAt:     now,
+			PEM:          leafPEM,
+		})
+		return nil
+	})
+	if err != nil {
+		return SignResponse{}, err
+	}
+
+	certs, err := domaincert.SplitCertChainWithInfo(chainPEM)
+	if err != nil {
+		return SignResponse{}, err
+	}
+	return SignResponse{
+		Certs:    toCertDetails(certs),
+		Count:    len(certs),
+		ChainPEM: chainPEM,
+	}, nil
+}
+
+func (s *Service) Issued() (IssuedResponse, error) {
+	st, err := s.store.Load()
+	if err != nil {
+		return IssuedResponse{}, err
+	}
+	if st == nil {
+		return IssuedResponse{}, ErrNotInitialized
+	}
+
+	items := make([]IssuedCert, len(st.Issued))
+	for i, it := range st.Issued {
+		// 最新签发的排在前面
+		items[len(items)-1-i] = toIssuedCert(it)
+	}
+	return IssuedResponse{Items: items, Count: len(items)}, nil
+}
+
+func (s *Service) Revoke(req RevokeRequest) (IssuedCert, error) {
+	serial, err := domaincert.ParseSerial(req.SerialNumber)
+	if err != nil {
+		return IssuedCert{}, err
+	}
+	if _, err := domaincert.RevocationReasonCode(req.Reason); err != nil {
+		return IssuedCert{}, err
+	}
+
+	var res IssuedCert
+	err = s.store.Update(func(st *castore.State) error {
+		for i := range st.Issued {
+			it := &st.Issued[i]
+			if it.SerialNumber != serial.String() {
+				continue
+			}
+			// 重复吊销时只更新原因，保留首次吊销时间
+			if it.RevokedAt == nil {
+				now := time.Now()
+				it.RevokedAt = &now
+				st.CRL = nil
+			}
+			if it.RevocationReason != req.Reason {
+				it.RevocationReason = req.Reason
+				st.CRL = nil
+			}
+			res = toIssuedCert(*it)
+			return nil
+		}
+		return ErrNotIssued
+	})
+	return res, err
+}
+
+// CRL 返回包含全部已吊销证书的 CRL。吊销记录没有变化且上一次的 CRL 未过期时直接返回它，
+// 否则生成新的 CRL，CRL 编号加一
+func (s *Service) CRL() ([]byte, error) {
+	st, err := s.store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, ErrNotInitialized
+	}
+	if cachedCRLValid(st.CRL, time.Now()) {
+		return st.CRL, nil
+	}
+
+	var der []byte
+	err = s.store.Update(func(st *castore.State) error {
+		now := time.Now()
+		// 并发请求可能已经生成了新的 CRL
+		if cachedCRLValid(st.CRL, now) {
+			der = st.CRL
+			return nil
+		}
+		a, err := loadAuthority(st)
+		if err != nil {
+			return err
+		}
+
+		var revoked []domainca.Revocation
+		for _, it := range st.Issued {
+			if it.RevokedAt == nil {
+				continue
+			}
+			serial, err := domaincert.ParseSerial(it.SerialNumber)
+			if err != nil {
+				return err
+			}
+			revoked = append(revoked, domainca.Revocation{SerialNumber: serial, RevokedAt: *it.RevokedAt, Reason: it.RevocationReason})
+		}
+
+		der, err = a.CreateCRL(revoked, st.CRLNumber+1, now)
+		if err != nil {
+			return err
+		}
+		st.CRLNumber++
+		st.CRL = der
+		return nil
+	})
+	return der, err
+}
+
+// cachedCRLValid 保存的 CRL 存在且还没有到 nextUpdate
+func cachedCRLValid(der []byte, now time.Time) bool {
+	if len(der) == 0 {
+		return false
+	}
+	crl, err := x509.ParseRevocationList(der)
+	return err == nil && now.Before(crl.NextUpdate)
+}
+
+// CRLPEM 与 CRL 相同，输出 PEM
+func (s *Service) CRLPEM() ([]byte, error) {
+	der, err := s.CRL()
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), nil
+}
+
+func loadAuthority(st *castore.State) (*domainca.Authority, error) {
+	return domainca.LoadAuthority(domainca.AuthorityPEM{
+		RootCert:         st.RootCert,
+		RootKey:          st.RootKey,
+		IntermediateCert: st.IntermediateCert,
+		IntermediateKey:  st.IntermediateKey,
+	})
+}
+
+func toIssuedCert(it castore.Issued) IssuedCert {
+	return IssuedCert{
+		SerialNumber:     it.SerialNumber,
+		SerialHex:        it.SerialHex,
+		Subject:          it.Subject,
+		SANs:             it.SANs,
+		NotBefore:        it.NotBefore,
+		NotAfter:         it.NotAfter,
+		IssuedAt:         it.IssuedAt,
+		Revoked:          it.RevokedAt != nil,
+		RevokedAt:        it.RevokedAt,
+		RevocationReason: it.RevocationReason,
+		PEM:              it.PEM,
+	}
+}
+
+func certSANs(c *x509.Certificate) []string {
+	sans := []string{}
+	for _, d := range c.DNSNames {
+		sans = append(sans, "DNS:"+d)
+	}
+	for _, ip := range c.IPAddresses {
+		sans = append(sans, "IP:"+ip.String())
+	}
+	for _, e := range c.EmailAddresses {
+		sans = append(sans, "Email:"+e)
+	}
+	for _, u := range c.URIs {
+		sans = append(sans, "URI:"+u.String())
+	}
+	return sans
+}
+
+func toCertDetails(certInfos []domaincert.CertInfo) []CertDetail {
+	result := make([]CertDetail, len(certInfos))
+	for i, info := range certInfos {
+		result[i] = toCertDetail(info)
+	}
+	return result
+}
+
+func toCertDetail(info domaincert.CertInfo) CertDetail {
+	scts := make([]SCT, len(info.SCTs))
+	for i, s := range info.SCTs {
+		scts[i] = SCT{
+			Version:            s.Version,
+			LogID:              s.LogID,
+			LogName:            s.LogName,
+			LogOperator:        s.LogOperator,
+			Timestamp:          s.Timestamp,
+			HashAlgorithm:      s.HashAlgorithm,
+			SignatureAlgorithm: s.SignatureAlgorithm,
+			Signature:          s.Signature,
+			Extensions:         s.Extensions,
+		}
+	}
+
+	return CertDetail{
+		PEM:          info.PEM,
+		Subject:      info.Subject,
+		Issuer:       info.Issuer,
+		NotBefore:    info.NotBefore,
+		NotAfter:     info.NotAfter,
+		SerialNumber: info.SerialNumber,
+		Version:      info.Version,
+		IsCA:         info.IsCA,
+
+		DNSNames:       info.DNSNames,
+		IPAddresses:    info.IPAddresses,
+		EmailAddresses: info.EmailAddresses,
+		URIs:           info.URIs,
+
+		KeyUsage:    info.KeyUsage,
+		ExtKeyUsage: info.ExtKeyUsage,
+
+		PublicKeyAlgorithm: info.PublicKeyAlgorithm,
+		PublicKeySize:      info.PublicKeySize,
+		PublicKeyCurve:     info.PublicKeyCurve,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+
+		FingerprintSHA1:   info.FingerprintSHA1,
+		FingerprintSHA256: info.FingerprintSHA256,
+		SubjectKeyID:      info.SubjectKeyID,
+		AuthorityKeyID:    info.AuthorityKeyID,
+
+		CRLDistributionPoints: info.CRLDistributionPoints,
+		OCSPServers:           info.OCSPServers,
+		IssuingCertificateURL: info.IssuingCertificateURL,
+
+		PolicyOIDs:        info.PolicyOIDs,
+		ValidationLevel:   info.ValidationLevel,
+		PathLenConstraint: info.PathLenConstraint,
+
+		SCTs:           scts,
+		SCTError:       info.SCTError,
+		Precertificate: info.Precertificate,
+	}
+}
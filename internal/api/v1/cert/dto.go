@@ -1,9 +1,16 @@
 package cert
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type SplitCertChainRequest struct {
 	CertChain string `json:"certChain" binding:"required"`
+	// Verify 为 true 时额外构建签发路径并做信任校验
+	Verify     bool   `json:"verify"`
+	TrustStore string `json:"trustStore"`
+	RootsPEM   string `json:"rootsPem"`
 }
 
 type CertDetail struct {
@@ -15,9 +22,410 @@ type CertDetail struct {
 	SerialNumber string    `json:"serialNumber"`
 	Version      int       `json:"version"`
 	IsCA         bool      `json:"isCA"`
+
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	URIs           []string `json:"uris"`
+
+	KeyUsage    []string `json:"keyUsage"`
+	ExtKeyUsage []string `json:"extKeyUsage"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+
+	FingerprintSHA1   string `json:"fingerprintSha1"`
+	FingerprintSHA256 string `json:"fingerprintSha256"`
+	SubjectKeyID      string `json:"subjectKeyId,omitempty"`
+	AuthorityKeyID    string `json:"authorityKeyId,omitempty"`
+
+	CRLDistributionPoints []string `json:"crlDistributionPoints"`
+	OCSPServers           []string `json:"ocspServers"`
+	IssuingCertificateURL []string `json:"issuingCertificateUrl"`
+
+	PolicyOIDs        []string `json:"policyOids"`
+	ValidationLevel   string   `json:"validationLevel,omitempty"`
+	PathLenConstraint *int     `json:"pathLenConstraint,omitempty"`
+
+	SCTs           []SCT  `json:"scts"`
+	SCTError       string `json:"sctError,omitempty"`
+	Precertificate bool   `json:"precertificate"`
+}
+
+type SCT struct {
+	Version            int       `json:"version"`
+	LogID              string    `json:"logId"`
+	LogName            string    `json:"logName,omitempty"`
+	LogOperator        string    `json:"logOperator,omitempty"`
+	Timestamp          time.Time `json:"timestamp"`
+	HashAlgorithm      string    `json:"hashAlgorithm"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	Signature          string    `json:"signature"`
+	Extensions         string    `json:"extensions,omitempty"`
+}
+
+type ChainLink struct {
+	Subject        string `json:"subject"`
+	Issuer         string `json:"issuer"`
+	IssuerSource   string `json:"issuerSource"`
+	SignatureValid bool   `json:"signatureValid"`
+	Error          string `json:"error,omitempty"`
+}
+
+type ChainVerification struct {
+	Verified             bool         `json:"verified"`
+	VerifyError          string       `json:"verifyError,omitempty"`
+	Order                []int        `json:"order"`
+	Ordered              []CertDetail `json:"ordered"`
+	Links                []ChainLink  `json:"links"`
+	OutOfOrder           bool         `json:"outOfOrder"`
+	MissingIntermediates bool         `json:"missingIntermediates"`
+	MissingIssuer        string       `json:"missingIssuer,omitempty"`
+	Unused               []int        `json:"unused"`
+	FullChainPEM         string       `json:"fullChainPem"`
 }
 
 type SplitCertChainResponse struct {
-	Certs []CertDetail `json:"certs"`
-	Count int          `json:"count"`
+	Certs        []CertDetail       `json:"certs"`
+	Count        int                `json:"count"`
+	Verification *ChainVerification `json:"verification,omitempty"`
+}
+
+type MatchRequest struct {
+	Cert       string `json:"cert"`
+	CSR        string `json:"csr"`
+	PrivateKey string `json:"privateKey"`
+	Passphrase string `json:"passphrase"`
+}
+
+type MatchItem struct {
+	Subject            string `json:"subject,omitempty"`
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeySHA256    string `json:"publicKeySha256"`
+	KeyFormat          string `json:"keyFormat,omitempty"`
+	Encrypted          bool   `json:"encrypted,omitempty"`
+}
+
+type MatchPair struct {
+	Pair  string `json:"pair"`
+	Match bool   `json:"match"`
+}
+
+type FieldDiff struct {
+	Field string `json:"field"`
+	CSR   string `json:"csr"`
+	Cert  string `json:"cert"`
+}
+
+type MatchResponse struct {
+	Cert              *MatchItem  `json:"cert,omitempty"`
+	CSR               *MatchItem  `json:"csr,omitempty"`
+	Key               *MatchItem  `json:"key,omitempty"`
+	Pairs             []MatchPair `json:"pairs"`
+	AllMatch          bool        `json:"allMatch"`
+	SubjectDiffs      []FieldDiff `json:"subjectDiffs"`
+	SANsMissingInCert []string    `json:"sansMissingInCert"`
+	SANsAddedInCert   []string    `json:"sansAddedInCert"`
+}
+
+// ConvertRequest 既可用 JSON 提交，也可用 multipart 上传（文件字段 file，其余为表单字段）
+type ConvertRequest struct {
+	// Data PEM 文本或二进制内容的 base64；上传文件时可不填
+	Data string `json:"data" form:"data"`
+	// Target 输出格式：pem / der / pkcs7 / pkcs12，为空时只解析
+	Target         string `json:"target" form:"target"`
+	Password       string `json:"password" form:"password"`
+	OutputPassword string `json:"outputPassword" form:"outputPassword"`
+	PrivateKey     string `json:"privateKey" form:"privateKey"`
+	KeyPassphrase  string `json:"keyPassphrase" form:"keyPassphrase"`
+	LegacyPKCS12   bool   `json:"legacyPkcs12" form:"legacyPkcs12"`
+}
+
+type ConvertResponse struct {
+	InputFormat string       `json:"inputFormat"`
+	Certs       []CertDetail `json:"certs"`
+	Count       int          `json:"count"`
+	HasKey      bool         `json:"hasKey"`
+
+	OutputFormat string `json:"outputFormat,omitempty"`
+	Output       string `json:"output,omitempty"`
+	// OutputEncoding text（PEM）或 base64（DER / PKCS#7 / PKCS#12）
+	OutputEncoding string `json:"outputEncoding,omitempty"`
+	FileName       string `json:"fileName,omitempty"`
+	PrivateKey     string `json:"privateKey,omitempty"`
+}
+
+type SaveInventoryRequest struct {
+	// Cert 证书 PEM，证书链只保存第一个
+	Cert  string `json:"cert" binding:"required"`
+	Label string `json:"label"`
+}
+
+type InventoryItem struct {
+	ID           string     `json:"id"`
+	Label        string     `json:"label"`
+	AddedAt      time.Time  `json:"addedAt"`
+	DaysToExpiry int        `json:"daysToExpiry"`
+	Status       string     `json:"status"`
+	Cert         CertDetail `json:"cert"`
+}
+
+type InventoryThresholds struct {
+	WarningDays  int `json:"warningDays"`
+	CriticalDays int `json:"criticalDays"`
+}
+
+type InventoryResponse struct {
+	Items      []InventoryItem     `json:"items"`
+	Count      int                 `json:"count"`
+	Thresholds InventoryThresholds `json:"thresholds"`
+	// Summary 各状态的数量：ok / warning / critical / expired
+	Summary map[string]int `json:"summary"`
+}
+
+type DeleteInventoryResponse struct {
+	ID string `json:"id"`
+}
+
+// LintRequest cert 与 csr 至少填写一项
+type LintRequest struct {
+	Cert string `json:"cert"`
+	CSR  string `json:"csr"`
+}
+
+type LintFinding struct {
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+type LintReport struct {
+	Subject  string        `json:"subject"`
+	IsCA     bool          `json:"isCA"`
+	Findings []LintFinding `json:"findings"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	Passed   bool          `json:"passed"`
+}
+
+type LintResponse struct {
+	Certs []LintReport `json:"certs"`
+	CSR   *LintReport  `json:"csr,omitempty"`
+	// Passed 所有证书和 CSR 都没有 error 级别的问题
+	Passed bool `json:"passed"`
+}
+
+// OCSPDecodeRequest 支持 JSON 或 multipart 上传（文件字段 file）
+type OCSPDecodeRequest struct {
+	// Data OCSP 响应的 base64 / PEM；上传文件时可不填
+	Data string `json:"data" form:"data"`
+	// Issuer 签发者证书 PEM，填写时校验响应签名
+	Issuer string `json:"issuer" form:"issuer"`
+}
+
+type OCSPDecodeResponse struct {
+	ResponseStatus string `json:"responseStatus"`
+
+	CertStatus       string     `json:"certStatus,omitempty"`
+	SerialNumber     string     `json:"serialNumber,omitempty"`
+	SerialHex        string     `json:"serialHex,omitempty"`
+	ProducedAt       *time.Time `json:"producedAt,omitempty"`
+	ThisUpdate       *time.Time `json:"thisUpdate,omitempty"`
+	NextUpdate       *time.Time `json:"nextUpdate,omitempty"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+	Expired          bool       `json:"expired"`
+
+	ResponderName      string      `json:"responderName,omitempty"`
+	ResponderKeyHash   string      `json:"responderKeyHash,omitempty"`
+	IssuerHash         string      `json:"issuerHash,omitempty"`
+	SignatureAlgorithm string      `json:"signatureAlgorithm,omitempty"`
+	ResponderCert      *CertDetail `json:"responderCert,omitempty"`
+
+	SignatureChecked bool   `json:"signatureChecked"`
+	SignatureValid   bool   `json:"signatureValid"`
+	SignatureError   string `json:"signatureError,omitempty"`
+}
+
+// CRLDecodeRequest 支持 JSON 或 multipart 上传（文件字段 file）
+type CRLDecodeRequest struct {
+	// Data CRL 的 PEM / base64；上传文件时可不填
+	Data   string `json:"data" form:"data"`
+	Issuer string `json:"issuer" form:"issuer"`
+	// Cert / Serial 用于检查某个证书是否在 CRL 中，Cert 优先
+	Cert   string `json:"cert" form:"cert"`
+	Serial string `json:"serial" form:"serial"`
+}
+
+type RevokedEntry struct {
+	SerialNumber   string    `json:"serialNumber"`
+	SerialHex      string    `json:"serialHex"`
+	RevocationTime time.Time `json:"revocationTime"`
+	Reason         string    `json:"reason,omitempty"`
+}
+
+type CRLLookup struct {
+	SerialNumber   string        `json:"serialNumber"`
+	SerialHex      string        `json:"serialHex"`
+	Revoked        bool          `json:"revoked"`
+	Entry          *RevokedEntry `json:"entry,omitempty"`
+	IssuerMismatch bool          `json:"issuerMismatch"`
+}
+
+type CRLDecodeResponse struct {
+	Issuer             string     `json:"issuer"`
+	Number             string     `json:"number,omitempty"`
+	ThisUpdate         time.Time  `json:"thisUpdate"`
+	NextUpdate         *time.Time `json:"nextUpdate,omitempty"`
+	SignatureAlgorithm string     `json:"signatureAlgorithm"`
+	AuthorityKeyID     string     `json:"authorityKeyId,omitempty"`
+	Expired            bool       `json:"expired"`
+
+	Revoked []RevokedEntry `json:"revoked"`
+	Count   int            `json:"count"`
+
+	SignatureChecked bool   `json:"signatureChecked"`
+	SignatureValid   bool   `json:"signatureValid"`
+	SignatureError   string `json:"signatureError,omitempty"`
+
+	Lookup *CRLLookup `json:"lookup,omitempty"`
+}
+
+// CertDiffRequest left / right 可以是证书、证书链或 CSR
+type CertDiffRequest struct {
+	Left  string `json:"left" binding:"required"`
+	Right string `json:"right" binding:"required"`
+}
+
+type FieldChange struct {
+	Field    string   `json:"field"`
+	Change   string   `json:"change"`
+	Left     string   `json:"left,omitempty"`
+	Right    string   `json:"right,omitempty"`
+	Added    []string `json:"added,omitempty"`
+	Removed  []string `json:"removed,omitempty"`
+	Expected bool     `json:"expected"`
+}
+
+type CertDiff struct {
+	Index        int           `json:"index"`
+	LeftKind     string        `json:"leftKind,omitempty"`
+	RightKind    string        `json:"rightKind,omitempty"`
+	LeftSubject  string        `json:"leftSubject,omitempty"`
+	RightSubject string        `json:"rightSubject,omitempty"`
+	Change       string        `json:"change,omitempty"`
+	Fields       []FieldChange `json:"fields"`
+	Match        bool          `json:"match"`
+}
+
+type CertDiffResponse struct {
+	Pairs []CertDiff `json:"pairs"`
+	// Match 除有效期、序列号外所有字段一致
+	Match bool `json:"match"`
+}
+
+// JWKSRequest cert 可以包含证书、证书链和公钥 PEM
+type JWKSRequest struct {
+	Cert string `json:"cert" binding:"required"`
+	// Use sig / enc，为空时省略
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+}
+
+type JWKSResponse struct {
+	// JWKS RFC 7517 JWK Set
+	JWKS  json.RawMessage `json:"jwks"`
+	Count int             `json:"count"`
+}
+
+// JWKSDecodeRequest jwks 可以是 JWK Set 或单个 JWK
+type JWKSDecodeRequest struct {
+	JWKS string `json:"jwks" binding:"required"`
+}
+
+type JWKInfo struct {
+	Kid                string       `json:"kid,omitempty"`
+	Kty                string       `json:"kty"`
+	Use                string       `json:"use,omitempty"`
+	Alg                string       `json:"alg,omitempty"`
+	PublicKeyAlgorithm string       `json:"publicKeyAlgorithm"`
+	PublicKeySize      int          `json:"publicKeySize"`
+	PublicKeyCurve     string       `json:"publicKeyCurve,omitempty"`
+	PublicKeySHA256    string       `json:"publicKeySha256"`
+	Thumbprint         string       `json:"thumbprint"`
+	PublicKeyPEM       string       `json:"publicKeyPem"`
+	Private            bool         `json:"private"`
+	Certs              []CertDetail `json:"certs"`
+	Warnings           []string     `json:"warnings"`
+}
+
+type JWKSDecodeResponse struct {
+	Keys  []JWKInfo `json:"keys"`
+	Count int       `json:"count"`
+}
+
+type FetchRequest struct {
+	// Address host 或 host:port，不带端口时按 startTls 使用 443 / 25 / 143 / 110
+	Address    string `json:"address" binding:"required"`
+	ServerName string `json:"serverName"`
+	// StartTLS 取值 smtp / imap / pop3，为空时直接 TLS
+	StartTLS       string `json:"startTls"`
+	TimeoutSeconds int    `json:"timeoutSeconds"`
+	// Verify / TrustStore / RootsPEM 与 /cert/split 相同
+	Verify     bool   `json:"verify"`
+	TrustStore string `json:"trustStore"`
+	RootsPEM   string `json:"rootsPem"`
+}
+
+// FetchResponse certs / count / verification 与 /cert/split 结构一致
+type FetchResponse struct {
+	SplitCertChainResponse
+	Address     string `json:"address"`
+	ServerName  string `json:"serverName,omitempty"`
+	StartTLS    string `json:"startTls,omitempty"`
+	Protocol    string `json:"protocol"`
+	CipherSuite string `json:"cipherSuite"`
+	ALPN        string `json:"alpn,omitempty"`
+	// HostnameMatch 叶子证书是否覆盖 serverName；没有 serverName 时为空
+	HostnameMatch *bool  `json:"hostnameMatch,omitempty"`
+	HostnameError string `json:"hostnameError,omitempty"`
+	// OCSPStaple 服务端装订的 OCSP 响应，有签发者证书时校验签名
+	OCSPStaple      *OCSPDecodeResponse `json:"ocspStaple,omitempty"`
+	OCSPStapleError string              `json:"ocspStapleError,omitempty"`
+	ChainPEM        string              `json:"chainPem"`
+}
+
+// BundleRequest 支持 JSON 或 multipart 上传（叶子证书文件字段 leafFile，候选证书文件字段 files，可多个，支持 zip）
+type BundleRequest struct {
+	// Leaf 叶子证书 PEM；上传 leafFile 时可不填
+	Leaf string `json:"leaf" form:"leaf"`
+	// Candidates 粘贴的候选中间证书 / 根证书
+	Candidates  string `json:"candidates" form:"candidates"`
+	IncludeRoot bool   `json:"includeRoot" form:"includeRoot"`
+	TrustStore  string `json:"trustStore" form:"trustStore"`
+	RootsPEM    string `json:"rootsPem" form:"rootsPem"`
+}
+
+type BundleResponse struct {
+	Leaf       CertDetail   `json:"leaf"`
+	Chain      []CertDetail `json:"chain"`
+	Unused     []CertDetail `json:"unused"`
+	Duplicates int          `json:"duplicates"`
+
+	Complete      bool     `json:"complete"`
+	MissingIssuer string   `json:"missingIssuer,omitempty"`
+	RootIncluded  bool     `json:"rootIncluded"`
+	RootSource    string   `json:"rootSource,omitempty"`
+	Verified      bool     `json:"verified"`
+	VerifyError   string   `json:"verifyError,omitempty"`
+	Warnings      []string `json:"warnings"`
+
+	// FullChainPEM nginx；CertPEM + ChainPEM Apache；PKCS7 IIS（base64）
+	FullChainPEM string `json:"fullChainPem"`
+	CertPEM      string `json:"certPem"`
+	ChainPEM     string `json:"chainPem"`
+	PKCS7        string `json:"pkcs7"`
 }
@@ -1,15 +1,24 @@
 package cert
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 
 	httpapi "my-tools/internal/api/http"
+	domaincert "my-tools/internal/domain/cert"
+	"my-tools/internal/infra/inventory"
+	"my-tools/internal/infra/tlsfetch"
 )
 
 func Register(r *gin.RouterGroup) {
-	svc := NewService()
+	svc := NewService(inventory.NewStore())
 	g := r.Group("/cert")
 	g.POST("/split", func(c *gin.Context) {
 		var req SplitCertChainRequest
@@ -24,9 +33,363 @@ func Register(r *gin.RouterGroup) {
 			return
 		}
 
-		c.JSON(http.StatusOK, httpapi.OK(SplitCertChainResponse{
+		resp := SplitCertChainResponse{
 			Certs: certs,
 			Count: len(certs),
-		}))
+		}
+		if req.Verify {
+			v, err := svc.VerifyCertChain(req.CertChain, req.TrustStore, req.RootsPEM)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_cert", err.Error()))
+				return
+			}
+			resp.Verification = v
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(resp))
+	})
+
+	g.POST("/match", func(c *gin.Context) {
+		var req MatchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Match(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/lint", func(c *gin.Context) {
+		var req LintRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+		if strings.TrimSpace(req.Cert) == "" && strings.TrimSpace(req.CSR) == "" {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", "cert or csr is required"))
+			return
+		}
+
+		resp := LintResponse{Certs: []LintReport{}, Passed: true}
+		if strings.TrimSpace(req.Cert) != "" {
+			certs, err := svc.LintCertChain(req.Cert)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_cert", err.Error()))
+				return
+			}
+			resp.Certs = certs
+		}
+		if strings.TrimSpace(req.CSR) != "" {
+			r, err := svc.LintCSR(req.CSR)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_csr", err.Error()))
+				return
+			}
+			resp.CSR = r
+		}
+		for _, r := range resp.Certs {
+			resp.Passed = resp.Passed && r.Passed
+		}
+		if resp.CSR != nil {
+			resp.Passed = resp.Passed && resp.CSR.Passed
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(resp))
+	})
+
+	g.POST("/diff", func(c *gin.Context) {
+		var req CertDiffRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Diff(req.Left, req.Right)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/jwks", func(c *gin.Context) {
+		var req JWKSRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.CertsToJWKS(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/jwks/decode", func(c *gin.Context) {
+		var req JWKSDecodeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.DecodeJWKS(req.JWKS)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_key", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
 	})
+
+	g.POST("/fetch", func(c *gin.Context) {
+		var req FetchRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Fetch(c.Request.Context(), req)
+		if err != nil {
+			if errors.Is(err, tlsfetch.ErrInvalidOptions) {
+				c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+				return
+			}
+			c.JSON(http.StatusBadGateway, httpapi.Fail("fetch_failed", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/convert", func(c *gin.Context) {
+		var req ConvertRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		data, err := readUpload(c, req.Data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Convert(data, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_cert", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/bundle", func(c *gin.Context) {
+		var req BundleRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		leaf, candidates, err := readBundleUploads(c, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Bundle(leaf, candidates, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_cert", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/ocsp/decode", func(c *gin.Context) {
+		var req OCSPDecodeRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		data, err := readUpload(c, req.Data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.DecodeOCSP(data, req.Issuer)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/crl/decode", func(c *gin.Context) {
+		var req CRLDecodeRequest
+		if err := c.ShouldBind(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		data, err := readUpload(c, req.Data)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.DecodeCRL(data, req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.GET("/inventory", func(c *gin.Context) {
+		thresholds, err := parseThresholds(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		if c.Query("format") == "csv" {
+			c.Header("Content-Type", "text/csv; charset=utf-8")
+			c.Header("Content-Disposition", "attachment; filename=\"cert_inventory.csv\"")
+			if err := svc.ExportInventoryCSV(c.Writer, thresholds); err != nil {
+				c.JSON(http.StatusInternalServerError, httpapi.Fail("internal", err.Error()))
+			}
+			return
+		}
+
+		res, err := svc.ListInventory(thresholds)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, httpapi.Fail("internal", err.Error()))
+			return
+		}
+		if c.Query("format") == "json" {
+			c.Header("Content-Disposition", "attachment; filename=\"cert_inventory.json\"")
+			c.IndentedJSON(http.StatusOK, res.Items)
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/inventory", func(c *gin.Context) {
+		var req SaveInventoryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		item, err := svc.SaveInventory(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_cert", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(item))
+	})
+
+	g.DELETE("/inventory/:id", func(c *gin.Context) {
+		found, err := svc.DeleteInventory(c.Param("id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, httpapi.Fail("internal", err.Error()))
+			return
+		}
+		if !found {
+			c.JSON(http.StatusNotFound, httpapi.Fail("not_found", "inventory entry not found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(DeleteInventoryResponse{ID: c.Param("id")}))
+	})
+}
+
+// readUpload multipart 上传了文件字段 file 时读取文件内容，否则使用文本字段
+func readUpload(c *gin.Context, text string) ([]byte, error) {
+	fh, err := c.FormFile("file")
+	if err != nil {
+		return []byte(text), nil
+	}
+	return readFormFile(fh)
+}
+
+// readBundleUploads 合并文本字段和 multipart 上传的文件：leafFile 优先于 leaf，files 追加在 candidates 之后
+func readBundleUploads(c *gin.Context, req BundleRequest) (domaincert.BundleInput, []domaincert.BundleInput, error) {
+	leaf := domaincert.BundleInput{Data: []byte(req.Leaf)}
+	candidates := []domaincert.BundleInput{{Data: []byte(req.Candidates)}}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return leaf, candidates, nil
+	}
+	if fhs := form.File["leafFile"]; len(fhs) > 0 {
+		data, err := readFormFile(fhs[0])
+		if err != nil {
+			return domaincert.BundleInput{}, nil, err
+		}
+		leaf = domaincert.BundleInput{Name: fhs[0].Filename, Data: data}
+	}
+	for _, fh := range form.File["files"] {
+		data, err := readFormFile(fh)
+		if err != nil {
+			return domaincert.BundleInput{}, nil, err
+		}
+		candidates = append(candidates, domaincert.BundleInput{Name: fh.Filename, Data: data})
+	}
+	return leaf, candidates, nil
+}
+
+// maxUploadSize 单个上传文件的最大字节数
+const maxUploadSize = 10 << 20
+
+func readFormFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxUploadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxUploadSize {
+		return nil, fmt.Errorf("file %s is too large (max %d bytes)", fh.Filename, maxUploadSize)
+	}
+	return data, nil
+}
+
+// parseThresholds 读取 warningDays / criticalDays 查询参数，未填写时使用默认值
+func parseThresholds(c *gin.Context) (domaincert.ExpiryThresholds, error) {
+	t := domaincert.DefaultExpiryThresholds
+	for _, q := range []struct {
+		name string
+		dst  *int
+	}{{"warningDays", &t.WarningDays}, {"criticalDays", &t.CriticalDays}} {
+		v := c.Query(q.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return t, errors.New(q.name + " must be an integer")
+		}
+		*q.dst = n
+	}
+	return t, t.Validate()
 }
@@ -1,11 +1,33 @@
 package cert
 
-import domaincert "my-tools/internal/domain/cert"
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
-type Service struct{}
+	domaincert "my-tools/internal/domain/cert"
+	"my-tools/internal/infra/inventory"
+	"my-tools/internal/infra/tlsfetch"
+)
 
-func NewService() *Service {
-	return &Service{}
+var convertFileNames = map[string]string{
+	domaincert.FormatPEM:    "fullchain.pem",
+	domaincert.FormatDER:    "cert.cer",
+	domaincert.FormatPKCS7:  "cert.p7b",
+	domaincert.FormatPKCS12: "cert.pfx",
+}
+
+type Service struct {
+	inventory *inventory.Store
+}
+
+func NewService(store *inventory.Store) *Service {
+	return &Service{inventory: store}
 }
 
 func (s *Service) SplitCertChain(input string) ([]string, error) {
@@ -17,19 +39,548 @@ func (s *Service) SplitCertChainWithInfo(input string) ([]CertDetail, error) {
 	if err != nil {
 		return nil, err
 	}
+	return toCertDetails(certInfos), nil
+}
+
+func (s *Service) VerifyCertChain(input, trustStore, rootsPEM string) (*ChainVerification, error) {
+	res, err := domaincert.VerifyCertChain(input, domaincert.VerifyOptions{
+		TrustStore: trustStore,
+		RootsPEM:   rootsPEM,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]ChainLink, len(res.Links))
+	for i, l := range res.Links {
+		links[i] = ChainLink{
+			Subject:        l.Subject,
+			Issuer:         l.Issuer,
+			IssuerSource:   l.IssuerSource,
+			SignatureValid: l.SignatureValid,
+			Error:          l.Error,
+		}
+	}
+
+	return &ChainVerification{
+		Verified:             res.Verified,
+		VerifyError:          res.VerifyError,
+		Order:                res.Order,
+		Ordered:              toCertDetails(res.Ordered),
+		Links:                links,
+		OutOfOrder:           res.OutOfOrder,
+		MissingIntermediates: res.MissingIntermediates,
+		MissingIssuer:        res.MissingIssuer,
+		Unused:               res.Unused,
+		FullChainPEM:         res.FullChainPEM,
+	}, nil
+}
+
+func (s *Service) Match(req MatchRequest) (MatchResponse, error) {
+	res, err := domaincert.MatchCertCSRKey(domaincert.MatchInput{
+		CertPEM:    req.Cert,
+		CSRPEM:     req.CSR,
+		KeyPEM:     req.PrivateKey,
+		Passphrase: req.Passphrase,
+	})
+	if err != nil {
+		return MatchResponse{}, err
+	}
+
+	pairs := make([]MatchPair, len(res.Pairs))
+	for i, p := range res.Pairs {
+		pairs[i] = MatchPair{Pair: p.Pair, Match: p.Match}
+	}
+	diffs := make([]FieldDiff, len(res.SubjectDiffs))
+	for i, d := range res.SubjectDiffs {
+		diffs[i] = FieldDiff{Field: d.Field, CSR: d.CSR, Cert: d.Cert}
+	}
+
+	return MatchResponse{
+		Cert:              toMatchItem(res.Cert),
+		CSR:               toMatchItem(res.CSR),
+		Key:               toMatchItem(res.Key),
+		Pairs:             pairs,
+		AllMatch:          res.AllMatch,
+		SubjectDiffs:      diffs,
+		SANsMissingInCert: res.SANsMissingInCert,
+		SANsAddedInCert:   res.SANsAddedInCert,
+	}, nil
+}
+
+func (s *Service) Convert(data []byte, req ConvertRequest) (ConvertResponse, error) {
+	res, err := domaincert.ConvertCert(data, domaincert.ConvertOptions{
+		Target:         req.Target,
+		InputPassword:  req.Password,
+		OutputPassword: req.OutputPassword,
+		KeyPEM:         req.PrivateKey,
+		KeyPassphrase:  req.KeyPassphrase,
+		LegacyPKCS12:   req.LegacyPKCS12,
+	})
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+
+	resp := ConvertResponse{
+		InputFormat:  res.InputFormat,
+		Certs:        toCertDetails(res.Certs),
+		Count:        len(res.Certs),
+		HasKey:       res.HasKey,
+		OutputFormat: res.OutputFormat,
+		FileName:     convertFileNames[res.OutputFormat],
+		PrivateKey:   res.PrivateKeyPEM,
+	}
+	switch res.OutputFormat {
+	case "":
+	case domaincert.FormatPEM:
+		resp.Output = string(res.Output)
+		resp.OutputEncoding = "text"
+	default:
+		resp.Output = base64.StdEncoding.EncodeToString(res.Output)
+		resp.OutputEncoding = "base64"
+	}
+	return resp, nil
+}
+
+func (s *Service) SaveInventory(req SaveInventoryRequest) (InventoryItem, error) {
+	entry, err := domaincert.NewInventoryEntry(req.Cert, req.Label, time.Now())
+	if err != nil {
+		return InventoryItem{}, err
+	}
+	rec, err := s.inventory.Put(inventory.Record{ID: entry.ID, Label: entry.Label, PEM: entry.PEM, AddedAt: entry.AddedAt})
+	if err != nil {
+		return InventoryItem{}, err
+	}
+
+	items, err := domaincert.BuildInventory([]domaincert.InventoryEntry{toInventoryEntry(rec)}, domaincert.DefaultExpiryThresholds, time.Now())
+	if err != nil {
+		return InventoryItem{}, err
+	}
+	return toInventoryItem(items[0]), nil
+}
+
+func (s *Service) DeleteInventory(id string) (bool, error) {
+	return s.inventory.Delete(id)
+}
+
+func (s *Service) Inventory(thresholds domaincert.ExpiryThresholds) ([]domaincert.InventoryItem, error) {
+	records, err := s.inventory.List()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]domaincert.InventoryEntry, len(records))
+	for i, r := range records {
+		entries[i] = toInventoryEntry(r)
+	}
+	return domaincert.BuildInventory(entries, thresholds, time.Now())
+}
+
+func (s *Service) ListInventory(thresholds domaincert.ExpiryThresholds) (InventoryResponse, error) {
+	items, err := s.Inventory(thresholds)
+	if err != nil {
+		return InventoryResponse{}, err
+	}
+
+	resp := InventoryResponse{
+		Items:      make([]InventoryItem, len(items)),
+		Count:      len(items),
+		Thresholds: InventoryThresholds{WarningDays: thresholds.WarningDays, CriticalDays: thresholds.CriticalDays},
+		Summary: map[string]int{
+			domaincert.ExpiryStatusOK:       0,
+			domaincert.ExpiryStatusWarning:  0,
+			domaincert.ExpiryStatusCritical: 0,
+			domaincert.ExpiryStatusExpired:  0,
+		},
+	}
+	for i, it := range items {
+		resp.Items[i] = toInventoryItem(it)
+		resp.Summary[it.Status]++
+	}
+	return resp, nil
+}
+
+func (s *Service) ExportInventoryCSV(w io.Writer, thresholds domaincert.ExpiryThresholds) error {
+	items, err := s.Inventory(thresholds)
+	if err != nil {
+		return err
+	}
+	return domaincert.WriteInventoryCSV(w, items)
+}
+
+func toInventoryEntry(r inventory.Record) domaincert.InventoryEntry {
+	return domaincert.InventoryEntry{ID: r.ID, Label: r.Label, PEM: r.PEM, AddedAt: r.AddedAt}
+}
+
+func toInventoryItem(it domaincert.InventoryItem) InventoryItem {
+	return InventoryItem{
+		ID:           it.ID,
+		Label:        it.Label,
+		AddedAt:      it.AddedAt,
+		DaysToExpiry: it.DaysToExpiry,
+		Status:       it.Status,
+		Cert:         toCertDetail(it.Cert),
+	}
+}
+
+func (s *Service) LintCertChain(input string) ([]LintReport, error) {
+	reports, err := domaincert.LintCertChain(input)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]LintReport, len(reports))
+	for i, r := range reports {
+		out[i] = toLintReport(r)
+	}
+	return out, nil
+}
+
+func (s *Service) LintCSR(input string) (*LintReport, error) {
+	r, err := domaincert.LintCSR(input)
+	if err != nil {
+		return nil, err
+	}
+	out := toLintReport(r)
+	return &out, nil
+}
+
+func toLintReport(r domaincert.LintReport) LintReport {
+	findings := make([]LintFinding, len(r.Findings))
+	for i, f := range r.Findings {
+		findings[i] = LintFinding{Code: f.Code, Severity: f.Severity, Message: f.Message}
+	}
+	return LintReport{
+		Subject:  r.Subject,
+		IsCA:     r.IsCA,
+		Findings: findings,
+		Errors:   r.Errors,
+		Warnings: r.Warnings,
+		Passed:   r.Passed,
+	}
+}
+
+func (s *Service) DecodeOCSP(data []byte, issuerPEM string) (OCSPDecodeResponse, error) {
+	info, err := domaincert.DecodeOCSPResponse(data, issuerPEM)
+	if err != nil {
+		return OCSPDecodeResponse{}, err
+	}
+
+	resp := OCSPDecodeResponse{
+		ResponseStatus:     info.ResponseStatus,
+		CertStatus:         info.CertStatus,
+		SerialNumber:       info.SerialNumber,
+		SerialHex:          info.SerialHex,
+		ProducedAt:         info.ProducedAt,
+		ThisUpdate:         info.ThisUpdate,
+		NextUpdate:         info.NextUpdate,
+		RevokedAt:          info.RevokedAt,
+		RevocationReason:   info.RevocationReason,
+		Expired:            info.Expired,
+		ResponderName:      info.ResponderName,
+		ResponderKeyHash:   info.ResponderKeyHash,
+		IssuerHash:         info.IssuerHash,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+		SignatureChecked:   info.SignatureChecked,
+		SignatureValid:     info.SignatureValid,
+		SignatureError:     info.SignatureError,
+	}
+	if info.ResponderCert != nil {
+		d := toCertDetail(*info.ResponderCert)
+		resp.ResponderCert = &d
+	}
+	return resp, nil
+}
+
+func (s *Service) DecodeCRL(data []byte, req CRLDecodeRequest) (CRLDecodeResponse, error) {
+	info, err := domaincert.DecodeCRL(data, domaincert.CRLOptions{
+		IssuerPEM: req.Issuer,
+		CertPEM:   req.Cert,
+		Serial:    req.Serial,
+	})
+	if err != nil {
+		return CRLDecodeResponse{}, err
+	}
+
+	revoked := make([]RevokedEntry, len(info.Revoked))
+	for i, e := range info.Revoked {
+		revoked[i] = toRevokedEntry(e)
+	}
+	resp := CRLDecodeResponse{
+		Issuer:             info.Issuer,
+		Number:             info.Number,
+		ThisUpdate:         info.ThisUpdate,
+		NextUpdate:         info.NextUpdate,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+		AuthorityKeyID:     info.AuthorityKeyID,
+		Expired:            info.Expired,
+		Revoked:            revoked,
+		Count:              info.Count,
+		SignatureChecked:   info.SignatureChecked,
+		SignatureValid:     info.SignatureValid,
+		SignatureError:     info.SignatureError,
+	}
+	if info.Lookup != nil {
+		resp.Lookup = &CRLLookup{
+			SerialNumber:   info.Lookup.SerialNumber,
+			SerialHex:      info.Lookup.SerialHex,
+			Revoked:        info.Lookup.Revoked,
+			IssuerMismatch: info.Lookup.IssuerMismatch,
+		}
+		if info.Lookup.Entry != nil {
+			e := toRevokedEntry(*info.Lookup.Entry)
+			resp.Lookup.Entry = &e
+		}
+	}
+	return resp, nil
+}
+
+func toRevokedEntry(e domaincert.RevokedEntry) RevokedEntry {
+	return RevokedEntry{
+		SerialNumber:   e.SerialNumber,
+		SerialHex:      e.SerialHex,
+		RevocationTime: e.RevocationTime,
+		Reason:         e.Reason,
+	}
+}
+
+func toMatchItem(item *domaincert.MatchItem) *MatchItem {
+	if item == nil {
+		return nil
+	}
+	return &MatchItem{
+		Subject:            item.Subject,
+		PublicKeyAlgorithm: item.PublicKeyAlgorithm,
+		PublicKeySize:      item.PublicKeySize,
+		PublicKeySHA256:    item.PublicKeySHA256,
+		KeyFormat:          item.KeyFormat,
+		Encrypted:          item.Encrypted,
+	}
+}
 
+func toCertDetails(certInfos []domaincert.CertInfo) []CertDetail {
 	result := make([]CertDetail, len(certInfos))
 	for i, info := range certInfos {
-		result[i] = CertDetail{
-			PEM:          info.PEM,
-			Subject:      info.Subject,
-			Issuer:       info.Issuer,
-			NotBefore:    info.NotBefore,
-			NotAfter:     info.NotAfter,
-			SerialNumber: info.SerialNumber,
-			Version:      info.Version,
-			IsCA:         info.IsCA,
-		}
-	}
-	return result, nil
+		result[i] = toCertDetail(info)
+	}
+	return result
+}
+
+func toCertDetail(info domaincert.CertInfo) CertDetail {
+	scts := make([]SCT, len(info.SCTs))
+	for i, s := range info.SCTs {
+		scts[i] = SCT{
+			Version:            s.Version,
+			LogID:              s.LogID,
+			LogName:            s.LogName,
+			LogOperator:        s.LogOperator,
+			Timestamp:          s.Timestamp,
+			HashAlgorithm:      s.HashAlgorithm,
+			SignatureAlgorithm: s.SignatureAlgorithm,
+			Signature:          s.Signature,
+			Extensions:         s.Extensions,
+		}
+	}
+
+	return CertDetail{
+		PEM:          info.PEM,
+		Subject:      info.Subject,
+		Issuer:       info.Issuer,
+		NotBefore:    info.NotBefore,
+		NotAfter:     info.NotAfter,
+		SerialNumber: info.SerialNumber,
+		Version:      info.Version,
+		IsCA:         info.IsCA,
+
+		DNSNames:       info.DNSNames,
+		IPAddresses:    info.IPAddresses,
+		EmailAddresses: info.EmailAddresses,
+		URIs:           info.URIs,
+
+		KeyUsage:    info.KeyUsage,
+		ExtKeyUsage: info.ExtKeyUsage,
+
+		PublicKeyAlgorithm: info.PublicKeyAlgorithm,
+		PublicKeySize:      info.PublicKeySize,
+		PublicKeyCurve:     info.PublicKeyCurve,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+
+		FingerprintSHA1:   info.FingerprintSHA1,
+		FingerprintSHA256: info.FingerprintSHA256,
+		SubjectKeyID:      info.SubjectKeyID,
+		AuthorityKeyID:    info.AuthorityKeyID,
+
+		CRLDistributionPoints: info.CRLDistributionPoints,
+		OCSPServers:           info.OCSPServers,
+		IssuingCertificateURL: info.IssuingCertificateURL,
+
+		PolicyOIDs:        info.PolicyOIDs,
+		ValidationLevel:   info.ValidationLevel,
+		PathLenConstraint: info.PathLenConstraint,
+
+		SCTs:           scts,
+		SCTError:       info.SCTError,
+		Precertificate: info.Precertificate,
+	}
+}
+
+func (s *Service) Diff(left, right string) (CertDiffResponse, error) {
+	res, err := domaincert.DiffCerts(left, right)
+	if err != nil {
+		return CertDiffResponse{}, err
+	}
+
+	pairs := make([]CertDiff, len(res.Pairs))
+	for i, p := range res.Pairs {
+		fields := make([]FieldChange, len(p.Fields))
+		for j, f := range p.Fields {
+			fields[j] = FieldChange{
+				Field:    f.Field,
+				Change:   f.Change,
+				Left:     f.Left,
+				Right:    f.Right,
+				Added:    f.Added,
+				Removed:  f.Removed,
+				Expected: f.Expected,
+			}
+		}
+		pairs[i] = CertDiff{
+			Index:        p.Index,
+			LeftKind:     p.LeftKind,
+			RightKind:    p.RightKind,
+			LeftSubject:  p.LeftSubject,
+			RightSubject: p.RightSubject,
+			Change:       p.Change,
+			Fields:       fields,
+			Match:        p.Match,
+		}
+	}
+	return CertDiffResponse{Pairs: pairs, Match: res.Match}, nil
+}
+
+func (s *Service) CertsToJWKS(req JWKSRequest) (JWKSResponse, error) {
+	set, err := domaincert.CertsToJWKS(req.Cert, domaincert.JWKOptions{Use: req.Use, Alg: req.Alg})
+	if err != nil {
+		return JWKSResponse{}, err
+	}
+	return JWKSResponse{JWKS: json.RawMessage(set.String()), Count: len(set.Keys)}, nil
+}
+
+func (s *Service) DecodeJWKS(input string) (JWKSDecodeResponse, error) {
+	infos, err := domaincert.JWKSToPEM(input)
+	if err != nil {
+		return JWKSDecodeResponse{}, err
+	}
+
+	keys := make([]JWKInfo, len(infos))
+	for i, info := range infos {
+		keys[i] = JWKInfo{
+			Kid:                info.Kid,
+			Kty:                info.Kty,
+			Use:                info.Use,
+			Alg:                info.Alg,
+			PublicKeyAlgorithm: info.PublicKeyAlgorithm,
+			PublicKeySize:      info.PublicKeySize,
+			PublicKeyCurve:     info.PublicKeyCurve,
+			PublicKeySHA256:    info.PublicKeySHA256,
+			Thumbprint:         info.Thumbprint,
+			PublicKeyPEM:       info.PublicKeyPEM,
+			Private:            info.Private,
+			Certs:              toCertDetails(info.Certs),
+			Warnings:           info.Warnings,
+		}
+	}
+	return JWKSDecodeResponse{Keys: keys, Count: len(keys)}, nil
+}
+
+func (s *Service) Fetch(ctx context.Context, req FetchRequest) (FetchResponse, error) {
+	res, err := tlsfetch.Fetch(ctx, tlsfetch.Options{
+		Address:    req.Address,
+		ServerName: req.ServerName,
+		StartTLS:   req.StartTLS,
+		Timeout:    time.Duration(req.TimeoutSeconds) * time.Second,
+	})
+	if err != nil {
+		return FetchResponse{}, err
+	}
+
+	var chain strings.Builder
+	for _, c := range res.Certificates {
+		chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+	}
+	chainPEM := chain.String()
+
+	certs, err := s.SplitCertChainWithInfo(chainPEM)
+	if err != nil {
+		return FetchResponse{}, err
+	}
+	resp := FetchResponse{
+		SplitCertChainResponse: SplitCertChainResponse{Certs: certs, Count: len(certs)},
+		Address:                res.Address,
+		ServerName:             res.ServerName,
+		StartTLS:               res.StartTLS,
+		Protocol:               res.Protocol,
+		CipherSuite:            res.CipherSuite,
+		ALPN:                   res.ALPN,
+		ChainPEM:               chainPEM,
+	}
+	if req.Verify {
+		v, err := s.VerifyCertChain(chainPEM, req.TrustStore, req.RootsPEM)
+		if err != nil {
+			// 证书已经取到，这里只可能是信任库参数错误
+			return FetchResponse{}, fmt.Errorf("%w: %v", tlsfetch.ErrInvalidOptions, err)
+		}
+		resp.Verification = v
+	}
+
+	if res.ServerName != "" {
+		match := true
+		if err := res.Certificates[0].VerifyHostname(res.ServerName); err != nil {
+			match = false
+			resp.HostnameError = err.Error()
+		}
+		resp.HostnameMatch = &match
+	}
+
+	if len(res.OCSPResponse) > 0 {
+		// 装订的 OCSP 响应针对叶子证书，由服务端发送的第二个证书签发
+		issuerPEM := ""
+		if len(certs) > 1 {
+			issuerPEM = certs[1].PEM
+		}
+		staple, err := s.DecodeOCSP(res.OCSPResponse, issuerPEM)
+		if err != nil {
+			resp.OCSPStapleError = err.Error()
+		} else {
+			resp.OCSPStaple = &staple
+		}
+	}
+	return resp, nil
+}
+
+func (s *Service) Bundle(leaf domaincert.BundleInput, candidates []domaincert.BundleInput, req BundleRequest) (BundleResponse, error) {
+	res, err := domaincert.BuildBundle(leaf, candidates, domaincert.BundleOptions{
+		IncludeRoot: req.IncludeRoot,
+		TrustStore:  req.TrustStore,
+		RootsPEM:    req.RootsPEM,
+	})
+	if err != nil {
+		return BundleResponse{}, err
+	}
+
+	return BundleResponse{
+		Leaf:          toCertDetail(res.Leaf),
+		Chain:         toCertDetails(res.Chain),
+		Unused:        toCertDetails(res.Unused),
+		Duplicates:    res.Duplicates,
+		Complete:      res.Complete,
+		MissingIssuer: res.MissingIssuer,
+		RootIncluded:  res.RootIncluded,
+		RootSource:    res.RootSource,
+		Verified:      res.Verified,
+		VerifyError:   res.VerifyError,
+		Warnings:      res.Warnings,
+		FullChainPEM:  res.FullChainPEM,
+		CertPEM:       res.CertPEM,
+		ChainPEM:      res.ChainPEM,
+		PKCS7:         base64.StdEncoding.EncodeToString(res.PKCS7),
+	}, nil
 }
@@ -0,0 +1,22 @@
+package convert
+
+type ConvertRequest struct {
+	Input string `json:"input" binding:"required"`
+	// From 源格式 json / yaml / toml / xml / csv，默认 json
+	From string `json:"from"`
+	// To 目标格式，取值同 From
+	To     string `json:"to" binding:"required"`
+	Indent int    `json:"indent"`
+	// RootName 转为 XML 时的根元素名，默认 root
+	RootName string `json:"rootName"`
+	// Delimiter CSV 分隔符，默认逗号
+	Delimiter string `json:"delimiter"`
+	// RawStrings CSV 转出时所有单元格都作为字符串
+	RawStrings bool `json:"rawStrings"`
+}
+
+type ConvertResponse struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Output string `json:"output"`
+}
@@ -0,0 +1,34 @@
+package convert
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpapi "my-tools/internal/api/http"
+	domainconvert "my-tools/internal/domain/convert"
+)
+
+func Register(r *gin.RouterGroup) {
+	svc := NewService()
+	r.POST("/convert", func(c *gin.Context) {
+		var req ConvertRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Convert(req)
+		if err != nil {
+			code := "invalid_input"
+			if errors.Is(err, domainconvert.ErrUnsupportedFormat) {
+				code = "bad_request"
+			}
+			c.JSON(http.StatusBadRequest, httpapi.Fail(code, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+}
@@ -0,0 +1,36 @@
+package convert
+
+import (
+	domainconvert "my-tools/internal/domain/convert"
+)
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Convert(req ConvertRequest) (ConvertResponse, error) {
+	from := req.From
+	if from == "" {
+		from = domainconvert.FormatJSON
+	}
+	indent := req.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	res, err := domainconvert.Convert(req.Input, from, req.To, domainconvert.Options{
+		Indent:     indent,
+		RootName:   req.RootName,
+		Delimiter:  req.Delimiter,
+		RawStrings: req.RawStrings,
+	})
+	if err != nil {
+		return ConvertResponse{}, err
+	}
+	return ConvertResponse{
+		From:   res.From,
+		To:     res.To,
+		Output: res.Output,
+	}, nil
+}
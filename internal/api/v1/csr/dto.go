@@ -7,3 +7,78 @@ type FormatCSRRequest struct {
 type FormatCSRResponse struct {
 	PEM string `json:"pem"`
 }
+
+type ParseCSRRequest struct {
+	CSR string `json:"csr" binding:"required"`
+}
+
+type CSRSubject struct {
+	CommonName         string   `json:"commonName"`
+	Organization       []string `json:"organization"`
+	OrganizationalUnit []string `json:"organizationalUnit"`
+	Locality           []string `json:"locality"`
+	Province           []string `json:"province"`
+	Country            []string `json:"country"`
+}
+
+type CSRExtension struct {
+	OID      string `json:"oid"`
+	Name     string `json:"name"`
+	Critical bool   `json:"critical"`
+	Value    string `json:"value"`
+}
+
+type CSRAttribute struct {
+	OID    string   `json:"oid"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type ParseCSRResponse struct {
+	PEM     string     `json:"pem"`
+	Subject string     `json:"subject"`
+	Fields  CSRSubject `json:"fields"`
+
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	URIs           []string `json:"uris"`
+
+	KeyUsage    []string `json:"keyUsage"`
+	ExtKeyUsage []string `json:"extKeyUsage"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+	SignatureValid     bool   `json:"signatureValid"`
+	SignatureError     string `json:"signatureError,omitempty"`
+
+	Extensions        []CSRExtension `json:"extensions"`
+	Attributes        []CSRAttribute `json:"attributes"`
+	ChallengePassword string         `json:"challengePassword,omitempty"`
+}
+
+type GenerateCSRRequest struct {
+	CommonName         string `json:"commonName" binding:"required"`
+	Organization       string `json:"organization"`
+	OrganizationalUnit string `json:"organizationalUnit"`
+	Locality           string `json:"locality"`
+	Province           string `json:"province"`
+	Country            string `json:"country"`
+
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+
+	// KeySpec RSA-2048 / RSA-3072 / RSA-4096 / ECDSA-P256 / ECDSA-P384 / Ed25519，默认 RSA-2048
+	KeySpec    string `json:"keySpec"`
+	Passphrase string `json:"passphrase"`
+}
+
+type GenerateCSRResponse struct {
+	CSR        string `json:"csr"`
+	PrivateKey string `json:"privateKey"`
+	KeySpec    string `json:"keySpec"`
+	Encrypted  bool   `json:"encrypted"`
+}
@@ -8,6 +8,85 @@ func NewService() *Service {
 	return &Service{}
 }
 
+// FormatCSR 规范化 CSR，无法解析的 CSR 直接报错，避免“格式化成功”的假象
 func (s *Service) FormatCSR(input string) (string, error) {
-	return domaincsr.NormalizeCSRPEM(input)
+	info, err := domaincsr.ParseCSR(input)
+	if err != nil {
+		return "", err
+	}
+	return info.PEM, nil
+}
+
+func (s *Service) ParseCSR(input string) (ParseCSRResponse, error) {
+	info, err := domaincsr.ParseCSR(input)
+	if err != nil {
+		return ParseCSRResponse{}, err
+	}
+
+	extensions := make([]CSRExtension, len(info.Extensions))
+	for i, e := range info.Extensions {
+		extensions[i] = CSRExtension{OID: e.OID, Name: e.Name, Critical: e.Critical, Value: e.Value}
+	}
+	attributes := make([]CSRAttribute, len(info.Attributes))
+	for i, a := range info.Attributes {
+		attributes[i] = CSRAttribute{OID: a.OID, Name: a.Name, Values: a.Values}
+	}
+
+	return ParseCSRResponse{
+		PEM:     info.PEM,
+		Subject: info.Subject,
+		Fields: CSRSubject{
+			CommonName:         info.Fields.CommonName,
+			Organization:       info.Fields.Organization,
+			OrganizationalUnit: info.Fields.OrganizationalUnit,
+			Locality:           info.Fields.Locality,
+			Province:           info.Fields.Province,
+			Country:            info.Fields.Country,
+		},
+
+		DNSNames:       info.DNSNames,
+		IPAddresses:    info.IPAddresses,
+		EmailAddresses: info.EmailAddresses,
+		URIs:           info.URIs,
+
+		KeyUsage:    info.KeyUsage,
+		ExtKeyUsage: info.ExtKeyUsage,
+
+		PublicKeyAlgorithm: info.PublicKeyAlgorithm,
+		PublicKeySize:      info.PublicKeySize,
+		PublicKeyCurve:     info.PublicKeyCurve,
+		SignatureAlgorithm: info.SignatureAlgorithm,
+		SignatureValid:     info.SignatureValid,
+		SignatureError:     info.SignatureError,
+
+		Extensions:        extensions,
+		Attributes:        attributes,
+		ChallengePassword: info.ChallengePassword,
+	}, nil
+}
+
+func (s *Service) GenerateCSR(req GenerateCSRRequest) (GenerateCSRResponse, error) {
+	res, err := domaincsr.GenerateCSR(domaincsr.GenerateOptions{
+		CommonName:         req.CommonName,
+		Organization:       req.Organization,
+		OrganizationalUnit: req.OrganizationalUnit,
+		Locality:           req.Locality,
+		Province:           req.Province,
+		Country:            req.Country,
+		DNSNames:           req.DNSNames,
+		IPAddresses:        req.IPAddresses,
+		EmailAddresses:     req.EmailAddresses,
+		KeySpec:            req.KeySpec,
+		Passphrase:         req.Passphrase,
+	})
+	if err != nil {
+		return GenerateCSRResponse{}, err
+	}
+
+	return GenerateCSRResponse{
+		CSR:        res.CSRPEM,
+		PrivateKey: res.PrivateKeyPEM,
+		KeySpec:    res.KeySpec,
+		Encrypted:  res.Encrypted,
+	}, nil
 }
@@ -1,12 +1,25 @@
 package json
 
+import "encoding/json"
+
 type FormatRequest struct {
 	JSON   string `json:"json" binding:"required"`
 	Indent int    `json:"indent"`
+	// Repair 宽松解析单引号、未加引号的键、多余的逗号、注释、True / None、NaN 等，并列出所做的修复
+	Repair bool `json:"repair"`
 }
 
 type FormatResponse struct {
 	Formatted string `json:"formatted"`
+	// Repairs 修复模式下所做的修复，输入本来就是合法 JSON 时为空
+	Repairs []JSONRepair `json:"repairs,omitempty"`
+}
+
+type JSONRepair struct {
+	Kind    string `json:"kind"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
 }
 
 type MinifyRequest struct {
@@ -16,3 +29,132 @@ type MinifyRequest struct {
 type MinifyResponse struct {
 	Minified string `json:"minified"`
 }
+
+type QueryRequest struct {
+	JSON  string `json:"json" binding:"required"`
+	Query string `json:"query" binding:"required"`
+	// Language jsonpath / jq，为空时按表达式自动判断（以 $ 开头为 JSONPath）
+	Language string `json:"language"`
+	Indent   int    `json:"indent"`
+	// Compact 每个结果压缩为一行，相当于 jq -c
+	Compact bool `json:"compact"`
+}
+
+type QueryResponse struct {
+	Language string        `json:"language"`
+	Count    int           `json:"count"`
+	Results  []interface{} `json:"results"`
+	// Paths 每个结果的规范化路径，仅 JSONPath 有
+	Paths  []string `json:"paths,omitempty"`
+	Output string   `json:"output"`
+}
+
+type DiffRequest struct {
+	Left  string `json:"left" binding:"required"`
+	Right string `json:"right" binding:"required"`
+	// IgnoreArrayOrder 数组元素顺序不同不算差异
+	IgnoreArrayOrder bool `json:"ignoreArrayOrder"`
+	// IgnoreKeys 忽略的键名（任意层级）或 JSON Pointer（以 / 开头，* 匹配任意一段）
+	IgnoreKeys []string `json:"ignoreKeys"`
+}
+
+type DiffResponse struct {
+	Equal   bool         `json:"equal"`
+	Summary DiffSummary  `json:"summary"`
+	Patch   []PatchOp    `json:"patch"`
+	Changes []DiffChange `json:"changes"`
+	// Text 每处差异一行：+ 新增、- 删除、~ 修改
+	Text string `json:"text"`
+}
+
+type DiffSummary struct {
+	Added   int `json:"added"`
+	Removed int `json:"removed"`
+	Changed int `json:"changed"`
+}
+
+// PatchOp RFC 6902 操作；value 为 null 时也要输出，remove 则不输出
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value"`
+}
+
+func (p PatchOp) MarshalJSON() ([]byte, error) {
+	if p.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{p.Op, p.Path})
+	}
+	type op PatchOp
+	return json.Marshal(op(p))
+}
+
+type DiffChange struct {
+	// Type added / removed / changed
+	Type string `json:"type"`
+	// Path JSON Pointer；删除为左侧文档中的位置，新增和修改为右侧文档中的位置
+	// Old、New 新增时 old 为 null，删除时 new 为 null
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+type ValidateRequest struct {
+	JSON   string `json:"json" binding:"required"`
+	Schema string `json:"schema" binding:"required"`
+	// Draft draft-07 / 2020-12，为空时按 schema 的 $schema 判断，默认 2020-12
+	Draft string `json:"draft"`
+}
+
+type ValidateResponse struct {
+	Valid      bool              `json:"valid"`
+	Draft      string            `json:"draft"`
+	Count      int               `json:"count"`
+	Violations []SchemaViolation `json:"violations"`
+	// Truncated 违规过多时只返回前 1000 条
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type SchemaViolation struct {
+	// Path 文档中的位置（JSON Pointer），根为空字符串
+	Path string `json:"path"`
+	// SchemaPath 触发违规的关键字在 schema 中的位置
+	SchemaPath string `json:"schemaPath"`
+	Keyword    string `json:"keyword"`
+	Message    string `json:"message"`
+	// Line、Column 在原始输入中的行列，从 1 开始
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+type InferRequest struct {
+	// Samples 一个或多个样本文档，每个样本的提取规则与格式化相同
+	Samples []string `json:"samples" binding:"required"`
+	// EnumMaxValues 字符串生成 enum 的最多不同取值，0 为默认值 5，负数不生成
+	EnumMaxValues int `json:"enumMaxValues"`
+	// Draft draft-07 / 2020-12，默认 2020-12
+	Draft  string `json:"draft"`
+	Indent int    `json:"indent"`
+}
+
+type InferResponse struct {
+	Draft   string `json:"draft"`
+	Samples int    `json:"samples"`
+	Schema  string `json:"schema"`
+}
+
+type CodegenRequest struct {
+	JSON string `json:"json" binding:"required"`
+	// Language go / typescript，默认 go
+	Language string `json:"language"`
+	// RootName 根类型名，默认 Root
+	RootName string `json:"rootName"`
+}
+
+type CodegenResponse struct {
+	Language string   `json:"language"`
+	Code     string   `json:"code"`
+	Types    []string `json:"types"`
+}
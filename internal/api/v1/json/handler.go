@@ -1,11 +1,13 @@
 package json
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	httpapi "my-tools/internal/api/http"
+	domainjson "my-tools/internal/domain/json"
 )
 
 func Register(r *gin.RouterGroup) {
@@ -19,6 +21,16 @@ func Register(r *gin.RouterGroup) {
 			return
 		}
 
+		if req.Repair {
+			res, err := svc.RepairJSON(req.JSON, req.Indent)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_json", err.Error()))
+				return
+			}
+			c.JSON(http.StatusOK, httpapi.OK(res))
+			return
+		}
+
 		formatted, err := svc.FormatJSON(req.JSON, req.Indent)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_json", err.Error()))
@@ -47,4 +59,92 @@ func Register(r *gin.RouterGroup) {
 			Minified: minified,
 		}))
 	})
+
+	g.POST("/query", func(c *gin.Context) {
+		var req QueryRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Query(req)
+		if err != nil {
+			code := "invalid_json"
+			if errors.Is(err, domainjson.ErrInvalidQuery) {
+				code = "invalid_query"
+			}
+			c.JSON(http.StatusBadRequest, httpapi.Fail(code, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/diff", func(c *gin.Context) {
+		var req DiffRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Diff(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_json", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/validate", func(c *gin.Context) {
+		var req ValidateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Validate(req)
+		if err != nil {
+			code := "invalid_json"
+			if errors.Is(err, domainjson.ErrInvalidSchema) {
+				code = "invalid_schema"
+			}
+			c.JSON(http.StatusBadRequest, httpapi.Fail(code, err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/infer", func(c *gin.Context) {
+		var req InferRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.InferSchema(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_json", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+
+	g.POST("/codegen", func(c *gin.Context) {
+		var req CodegenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.GenerateCode(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_json", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
 }
@@ -1,6 +1,10 @@
 package json
 
-import domainjson "my-tools/internal/domain/json"
+import (
+	"strings"
+
+	domainjson "my-tools/internal/domain/json"
+)
 
 type Service struct{}
 
@@ -15,6 +19,142 @@ func (s *Service) FormatJSON(input string, indent int) (string, error) {
 	return domainjson.FormatJSON(input, indent)
 }
 
+func (s *Service) RepairJSON(input string, indent int) (FormatResponse, error) {
+	if indent <= 0 {
+		indent = 2
+	}
+	res, err := domainjson.RepairJSON(input, indent)
+	if err != nil {
+		return FormatResponse{}, err
+	}
+	resp := FormatResponse{
+		Formatted: res.JSON,
+		Repairs:   make([]JSONRepair, 0, len(res.Repairs)),
+	}
+	for _, r := range res.Repairs {
+		resp.Repairs = append(resp.Repairs, JSONRepair{
+			Kind:    r.Kind,
+			Line:    r.Line,
+			Column:  r.Column,
+			Message: r.Message,
+		})
+	}
+	return resp, nil
+}
+
 func (s *Service) MinifyJSON(input string) (string, error) {
 	return domainjson.MinifyJSON(input)
 }
+
+func (s *Service) Query(req QueryRequest) (QueryResponse, error) {
+	indent := req.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	if req.Compact {
+		indent = 0
+	}
+	res, err := domainjson.Query(req.JSON, req.Query, strings.ToLower(strings.TrimSpace(req.Language)), indent)
+	if err != nil {
+		return QueryResponse{}, err
+	}
+	return QueryResponse{
+		Language: res.Language,
+		Count:    len(res.Values),
+		Results:  res.Values,
+		Paths:    res.Paths,
+		Output:   res.Output,
+	}, nil
+}
+
+func (s *Service) Diff(req DiffRequest) (DiffResponse, error) {
+	res, err := domainjson.Diff(req.Left, req.Right, domainjson.DiffOptions{
+		IgnoreArrayOrder: req.IgnoreArrayOrder,
+		IgnoreKeys:       req.IgnoreKeys,
+	})
+	if err != nil {
+		return DiffResponse{}, err
+	}
+
+	resp := DiffResponse{
+		Equal:   res.Equal,
+		Patch:   make([]PatchOp, 0, len(res.Patch)),
+		Changes: make([]DiffChange, 0, len(res.Changes)),
+		Text:    domainjson.FormatChanges(res.Changes),
+	}
+	for _, op := range res.Patch {
+		resp.Patch = append(resp.Patch, PatchOp{Op: op.Op, Path: op.Path, Value: op.Value})
+	}
+	for _, c := range res.Changes {
+		switch c.Type {
+		case domainjson.ChangeAdded:
+			resp.Summary.Added++
+		case domainjson.ChangeRemoved:
+			resp.Summary.Removed++
+		default:
+			resp.Summary.Changed++
+		}
+		resp.Changes = append(resp.Changes, DiffChange{Type: c.Type, Path: c.Path, Old: c.Old, New: c.New})
+	}
+	return resp, nil
+}
+
+func (s *Service) Validate(req ValidateRequest) (ValidateResponse, error) {
+	res, err := domainjson.Validate(req.JSON, req.Schema, strings.ToLower(strings.TrimSpace(req.Draft)))
+	if err != nil {
+		return ValidateResponse{}, err
+	}
+
+	resp := ValidateResponse{
+		Valid:      res.Valid,
+		Draft:      res.Draft,
+		Count:      len(res.Violations),
+		Violations: make([]SchemaViolation, 0, len(res.Violations)),
+		Truncated:  res.Truncated,
+	}
+	for _, v := range res.Violations {
+		resp.Violations = append(resp.Violations, SchemaViolation{
+			Path:       v.Path,
+			SchemaPath: v.SchemaPath,
+			Keyword:    v.Keyword,
+			Message:    v.Message,
+			Line:       v.Line,
+			Column:     v.Column,
+		})
+	}
+	return resp, nil
+}
+
+func (s *Service) InferSchema(req InferRequest) (InferResponse, error) {
+	indent := req.Indent
+	if indent <= 0 {
+		indent = 2
+	}
+	res, err := domainjson.InferSchema(req.Samples, domainjson.InferOptions{
+		EnumMaxValues: req.EnumMaxValues,
+		Draft:         strings.ToLower(strings.TrimSpace(req.Draft)),
+	}, indent)
+	if err != nil {
+		return InferResponse{}, err
+	}
+	return InferResponse{
+		Draft:   res.Draft,
+		Samples: res.Samples,
+		Schema:  res.Schema,
+	}, nil
+}
+
+func (s *Service) GenerateCode(req CodegenRequest) (CodegenResponse, error) {
+	res, err := domainjson.GenerateCode(req.JSON, domainjson.CodegenOptions{
+		Language: strings.ToLower(strings.TrimSpace(req.Language)),
+		RootName: req.RootName,
+	})
+	if err != nil {
+		return CodegenResponse{}, err
+	}
+	return CodegenResponse{
+		Language: res.Language,
+		Code:     res.Code,
+		Types:    res.Types,
+	}, nil
+}
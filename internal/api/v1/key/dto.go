@@ -0,0 +1,29 @@
+package key
+
+type InspectRequest struct {
+	Key        string `json:"key" binding:"required"`
+	Passphrase string `json:"passphrase"`
+	// Target 转换目标：pkcs1 / pkcs8 / sec1 / openssh / public / ssh-public / jwk，为空时只解析
+	Target string `json:"target"`
+	// OutputPassphrase 输出 PKCS#8 时用于加密私钥
+	OutputPassphrase string `json:"outputPassphrase"`
+}
+
+type InspectResponse struct {
+	Type      string `json:"type"`
+	Format    string `json:"format"`
+	Encrypted bool   `json:"encrypted"`
+	Locked    bool   `json:"locked"`
+
+	Algorithm string `json:"algorithm,omitempty"`
+	Size      int    `json:"size,omitempty"`
+	Curve     string `json:"curve,omitempty"`
+
+	PublicKeySHA256 string `json:"publicKeySha256,omitempty"`
+	SSHFingerprint  string `json:"sshFingerprint,omitempty"`
+	PublicKeyPEM    string `json:"publicKeyPem,omitempty"`
+	SSHPublicKey    string `json:"sshPublicKey,omitempty"`
+
+	OutputFormat string `json:"outputFormat,omitempty"`
+	Output       string `json:"output,omitempty"`
+}
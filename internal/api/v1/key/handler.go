@@ -0,0 +1,29 @@
+package key
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpapi "my-tools/internal/api/http"
+)
+
+func Register(r *gin.RouterGroup) {
+	svc := NewService()
+	g := r.Group("/key")
+	g.POST("/inspect", func(c *gin.Context) {
+		var req InspectRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res, err := svc.Inspect(req)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_key", err.Error()))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+}
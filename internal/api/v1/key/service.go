@@ -0,0 +1,39 @@
+package key
+
+import domainkey "my-tools/internal/domain/key"
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Inspect(req InspectRequest) (InspectResponse, error) {
+	info, err := domainkey.Inspect(req.Key, domainkey.InspectOptions{
+		Passphrase:       req.Passphrase,
+		Target:           req.Target,
+		OutputPassphrase: req.OutputPassphrase,
+	})
+	if err != nil {
+		return InspectResponse{}, err
+	}
+
+	return InspectResponse{
+		Type:      info.Type,
+		Format:    info.Format,
+		Encrypted: info.Encrypted,
+		Locked:    info.Locked,
+
+		Algorithm: info.Algorithm,
+		Size:      info.Size,
+		Curve:     info.Curve,
+
+		PublicKeySHA256: info.PublicKeySHA256,
+		SSHFingerprint:  info.SSHFingerprint,
+		PublicKeyPEM:    info.PublicKeyPEM,
+		SSHPublicKey:    info.SSHPublicKey,
+
+		OutputFormat: info.OutputFormat,
+		Output:       info.Output,
+	}, nil
+}
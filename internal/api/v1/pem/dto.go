@@ -0,0 +1,34 @@
+package pem
+
+type ScanRequest struct {
+	Input string `json:"input" binding:"required"`
+}
+
+type Block struct {
+	Type    string            `json:"type"`
+	Headers map[string]string `json:"headers,omitempty"`
+	// Line / Column BEGIN 行在输入中的位置，Offset / End 为字节范围
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Offset  int    `json:"offset"`
+	End     int    `json:"end"`
+	Size    int    `json:"size"`
+	Summary string `json:"summary,omitempty"`
+	PEM     string `json:"pem"`
+	Error   string `json:"error,omitempty"`
+}
+
+type Warning struct {
+	Kind    string `json:"kind"`
+	Type    string `json:"type"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Offset  int    `json:"offset"`
+	Message string `json:"message"`
+}
+
+type ScanResponse struct {
+	Count    int       `json:"count"`
+	Blocks   []Block   `json:"blocks"`
+	Warnings []Warning `json:"warnings"`
+}
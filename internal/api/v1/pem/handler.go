@@ -0,0 +1,29 @@
+package pem
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	httpapi "my-tools/internal/api/http"
+)
+
+func Register(r *gin.RouterGroup) {
+	svc := NewService()
+	g := r.Group("/pem")
+	g.POST("/scan", func(c *gin.Context) {
+		var req ScanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("bad_request", err.Error()))
+			return
+		}
+
+		res := svc.Scan(req)
+		if res.Count == 0 && len(res.Warnings) == 0 {
+			c.JSON(http.StatusBadRequest, httpapi.Fail("invalid_input", "no PEM block found"))
+			return
+		}
+
+		c.JSON(http.StatusOK, httpapi.OK(res))
+	})
+}
@@ -0,0 +1,44 @@
+package pem
+
+import "my-tools/internal/domain/pemscan"
+
+type Service struct{}
+
+func NewService() *Service {
+	return &Service{}
+}
+
+func (s *Service) Scan(req ScanRequest) ScanResponse {
+	res := pemscan.Scan(req.Input)
+
+	resp := ScanResponse{
+		Count:    len(res.Blocks),
+		Blocks:   make([]Block, len(res.Blocks)),
+		Warnings: make([]Warning, len(res.Warnings)),
+	}
+	for i, b := range res.Blocks {
+		resp.Blocks[i] = Block{
+			Type:    b.Type,
+			Headers: b.Headers,
+			Line:    b.Line,
+			Column:  b.Column,
+			Offset:  b.Offset,
+			End:     b.End,
+			Size:    len(b.Bytes),
+			Summary: pemscan.Describe(b),
+			PEM:     b.PEM,
+			Error:   b.Error,
+		}
+	}
+	for i, w := range res.Warnings {
+		resp.Warnings[i] = Warning{
+			Kind:    w.Kind,
+			Type:    w.Type,
+			Line:    w.Line,
+			Column:  w.Column,
+			Offset:  w.Offset,
+			Message: w.Message,
+		}
+	}
+	return resp
+}
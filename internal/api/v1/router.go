@@ -3,15 +3,25 @@ package v1
 import (
 	"github.com/gin-gonic/gin"
 
+	"my-tools/internal/api/v1/asn1"
+	"my-tools/internal/api/v1/ca"
 	"my-tools/internal/api/v1/cert"
+	"my-tools/internal/api/v1/convert"
 	"my-tools/internal/api/v1/csr"
 	"my-tools/internal/api/v1/json"
+	"my-tools/internal/api/v1/key"
+	"my-tools/internal/api/v1/pem"
 	"my-tools/internal/api/v1/sectigo"
 )
 
 func Register(r *gin.RouterGroup) {
 	csr.Register(r)
 	cert.Register(r)
+	ca.Register(r)
 	json.Register(r)
+	key.Register(r)
+	pem.Register(r)
+	asn1.Register(r)
 	sectigo.Register(r)
+	convert.Register(r)
 }
@@ -0,0 +1,496 @@
+package asn1dump
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// 标签类别
+const (
+	ClassUniversal   = "universal"
+	ClassApplication = "application"
+	ClassContext     = "context"
+	ClassPrivate     = "private"
+)
+
+const (
+	// maxDepth / maxNodes 防止恶意输入导致深度递归或输出过大
+	maxDepth = 64
+	maxNodes = 20000
+	// maxHexBytes 节点 Hex 字段最多展示的字节数
+	maxHexBytes = 256
+)
+
+var classNames = [4]string{ClassUniversal, ClassApplication, ClassContext, ClassPrivate}
+
+// universalTypes UNIVERSAL 类标签名称，与 openssl asn1parse 一致
+var universalTypes = map[int]string{
+	0:  "EOC",
+	1:  "BOOLEAN",
+	2:  "INTEGER",
+	3:  "BIT STRING",
+	4:  "OCTET STRING",
+	5:  "NULL",
+	6:  "OBJECT",
+	7:  "OBJECT DESCRIPTOR",
+	8:  "EXTERNAL",
+	9:  "REAL",
+	10: "ENUMERATED",
+	12: "UTF8STRING",
+	13: "RELATIVE-OID",
+	16: "SEQUENCE",
+	17: "SET",
+	18: "NUMERICSTRING",
+	19: "PRINTABLESTRING",
+	20: "T61STRING",
+	21: "VIDEOTEXSTRING",
+	22: "IA5STRING",
+	23: "UTCTIME",
+	24: "GENERALIZEDTIME",
+	25: "GRAPHICSTRING",
+	26: "VISIBLESTRING",
+	27: "GENERALSTRING",
+	28: "UNIVERSALSTRING",
+	30: "BMPSTRING",
+}
+
+// Node ASN.1 树中的一个元素
+type Node struct {
+	// Offset 标签在数据中的字节位置，HeaderLength 为标签 + 长度字段的字节数
+	Offset       int `json:"offset"`
+	HeaderLength int `json:"headerLength"`
+	// Length 内容长度；不定长编码时为实际内容长度（含结束标记）
+	Length     int  `json:"length"`
+	Indefinite bool `json:"indefinite,omitempty"`
+	Depth      int  `json:"depth"`
+
+	Class       string `json:"class"`
+	Tag         int    `json:"tag"`
+	Constructed bool   `json:"constructed"`
+	// Type 类型名称，如 SEQUENCE、INTEGER、[0]、[APPLICATION 1]
+	Type string `json:"type"`
+
+	// Value 解码后的值：字符串、整数、OID、时间原文等
+	Value   string `json:"value,omitempty"`
+	OIDName string `json:"oidName,omitempty"`
+	// Time UTCTIME / GENERALIZEDTIME 转换为 RFC 3339（UTC）
+	Time string `json:"time,omitempty"`
+	// Hex 原始内容的十六进制，超过 maxHexBytes 时截断
+	Hex       string `json:"hex,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+
+	// Encapsulated OCTET STRING / BIT STRING 的内容本身是 DER，已展开到 Children
+	Encapsulated bool    `json:"encapsulated,omitempty"`
+	Children     []*Node `json:"children,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// Parse 解析 DER（兼容 BER 不定长编码），返回顶层元素。
+// 数据有误时仍返回已解析的部分，出错的节点带 Error，error 为第一个错误
+func Parse(data []byte) ([]*Node, error) {
+	p := &parser{}
+	nodes, _, _, err := p.parse(data, 0, 0, false)
+	return nodes, err
+}
+
+type parser struct {
+	count int
+}
+
+type header struct {
+	class       int
+	constructed bool
+	tag         int
+	length      int
+	indefinite  bool
+	size        int
+}
+
+// parse 依次解析 data 中的元素；untilEOC 为真时遇到结束标记（00 00）即返回，found 表示是否找到
+func (p *parser) parse(data []byte, base, depth int, untilEOC bool) (nodes []*Node, used int, found bool, err error) {
+	for used < len(data) {
+		if untilEOC && used+1 < len(data) && data[used] == 0 && data[used+1] == 0 {
+			return nodes, used + 2, true, nil
+		}
+		n, size, err := p.parseElement(data[used:], base+used, depth)
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+		used += size
+		if err != nil {
+			return nodes, used, false, err
+		}
+	}
+	return nodes, used, false, nil
+}
+
+func (p *parser) parseElement(data []byte, off, depth int) (*Node, int, error) {
+	p.count++
+	if p.count > maxNodes {
+		return nil, len(data), fmt.Errorf("offset %d: too many elements (max %d)", off, maxNodes)
+	}
+
+	n := &Node{Offset: off, Depth: depth}
+	fail := func(size int, msg string) (*Node, int, error) {
+		n.Error = msg
+		return n, size, fmt.Errorf("offset %d: %s", off, msg)
+	}
+
+	h, err := readHeader(data)
+	if err != nil {
+		n.Type = "?"
+		return fail(len(data), err.Error())
+	}
+	n.HeaderLength, n.Class, n.Tag, n.Constructed = h.size, classNames[h.class], h.tag, h.constructed
+	n.Type = typeName(h.class, h.tag)
+
+	if h.constructed && depth >= maxDepth {
+		return fail(len(data), fmt.Sprintf("nesting is too deep (max %d)", maxDepth))
+	}
+
+	if h.indefinite {
+		n.Indefinite = true
+		if !h.constructed {
+			return fail(len(data), "indefinite length on a primitive element")
+		}
+		children, used, found, err := p.parse(data[h.size:], off+h.size, depth+1, true)
+		n.Children, n.Length = children, used
+		if err != nil {
+			return n, h.size + used, err
+		}
+		if !found {
+			return fail(h.size+used, "missing end-of-contents for indefinite length")
+		}
+		return n, h.size + used, nil
+	}
+
+	n.Length = h.length
+	content := data[h.size:]
+	if h.length > len(content) {
+		// 数据被截断：构造类型尽量解析剩余部分，便于看出断在哪里
+		if h.constructed {
+			n.Children, _, _, _ = p.parse(content, off+h.size, depth+1, false)
+		} else {
+			n.Hex, n.Truncated = hexPrefix(content)
+		}
+		return fail(len(data), fmt.Sprintf("length %d exceeds the remaining %d bytes", h.length, len(content)))
+	}
+	content = content[:h.length]
+	size := h.size + h.length
+
+	if h.constructed {
+		children, _, _, err := p.parse(content, off+h.size, depth+1, false)
+		n.Children = children
+		return n, size, err
+	}
+	if msg := p.decodePrimitive(n, h, content); msg != "" {
+		return fail(size, msg)
+	}
+	return n, size, nil
+}
+
+// readHeader 解析标签和长度字段
+func readHeader(b []byte) (header, error) {
+	var h header
+	if len(b) < 2 {
+		return h, errors.New("truncated header")
+	}
+	h.class = int(b[0] >> 6)
+	h.constructed = b[0]&0x20 != 0
+	h.tag = int(b[0] & 0x1f)
+	i := 1
+	if h.tag == 0x1f {
+		// 高位标签号：base-128，最高位为 1 表示后面还有
+		h.tag = 0
+		for {
+			if i >= len(b) {
+				return h, errors.New("truncated tag")
+			}
+			if i > 4 {
+				return h, errors.New("tag number is too large")
+			}
+			c := b[i]
+			i++
+			h.tag = h.tag<<7 | int(c&0x7f)
+			if c&0x80 == 0 {
+				break
+			}
+		}
+	}
+	if i >= len(b) {
+		return h, errors.New("truncated length")
+	}
+	l := b[i]
+	i++
+	switch {
+	case l < 0x80:
+		h.length = int(l)
+	case l == 0x80:
+		h.indefinite = true
+	default:
+		n := int(l & 0x7f)
+		if n > 4 {
+			return h, fmt.Errorf("length field of %d bytes is not supported", n)
+		}
+		if i+n > len(b) {
+			return h, errors.New("truncated length")
+		}
+		for j := 0; j < n; j++ {
+			h.length = h.length<<8 | int(b[i+j])
+		}
+		if h.length < 0 {
+			return h, errors.New("length is too large")
+		}
+		i += n
+	}
+	h.size = i
+	return h, nil
+}
+
+func typeName(class, tag int) string {
+	switch class {
+	case 0:
+		if name, ok := universalTypes[tag]; ok {
+			return name
+		}
+		return fmt.Sprintf("[UNIVERSAL %d]", tag)
+	case 1:
+		return fmt.Sprintf("[APPLICATION %d]", tag)
+	case 2:
+		return fmt.Sprintf("[%d]", tag)
+	}
+	return fmt.Sprintf("[PRIVATE %d]", tag)
+}
+
+// decodePrimitive 按类型解码基本元素的内容，返回错误信息
+func (p *parser) decodePrimitive(n *Node, h header, content []byte) string {
+	n.Hex, n.Truncated = hexPrefix(content)
+	if h.class != 0 {
+		// 上下文标签的含义取决于外层结构，只能猜测：可打印的按字符串显示（如 SAN 中的 dNSName、URI）
+		if isPrintable(content) {
+			n.Value = string(content)
+		}
+		return ""
+	}
+
+	switch h.tag {
+	case 1:
+		if len(content) != 1 {
+			return "invalid BOOLEAN length"
+		}
+		n.Value, n.Hex = "FALSE", ""
+		if content[0] != 0 {
+			n.Value = "TRUE"
+		}
+	case 2, 10:
+		if len(content) == 0 {
+			return "empty INTEGER"
+		}
+		n.Value, n.Hex, n.Truncated = formatInteger(content), "", false
+	case 3:
+		if len(content) == 0 {
+			return "empty BIT STRING"
+		}
+		n.Value = fmt.Sprintf("unused bits: %d", content[0])
+		n.Hex, n.Truncated = hexPrefix(content[1:])
+		if content[0] == 0 {
+			p.encapsulate(n, content[1:], n.Offset+n.HeaderLength+1)
+		}
+	case 4:
+		p.encapsulate(n, content, n.Offset+n.HeaderLength)
+	case 5:
+		if len(content) != 0 {
+			return "NULL with content"
+		}
+	case 6:
+		oid, err := parseOID(content)
+		if err != nil {
+			return err.Error()
+		}
+		n.Value, n.OIDName, n.Hex = oid, oidNames[oid], ""
+	case 23, 24:
+		n.Value, n.Hex = string(content), ""
+		if t, err := parseTime(h.tag, string(content)); err == nil {
+			n.Time = t.UTC().Format(time.RFC3339)
+		}
+	case 30:
+		if len(content)%2 != 0 {
+			return "invalid BMPSTRING length"
+		}
+		u := make([]uint16, len(content)/2)
+		for i := range u {
+			u[i] = uint16(content[2*i])<<8 | uint16(content[2*i+1])
+		}
+		n.Value, n.Hex = string(utf16.Decode(u)), ""
+	case 28:
+		if len(content)%4 != 0 {
+			return "invalid UNIVERSALSTRING length"
+		}
+		var b strings.Builder
+		for i := 0; i < len(content); i += 4 {
+			b.WriteRune(rune(uint32(content[i])<<24 | uint32(content[i+1])<<16 | uint32(content[i+2])<<8 | uint32(content[i+3])))
+		}
+		n.Value, n.Hex = b.String(), ""
+	case 12, 18, 19, 20, 21, 22, 25, 26, 27:
+		n.Value, n.Hex = decodeString(content), ""
+	}
+	return ""
+}
+
+// encapsulate 内容能完整解析为 DER 时展开为子节点；随机字节恰好能解析的概率很低，
+// 只接受 UNIVERSAL 类开头且没有任何错误的内容
+func (p *parser) encapsulate(n *Node, content []byte, off int) {
+	if len(content) < 2 || content[0]&0xc0 != 0 || n.Depth >= maxDepth {
+		return
+	}
+	sub := &parser{count: p.count}
+	children, used, _, err := sub.parse(content, off, n.Depth+1, false)
+	if err != nil || used != len(content) {
+		return
+	}
+	p.count = sub.count
+	n.Children, n.Encapsulated = children, true
+}
+
+// formatInteger 8 字节以内按十进制（含负数），更长的（序列号、RSA 模数）按十六进制
+func formatInteger(b []byte) string {
+	if len(b) > 8 {
+		return "0x" + strings.ToUpper(hex.EncodeToString(b))
+	}
+	v := new(big.Int).SetBytes(b)
+	if b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+	return v.String()
+}
+
+func parseOID(b []byte) (string, error) {
+	if len(b) == 0 {
+		return "", errors.New("empty OBJECT")
+	}
+	var arcs []string
+	var v uint64
+	for i, c := range b {
+		if v > 1<<56 {
+			return "", errors.New("OBJECT arc is too large")
+		}
+		v = v<<7 | uint64(c&0x7f)
+		if c&0x80 != 0 {
+			if i == len(b)-1 {
+				return "", errors.New("truncated OBJECT")
+			}
+			continue
+		}
+		if arcs == nil {
+			// 第一个子标识同时编码了前两段
+			switch {
+			case v < 40:
+				arcs = []string{"0", strconv.FormatUint(v, 10)}
+			case v < 80:
+				arcs = []string{"1", strconv.FormatUint(v-40, 10)}
+			default:
+				arcs = []string{"2", strconv.FormatUint(v-80, 10)}
+			}
+		} else {
+			arcs = append(arcs, strconv.FormatUint(v, 10))
+		}
+		v = 0
+	}
+	return strings.Join(arcs, "."), nil
+}
+
+func parseTime(tag int, s string) (time.Time, error) {
+	layouts := []string{"20060102150405Z0700", "20060102150405.999999999Z0700", "200601021504Z0700"}
+	if tag == 23 {
+		layouts = []string{"060102150405Z0700", "0601021504Z0700"}
+	}
+	var err error
+	for _, layout := range layouts {
+		var t time.Time
+		if t, err = time.Parse(layout, s); err == nil {
+			// RFC 5280：UTCTime 的 50-99 表示 19xx
+			if tag == 23 && t.Year() >= 2050 {
+				t = t.AddDate(-100, 0, 0)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// decodeString 非 UTF-8 的内容（如 T61STRING）按 Latin-1 解码
+func decodeString(b []byte) string {
+	if utf8.Valid(b) {
+		return string(b)
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+	}
+	return string(r)
+}
+
+func isPrintable(b []byte) bool {
+	if len(b) == 0 {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+func hexPrefix(b []byte) (string, bool) {
+	if len(b) > maxHexBytes {
+		return strings.ToUpper(hex.EncodeToString(b[:maxHexBytes])), true
+	}
+	return strings.ToUpper(hex.EncodeToString(b)), false
+}
+
+// Text 按 openssl asn1parse 的格式输出，便于对照
+func Text(nodes []*Node) string {
+	var b strings.Builder
+	var walk func([]*Node)
+	walk = func(nodes []*Node) {
+		for _, n := range nodes {
+			length := strconv.Itoa(n.Length)
+			if n.Indefinite {
+				length = "inf"
+			}
+			kind := "prim"
+			if n.Constructed {
+				kind = "cons"
+			}
+			fmt.Fprintf(&b, "%5d:d=%-2d hl=%d l=%4s %s: %s%-18s", n.Offset, n.Depth, n.HeaderLength, length, kind, strings.Repeat(" ", n.Depth), n.Type)
+			switch {
+			case n.OIDName != "":
+				b.WriteString(":" + n.OIDName)
+			case n.Value != "":
+				b.WriteString(":" + n.Value)
+			}
+			// BIT STRING 的 Value 只是未用位数，内容本身仍需展示
+			if n.Hex != "" && !n.Encapsulated && (n.Value == "" || n.Type == "BIT STRING") {
+				b.WriteString("[HEX DUMP]:" + n.Hex)
+				if n.Truncated {
+					b.WriteString("...")
+				}
+			}
+			if n.Error != "" {
+				b.WriteString("  <error: " + n.Error + ">")
+			}
+			b.WriteString("\n")
+			walk(n.Children)
+		}
+	}
+	walk(nodes)
+	return b.String()
+}
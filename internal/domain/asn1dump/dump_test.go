@@ -0,0 +1,274 @@
+package asn1dump
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertDER(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(4660),
+		Subject:      pkix.Name{CommonName: "asn1.example.test", Organization: []string{"Example"}},
+		NotBefore:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		NotAfter:     time.Date(2055, 1, 2, 3, 4, 5, 0, time.UTC),
+		DNSNames:     []string{"asn1.example.test"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return der
+}
+
+// find 深度优先查找第一个满足条件的节点
+func find(nodes []*Node, match func(*Node) bool) *Node {
+	for _, n := range nodes {
+		if match(n) {
+			return n
+		}
+		if got := find(n.Children, match); got != nil {
+			return got
+		}
+	}
+	return nil
+}
+
+func TestParseCertificate(t *testing.T) {
+	der := newTestCertDER(t)
+	nodes, err := Parse(der)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Type != "SEQUENCE" || nodes[0].HeaderLength+nodes[0].Length != len(der) {
+		t.Fatalf("top level = %+v", nodes)
+	}
+
+	tbs := nodes[0].Children[0]
+	if v := tbs.Children[0]; v.Type != "[0]" || v.Class != ClassContext || !v.Constructed || v.Children[0].Value != "2" {
+		t.Errorf("version = %+v", v)
+	}
+	if serial := tbs.Children[1]; serial.Type != "INTEGER" || serial.Value != "4660" {
+		t.Errorf("serial = %+v", serial)
+	}
+
+	oids := map[string]string{}
+	find(nodes, func(n *Node) bool {
+		if n.Type == "OBJECT" {
+			oids[n.Value] = n.OIDName
+		}
+		return false
+	})
+	for oid, name := range map[string]string{
+		"1.2.840.10045.4.3.2": "ecdsa-with-SHA256",
+		"2.5.4.3":             "commonName",
+		"1.2.840.10045.3.1.7": "prime256v1",
+		"2.5.29.17":           "subjectAltName",
+	} {
+		if oids[oid] != name {
+			t.Errorf("OID %s name = %q, want %q", oid, oids[oid], name)
+		}
+	}
+
+	cn := find(nodes, func(n *Node) bool { return n.Value == "asn1.example.test" && n.Class == ClassUniversal })
+	if cn == nil || (cn.Type != "UTF8STRING" && cn.Type != "PRINTABLESTRING") {
+		t.Errorf("commonName value = %+v", cn)
+	}
+
+	times := []*Node{}
+	find(nodes, func(n *Node) bool {
+		if n.Type == "UTCTIME" || n.Type == "GENERALIZEDTIME" {
+			times = append(times, n)
+		}
+		return false
+	})
+	if len(times) != 2 || times[0].Time != "2024-01-02T03:04:05Z" || times[1].Time != "2055-01-02T03:04:05Z" {
+		t.Errorf("times = %+v", times)
+	}
+
+	// SAN 扩展值是 OCTET STRING 包着的 DER，dNSName 为上下文标签 [2]
+	san := find(nodes, func(n *Node) bool { return n.Type == "[2]" })
+	if san == nil || san.Value != "asn1.example.test" {
+		t.Errorf("SAN dNSName = %+v", san)
+	}
+	octet := find(nodes, func(n *Node) bool { return n.Type == "OCTET STRING" })
+	if octet == nil || !octet.Encapsulated || len(octet.Children) != 1 || octet.Children[0].Offset != octet.Offset+octet.HeaderLength {
+		t.Errorf("encapsulated OCTET STRING = %+v", octet)
+	}
+	// 公钥 BIT STRING 内容以 04 开头，不是合法 DER，不应展开
+	bits := find(nodes, func(n *Node) bool { return n.Type == "BIT STRING" })
+	if bits == nil || bits.Encapsulated || bits.Value != "unused bits: 0" || !strings.HasPrefix(bits.Hex, "04") {
+		t.Errorf("public key BIT STRING = %+v", bits)
+	}
+
+	text := Text(nodes)
+	for _, want := range []string{
+		"    0:d=0  hl=4 l=",
+		"OBJECT            :ecdsa-with-SHA256",
+		"INTEGER           :4660",
+		"BIT STRING        :unused bits: 0[HEX DUMP]:04",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Text() missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	tests := []struct {
+		name string
+		hex  string
+		typ  string
+		want string
+	}{
+		{"boolean", "0101ff", "BOOLEAN", "TRUE"},
+		{"negative integer", "0202ff7f", "INTEGER", "-129"},
+		{"big integer", "020900ffffffffffffffff", "INTEGER", "0x00FFFFFFFFFFFFFFFF"},
+		{"enumerated", "0a0101", "ENUMERATED", "1"},
+		{"oid", "06092a864886f70d010101", "OBJECT", "1.2.840.113549.1.1.1"},
+		{"bmp string", "1e0400e4004b", "BMPSTRING", "äK"},
+		{"t61 latin1", "1402e9e8", "T61STRING", "éè"},
+		{"high tag number", "9f81000141", "[128]", "A"},
+		{"application", "4100", "[APPLICATION 1]", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.hex)
+			nodes, err := Parse(data)
+			if err != nil || len(nodes) != 1 {
+				t.Fatalf("Parse() = %+v, %v", nodes, err)
+			}
+			if nodes[0].Type != tt.typ || nodes[0].Value != tt.want {
+				t.Errorf("node = %s %q, want %s %q", nodes[0].Type, nodes[0].Value, tt.typ, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Run("indefinite length", func(t *testing.T) {
+		data, _ := hex.DecodeString("30800201010000")
+		nodes, err := Parse(data)
+		if err != nil || len(nodes) != 1 || !nodes[0].Indefinite || len(nodes[0].Children) != 1 || nodes[0].Length != 5 {
+			t.Fatalf("Parse() = %+v, %v", nodes, err)
+		}
+		if !strings.Contains(Text(nodes), "l= inf cons") {
+			t.Errorf("Text() = %q", Text(nodes))
+		}
+	})
+
+	t.Run("truncated keeps parsed part", func(t *testing.T) {
+		der := newTestCertDER(t)
+		nodes, err := Parse(der[:len(der)-10])
+		if err == nil || !strings.Contains(err.Error(), "exceeds the remaining") {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if len(nodes) != 1 || nodes[0].Error == "" || len(nodes[0].Children) == 0 || nodes[0].Children[0].Type != "SEQUENCE" {
+			t.Errorf("nodes = %+v", nodes)
+		}
+	})
+
+	t.Run("inner error", func(t *testing.T) {
+		data, _ := hex.DecodeString("3006020101010200")
+		nodes, err := Parse(data)
+		if err == nil || !strings.HasPrefix(err.Error(), "offset 5:") {
+			t.Fatalf("Parse() error = %v", err)
+		}
+		if bad := find(nodes, func(n *Node) bool { return n.Error != "" }); bad == nil || bad.Type != "BOOLEAN" {
+			t.Errorf("nodes = %+v", nodes)
+		}
+	})
+
+	t.Run("too deep", func(t *testing.T) {
+		data := []byte{}
+		for i := 0; i < maxDepth+2; i++ {
+			data = append([]byte{0x30, 0x80}, append(data, 0, 0)...)
+		}
+		if _, err := Parse(data); err == nil || !strings.Contains(err.Error(), "too deep") {
+			t.Errorf("Parse() error = %v", err)
+		}
+	})
+}
+
+func TestDumpInputFormats(t *testing.T) {
+	der := newTestCertDER(t)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	hexStr := hex.EncodeToString(der)
+
+	tests := []struct {
+		name   string
+		in     string
+		opts   Options
+		format string
+	}{
+		{"pem", certPEM, Options{}, FormatPEM},
+		{"second pem block", "-----BEGIN X-----\nMAA=\n-----END X-----\n" + certPEM, Options{Block: 1}, FormatPEM},
+		{"hex with colons", "0x" + strings.Join(splitEvery(hexStr, 2), ":"), Options{}, FormatHex},
+		{"hex with spaces", strings.Join(splitEvery(strings.ToUpper(hexStr), 32), "\n"), Options{}, FormatHex},
+		{"base64", base64.StdEncoding.EncodeToString(der), Options{}, FormatBase64},
+		{"base64url", base64.RawURLEncoding.EncodeToString(der), Options{}, FormatBase64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := Dump(tt.in, tt.opts)
+			if err != nil {
+				t.Fatalf("Dump() error = %v", err)
+			}
+			if res.Format != tt.format || res.Length != len(der) || res.Error != "" || len(res.Nodes) != 1 {
+				t.Errorf("Dump() = format %s length %d error %q", res.Format, res.Length, res.Error)
+			}
+		})
+	}
+
+	res, err := Dump(certPEM, Options{})
+	if err != nil || res.PEMType != "CERTIFICATE" || res.PEMBlocks != 1 || !strings.HasPrefix(res.Text, "    0:d=0") {
+		t.Errorf("Dump(pem) = %+v, %v", res, err)
+	}
+
+	for name, in := range map[string]string{
+		"empty":         "  ",
+		"garbage":       "not asn1 at all!",
+		"not der":       "zzz",
+		"block range":   certPEM,
+		"bad pem body":  "-----BEGIN CERTIFICATE-----\nMIIBx\n-----END CERTIFICATE-----",
+		"no pem blocks": "-----BEGIN CERTIFICATE-----\nMIIB",
+	} {
+		opts := Options{}
+		if name == "block range" {
+			opts.Block = 3
+		}
+		if _, err := Dump(in, opts); err == nil {
+			t.Errorf("Dump(%s) error = nil", name)
+		}
+	}
+
+	// 截断的数据仍返回已解析部分
+	res, err = Dump(hexStr[:len(hexStr)-20], Options{})
+	if err != nil || res.Error == "" || len(res.Nodes) != 1 {
+		t.Errorf("Dump(truncated) = %+v, %v", res, err)
+	}
+}
+
+func splitEvery(s string, n int) []string {
+	var out []string
+	for len(s) > n {
+		out = append(out, s[:n])
+		s = s[n:]
+	}
+	return append(out, s)
+}
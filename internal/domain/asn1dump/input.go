@@ -0,0 +1,132 @@
+package asn1dump
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"my-tools/internal/domain/pemscan"
+)
+
+// MaxInputSize 解码后 DER 的最大字节数
+const MaxInputSize = 1 << 20
+
+// 输入格式
+const (
+	FormatPEM    = "pem"
+	FormatHex    = "hex"
+	FormatBase64 = "base64"
+)
+
+// Options 解析选项
+type Options struct {
+	// Block 输入包含多个 PEM 块时选择第几个（从 0 开始）
+	Block int
+}
+
+// Result ASN.1 解析结果
+type Result struct {
+	Format string
+	// PEMType / PEMBlocks 仅 PEM 输入时有值
+	PEMType   string
+	PEMBlocks int
+	Length    int
+	Nodes     []*Node
+	Text      string
+	// Error 解析过程中的第一个错误；已解析的部分仍在 Nodes 中
+	Error string
+}
+
+// Dump 自动识别 PEM / 十六进制 / base64 输入并解析 ASN.1 结构。
+// 仅在输入无法解码或一个元素都解析不出来时返回 error
+func Dump(input string, opts Options) (Result, error) {
+	var res Result
+	data, err := decodeInput(input, opts, &res)
+	if err != nil {
+		return res, err
+	}
+	if len(data) > MaxInputSize {
+		return res, fmt.Errorf("input is too large: %d bytes (max %d)", len(data), MaxInputSize)
+	}
+	res.Length = len(data)
+
+	nodes, err := Parse(data)
+	// 第一个元素就无法解析，基本可以确定输入不是 DER
+	if len(nodes) == 0 || (len(nodes) == 1 && nodes[0].Error != "" && len(nodes[0].Children) == 0) {
+		if err == nil {
+			err = errors.New("no ASN.1 element found")
+		}
+		return res, fmt.Errorf("input is not valid DER: %v", err)
+	}
+	res.Nodes, res.Text = nodes, Text(nodes)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+func decodeInput(input string, opts Options, res *Result) ([]byte, error) {
+	in := strings.TrimSpace(input)
+	if in == "" {
+		return nil, errors.New("input is empty")
+	}
+
+	if strings.Contains(in, "BEGIN ") {
+		res.Format = FormatPEM
+		scan := pemscan.Scan(in)
+		res.PEMBlocks = len(scan.Blocks)
+		if len(scan.Blocks) == 0 {
+			if len(scan.Warnings) > 0 {
+				return nil, errors.New(scan.Warnings[0].String())
+			}
+			return nil, errors.New("no PEM block found")
+		}
+		if opts.Block < 0 || opts.Block >= len(scan.Blocks) {
+			return nil, fmt.Errorf("block %d out of range, input contains %d PEM blocks", opts.Block, len(scan.Blocks))
+		}
+		b := scan.Blocks[opts.Block]
+		res.PEMType = b.Type
+		if b.Error != "" {
+			return nil, fmt.Errorf("PEM block at line %d: %s", b.Line, b.Error)
+		}
+		return b.Bytes, nil
+	}
+
+	if data, ok := decodeHex(in); ok {
+		res.Format = FormatHex
+		return data, nil
+	}
+
+	res.Format = FormatBase64
+	s := strings.TrimRight(strings.Join(strings.Fields(in), ""), "=")
+	enc := base64.RawStdEncoding
+	if strings.ContainsAny(s, "-_") {
+		enc = base64.RawURLEncoding
+	}
+	data, err := enc.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("input is not PEM, hex or base64: %v", err)
+	}
+	return data, nil
+}
+
+// decodeHex 支持 "30 82 01 0a"、"30:82:01:0a"、"0x3082..." 等写法
+func decodeHex(in string) ([]byte, bool) {
+	s := strings.TrimPrefix(strings.TrimPrefix(in, "0x"), "0X")
+	s = strings.Map(func(r rune) rune {
+		if r == ':' || r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" || len(s)%2 != 0 {
+		return nil, false
+	}
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
@@ -0,0 +1,178 @@
+package asn1dump
+
+// oidNames 内置的 OID 名称表，名称与 openssl 的短名 / 长名保持一致，便于对照 openssl asn1parse 的输出
+var oidNames = map[string]string{
+	// X.500 属性
+	"2.5.4.3":                    "commonName",
+	"2.5.4.4":                    "surname",
+	"2.5.4.5":                    "serialNumber",
+	"2.5.4.6":                    "countryName",
+	"2.5.4.7":                    "localityName",
+	"2.5.4.8":                    "stateOrProvinceName",
+	"2.5.4.9":                    "streetAddress",
+	"2.5.4.10":                   "organizationName",
+	"2.5.4.11":                   "organizationalUnitName",
+	"2.5.4.12":                   "title",
+	"2.5.4.15":                   "businessCategory",
+	"2.5.4.17":                   "postalCode",
+	"2.5.4.42":                   "givenName",
+	"2.5.4.97":                   "organizationIdentifier",
+	"0.9.2342.19200300.100.1.1":  "userId",
+	"0.9.2342.19200300.100.1.25": "domainComponent",
+	"1.3.6.1.4.1.311.60.2.1.1":   "jurisdictionLocalityName",
+	"1.3.6.1.4.1.311.60.2.1.2":   "jurisdictionStateOrProvinceName",
+	"1.3.6.1.4.1.311.60.2.1.3":   "jurisdictionCountryName",
+
+	// 证书扩展
+	"2.5.29.9":                "subjectDirectoryAttributes",
+	"2.5.29.14":               "subjectKeyIdentifier",
+	"2.5.29.15":               "keyUsage",
+	"2.5.29.16":               "privateKeyUsagePeriod",
+	"2.5.29.17":               "subjectAltName",
+	"2.5.29.18":               "issuerAltName",
+	"2.5.29.19":               "basicConstraints",
+	"2.5.29.20":               "cRLNumber",
+	"2.5.29.21":               "cRLReason",
+	"2.5.29.24":               "invalidityDate",
+	"2.5.29.27":               "deltaCRLIndicator",
+	"2.5.29.28":               "issuingDistributionPoint",
+	"2.5.29.29":               "certificateIssuer",
+	"2.5.29.30":               "nameConstraints",
+	"2.5.29.31":               "cRLDistributionPoints",
+	"2.5.29.32":               "certificatePolicies",
+	"2.5.29.32.0":             "anyPolicy",
+	"2.5.29.33":               "policyMappings",
+	"2.5.29.35":               "authorityKeyIdentifier",
+	"2.5.29.36":               "policyConstraints",
+	"2.5.29.37":               "extendedKeyUsage",
+	"2.5.29.37.0":             "anyExtendedKeyUsage",
+	"2.5.29.46":               "freshestCRL",
+	"2.5.29.54":               "inhibitAnyPolicy",
+	"1.3.6.1.5.5.7.1.1":       "authorityInfoAccess",
+	"1.3.6.1.5.5.7.1.11":      "subjectInfoAccess",
+	"1.3.6.1.5.5.7.1.24":      "tlsFeature",
+	"1.3.6.1.4.1.11129.2.4.2": "ctSignedCertificateTimestamps",
+	"1.3.6.1.4.1.11129.2.4.3": "ctPrecertificatePoison",
+	"1.3.6.1.4.1.11129.2.4.5": "ctOCSPSignedCertificateTimestamps",
+	"1.3.6.1.4.1.311.20.2":    "msCertificateTemplateName",
+	"1.3.6.1.4.1.311.21.7":    "msCertificateTemplate",
+	"1.3.6.1.4.1.311.21.10":   "msApplicationPolicies",
+	"1.3.6.1.5.5.7.48.1.2":    "ocspNonce",
+	"1.3.6.1.5.5.7.48.1.5":    "ocspNoCheck",
+
+	// AIA / SIA 访问方式
+	"1.3.6.1.5.5.7.48.1": "ocsp",
+	"1.3.6.1.5.5.7.48.2": "caIssuers",
+	"1.3.6.1.5.5.7.48.3": "timeStamping",
+	"1.3.6.1.5.5.7.48.5": "caRepository",
+
+	// OCSP 响应类型
+	"1.3.6.1.5.5.7.48.1.1": "basicOCSPResponse",
+
+	// 扩展密钥用途
+	"1.3.6.1.5.5.7.3.1":      "serverAuth",
+	"1.3.6.1.5.5.7.3.2":      "clientAuth",
+	"1.3.6.1.5.5.7.3.3":      "codeSigning",
+	"1.3.6.1.5.5.7.3.4":      "emailProtection",
+	"1.3.6.1.5.5.7.3.8":      "timeStamping",
+	"1.3.6.1.5.5.7.3.9":      "OCSPSigning",
+	"1.3.6.1.4.1.311.10.3.3": "msSGC",
+	"1.3.6.1.4.1.311.10.3.4": "msEFS",
+	"1.3.6.1.4.1.311.20.2.2": "msSmartcardLogin",
+	"2.16.840.1.113730.4.1":  "nsSGC",
+
+	// 证书策略
+	"2.23.140.1.1":      "ev-guidelines",
+	"2.23.140.1.2.1":    "domain-validated",
+	"2.23.140.1.2.2":    "organization-validated",
+	"2.23.140.1.2.3":    "individual-validated",
+	"1.3.6.1.5.5.7.2.1": "id-qt-cps",
+	"1.3.6.1.5.5.7.2.2": "id-qt-unotice",
+
+	// 公钥算法与曲线
+	"1.2.840.113549.1.1.1":  "rsaEncryption",
+	"1.2.840.113549.1.1.7":  "rsaesOaep",
+	"1.2.840.113549.1.1.10": "rsassaPss",
+	"1.2.840.113549.1.1.8":  "mgf1",
+	"1.2.840.10045.2.1":     "id-ecPublicKey",
+	"1.2.840.10040.4.1":     "dsaEncryption",
+	"1.3.101.110":           "X25519",
+	"1.3.101.111":           "X448",
+	"1.3.101.112":           "ED25519",
+	"1.3.101.113":           "ED448",
+	"1.2.840.10045.3.1.7":   "prime256v1",
+	"1.3.132.0.34":          "secp384r1",
+	"1.3.132.0.35":          "secp521r1",
+	"1.3.132.0.10":          "secp256k1",
+	"1.2.156.10197.1.301":   "SM2",
+
+	// 签名算法
+	"1.2.840.113549.1.1.4":   "md5WithRSAEncryption",
+	"1.2.840.113549.1.1.5":   "sha1WithRSAEncryption",
+	"1.2.840.113549.1.1.11":  "sha256WithRSAEncryption",
+	"1.2.840.113549.1.1.12":  "sha384WithRSAEncryption",
+	"1.2.840.113549.1.1.13":  "sha512WithRSAEncryption",
+	"1.2.840.113549.1.1.14":  "sha224WithRSAEncryption",
+	"1.2.840.10045.4.1":      "ecdsa-with-SHA1",
+	"1.2.840.10045.4.3.1":    "ecdsa-with-SHA224",
+	"1.2.840.10045.4.3.2":    "ecdsa-with-SHA256",
+	"1.2.840.10045.4.3.3":    "ecdsa-with-SHA384",
+	"1.2.840.10045.4.3.4":    "ecdsa-with-SHA512",
+	"1.2.840.10040.4.3":      "dsaWithSHA1",
+	"2.16.840.1.101.3.4.3.2": "dsa_with_SHA256",
+	"1.2.156.10197.1.501":    "SM2-with-SM3",
+
+	// 摘要与对称算法
+	"1.2.840.113549.2.5":      "md5",
+	"1.3.14.3.2.26":           "sha1",
+	"2.16.840.1.101.3.4.2.1":  "sha256",
+	"2.16.840.1.101.3.4.2.2":  "sha384",
+	"2.16.840.1.101.3.4.2.3":  "sha512",
+	"2.16.840.1.101.3.4.2.4":  "sha224",
+	"1.2.840.113549.2.7":      "hmacWithSHA1",
+	"1.2.840.113549.2.9":      "hmacWithSHA256",
+	"1.2.840.113549.2.10":     "hmacWithSHA384",
+	"1.2.840.113549.2.11":     "hmacWithSHA512",
+	"2.16.840.1.101.3.4.1.2":  "aes-128-cbc",
+	"2.16.840.1.101.3.4.1.22": "aes-192-cbc",
+	"2.16.840.1.101.3.4.1.42": "aes-256-cbc",
+	"1.2.840.113549.3.7":      "des-ede3-cbc",
+	"1.2.840.113549.3.2":      "rc2-cbc",
+
+	// PKCS#5 / PKCS#12 口令加密
+	"1.2.840.113549.1.5.12":      "PBKDF2",
+	"1.2.840.113549.1.5.13":      "PBES2",
+	"1.2.840.113549.1.12.1.3":    "pbeWithSHA1And3-KeyTripleDES-CBC",
+	"1.2.840.113549.1.12.1.6":    "pbeWithSHA1And40BitRC2-CBC",
+	"1.2.840.113549.1.12.10.1.1": "keyBag",
+	"1.2.840.113549.1.12.10.1.2": "pkcs8ShroudedKeyBag",
+	"1.2.840.113549.1.12.10.1.3": "certBag",
+	"1.2.840.113549.1.12.10.1.5": "secretBag",
+	"1.2.840.113549.1.9.22.1":    "x509Certificate",
+
+	// PKCS#7 / CMS 内容类型
+	"1.2.840.113549.1.7.1":      "pkcs7-data",
+	"1.2.840.113549.1.7.2":      "pkcs7-signedData",
+	"1.2.840.113549.1.7.3":      "pkcs7-envelopedData",
+	"1.2.840.113549.1.7.5":      "pkcs7-digestData",
+	"1.2.840.113549.1.7.6":      "pkcs7-encryptedData",
+	"1.2.840.113549.1.9.16.1.4": "id-smime-ct-TSTInfo",
+
+	// PKCS#9 属性
+	"1.2.840.113549.1.9.1":  "emailAddress",
+	"1.2.840.113549.1.9.2":  "unstructuredName",
+	"1.2.840.113549.1.9.3":  "contentType",
+	"1.2.840.113549.1.9.4":  "messageDigest",
+	"1.2.840.113549.1.9.5":  "signingTime",
+	"1.2.840.113549.1.9.7":  "challengePassword",
+	"1.2.840.113549.1.9.14": "extensionRequest",
+	"1.2.840.113549.1.9.15": "SMIMECapabilities",
+	"1.2.840.113549.1.9.20": "friendlyName",
+	"1.2.840.113549.1.9.21": "localKeyID",
+
+	// 微软 CSR 属性
+	"1.3.6.1.4.1.311.13.2.1": "enrollmentNameValuePair",
+	"1.3.6.1.4.1.311.13.2.2": "enrollmentCSP",
+	"1.3.6.1.4.1.311.13.2.3": "osVersion",
+	"1.3.6.1.4.1.311.21.20":  "requestClientInfo",
+}
@@ -0,0 +1,400 @@
+package ca
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	domaincert "my-tools/internal/domain/cert"
+	domaincsr "my-tools/internal/domain/csr"
+	domainkey "my-tools/internal/domain/key"
+)
+
+// 默认有效期（天）
+const (
+	DefaultRootValidityDays         = 3650
+	DefaultIntermediateValidityDays = 1825
+	DefaultLeafValidityDays         = 90
+	// CRLValidity CRL 的 nextUpdate 间隔
+	CRLValidity = 7 * 24 * time.Hour
+)
+
+// MaxLeafValidityDays 返回 now 时签发终端证书允许的最长有效期（天），与证书 lint 使用同一张
+// CA/B Forum 日期表，避免签出浏览器不接受的证书
+func MaxLeafValidityDays(now time.Time) int {
+	if days, ok := domaincert.BRMaxValidityDays(now); ok {
+		return days
+	}
+	return DefaultLeafValidityDays
+}
+
+// DefaultName 未指定名称时根证书和中间证书 CN 的前缀
+const DefaultName = "My Tools Test"
+
+var oidCRLReason = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+// extKeyUsages 签发时可选的扩展密钥用途
+var extKeyUsages = map[string]x509.ExtKeyUsage{
+	"serverAuth":      x509.ExtKeyUsageServerAuth,
+	"clientAuth":      x509.ExtKeyUsageClientAuth,
+	"codeSigning":     x509.ExtKeyUsageCodeSigning,
+	"emailProtection": x509.ExtKeyUsageEmailProtection,
+	"timeStamping":    x509.ExtKeyUsageTimeStamping,
+}
+
+// InitOptions 创建根证书和中间证书的参数
+type InitOptions struct {
+	// Name 为空时使用 DefaultName，根证书 CN 为 "<Name> Root CA"，中间证书为 "<Name> Intermediate CA"
+	Name         string
+	Organization string
+	// KeySpec 取值同 CSR 生成，为空时使用 ECDSA-P256
+	KeySpec                  string
+	RootValidityDays         int
+	IntermediateValidityDays int
+}
+
+// Authority 本地测试 CA：根证书签发中间证书，中间证书签发终端证书和 CRL
+type Authority struct {
+	Root            *x509.Certificate
+	RootKey         crypto.Signer
+	Intermediate    *x509.Certificate
+	IntermediateKey crypto.Signer
+}
+
+// AuthorityPEM CA 证书和私钥（PKCS#8，不加密）的 PEM，用于持久化
+type AuthorityPEM struct {
+	RootCert         string
+	RootKey          string
+	IntermediateCert string
+	IntermediateKey  string
+}
+
+// SignOptions 签发终端证书的参数
+type SignOptions struct {
+	// ValidityDays 为 0 时使用 DefaultLeafValidityDays
+	ValidityDays int
+	// DNSNames / IPAddresses / EmailAddresses 任一不为空时替换 CSR 中的 SAN
+	DNSNames       []string
+	IPAddresses    []string
+	EmailAddresses []string
+	// ExtKeyUsages 取值 serverAuth / clientAuth / codeSigning / emailProtection / timeStamping，为空时使用 serverAuth
+	ExtKeyUsages []string
+}
+
+// Revocation 一条吊销记录
+type Revocation struct {
+	SerialNumber *big.Int
+	RevokedAt    time.Time
+	// Reason RFC 5280 CRLReason 名称，为空时不写入原因
+	Reason string
+}
+
+// NewAuthority 生成根证书和中间证书
+func NewAuthority(opts InitOptions, now time.Time) (*Authority, error) {
+	name := strings.TrimSpace(opts.Name)
+	if name == "" {
+		name = DefaultName
+	}
+	spec := opts.KeySpec
+	if spec == "" {
+		spec = domaincsr.KeySpecECDSAP256
+	}
+	rootDays := opts.RootValidityDays
+	if rootDays == 0 {
+		rootDays = DefaultRootValidityDays
+	}
+	interDays := opts.IntermediateValidityDays
+	if interDays == 0 {
+		interDays = DefaultIntermediateValidityDays
+	}
+	if rootDays < 0 || interDays < 0 || interDays > rootDays {
+		return nil, errors.New("intermediate validity must be positive and not exceed root validity")
+	}
+
+	rootKey, err := domaincsr.GenerateKey(spec)
+	if err != nil {
+		return nil, err
+	}
+	interKey, err := domaincsr.GenerateKey(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	notBefore := now.Add(-time.Hour)
+	rootTmpl := &x509.Certificate{
+		Subject:               caName(name+" Root CA", opts.Organization),
+		NotBefore:             notBefore,
+		NotAfter:              now.AddDate(0, 0, rootDays),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}
+	root, err := createCert(rootTmpl, rootTmpl, rootKey.Public(), rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	interTmpl := &x509.Certificate{
+		Subject:               caName(name+" Intermediate CA", opts.Organization),
+		NotBefore:             notBefore,
+		NotAfter:              now.AddDate(0, 0, interDays),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign | x509.KeyUsageDigitalSignature,
+	}
+	inter, err := createCert(interTmpl, root, interKey.Public(), rootKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Authority{Root: root, RootKey: rootKey, Intermediate: inter, IntermediateKey: interKey}, nil
+}
+
+// LoadAuthority 从 PEM 恢复 CA，并检查私钥与证书、中间证书与根证书是否对应
+func LoadAuthority(p AuthorityPEM) (*Authority, error) {
+	root, rootKey, err := loadPair(p.RootCert, p.RootKey)
+	if err != nil {
+		return nil, errors.New("root: " + err.Error())
+	}
+	inter, interKey, err := loadPair(p.IntermediateCert, p.IntermediateKey)
+	if err != nil {
+		return nil, errors.New("intermediate: " + err.Error())
+	}
+	if err := inter.CheckSignatureFrom(root); err != nil {
+		return nil, errors.New("intermediate is not signed by root: " + err.Error())
+	}
+	return &Authority{Root: root, RootKey: rootKey, Intermediate: inter, IntermediateKey: interKey}, nil
+}
+
+// PEM 导出 CA 证书和私钥
+func (a *Authority) PEM() (AuthorityPEM, error) {
+	rootKey, err := encodeKeyPEM(a.RootKey)
+	if err != nil {
+		return AuthorityPEM{}, err
+	}
+	interKey, err := encodeKeyPEM(a.IntermediateKey)
+	if err != nil {
+		return AuthorityPEM{}, err
+	}
+	return AuthorityPEM{
+		RootCert:         EncodeCertPEM(a.Root),
+		RootKey:          rootKey,
+		IntermediateCert: EncodeCertPEM(a.Intermediate),
+		IntermediateKey:  interKey,
+	}, nil
+}
+
+// Chain 返回 中间证书、根证书，拼在终端证书后即为完整证书链
+func (a *Authority) Chain() []*x509.Certificate {
+	return []*x509.Certificate{a.Intermediate, a.Root}
+}
+
+// SignCSR 用中间证书签发 CSR；CSR 先经过 NormalizeCSRPEM 清理，主题沿用 CSR，
+// 没有 SAN 时把 CN 作为 DNS / IP SAN
+func (a *Authority) SignCSR(csrInput string, opts SignOptions, now time.Time) (*x509.Certificate, error) {
+	normalized, err := domaincsr.NormalizeCSRPEM(csrInput)
+	if err != nil {
+		return nil, err
+	}
+	req, err := domaincsr.ParseCSRPEM(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, errors.New("invalid csr signature: " + err.Error())
+	}
+
+	days := opts.ValidityDays
+	if days == 0 {
+		days = DefaultLeafValidityDays
+	}
+	notBefore := now.Add(-5 * time.Minute)
+	if maxDays := MaxLeafValidityDays(notBefore); days < 0 || days > maxDays {
+		return nil, errors.New("validity days must be between 1 and " + strconv.Itoa(maxDays))
+	}
+	// 从回拨后的 notBefore 起算，保证整段有效期不超过上限
+	notAfter := notBefore.AddDate(0, 0, days)
+	if notAfter.After(a.Intermediate.NotAfter) {
+		return nil, errors.New("validity exceeds the intermediate certificate expiry")
+	}
+
+	ekus, err := parseExtKeyUsages(opts.ExtKeyUsages)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		RawSubject:     req.RawSubject,
+		NotBefore:      notBefore,
+		NotAfter:       notAfter,
+		KeyUsage:       x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:    ekus,
+		DNSNames:       req.DNSNames,
+		IPAddresses:    req.IPAddresses,
+		EmailAddresses: req.EmailAddresses,
+		URIs:           req.URIs,
+	}
+	if _, ok := req.PublicKey.(*rsa.PublicKey); ok {
+		tmpl.KeyUsage |= x509.KeyUsageKeyEncipherment
+	}
+
+	dnsNames, ips, emails := trimAll(opts.DNSNames), trimAll(opts.IPAddresses), trimAll(opts.EmailAddresses)
+	if len(dnsNames)+len(ips)+len(emails) > 0 {
+		tmpl.DNSNames = dnsNames
+		tmpl.EmailAddresses = emails
+		tmpl.IPAddresses = nil
+		tmpl.URIs = nil
+		for _, s := range ips {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return nil, errors.New("invalid ip address: " + s)
+			}
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		}
+	}
+	if len(tmpl.DNSNames)+len(tmpl.IPAddresses)+len(tmpl.EmailAddresses)+len(tmpl.URIs) == 0 {
+		cn := req.Subject.CommonName
+		switch {
+		case cn == "":
+			return nil, errors.New("csr has no common name or SAN")
+		case net.ParseIP(cn) != nil:
+			tmpl.IPAddresses = []net.IP{net.ParseIP(cn)}
+		default:
+			tmpl.DNSNames = []string{cn}
+		}
+	}
+
+	return createCert(tmpl, a.Intermediate, req.PublicKey, a.IntermediateKey)
+}
+
+// CreateCRL 由中间证书签发 CRL，返回 DER
+func (a *Authority) CreateCRL(revoked []Revocation, number int64, now time.Time) ([]byte, error) {
+	entries := make([]pkix.RevokedCertificate, 0, len(revoked))
+	for _, r := range revoked {
+		e := pkix.RevokedCertificate{SerialNumber: r.SerialNumber, RevocationTime: r.RevokedAt.UTC()}
+		if r.Reason != "" {
+			code, err := domaincert.RevocationReasonCode(r.Reason)
+			if err != nil {
+				return nil, err
+			}
+			v, err := asn1.Marshal(asn1.Enumerated(code))
+			if err != nil {
+				return nil, err
+			}
+			e.Extensions = []pkix.Extension{{Id: oidCRLReason, Value: v}}
+		}
+		entries = append(entries, e)
+	}
+
+	return x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		RevokedCertificates: entries,
+		Number:              big.NewInt(number),
+		ThisUpdate:          now,
+		NextUpdate:          now.Add(CRLValidity),
+	}, a.Intermediate, a.IntermediateKey)
+}
+
+// EncodeCertPEM 输出证书 PEM
+func EncodeCertPEM(c *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+}
+
+func createCert(tmpl, parent *x509.Certificate, pub crypto.PublicKey, signer crypto.Signer) (*x509.Certificate, error) {
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+	tmpl.SerialNumber = serial
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, pub, signer)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
+
+// newSerial 生成 128 bit 正整数序列号，满足 CA/B Forum 至少 64 bit 熵的要求
+func newSerial() (*big.Int, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	b[0] &= 0x7f
+	b[0] |= 0x40
+	return new(big.Int).SetBytes(b), nil
+}
+
+func caName(cn, org string) pkix.Name {
+	n := pkix.Name{CommonName: cn}
+	if org = strings.TrimSpace(org); org != "" {
+		n.Organization = []string{org}
+	}
+	return n
+}
+
+func loadPair(certPEM, keyPEM string) (*x509.Certificate, crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, nil, errors.New("failed to decode certificate PEM")
+	}
+	c, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	k, _, _, err := domainkey.ParsePrivateKeyPEM(keyPEM, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	signer, ok := k.(crypto.Signer)
+	if !ok {
+		return nil, nil, errors.New("unsupported private key type")
+	}
+	if pub, ok := c.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !pub.Equal(signer.Public()) {
+		return nil, nil, errors.New("private key does not match certificate")
+	}
+	return c, signer, nil
+}
+
+func encodeKeyPEM(k crypto.Signer) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k)
+	if err != nil {
+		return "", err
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+func parseExtKeyUsages(names []string) ([]x509.ExtKeyUsage, error) {
+	names = trimAll(names)
+	if len(names) == 0 {
+		return []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}, nil
+	}
+	out := make([]x509.ExtKeyUsage, 0, len(names))
+	for _, n := range names {
+		eku, ok := extKeyUsages[n]
+		if !ok {
+			return nil, errors.New("unsupported ext key usage: " + n)
+		}
+		out = append(out, eku)
+	}
+	return out, nil
+}
+
+func trimAll(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
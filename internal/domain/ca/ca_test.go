@@ -0,0 +1,205 @@
+package ca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	domaincert "my-tools/internal/domain/cert"
+)
+
+func newTestCSR(t *testing.T, tmpl *x509.CertificateRequest) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func newTestAuthority(t *testing.T) *Authority {
+	t.Helper()
+	a, err := NewAuthority(InitOptions{Organization: "Example Ltd"}, time.Now())
+	if err != nil {
+		t.Fatalf("NewAuthority() error = %v", err)
+	}
+	return a
+}
+
+func TestNewAuthority(t *testing.T) {
+	a := newTestAuthority(t)
+
+	if a.Root.Subject.CommonName != DefaultName+" Root CA" || !a.Root.IsCA {
+		t.Errorf("root = %s", a.Root.Subject)
+	}
+	if a.Intermediate.Subject.CommonName != DefaultName+" Intermediate CA" || !a.Intermediate.MaxPathLenZero {
+		t.Errorf("intermediate = %s, maxPathLenZero = %v", a.Intermediate.Subject, a.Intermediate.MaxPathLenZero)
+	}
+	if err := a.Intermediate.CheckSignatureFrom(a.Root); err != nil {
+		t.Errorf("intermediate not signed by root: %v", err)
+	}
+
+	p, err := a.PEM()
+	if err != nil {
+		t.Fatalf("PEM() error = %v", err)
+	}
+	loaded, err := LoadAuthority(p)
+	if err != nil {
+		t.Fatalf("LoadAuthority() error = %v", err)
+	}
+	if !loaded.Root.Equal(a.Root) || !loaded.Intermediate.Equal(a.Intermediate) {
+		t.Errorf("loaded authority differs")
+	}
+
+	t.Run("mismatched key", func(t *testing.T) {
+		bad := p
+		bad.IntermediateKey = p.RootKey
+		if _, err := LoadAuthority(bad); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("invalid validity", func(t *testing.T) {
+		if _, err := NewAuthority(InitOptions{RootValidityDays: 10, IntermediateValidityDays: 20}, time.Now()); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+func TestSignCSR(t *testing.T) {
+	a := newTestAuthority(t)
+	now := time.Now()
+
+	t.Run("csr sans", func(t *testing.T) {
+		csrPEM := newTestCSR(t, &x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: "app.test", Organization: []string{"Staging"}},
+			DNSNames: []string{"app.test", "www.app.test"},
+		})
+		// 与 /csr/format 一样接受带转义换行的输入
+		escaped := strings.ReplaceAll(csrPEM, "\n", "\\n")
+		c, err := a.SignCSR(escaped, SignOptions{ExtKeyUsages: []string{"serverAuth", "clientAuth"}}, now)
+		if err != nil {
+			t.Fatalf("SignCSR() error = %v", err)
+		}
+		if c.Subject.String() != "CN=app.test,O=Staging" || !reflect.DeepEqual(c.DNSNames, []string{"app.test", "www.app.test"}) {
+			t.Errorf("subject = %s, dns = %v", c.Subject, c.DNSNames)
+		}
+		if !reflect.DeepEqual(c.ExtKeyUsage, []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth}) {
+			t.Errorf("ekus = %v", c.ExtKeyUsage)
+		}
+		if d := c.NotAfter.Sub(now); d < 89*24*time.Hour || d > 91*24*time.Hour {
+			t.Errorf("validity = %v", d)
+		}
+
+		roots := x509.NewCertPool()
+		roots.AddCert(a.Root)
+		inters := x509.NewCertPool()
+		inters.AddCert(a.Intermediate)
+		if _, err := c.Verify(x509.VerifyOptions{DNSName: "www.app.test", Roots: roots, Intermediates: inters}); err != nil {
+			t.Errorf("verify: %v", err)
+		}
+		if report := domaincert.LintCert(c); !report.Passed {
+			t.Errorf("lint findings = %+v", report.Findings)
+		}
+	})
+
+	t.Run("override sans", func(t *testing.T) {
+		csrPEM := newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "app.test"}, DNSNames: []string{"app.test"}})
+		c, err := a.SignCSR(csrPEM, SignOptions{ValidityDays: 30, DNSNames: []string{" api.test "}, IPAddresses: []string{"10.0.0.1"}}, now)
+		if err != nil {
+			t.Fatalf("SignCSR() error = %v", err)
+		}
+		if !reflect.DeepEqual(c.DNSNames, []string{"api.test"}) || len(c.IPAddresses) != 1 || !c.IPAddresses[0].Equal(net.ParseIP("10.0.0.1")) {
+			t.Errorf("sans = %v %v", c.DNSNames, c.IPAddresses)
+		}
+	})
+
+	t.Run("cn as san", func(t *testing.T) {
+		c, err := a.SignCSR(newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "192.168.1.10"}}), SignOptions{}, now)
+		if err != nil {
+			t.Fatalf("SignCSR() error = %v", err)
+		}
+		if len(c.IPAddresses) != 1 || len(c.DNSNames) != 0 {
+			t.Errorf("sans = %v %v", c.DNSNames, c.IPAddresses)
+		}
+	})
+
+	errTests := []struct {
+		name string
+		csr  string
+		opts SignOptions
+	}{
+		{"empty", "", SignOptions{}},
+		{"no cn or san", newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{Organization: []string{"x"}}}), SignOptions{}},
+		{"too long", newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "a.test"}}), SignOptions{ValidityDays: 400}},
+		{"bad eku", newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "a.test"}}), SignOptions{ExtKeyUsages: []string{"any"}}},
+		{"bad ip", newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "a.test"}}), SignOptions{IPAddresses: []string{"x"}}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := a.SignCSR(tt.csr, tt.opts, now); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestSignCSRValidityLimit(t *testing.T) {
+	a := newTestAuthority(t)
+	csrPEM := newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "app.test"}})
+	now := time.Now()
+	maxDays := MaxLeafValidityDays(now)
+
+	c, err := a.SignCSR(csrPEM, SignOptions{ValidityDays: maxDays}, now)
+	if err != nil {
+		t.Fatalf("SignCSR(%d days) error = %v", maxDays, err)
+	}
+	for _, f := range domaincert.LintCert(c).Findings {
+		if f.Code == "validity_too_long" {
+			t.Errorf("signed certificate fails lint: %s", f.Message)
+		}
+	}
+	if _, err := a.SignCSR(csrPEM, SignOptions{ValidityDays: maxDays + 1}, now); err == nil {
+		t.Errorf("SignCSR(%d days) expected error", maxDays+1)
+	}
+}
+
+func TestCreateCRL(t *testing.T) {
+	a := newTestAuthority(t)
+	csrPEM := newTestCSR(t, &x509.CertificateRequest{Subject: pkix.Name{CommonName: "app.test"}})
+	c, err := a.SignCSR(csrPEM, SignOptions{}, time.Now())
+	if err != nil {
+		t.Fatalf("SignCSR() error = %v", err)
+	}
+
+	der, err := a.CreateCRL([]Revocation{{SerialNumber: c.SerialNumber, RevokedAt: time.Now(), Reason: "keyCompromise"}}, 3, time.Now())
+	if err != nil {
+		t.Fatalf("CreateCRL() error = %v", err)
+	}
+	info, err := domaincert.DecodeCRL(der, domaincert.CRLOptions{IssuerPEM: EncodeCertPEM(a.Intermediate), CertPEM: EncodeCertPEM(c)})
+	if err != nil {
+		t.Fatalf("DecodeCRL() error = %v", err)
+	}
+	if !info.SignatureValid || info.Number != "3" || info.Count != 1 {
+		t.Errorf("crl = %+v", info)
+	}
+	if info.Lookup == nil || !info.Lookup.Revoked || info.Lookup.Entry.Reason != "keyCompromise" {
+		t.Errorf("lookup = %+v", info.Lookup)
+	}
+
+	if _, err := a.CreateCRL([]Revocation{{SerialNumber: c.SerialNumber, RevokedAt: time.Now(), Reason: "bogus"}}, 4, time.Now()); err == nil {
+		t.Errorf("expected error for unknown reason")
+	}
+}
@@ -0,0 +1,345 @@
+package cert
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// 证书包中根证书的来源
+const (
+	RootSourcePool       = "pool"
+	RootSourceTrustStore = "trust_store"
+)
+
+const (
+	// maxZipEntries / maxZipEntrySize 限制上传压缩包的文件数和单个文件大小
+	maxZipEntries   = 200
+	maxZipEntrySize = 1 << 20
+)
+
+// BundleOptions 证书包生成参数
+type BundleOptions struct {
+	// IncludeRoot 在证书链末尾附加根证书：优先使用候选证书中的自签名根证书，其次使用信任库
+	IncludeRoot bool
+	// TrustStore / RootsPEM 同 VerifyOptions，用于查找根证书和信任校验
+	TrustStore string
+	RootsPEM   string
+	// CurrentTime 验证时使用的时间，零值表示当前时间
+	CurrentTime time.Time
+}
+
+// BundleInput 一份候选证书：粘贴的文本或上传的文件
+type BundleInput struct {
+	// Name 文件名，用于提示信息，可为空
+	Name string
+	Data []byte
+}
+
+// BundleResult 证书包生成结果
+type BundleResult struct {
+	Leaf CertInfo `json:"leaf"`
+	// Chain 按签发顺序排列的中间证书（IncludeRoot 时包含根证书）
+	Chain []CertInfo `json:"chain"`
+	// Unused 未用到的候选证书
+	Unused []CertInfo `json:"unused"`
+	// Duplicates 重复的候选证书数量
+	Duplicates int `json:"duplicates"`
+
+	// Complete 签发路径到达根证书（候选证书中的自签名证书或信任库中的根证书）
+	Complete      bool   `json:"complete"`
+	MissingIssuer string `json:"missingIssuer,omitempty"`
+	RootIncluded  bool   `json:"rootIncluded"`
+	// RootSource 根证书来源：pool / trust_store，未找到根证书时为空
+	RootSource  string   `json:"rootSource,omitempty"`
+	Verified    bool     `json:"verified"`
+	VerifyError string   `json:"verifyError,omitempty"`
+	Warnings    []string `json:"warnings"`
+
+	// FullChainPEM 叶子 + 证书链（nginx ssl_certificate）
+	FullChainPEM string `json:"fullChainPem"`
+	// CertPEM / ChainPEM 叶子与证书链分开（Apache SSLCertificateFile / SSLCertificateChainFile）
+	CertPEM  string `json:"certPem"`
+	ChainPEM string `json:"chainPem"`
+	// PKCS7 叶子 + 证书链的 PKCS#7（IIS .p7b）
+	PKCS7 []byte `json:"pkcs7"`
+}
+
+// BuildBundle 从候选证书中选出叶子证书实际需要的签发者，按 叶子 -> 中间 -> 根 排列并输出多种部署格式；
+// leaf 含多个证书时选出叶子，其余证书并入候选
+func BuildBundle(leaf BundleInput, candidates []BundleInput, opts BundleOptions) (BundleResult, error) {
+	res := BundleResult{Chain: []CertInfo{}, Unused: []CertInfo{}, Warnings: []string{}}
+
+	leafCerts, err := decodeBundleInput(leaf, &res.Warnings)
+	if err != nil {
+		return BundleResult{}, fmt.Errorf("leaf: %w", err)
+	}
+	leafIdx := findLeaf(leafCerts)
+	leafCert := leafCerts[leafIdx]
+	if len(leafCerts) > 1 {
+		res.Warnings = append(res.Warnings, fmt.Sprintf("leaf input contains %d certificates, using %s as leaf", len(leafCerts), leafCert.Subject))
+	}
+	if leafCert.IsCA {
+		res.Warnings = append(res.Warnings, "leaf certificate is a CA certificate")
+	}
+
+	var pool []*x509.Certificate
+	seen := map[string]bool{string(leafCert.Raw): true}
+	addCandidates := func(certs []*x509.Certificate) {
+		for _, c := range certs {
+			if seen[string(c.Raw)] {
+				res.Duplicates++
+				continue
+			}
+			seen[string(c.Raw)] = true
+			pool = append(pool, c)
+		}
+	}
+	addCandidates(append(leafCerts[:leafIdx:leafIdx], leafCerts[leafIdx+1:]...))
+	for _, in := range candidates {
+		if len(bytes.TrimSpace(in.Data)) == 0 {
+			continue
+		}
+		certs, err := decodeBundleInput(in, &res.Warnings)
+		if err != nil {
+			return BundleResult{}, fmt.Errorf("%s: %w", inputName(in), err)
+		}
+		addCandidates(certs)
+	}
+
+	now := opts.CurrentTime
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	// 沿签发关系向上查找，遇到自签名证书即到达根证书
+	var chain []*x509.Certificate
+	var root *x509.Certificate
+	used := map[int]bool{}
+	cur := leafCert
+	for !isSelfSigned(cur) {
+		idx := pickIssuer(cur, pool, used, now)
+		if idx == -1 {
+			break
+		}
+		used[idx] = true
+		if isSelfSigned(pool[idx]) {
+			root = pool[idx]
+			res.RootSource = RootSourcePool
+			break
+		}
+		chain = append(chain, pool[idx])
+		cur = pool[idx]
+	}
+
+	roots, rootCerts, err := loadTrustStore(VerifyOptions{TrustStore: opts.TrustStore, RootsPEM: opts.RootsPEM})
+	if err != nil {
+		return BundleResult{}, err
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range chain {
+		intermediates.AddCert(c)
+	}
+	chains, verr := leafCert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   opts.CurrentTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if verr != nil {
+		res.VerifyError = verr.Error()
+	} else {
+		res.Verified = true
+	}
+
+	top := leafCert
+	if len(chain) > 0 {
+		top = chain[len(chain)-1]
+	}
+	if root == nil && !isSelfSigned(top) {
+		anchor, aerr := findAnchor(top, chains, roots, rootCerts, opts.CurrentTime)
+		switch {
+		case anchor != nil:
+			root = anchor
+			res.RootSource = RootSourceTrustStore
+		case isUnknownAuthority(verr) || isUnknownAuthority(aerr):
+			res.MissingIssuer = top.Issuer.String()
+		case aerr != nil:
+			res.Warnings = append(res.Warnings, "root certificate lookup failed: "+aerr.Error())
+		}
+	}
+	res.Complete = root != nil || isSelfSigned(top)
+	if opts.IncludeRoot && root != nil {
+		chain = append(chain, root)
+		res.RootIncluded = true
+	}
+	if opts.IncludeRoot && root == nil && res.MissingIssuer == "" {
+		// 叶子本身是自签名证书
+		res.Warnings = append(res.Warnings, "leaf certificate is self-signed, no root to append")
+	}
+
+	for _, c := range append([]*x509.Certificate{leafCert}, chain...) {
+		if now.After(c.NotAfter) {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("certificate %s expired at %s", c.Subject, c.NotAfter.UTC().Format(time.RFC3339)))
+		}
+	}
+
+	res.CertPEM = encodeCertPEM(leafCert)
+	res.Leaf = newCertInfo(res.CertPEM, leafCert)
+	var b strings.Builder
+	for _, c := range chain {
+		p := encodeCertPEM(c)
+		b.WriteString(p)
+		res.Chain = append(res.Chain, newCertInfo(p, c))
+	}
+	res.ChainPEM = b.String()
+	res.FullChainPEM = res.CertPEM + res.ChainPEM
+
+	for i, c := range pool {
+		if !used[i] {
+			res.Unused = append(res.Unused, newCertInfo(encodeCertPEM(c), c))
+		}
+	}
+	if root != nil && !res.RootIncluded && res.RootSource == RootSourcePool {
+		// 找到但按要求未附加的根证书也视为未使用
+		res.Unused = append(res.Unused, newCertInfo(encodeCertPEM(root), root))
+	}
+
+	res.PKCS7, err = EncodePKCS7(append([]*x509.Certificate{leafCert}, chain...))
+	if err != nil {
+		return BundleResult{}, err
+	}
+	return res, nil
+}
+
+// pickIssuer 在未使用的候选证书中查找签名校验通过的签发者；
+// 交叉签名时可能有多个，优先选择未过期的，其次选择自签名根证书（路径更短）
+func pickIssuer(child *x509.Certificate, pool []*x509.Certificate, used map[int]bool, now time.Time) int {
+	best, bestScore := -1, -1
+	for i, c := range pool {
+		if used[i] || !issuedBy(child, c) {
+			continue
+		}
+		score := 0
+		if !now.After(c.NotAfter) {
+			score += 2
+		}
+		if isSelfSigned(c) {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// decodeBundleInput 解析一份候选证书：PEM（证书链或 PKCS7）、DER、PKCS#7、base64 或包含上述文件的 zip；
+// zip 中无法解析的文件跳过并记录到 warnings
+func decodeBundleInput(in BundleInput, warnings *[]string) ([]*x509.Certificate, error) {
+	data := bytes.TrimSpace(in.Data)
+	if len(data) == 0 {
+		return nil, errors.New("input is empty")
+	}
+	// 二进制内容的首尾字节可能恰好是空白字符，zip 使用原始数据
+	if bytes.HasPrefix(in.Data, []byte("PK\x03\x04")) {
+		return decodeBundleZip(in.Data, warnings)
+	}
+
+	if containsCertPEM(string(data)) {
+		pems, err := SplitCertChain(string(data))
+		if err != nil {
+			return nil, err
+		}
+		certs := make([]*x509.Certificate, 0, len(pems))
+		for _, p := range pems {
+			c, err := parseCertPEM(p)
+			if err != nil {
+				return nil, err
+			}
+			certs = append(certs, c)
+		}
+		return certs, nil
+	}
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		der, err := decodeDERInput(data, "PKCS7")
+		if err != nil {
+			return nil, err
+		}
+		return DecodePKCS7(der)
+	}
+
+	raw, err := DecodeBinaryInput(in.Data)
+	if err != nil {
+		return nil, err
+	}
+	switch DetectFormat(raw) {
+	case FormatPKCS7:
+		return DecodePKCS7(raw)
+	case FormatDER:
+		return x509.ParseCertificates(raw)
+	case FormatPKCS12:
+		return nil, errors.New("pkcs12 is not supported here, convert it to PEM first")
+	}
+	return nil, errors.New("no certificates found")
+}
+
+// decodeBundleZip 解析 zip 中的全部证书文件，不处理嵌套的 zip
+func decodeBundleZip(data []byte, warnings *[]string) ([]*x509.Certificate, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("invalid zip: " + err.Error())
+	}
+	if len(zr.File) > maxZipEntries {
+		return nil, fmt.Errorf("zip contains too many files (max %d)", maxZipEntries)
+	}
+
+	var certs []*x509.Certificate
+	for _, f := range zr.File {
+		name := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(name, ".") || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		if f.UncompressedSize64 > maxZipEntrySize {
+			*warnings = append(*warnings, "skipped "+f.Name+": file is too large")
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		b, err := io.ReadAll(io.LimitReader(rc, maxZipEntrySize+1))
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if bytes.HasPrefix(b, []byte("PK\x03\x04")) {
+			*warnings = append(*warnings, "skipped "+f.Name+": nested zip is not supported")
+			continue
+		}
+
+		cs, err := decodeBundleInput(BundleInput{Name: f.Name, Data: b}, warnings)
+		if err != nil {
+			*warnings = append(*warnings, "skipped "+f.Name+": "+err.Error())
+			continue
+		}
+		certs = append(certs, cs...)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("no certificates found in zip")
+	}
+	return certs, nil
+}
+
+func inputName(in BundleInput) string {
+	if in.Name != "" {
+		return in.Name
+	}
+	return "candidates"
+}
@@ -0,0 +1,177 @@
+package cert
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildBundle(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	other := newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(9),
+		Subject:               pkix.Name{CommonName: "Unrelated CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}, nil)
+	custom := BundleOptions{TrustStore: TrustStoreCustom, RootsPEM: root.pem}
+
+	t.Run("selects needed issuers", func(t *testing.T) {
+		pool := []BundleInput{
+			{Data: []byte(other.pem + root.pem)},
+			{Data: []byte(strings.ReplaceAll(inter.pem, "\n", "\\r\\n"))},
+			{Data: []byte(inter.pem)},
+		}
+		got, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, pool, custom)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.Leaf.Subject != "CN=example.com" || len(got.Chain) != 1 || got.Chain[0].Subject != "CN=Test Intermediate CA" {
+			t.Fatalf("leaf = %q chain = %+v", got.Leaf.Subject, got.Chain)
+		}
+		if !got.Complete || !got.Verified || got.RootIncluded || got.RootSource != RootSourcePool {
+			t.Errorf("complete = %v verified = %v (%s) rootIncluded = %v rootSource = %q", got.Complete, got.Verified, got.VerifyError, got.RootIncluded, got.RootSource)
+		}
+		if got.Duplicates != 1 || len(got.Unused) != 2 {
+			t.Errorf("duplicates = %d unused = %d", got.Duplicates, len(got.Unused))
+		}
+		if got.FullChainPEM != leaf.pem+inter.pem || got.CertPEM != leaf.pem || got.ChainPEM != inter.pem {
+			t.Errorf("fullchain = %q", got.FullChainPEM)
+		}
+		certs, err := DecodePKCS7(got.PKCS7)
+		if err != nil || len(certs) != 2 || !certs[0].Equal(leaf.cert) || !certs[1].Equal(inter.cert) {
+			t.Errorf("pkcs7 = %d certs, err = %v", len(certs), err)
+		}
+	})
+
+	t.Run("include root from pool", func(t *testing.T) {
+		opts := custom
+		opts.IncludeRoot = true
+		got, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, []BundleInput{{Data: []byte(root.pem + inter.pem)}}, opts)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if !got.RootIncluded || got.FullChainPEM != leaf.pem+inter.pem+root.pem || len(got.Unused) != 0 {
+			t.Errorf("rootIncluded = %v unused = %d fullchain = %q", got.RootIncluded, len(got.Unused), got.FullChainPEM)
+		}
+	})
+
+	t.Run("include root from trust store", func(t *testing.T) {
+		opts := custom
+		opts.IncludeRoot = true
+		got, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, []BundleInput{{Data: []byte(inter.pem)}}, opts)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.RootSource != RootSourceTrustStore || !got.RootIncluded || got.ChainPEM != inter.pem+root.pem {
+			t.Errorf("rootSource = %q chain = %q", got.RootSource, got.ChainPEM)
+		}
+	})
+
+	t.Run("missing intermediate", func(t *testing.T) {
+		got, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, []BundleInput{{Data: []byte(other.pem)}}, custom)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.Complete || got.Verified || got.MissingIssuer != "CN=Test Intermediate CA" || len(got.Chain) != 0 {
+			t.Errorf("complete = %v missingIssuer = %q chain = %d", got.Complete, got.MissingIssuer, len(got.Chain))
+		}
+	})
+
+	t.Run("expired leaf with system store", func(t *testing.T) {
+		useSystemRoots(t, root)
+		expired := newTestCert(t, &x509.Certificate{
+			SerialNumber: big.NewInt(4),
+			Subject:      pkix.Name{CommonName: "expired.example.com"},
+			NotBefore:    time.Now().Add(-48 * time.Hour),
+			NotAfter:     time.Now().Add(-24 * time.Hour),
+		}, inter)
+		got, err := BuildBundle(BundleInput{Data: []byte(expired.pem)}, []BundleInput{{Data: []byte(inter.pem)}}, BundleOptions{IncludeRoot: true})
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.Verified || !got.Complete || got.MissingIssuer != "" {
+			t.Errorf("verified = %v complete = %v missingIssuer = %q", got.Verified, got.Complete, got.MissingIssuer)
+		}
+		if got.RootSource != RootSourceTrustStore || !got.RootIncluded || got.ChainPEM != inter.pem+root.pem {
+			t.Errorf("rootSource = %q chain = %q", got.RootSource, got.ChainPEM)
+		}
+	})
+
+	t.Run("missing intermediate with system store", func(t *testing.T) {
+		useSystemRoots(t, root)
+		got, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, nil, BundleOptions{IncludeRoot: true})
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.Complete || got.RootIncluded || got.MissingIssuer != "CN=Test Intermediate CA" {
+			t.Errorf("complete = %v rootIncluded = %v missingIssuer = %q", got.Complete, got.RootIncluded, got.MissingIssuer)
+		}
+	})
+
+	t.Run("leaf input with whole chain", func(t *testing.T) {
+		got, err := BuildBundle(BundleInput{Data: []byte(root.pem + inter.pem + leaf.pem)}, nil, custom)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.Leaf.Subject != "CN=example.com" || got.ChainPEM != inter.pem || len(got.Warnings) != 1 {
+			t.Errorf("leaf = %q chain = %q warnings = %v", got.Leaf.Subject, got.ChainPEM, got.Warnings)
+		}
+	})
+
+	t.Run("zip and pkcs7 uploads", func(t *testing.T) {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		for name, data := range map[string][]byte{
+			"intermediate.crt":       inter.cert.Raw,
+			"readme.txt":             []byte("install the intermediates in order"),
+			"__MACOSX/._root.crt":    {0, 5, 22, 7},
+			"certs/":                 nil,
+			"certs/unrelated-ca.pem": []byte(other.pem),
+		} {
+			w, err := zw.Create(name)
+			if err != nil {
+				t.Fatalf("zip create: %v", err)
+			}
+			w.Write(data)
+		}
+		if err := zw.Close(); err != nil {
+			t.Fatalf("zip close: %v", err)
+		}
+		p7, err := EncodePKCS7([]*x509.Certificate{root.cert})
+		if err != nil {
+			t.Fatalf("EncodePKCS7() error = %v", err)
+		}
+
+		pool := []BundleInput{{Name: "bundle.zip", Data: buf.Bytes()}, {Name: "root.p7b", Data: p7}}
+		opts := custom
+		opts.IncludeRoot = true
+		got, err := BuildBundle(BundleInput{Data: leaf.cert.Raw}, pool, opts)
+		if err != nil {
+			t.Fatalf("BuildBundle() error = %v", err)
+		}
+		if got.ChainPEM != inter.pem+root.pem || len(got.Unused) != 1 {
+			t.Errorf("chain = %q unused = %d", got.ChainPEM, len(got.Unused))
+		}
+		if len(got.Warnings) != 1 || !strings.Contains(got.Warnings[0], "readme.txt") {
+			t.Errorf("warnings = %v", got.Warnings)
+		}
+	})
+
+	t.Run("invalid candidates", func(t *testing.T) {
+		_, err := BuildBundle(BundleInput{Data: []byte(leaf.pem)}, []BundleInput{{Name: "chain.txt", Data: []byte("not a certificate")}}, custom)
+		if err == nil || !strings.HasPrefix(err.Error(), "chain.txt:") {
+			t.Errorf("BuildBundle() error = %v", err)
+		}
+		if _, err := BuildBundle(BundleInput{}, nil, custom); err == nil {
+			t.Error("BuildBundle() with empty leaf should fail")
+		}
+	})
+}
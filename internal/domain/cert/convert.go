@@ -0,0 +1,329 @@
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	pkcs12 "software.sslmate.com/src/go-pkcs12"
+
+	"my-tools/internal/domain/pemscan"
+	"my-tools/internal/domain/pkcs8"
+)
+
+// 证书文件格式
+const (
+	FormatPEM    = "pem"
+	FormatDER    = "der"
+	FormatPKCS7  = "pkcs7"
+	FormatPKCS12 = "pkcs12"
+)
+
+// ConvertOptions 证书格式转换参数
+type ConvertOptions struct {
+	// Target 输出格式，为空时只解析不输出
+	Target string
+	// InputPassword PKCS#12 输入的密码
+	InputPassword string
+	// OutputPassword PKCS#12 输出的密码；输出 PEM 时用于加密私钥
+	OutputPassword string
+	// KeyPEM 导出 PKCS#12 时使用的私钥，输入本身带私钥时可不填
+	KeyPEM        string
+	KeyPassphrase string
+	// LegacyPKCS12 使用 3DES 加密导出，兼容旧版 Windows / Java
+	LegacyPKCS12 bool
+}
+
+// ConvertResult 证书格式转换结果
+type ConvertResult struct {
+	InputFormat string     `json:"inputFormat"`
+	Certs       []CertInfo `json:"certs"`
+	// HasKey 输入中是否带有私钥（PEM 或 PKCS#12）
+	HasKey       bool   `json:"hasKey"`
+	OutputFormat string `json:"outputFormat,omitempty"`
+	// Output PEM 为文本，其余格式为二进制
+	Output []byte `json:"output,omitempty"`
+	// PrivateKeyPEM 输出 PEM 且输入带私钥时返回 PKCS#8 私钥
+	PrivateKeyPEM string `json:"privateKeyPem,omitempty"`
+}
+
+type pfxHeader struct {
+	Version  int
+	AuthSafe asn1.RawValue
+	MacData  asn1.RawValue `asn1:"optional"`
+}
+
+// DetectFormat 识别证书数据格式：PEM / DER / PKCS#7 / PKCS#12，无法识别时返回空字符串
+func DetectFormat(data []byte) string {
+	if bytes.Contains(data, []byte("-----BEGIN ")) {
+		return FormatPEM
+	}
+	if isPKCS7(data) {
+		return FormatPKCS7
+	}
+	if isPKCS12(data) {
+		return FormatPKCS12
+	}
+	if _, err := x509.ParseCertificates(data); err == nil {
+		return FormatDER
+	}
+	return ""
+}
+
+// DecodeBinaryInput 将上传内容还原为原始字节：已是二进制时原样返回，含 PEM 头时去掉首尾空白，否则按 base64 解码
+func DecodeBinaryInput(data []byte) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("input is empty")
+	}
+	// 二进制内容的首尾字节可能恰好是空白字符，先用未修改的数据识别，只对文本去空白
+	switch DetectFormat(data) {
+	case FormatPEM:
+		return trimmed, nil
+	case FormatDER, FormatPKCS7, FormatPKCS12:
+		return data, nil
+	}
+
+	if der, ok := decodeBase64(trimmed); ok {
+		return der, nil
+	}
+	return nil, errors.New("input is neither PEM, DER, PKCS#7, PKCS#12 nor base64")
+}
+
+// decodeBase64 忽略空白和转义换行，依次尝试标准与 URL 安全的 base64
+func decodeBase64(data []byte) ([]byte, bool) {
+	s := strings.NewReplacer("\\r\\n", "", "\\n", "", "\r", "", "\n", "", "\t", "", " ", "").Replace(string(data))
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding, base64.URLEncoding, base64.RawURLEncoding} {
+		if der, err := enc.DecodeString(s); err == nil {
+			return der, true
+		}
+	}
+	return nil, false
+}
+
+// decodeDERInput 将 PEM / base64 / 原始 DER 输入统一还原为 DER；PEM 输入取第一个类型匹配的块
+func decodeDERInput(data []byte, pemTypes ...string) ([]byte, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 {
+		return nil, errors.New("input is empty")
+	}
+
+	if bytes.Contains(trimmed, []byte("-----BEGIN ")) {
+		blocks := pemscan.Scan(string(trimmed)).Find(pemTypes...)
+		if len(blocks) == 0 {
+			return nil, errors.New("no " + strings.Join(pemTypes, " / ") + " PEM block found")
+		}
+		if blocks[0].Error != "" {
+			return nil, fmt.Errorf("%s at line %d: %s", blocks[0].Type, blocks[0].Line, blocks[0].Error)
+		}
+		return blocks[0].Bytes, nil
+	}
+
+	// 含不可打印字符的视为原始 DER，原样返回（末尾字节可能恰好是空白字符），否则按 base64 解码
+	for _, b := range data {
+		if b < 0x20 || b > 0x7e {
+			if b != '\n' && b != '\r' && b != '\t' {
+				return data, nil
+			}
+		}
+	}
+	der, ok := decodeBase64(trimmed)
+	if !ok {
+		return nil, errors.New("input is neither PEM, DER nor base64")
+	}
+	return der, nil
+}
+
+// ConvertCert 自动识别输入格式，解析其中的证书，并按需导出为 PEM 链 / DER / PKCS#7 / PKCS#12
+func ConvertCert(data []byte, opts ConvertOptions) (ConvertResult, error) {
+	raw, err := DecodeBinaryInput(data)
+	if err != nil {
+		return ConvertResult{}, err
+	}
+
+	format := DetectFormat(raw)
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+	switch format {
+	case FormatPEM:
+		certs, key, err = decodePEMBundle(string(raw), opts.KeyPassphrase)
+	case FormatDER:
+		certs, err = x509.ParseCertificates(raw)
+	case FormatPKCS7:
+		certs, err = DecodePKCS7(raw)
+	case FormatPKCS12:
+		certs, key, err = decodePKCS12(raw, opts.InputPassword)
+	default:
+		err = errors.New("unrecognized certificate format")
+	}
+	if err != nil {
+		return ConvertResult{}, err
+	}
+	if len(certs) == 0 {
+		return ConvertResult{}, errors.New("no certificates found")
+	}
+
+	res := ConvertResult{
+		InputFormat: format,
+		Certs:       make([]CertInfo, len(certs)),
+		HasKey:      key != nil,
+	}
+	for i, c := range certs {
+		res.Certs[i] = newCertInfo(encodeCertPEM(c), c)
+	}
+
+	if strings.TrimSpace(opts.KeyPEM) != "" {
+		key, _, _, err = ParsePrivateKeyPEM(opts.KeyPEM, opts.KeyPassphrase)
+		if err != nil {
+			return ConvertResult{}, err
+		}
+	}
+
+	switch opts.Target {
+	case "":
+		return res, nil
+	case FormatPEM:
+		var b strings.Builder
+		for _, c := range certs {
+			b.WriteString(encodeCertPEM(c))
+		}
+		res.Output = []byte(b.String())
+		if key != nil {
+			res.PrivateKeyPEM, err = encodeKeyPEM(key, opts.OutputPassword)
+		}
+	case FormatDER:
+		// DER 只能承载单个证书，取第一个
+		res.Output = certs[0].Raw
+	case FormatPKCS7:
+		res.Output, err = EncodePKCS7(certs)
+	case FormatPKCS12:
+		res.Output, err = encodePKCS12(certs, key, opts.OutputPassword, opts.LegacyPKCS12)
+	default:
+		return ConvertResult{}, errors.New("unsupported target format: " + opts.Target)
+	}
+	if err != nil {
+		return ConvertResult{}, err
+	}
+	res.OutputFormat = opts.Target
+
+	return res, nil
+}
+
+// decodePEMBundle 从 PEM 文本中取出证书（含 PKCS7 块）和第一个私钥
+func decodePEMBundle(in, passphrase string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	var certs []*x509.Certificate
+	var key crypto.PrivateKey
+	for _, block := range pemscan.Scan(in).Blocks {
+		isKey := strings.HasSuffix(block.Type, "PRIVATE KEY")
+		if block.Error != "" && (block.Type == "CERTIFICATE" || block.Type == "PKCS7" || isKey) {
+			return nil, nil, fmt.Errorf("%s at line %d: %s", block.Type, block.Line, block.Error)
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			certs = append(certs, c)
+		case block.Type == "PKCS7":
+			cs, err := DecodePKCS7(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			certs = append(certs, cs...)
+		case isKey && key == nil:
+			k, _, _, err := ParsePrivateKeyPEM(block.PEM, passphrase)
+			if err != nil {
+				return nil, nil, err
+			}
+			key = k
+		}
+	}
+	return certs, key, nil
+}
+
+// decodePKCS12 解析 PKCS#12；带私钥时把与私钥匹配的证书排在第一位
+func decodePKCS12(der []byte, password string) ([]*x509.Certificate, crypto.PrivateKey, error) {
+	key, leaf, cas, err := pkcs12.DecodeChain(der, password)
+	if err != nil {
+		// 不含私钥的 PKCS#12 只能按 Java 信任库解析
+		certs, tsErr := pkcs12.DecodeTrustStore(der, password)
+		if tsErr != nil {
+			return nil, nil, errors.New("invalid pkcs12: " + err.Error())
+		}
+		return certs, nil, nil
+	}
+	return orderLeafFirst(append([]*x509.Certificate{leaf}, cas...), key), key, nil
+}
+
+func encodePKCS12(certs []*x509.Certificate, key crypto.PrivateKey, password string, legacy bool) ([]byte, error) {
+	enc := pkcs12.Modern2023
+	switch {
+	case password == "":
+		enc = pkcs12.Passwordless
+	case legacy:
+		enc = pkcs12.LegacyDES
+	}
+
+	if key == nil {
+		return enc.EncodeTrustStore(certs, password)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	ordered := orderLeafFirst(certs, key)
+	if !publicKeysEqual(ordered[0].PublicKey, signer.Public()) {
+		return nil, errors.New("private key does not match any certificate")
+	}
+	return enc.Encode(key, ordered[0], ordered[1:], password)
+}
+
+// orderLeafFirst 把与私钥匹配的证书移到第一位，其余保持原顺序
+func orderLeafFirst(certs []*x509.Certificate, key crypto.PrivateKey) []*x509.Certificate {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return certs
+	}
+	for i, c := range certs {
+		if publicKeysEqual(c.PublicKey, signer.Public()) {
+			out := append([]*x509.Certificate{c}, certs[:i]...)
+			return append(out, certs[i+1:]...)
+		}
+	}
+	return certs
+}
+
+func encodeCertPEM(c *x509.Certificate) string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}))
+}
+
+func encodeKeyPEM(key crypto.PrivateKey, passphrase string) (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", err
+	}
+	block := &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+	if passphrase != "" {
+		encrypted, err := pkcs8.Encrypt(der, passphrase)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encrypted}
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// isPKCS12 判断 DER 是否为 PFX（version 3）
+func isPKCS12(der []byte) bool {
+	var pfx pfxHeader
+	rest, err := asn1.Unmarshal(der, &pfx)
+	return err == nil && len(rest) == 0 && pfx.Version == 3
+}
@@ -0,0 +1,270 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// opensslPKCS7 由 `openssl crl2pkcs7 -nocrl -certfile c.pem -outform DER` 生成，内含一张 CN=fixture.example 的自签名证书
+const opensslPKCS7 = `
+MIIBuQYJKoZIhvcNAQcCoIIBqjCCAaYCAQExADALBgkqhkiG9w0BBwGgggGOMIIB
+ijCCAS+gAwIBAgIUXLCgsVYd7twG4OeejhHyzA4BhI4wCgYIKoZIzj0EAwIwGjEY
+MBYGA1UEAwwPZml4dHVyZS5leGFtcGxlMB4XDTI2MTAxODEwMDU0NVoXDTM2MTAx
+NTEwMDU0NVowGjEYMBYGA1UEAwwPZml4dHVyZS5leGFtcGxlMFkwEwYHKoZIzj0C
+AQYIKoZIzj0DAQcDQgAEBf+cF/OzkN03/NZSJlHVxs+AbuWqPLwpOawQOSvs4WMT
+95JJ56kYFrJTXAWSxRGUWIeAuPDa2AyqC4TdNcWqEKNTMFEwHQYDVR0OBBYEFLTX
+bm/Ar8ePsXbK21QwYYeQMPpxMB8GA1UdIwQYMBaAFLTXbm/Ar8ePsXbK21QwYYeQ
+MPpxMA8GA1UdEwEB/wQFMAMBAf8wCgYIKoZIzj0EAwIDSQAwRgIhAL98glTNj3PY
+6Sx0gqo2eOgPjO5fFYiAD3JOdl8nH4vgAiEAt+IMbcsaewU5Ag2yIXHlfGB1iKXX
+0njJmWHoAXJ4EoIxAA==
+`
+
+// opensslPKCS12 由 `openssl pkcs12 -export -in c.pem -inkey k.pem -passout pass:test` 生成（OpenSSL 3 默认 PBES2 + AES-256-CBC）
+const opensslPKCS12 = `
+MIIEHAIBAzCCA9IGCSqGSIb3DQEHAaCCA8MEggO/MIIDuzCCAnIGCSqGSIb3DQEH
+BqCCAmMwggJfAgEAMIICWAYJKoZIhvcNAQcBMFcGCSqGSIb3DQEFDTBKMCkGCSqG
+SIb3DQEFDDAcBAjkNEz8VLgV2wICCAAwDAYIKoZIhvcNAgkFADAdBglghkgBZQME
+ASoEEK0N9pBpH2WdKPkDW3fQ14WAggHw4BoeiEane2nEnLJSy3AzURz58jWOj6KE
+BUaDksMrKqQtAuumSbzMkxR1XUc/8ScIDJUga569JprRKTWBasEqFO5c9bk36PdW
+GUxuIZrrmZYyw+IB7DQoaB13GhqmDHZS1oxDg3pk37b/AVhhjIoIXHAvhsyKZThw
+61aSMD0puMpEG0ECgo1kf3rHcdVAT5MY88pXr4Ib+snOPVAxE6KrX06Fufrppw7C
+IkD8F6TWqpstNzHgKJLoEirZ/dKb7tdk1wQDS+u2E0ioC1eTQ0g3spVXY8Fqy80L
+VYd2h7I5+Kn0+sc0fgDzTFXHFiGBUJDclpncsS7l6QOP0gBCRZVKw4i5VWz0Iag9
+3KIuiiHRlKoPfcBYlPL3rDKtHILAoCmTlp3pmLhaOLIXWutaCAXHy59ijaYCBvCp
+KQAuKTQTg2TO5Mfemwq7sghr76yA1mfJqHWw6jPaacMhk48SaxVp2x06OQwzBIbw
+Ycq2a4EqObBFsxWvsyRgzr7cWiuHR82d68hQY31i2u4JrJLUU1oVOEXSKuZAY4Q+
+PcNgJfPJjdZ8/b1YCfJ2PcAgLXDIMYRvTAA3nregyD3zIZeDIDQEzLEQfqiPowjF
+og1FUlOAWYMYZn9fMX/x70QIo/x9wEgdGOVry7d8lkiV8uZZlveJ1jCCAUEGCSqG
+SIb3DQEHAaCCATIEggEuMIIBKjCCASYGCyqGSIb3DQEMCgECoIHvMIHsMFcGCSqG
+SIb3DQEFDTBKMCkGCSqGSIb3DQEFDDAcBAh0ZrgJTct76AICCAAwDAYIKoZIhvcN
+AgkFADAdBglghkgBZQMEASoEEDFMu07w3pOeSWAmNjK+rWUEgZBr66R34IVl8HIG
+pWop6jJFmJgywHeXVY4bB7oJ8gxRaAuzhLPJAIq+OorzoF1dawMKSbb+OCljO78F
+Hs5/trpF7aQhyxTir2dsxCNUuqBLfYe7AnU9yYYDUNoVhPKBw/PxLc9ZnWT5AwOT
+GCVH94YnZ3IR/eyZJre0Cvt6GGhBtcW/IJjsZ2aOUQRu8e0zpwQxJTAjBgkqhkiG
+9w0BCRUxFgQUA/8V780oQZz35pd7rPNh4TqysD8wQTAxMA0GCWCGSAFlAwQCAQUA
+BCCA7fX495m6jAWmHjv4l8O8rY/BsFv/nplMob0Pwy+UXQQIQN3ajgtREqMCAggA
+`
+
+func mustBase64(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := DecodeBinaryInput([]byte(s))
+	if err != nil {
+		t.Fatalf("DecodeBinaryInput() error = %v", err)
+	}
+	return b
+}
+
+func TestDetectFormat(t *testing.T) {
+	_, _, leaf := newTestChain(t)
+	p7, err := EncodePKCS7([]*x509.Certificate{leaf.cert})
+	if err != nil {
+		t.Fatalf("EncodePKCS7() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"pem", []byte(leaf.pem), FormatPEM},
+		{"der", leaf.cert.Raw, FormatDER},
+		{"pkcs7", p7, FormatPKCS7},
+		{"openssl pkcs7", mustBase64(t, opensslPKCS7), FormatPKCS7},
+		{"openssl pkcs12", mustBase64(t, opensslPKCS12), FormatPKCS12},
+		{"garbage", []byte("hello"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFormat(tt.data); got != tt.want {
+				t.Errorf("DetectFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertCertOpenSSLInputs(t *testing.T) {
+	p7, err := ConvertCert([]byte(opensslPKCS7), ConvertOptions{Target: FormatPEM})
+	if err != nil {
+		t.Fatalf("ConvertCert(pkcs7) error = %v", err)
+	}
+	if p7.InputFormat != FormatPKCS7 || len(p7.Certs) != 1 || p7.Certs[0].Subject != "CN=fixture.example" {
+		t.Errorf("pkcs7 result = %+v", p7)
+	}
+	if string(p7.Output) != p7.Certs[0].PEM {
+		t.Errorf("pem output = %q", p7.Output)
+	}
+
+	if _, err := ConvertCert([]byte(opensslPKCS12), ConvertOptions{InputPassword: "wrong"}); err == nil {
+		t.Errorf("ConvertCert(pkcs12) with wrong password expected error")
+	}
+
+	p12, err := ConvertCert([]byte(opensslPKCS12), ConvertOptions{InputPassword: "test", Target: FormatPEM})
+	if err != nil {
+		t.Fatalf("ConvertCert(pkcs12) error = %v", err)
+	}
+	if p12.InputFormat != FormatPKCS12 || !p12.HasKey || len(p12.Certs) != 1 {
+		t.Errorf("pkcs12 result = %+v", p12)
+	}
+	if p12.Certs[0].FingerprintSHA256 != p7.Certs[0].FingerprintSHA256 {
+		t.Errorf("pkcs12 and pkcs7 fixtures should contain the same certificate")
+	}
+	block, _ := pem.Decode([]byte(p12.PrivateKeyPEM))
+	if block == nil || block.Type != "PRIVATE KEY" {
+		t.Errorf("private key pem = %q", p12.PrivateKeyPEM)
+	}
+}
+
+func TestConvertCertRoundTrip(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	chainPEM := leaf.pem + inter.pem + root.pem
+	keyDER, err := x509.MarshalPKCS8PrivateKey(leaf.key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+
+	tests := []struct {
+		name      string
+		target    string
+		password  string
+		legacy    bool
+		wantCerts int
+	}{
+		{"pkcs7", FormatPKCS7, "", false, 3},
+		{"der", FormatDER, "", false, 1},
+		{"pkcs12", FormatPKCS12, "secret", false, 3},
+		{"pkcs12 legacy", FormatPKCS12, "secret", true, 3},
+		{"pkcs12 passwordless", FormatPKCS12, "", false, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ConvertCert([]byte(chainPEM), ConvertOptions{
+				Target:         tt.target,
+				OutputPassword: tt.password,
+				KeyPEM:         keyPEM,
+				LegacyPKCS12:   tt.legacy,
+			})
+			if err != nil {
+				t.Fatalf("ConvertCert() error = %v", err)
+			}
+			if out.InputFormat != FormatPEM || len(out.Certs) != 3 || out.OutputFormat != tt.target {
+				t.Fatalf("result = %+v", out)
+			}
+
+			// 以 base64 形式再次输入，应还原出相同的证书
+			back, err := ConvertCert([]byte(base64.StdEncoding.EncodeToString(out.Output)), ConvertOptions{InputPassword: tt.password})
+			if err != nil {
+				t.Fatalf("ConvertCert(output) error = %v", err)
+			}
+			if back.InputFormat != tt.target || len(back.Certs) != tt.wantCerts {
+				t.Fatalf("round trip = %s with %d certs", back.InputFormat, len(back.Certs))
+			}
+			if back.Certs[0].PEM != leaf.pem {
+				t.Errorf("first certificate is not the leaf")
+			}
+			if tt.target == FormatPKCS12 && !back.HasKey {
+				t.Errorf("pkcs12 output lost the private key")
+			}
+		})
+	}
+}
+
+func TestConvertCertPKCS12Errors(t *testing.T) {
+	_, inter, leaf := newTestChain(t)
+	otherDER, err := x509.MarshalPKCS8PrivateKey(inter.key)
+	if err != nil {
+		t.Fatalf("marshal key: %v", err)
+	}
+	otherKey := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherDER}))
+
+	// 私钥只匹配中间证书时，中间证书会被当作叶子
+	out, err := ConvertCert([]byte(leaf.pem+inter.pem), ConvertOptions{Target: FormatPKCS12, OutputPassword: "x", KeyPEM: otherKey})
+	if err != nil {
+		t.Fatalf("ConvertCert() error = %v", err)
+	}
+	back, err := ConvertCert(out.Output, ConvertOptions{InputPassword: "x"})
+	if err != nil || back.Certs[0].PEM != inter.pem {
+		t.Errorf("expected intermediate first, err = %v", err)
+	}
+
+	if _, err := ConvertCert([]byte(leaf.pem), ConvertOptions{Target: FormatPKCS12, OutputPassword: "x", KeyPEM: otherKey}); err == nil {
+		t.Errorf("expected error for mismatched key")
+	}
+	if _, err := ConvertCert([]byte(leaf.pem), ConvertOptions{Target: "jks"}); err == nil {
+		t.Errorf("expected error for unsupported target")
+	}
+	if _, err := ConvertCert([]byte("!!!"), ConvertOptions{}); err == nil {
+		t.Errorf("expected error for garbage input")
+	}
+}
+
+func TestPKCS7RoundTrip(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	der, err := EncodePKCS7([]*x509.Certificate{leaf.cert, inter.cert, root.cert})
+	if err != nil {
+		t.Fatalf("EncodePKCS7() error = %v", err)
+	}
+	certs, err := DecodePKCS7(der)
+	if err != nil {
+		t.Fatalf("DecodePKCS7() error = %v", err)
+	}
+	if len(certs) != 3 || !bytes.Equal(certs[2].Raw, root.cert.Raw) {
+		t.Errorf("DecodePKCS7() returned %d certs", len(certs))
+	}
+	if _, err := EncodePKCS7(nil); err == nil {
+		t.Errorf("EncodePKCS7(nil) expected error")
+	}
+}
+
+// isWhitespaceByte bytes.TrimSpace 会去掉的 ASCII 空白字节
+func isWhitespaceByte(b byte) bool {
+	return b == ' ' || (b >= '\t' && b <= '\r')
+}
+
+// whitespaceTerminatedDER 以 n = 1, 2, ... 调用 create，返回第一份以空白字节结尾的 DER。
+// 密钥由固定种子生成，Ed25519 签名是确定的，模板也不含当前时间，因此每次运行得到同一份数据
+func whitespaceTerminatedDER(t *testing.T, create func(key ed25519.PrivateKey, n int64) ([]byte, error)) []byte {
+	t.Helper()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	for n := int64(1); n <= 4096; n++ {
+		der, err := create(key, n)
+		if err != nil {
+			t.Fatalf("create DER: %v", err)
+		}
+		if isWhitespaceByte(der[len(der)-1]) {
+			return der
+		}
+	}
+	t.Fatalf("no DER ending in a whitespace byte")
+	return nil
+}
+
+func TestConvertCertTrailingWhitespaceByte(t *testing.T) {
+	der := whitespaceTerminatedDER(t, func(key ed25519.PrivateKey, n int64) ([]byte, error) {
+		tmpl := &x509.Certificate{
+			SerialNumber: big.NewInt(n),
+			Subject:      pkix.Name{CommonName: "trailing.example"},
+			NotBefore:    time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			NotAfter:     time.Date(2036, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+		return x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+	})
+
+	res, err := ConvertCert(der, ConvertOptions{Target: FormatDER})
+	if err != nil {
+		t.Fatalf("ConvertCert() error = %v", err)
+	}
+	if res.InputFormat != FormatDER || !bytes.Equal(res.Output, der) {
+		t.Errorf("InputFormat = %s, output equal = %v", res.InputFormat, bytes.Equal(res.Output, der))
+	}
+}
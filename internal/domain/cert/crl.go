@@ -0,0 +1,221 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	domainkey "my-tools/internal/domain/key"
+)
+
+var oidCRLReason = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+// revocationReasons RFC 5280 5.3.1 CRLReason
+var revocationReasons = map[int]string{
+	0:  "unspecified",
+	1:  "keyCompromise",
+	2:  "cACompromise",
+	3:  "affiliationChanged",
+	4:  "superseded",
+	5:  "cessationOfOperation",
+	6:  "certificateHold",
+	8:  "removeFromCRL",
+	9:  "privilegeWithdrawn",
+	10: "aACompromise",
+}
+
+// RevokedEntry CRL 中的一条吊销记录
+type RevokedEntry struct {
+	SerialNumber   string    `json:"serialNumber"`
+	SerialHex      string    `json:"serialHex"`
+	RevocationTime time.Time `json:"revocationTime"`
+	// Reason 未携带 reasonCode 扩展时为空
+	Reason string `json:"reason,omitempty"`
+}
+
+// CRLLookup 指定序列号在 CRL 中的查询结果
+type CRLLookup struct {
+	SerialNumber string        `json:"serialNumber"`
+	SerialHex    string        `json:"serialHex"`
+	Revoked      bool          `json:"revoked"`
+	Entry        *RevokedEntry `json:"entry,omitempty"`
+	// IssuerMismatch 按证书查询时证书的签发者与 CRL 签发者不同，此时序列号不可比较，Revoked 恒为 false
+	IssuerMismatch bool `json:"issuerMismatch"`
+}
+
+// CRLInfo CRL 解析结果
+type CRLInfo struct {
+	Issuer             string     `json:"issuer"`
+	Number             string     `json:"number,omitempty"`
+	ThisUpdate         time.Time  `json:"thisUpdate"`
+	NextUpdate         *time.Time `json:"nextUpdate,omitempty"`
+	SignatureAlgorithm string     `json:"signatureAlgorithm"`
+	AuthorityKeyID     string     `json:"authorityKeyId,omitempty"`
+	// Expired 当前时间已超过 nextUpdate
+	Expired bool `json:"expired"`
+
+	Revoked []RevokedEntry `json:"revoked"`
+	Count   int            `json:"count"`
+
+	SignatureChecked bool   `json:"signatureChecked"`
+	SignatureValid   bool   `json:"signatureValid"`
+	SignatureError   string `json:"signatureError,omitempty"`
+
+	Lookup *CRLLookup `json:"lookup,omitempty"`
+}
+
+// CRLOptions CRL 解析的可选参数
+type CRLOptions struct {
+	// IssuerPEM 填写时校验 CRL 签名
+	IssuerPEM string
+	// CertPEM 或 Serial 填写时检查该序列号是否被吊销，CertPEM 优先
+	CertPEM string
+	// Serial 含 ':'、'0x' 前缀或 a-f 时按十六进制解析，否则按十进制
+	Serial string
+}
+
+// DecodeCRL 解析 PEM / DER / base64 格式的 CRL，列出吊销记录，并可校验签名、查询指定序列号
+func DecodeCRL(data []byte, opts CRLOptions) (CRLInfo, error) {
+	der, err := decodeDERInput(data, "X509 CRL")
+	if err != nil {
+		return CRLInfo{}, err
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		return CRLInfo{}, errors.New("invalid crl: " + err.Error())
+	}
+
+	info := CRLInfo{
+		Issuer:             crl.Issuer.String(),
+		ThisUpdate:         crl.ThisUpdate,
+		SignatureAlgorithm: crl.SignatureAlgorithm.String(),
+		AuthorityKeyID:     domainkey.ColonHex(crl.AuthorityKeyId),
+		Revoked:            make([]RevokedEntry, 0, len(crl.RevokedCertificates)),
+	}
+	if crl.Number != nil {
+		info.Number = crl.Number.String()
+	}
+	if !crl.NextUpdate.IsZero() {
+		next := crl.NextUpdate
+		info.NextUpdate = &next
+		info.Expired = time.Now().After(next)
+	}
+
+	for _, rc := range crl.RevokedCertificates {
+		e := RevokedEntry{
+			SerialNumber:   rc.SerialNumber.String(),
+			SerialHex:      domainkey.ColonHex(rc.SerialNumber.Bytes()),
+			RevocationTime: rc.RevocationTime,
+		}
+		for _, ext := range rc.Extensions {
+			if !ext.Id.Equal(oidCRLReason) {
+				continue
+			}
+			var code asn1.Enumerated
+			if _, err := asn1.Unmarshal(ext.Value, &code); err == nil {
+				e.Reason = revocationReasonName(int(code))
+			}
+		}
+		info.Revoked = append(info.Revoked, e)
+	}
+	info.Count = len(info.Revoked)
+
+	if strings.TrimSpace(opts.IssuerPEM) != "" {
+		issuer, err := parseFirstCert(opts.IssuerPEM)
+		if err != nil {
+			return CRLInfo{}, err
+		}
+		info.SignatureChecked = true
+		if err := crl.CheckSignatureFrom(issuer); err != nil {
+			info.SignatureError = err.Error()
+		} else {
+			info.SignatureValid = true
+		}
+	}
+
+	var serial *big.Int
+	mismatch := false
+	switch {
+	case strings.TrimSpace(opts.CertPEM) != "":
+		c, err := parseFirstCert(opts.CertPEM)
+		if err != nil {
+			return CRLInfo{}, err
+		}
+		serial = c.SerialNumber
+		mismatch = !bytes.Equal(c.RawIssuer, crl.RawIssuer)
+	case strings.TrimSpace(opts.Serial) != "":
+		serial, err = ParseSerial(opts.Serial)
+		if err != nil {
+			return CRLInfo{}, err
+		}
+	}
+	if serial != nil {
+		info.Lookup = &CRLLookup{SerialNumber: serial.String(), SerialHex: domainkey.ColonHex(serial.Bytes()), IssuerMismatch: mismatch}
+		for i, e := range info.Revoked {
+			if !mismatch && e.SerialNumber == serial.String() {
+				info.Lookup.Revoked = true
+				info.Lookup.Entry = &info.Revoked[i]
+				break
+			}
+		}
+	}
+
+	return info, nil
+}
+
+// ParseSerial 解析序列号：含 ':'、'0x' 前缀或 a-f 字母时按十六进制，否则按十进制
+func ParseSerial(s string) (*big.Int, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	hex := strings.HasPrefix(s, "0x") || strings.ContainsAny(s, ":abcdef")
+	s = strings.TrimPrefix(s, "0x")
+	s = strings.NewReplacer(":", "", " ", "").Replace(s)
+
+	base := 10
+	if hex {
+		base = 16
+	}
+	n, ok := new(big.Int).SetString(s, base)
+	if !ok || s == "" {
+		return nil, errors.New("invalid serial number")
+	}
+	return n, nil
+}
+
+// SerialHex 序列号的冒号分隔大写十六进制，与 CRL / OCSP 解析结果中的 serialHex 一致
+func SerialHex(n *big.Int) string {
+	return domainkey.ColonHex(n.Bytes())
+}
+
+// RevocationReasonCode 按 RFC 5280 名称查找 CRLReason 代码，名称为空时返回 unspecified
+func RevocationReasonCode(name string) (int, error) {
+	if name == "" {
+		return 0, nil
+	}
+	for code, n := range revocationReasons {
+		if n == name {
+			return code, nil
+		}
+	}
+	return 0, errors.New("unknown revocation reason: " + name)
+}
+
+func revocationReasonName(code int) string {
+	if name, ok := revocationReasons[code]; ok {
+		return name
+	}
+	return "unknown(" + strconv.Itoa(code) + ")"
+}
+
+// parseFirstCert 解析 PEM 证书（链）中的第一个证书
+func parseFirstCert(input string) (*x509.Certificate, error) {
+	pems, err := SplitCertChain(input)
+	if err != nil {
+		return nil, err
+	}
+	return parseCertPEM(pems[0])
+}
@@ -0,0 +1,169 @@
+package cert
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestCRL(t *testing.T, issuer *testCert, revoked []pkix.RevokedCertificate) []byte {
+	t.Helper()
+	now := time.Now()
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:              big.NewInt(42),
+		ThisUpdate:          now.Add(-time.Hour),
+		NextUpdate:          now.Add(7 * 24 * time.Hour),
+		RevokedCertificates: revoked,
+	}, issuer.cert, issuer.key)
+	if err != nil {
+		t.Fatalf("create crl: %v", err)
+	}
+	return der
+}
+
+func reasonExtension(t *testing.T, code int) pkix.Extension {
+	t.Helper()
+	v, err := asn1.Marshal(asn1.Enumerated(code))
+	if err != nil {
+		t.Fatalf("marshal reason: %v", err)
+	}
+	return pkix.Extension{Id: oidCRLReason, Value: v}
+}
+
+func TestDecodeCRL(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	revokedAt := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	der := newTestCRL(t, inter, []pkix.RevokedCertificate{
+		{SerialNumber: leaf.cert.SerialNumber, RevocationTime: revokedAt, Extensions: []pkix.Extension{reasonExtension(t, 1)}},
+		{SerialNumber: big.NewInt(0xabcdef), RevocationTime: revokedAt},
+	})
+	pemCRL := string(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}))
+	other := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "other.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, inter)
+	// 与已吊销的叶子证书序列号相同，但由根证书签发
+	foreign := newTestCert(t, &x509.Certificate{
+		SerialNumber: leaf.cert.SerialNumber,
+		Subject:      pkix.Name{CommonName: "foreign.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}, root)
+
+	tests := []struct {
+		name        string
+		data        []byte
+		opts        CRLOptions
+		wantRevoked *bool
+		wantSigOK   bool
+		// wantMismatch 证书签发者与 CRL 签发者不同
+		wantMismatch bool
+	}{
+		{name: "der", data: der},
+		{name: "pem with issuer", data: []byte(pemCRL), opts: CRLOptions{IssuerPEM: inter.pem}, wantSigOK: true},
+		{name: "base64 wrong issuer", data: []byte(base64.StdEncoding.EncodeToString(der)), opts: CRLOptions{IssuerPEM: root.pem}},
+		{name: "lookup by cert", data: der, opts: CRLOptions{CertPEM: leaf.pem}, wantRevoked: boolPtr(true)},
+		{name: "lookup by hex serial", data: der, opts: CRLOptions{Serial: "AB:CD:EF"}, wantRevoked: boolPtr(true)},
+		{name: "lookup not revoked", data: der, opts: CRLOptions{CertPEM: other.pem}, wantRevoked: boolPtr(false)},
+		{name: "lookup cert from another issuer", data: der, opts: CRLOptions{CertPEM: foreign.pem}, wantRevoked: boolPtr(false), wantMismatch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCRL(tt.data, tt.opts)
+			if err != nil {
+				t.Fatalf("DecodeCRL() error = %v", err)
+			}
+			if got.Issuer != "CN=Test Intermediate CA" || got.Number != "42" || got.Count != 2 || got.Expired {
+				t.Errorf("crl = %+v", got)
+			}
+			if got.Revoked[0].Reason != "keyCompromise" || !got.Revoked[0].RevocationTime.Equal(revokedAt) || got.Revoked[1].Reason != "" {
+				t.Errorf("revoked = %+v", got.Revoked)
+			}
+			if got.Revoked[1].SerialHex != "AB:CD:EF" {
+				t.Errorf("serial hex = %q", got.Revoked[1].SerialHex)
+			}
+			if got.SignatureChecked != (tt.opts.IssuerPEM != "") || got.SignatureValid != tt.wantSigOK {
+				t.Errorf("signature checked=%v valid=%v err=%q", got.SignatureChecked, got.SignatureValid, got.SignatureError)
+			}
+			if tt.wantRevoked == nil {
+				if got.Lookup != nil {
+					t.Errorf("unexpected lookup %+v", got.Lookup)
+				}
+				return
+			}
+			if got.Lookup == nil || got.Lookup.Revoked != *tt.wantRevoked || (got.Lookup.Entry != nil) != *tt.wantRevoked || got.Lookup.IssuerMismatch != tt.wantMismatch {
+				t.Errorf("lookup = %+v", got.Lookup)
+			}
+		})
+	}
+
+	if _, err := DecodeCRL([]byte(leaf.pem), CRLOptions{}); err == nil {
+		t.Errorf("expected error for certificate input")
+	}
+	if _, err := DecodeCRL(der, CRLOptions{Serial: "xyz"}); err == nil {
+		t.Errorf("expected error for invalid serial")
+	}
+}
+
+func TestParseSerial(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int64
+	}{
+		{"12345", 12345},
+		{"0x3039", 12345},
+		{"30:39", 12345},
+		{"ff", 255},
+	}
+	for _, tt := range tests {
+		got, err := ParseSerial(tt.in)
+		if err != nil || got.Int64() != tt.want {
+			t.Errorf("ParseSerial(%q) = %v, %v, want %d", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseSerial(""); err == nil {
+		t.Errorf("expected error for empty serial")
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func TestDecodeCRLTrailingWhitespaceByte(t *testing.T) {
+	der := whitespaceTerminatedDER(t, func(key ed25519.PrivateKey, n int64) ([]byte, error) {
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "Trailing CRL Issuer"},
+			NotBefore:             time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			NotAfter:              time.Date(2036, 1, 1, 0, 0, 0, 0, time.UTC),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+			KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		}
+		issuerDER, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, key.Public(), key)
+		if err != nil {
+			return nil, err
+		}
+		issuer, err := x509.ParseCertificate(issuerDER)
+		if err != nil {
+			return nil, err
+		}
+		return x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(n),
+			ThisUpdate: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			NextUpdate: time.Date(2026, 1, 8, 0, 0, 0, 0, time.UTC),
+		}, issuer, key)
+	})
+	if _, err := DecodeCRL(der, CRLOptions{}); err != nil {
+		t.Fatalf("DecodeCRL() error = %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domaincsr "my-tools/internal/domain/csr"
+)
+
+// 字段差异类型
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// 比对对象类型
+const (
+	DiffKindCert = "cert"
+	DiffKindCSR  = "csr"
+)
+
+// extensionNames 证书中常见扩展的名称
+var extensionNames = map[string]string{
+	"2.5.29.14":               "subjectKeyIdentifier",
+	"2.5.29.15":               "keyUsage",
+	"2.5.29.17":               "subjectAltName",
+	"2.5.29.19":               "basicConstraints",
+	"2.5.29.30":               "nameConstraints",
+	"2.5.29.31":               "cRLDistributionPoints",
+	"2.5.29.32":               "certificatePolicies",
+	"2.5.29.35":               "authorityKeyIdentifier",
+	"2.5.29.37":               "extKeyUsage",
+	"1.3.6.1.5.5.7.1.1":       "authorityInfoAccess",
+	"1.3.6.1.5.5.7.1.24":      "tlsFeature",
+	"1.3.6.1.4.1.11129.2.4.2": "signedCertificateTimestampList",
+	"1.3.6.1.4.1.11129.2.4.3": "ctPrecertificatePoison",
+}
+
+// FieldChange 单个字段的差异；列表字段用 Added / Removed 给出增减的项
+type FieldChange struct {
+	// Field 字段名，如 subject.CN / san / publicKey / extensions
+	Field   string   `json:"field"`
+	Change  string   `json:"change"`
+	Left    string   `json:"left,omitempty"`
+	Right   string   `json:"right,omitempty"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	// Expected 续期时预期会变化的字段：有效期和序列号
+	Expected bool `json:"expected"`
+}
+
+// CertDiff 两侧同一位置的证书（或 CSR）的比对结果
+type CertDiff struct {
+	Index        int    `json:"index"`
+	LeftKind     string `json:"leftKind,omitempty"`
+	RightKind    string `json:"rightKind,omitempty"`
+	LeftSubject  string `json:"leftSubject,omitempty"`
+	RightSubject string `json:"rightSubject,omitempty"`
+	// Change 只有一侧存在该位置的证书时为 added / removed
+	Change string        `json:"change,omitempty"`
+	Fields []FieldChange `json:"fields"`
+	// Match 除预期变化外没有差异
+	Match bool `json:"match"`
+}
+
+// CertDiffResult 证书比对结果
+type CertDiffResult struct {
+	Pairs []CertDiff `json:"pairs"`
+	Match bool       `json:"match"`
+}
+
+// diffSide 参与比对的一个证书或 CSR
+type diffSide struct {
+	kind      string
+	info      CertInfo
+	subject   pkix.Name
+	publicKey crypto.PublicKey
+	// extensions 扩展名称列表，仅证书有值
+	extensions []string
+}
+
+// DiffCerts 逐字段比对两个证书（链）或 CSR；证书链按位置一一比对。
+// 有一侧是 CSR 时只比对主题、SAN、公钥、签名算法和密钥用途
+func DiffCerts(left, right string) (CertDiffResult, error) {
+	ls, err := parseDiffInput(left)
+	if err != nil {
+		return CertDiffResult{}, errors.New("left: " + err.Error())
+	}
+	rs, err := parseDiffInput(right)
+	if err != nil {
+		return CertDiffResult{}, errors.New("right: " + err.Error())
+	}
+
+	n := len(ls)
+	if len(rs) > n {
+		n = len(rs)
+	}
+	res := CertDiffResult{Pairs: make([]CertDiff, 0, n), Match: true}
+	for i := 0; i < n; i++ {
+		d := CertDiff{Index: i, Fields: []FieldChange{}}
+		var l, r *diffSide
+		if i < len(ls) {
+			l = &ls[i]
+			d.LeftKind, d.LeftSubject = l.kind, l.info.Subject
+		}
+		if i < len(rs) {
+			r = &rs[i]
+			d.RightKind, d.RightSubject = r.kind, r.info.Subject
+		}
+
+		switch {
+		case l == nil:
+			d.Change = DiffAdded
+		case r == nil:
+			d.Change = DiffRemoved
+		default:
+			d.Fields = diffSides(*l, *r)
+			d.Match = true
+			for _, f := range d.Fields {
+				if !f.Expected {
+					d.Match = false
+				}
+			}
+		}
+		if !d.Match {
+			res.Match = false
+		}
+		res.Pairs = append(res.Pairs, d)
+	}
+	return res, nil
+}
+
+// parseDiffInput 解析 CSR 或证书（链）
+func parseDiffInput(input string) ([]diffSide, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, errors.New("input is empty")
+	}
+
+	if strings.Contains(input, "CERTIFICATE REQUEST") {
+		info, err := domaincsr.ParseCSR(input)
+		if err != nil {
+			return nil, err
+		}
+		req, err := domaincsr.ParseCSRPEM(info.PEM)
+		if err != nil {
+			return nil, err
+		}
+		return []diffSide{{
+			kind: DiffKindCSR,
+			info: CertInfo{
+				Subject:            info.Subject,
+				DNSNames:           info.DNSNames,
+				IPAddresses:        info.IPAddresses,
+				EmailAddresses:     info.EmailAddresses,
+				URIs:               info.URIs,
+				KeyUsage:           info.KeyUsage,
+				ExtKeyUsage:        info.ExtKeyUsage,
+				PublicKeyAlgorithm: info.PublicKeyAlgorithm,
+				PublicKeySize:      info.PublicKeySize,
+				PublicKeyCurve:     info.PublicKeyCurve,
+				SignatureAlgorithm: info.SignatureAlgorithm,
+			},
+			subject:   req.Subject,
+			publicKey: req.PublicKey,
+		}}, nil
+	}
+
+	pems, err := SplitCertChain(input)
+	if err != nil {
+		return nil, err
+	}
+	sides := make([]diffSide, 0, len(pems))
+	for _, p := range pems {
+		c, err := parseCertPEM(p)
+		if err != nil {
+			return nil, err
+		}
+		sides = append(sides, diffSide{
+			kind:       DiffKindCert,
+			info:       newCertInfo(p, c),
+			subject:    c.Subject,
+			publicKey:  c.PublicKey,
+			extensions: extensionList(c),
+		})
+	}
+	return sides, nil
+}
+
+func diffSides(l, r diffSide) []FieldChange {
+	changes := []FieldChange{}
+	scalar := func(field, a, b string, expected bool) {
+		if a == b {
+			return
+		}
+		change := DiffChanged
+		switch {
+		case a == "":
+			change = DiffAdded
+		case b == "":
+			change = DiffRemoved
+		}
+		changes = append(changes, FieldChange{Field: field, Change: change, Left: a, Right: b, Expected: expected})
+	}
+	changed := func(field string, removed, added []string) {
+		if len(added) == 0 && len(removed) == 0 {
+			return
+		}
+		change := DiffChanged
+		switch {
+		case len(removed) == 0:
+			change = DiffAdded
+		case len(added) == 0:
+			change = DiffRemoved
+		}
+		changes = append(changes, FieldChange{Field: field, Change: change, Added: added, Removed: removed})
+	}
+	list := func(field string, a, b []string) {
+		removed, added := listDiff(a, b)
+		changed(field, removed, added)
+	}
+
+	for _, f := range subjectFieldValues(l.subject, r.subject) {
+		scalar("subject."+f.name, f.left, f.right, false)
+	}
+	// SAN 中的 DNS 名称不区分大小写
+	removedSANs, addedSANs := sanDiff(infoSANs(l.info), infoSANs(r.info))
+	changed("san", removedSANs, addedSANs)
+	scalar("publicKey", publicKeySummary(l), publicKeySummary(r), false)
+	scalar("signatureAlgorithm", l.info.SignatureAlgorithm, r.info.SignatureAlgorithm, false)
+	list("keyUsage", l.info.KeyUsage, r.info.KeyUsage)
+	list("extKeyUsage", l.info.ExtKeyUsage, r.info.ExtKeyUsage)
+
+	// CSR 中没有签发者、有效期等信息，只在两侧都是证书时比对
+	if l.kind != DiffKindCert || r.kind != DiffKindCert {
+		return changes
+	}
+	scalar("issuer", l.info.Issuer, r.info.Issuer, false)
+	scalar("serialNumber", l.info.SerialNumber, r.info.SerialNumber, true)
+	scalar("notBefore", l.info.NotBefore.UTC().Format(time.RFC3339), r.info.NotBefore.UTC().Format(time.RFC3339), true)
+	scalar("notAfter", l.info.NotAfter.UTC().Format(time.RFC3339), r.info.NotAfter.UTC().Format(time.RFC3339), true)
+	scalar("isCA", strconv.FormatBool(l.info.IsCA), strconv.FormatBool(r.info.IsCA), false)
+	scalar("pathLenConstraint", intPtrString(l.info.PathLenConstraint), intPtrString(r.info.PathLenConstraint), false)
+	scalar("authorityKeyId", l.info.AuthorityKeyID, r.info.AuthorityKeyID, false)
+	list("policies", l.info.PolicyOIDs, r.info.PolicyOIDs)
+	list("crlDistributionPoints", l.info.CRLDistributionPoints, r.info.CRLDistributionPoints)
+	list("ocspServers", l.info.OCSPServers, r.info.OCSPServers)
+	list("issuingCertificateUrl", l.info.IssuingCertificateURL, r.info.IssuingCertificateURL)
+	list("extensions", l.extensions, r.extensions)
+	return changes
+}
+
+type subjectFieldValue struct {
+	name, left, right string
+}
+
+func subjectFieldValues(l, r pkix.Name) []subjectFieldValue {
+	return []subjectFieldValue{
+		{"CN", l.CommonName, r.CommonName},
+		{"O", strings.Join(l.Organization, ", "), strings.Join(r.Organization, ", ")},
+		{"OU", strings.Join(l.OrganizationalUnit, ", "), strings.Join(r.OrganizationalUnit, ", ")},
+		{"L", strings.Join(l.Locality, ", "), strings.Join(r.Locality, ", ")},
+		{"ST", strings.Join(l.Province, ", "), strings.Join(r.Province, ", ")},
+		{"C", strings.Join(l.Country, ", "), strings.Join(r.Country, ", ")},
+	}
+}
+
+func infoSANs(info CertInfo) []string {
+	out := append([]string{}, prefixed("DNS:", info.DNSNames)...)
+	out = append(out, prefixed("IP:", info.IPAddresses)...)
+	out = append(out, prefixed("email:", info.EmailAddresses)...)
+	return append(out, prefixed("URI:", info.URIs)...)
+}
+
+// publicKeySummary 返回“算法 长度/曲线 SPKI SHA-256”，用于判断公钥是否一致
+func publicKeySummary(s diffSide) string {
+	item, err := newMatchItem(s.publicKey)
+	if err != nil {
+		return s.info.PublicKeyAlgorithm
+	}
+	desc := item.PublicKeyAlgorithm + " " + strconv.Itoa(item.PublicKeySize)
+	if s.info.PublicKeyCurve != "" {
+		desc = item.PublicKeyAlgorithm + " " + s.info.PublicKeyCurve
+	}
+	return desc + " SHA256:" + item.PublicKeySHA256
+}
+
+// extensionList 返回证书扩展名称，关键扩展带 (critical) 标记
+func extensionList(c *x509.Certificate) []string {
+	out := make([]string, len(c.Extensions))
+	for i, ext := range c.Extensions {
+		name, ok := extensionNames[ext.Id.String()]
+		if !ok {
+			name = ext.Id.String()
+		}
+		if ext.Critical {
+			name += " (critical)"
+		}
+		out[i] = name
+	}
+	return out
+}
+
+// listDiff 返回只在 a 中、只在 b 中的项（区分大小写）
+func listDiff(a, b []string) (onlyA, onlyB []string) {
+	setA, setB := map[string]bool{}, map[string]bool{}
+	for _, s := range a {
+		setA[s] = true
+	}
+	for _, s := range b {
+		setB[s] = true
+	}
+	onlyA, onlyB = []string{}, []string{}
+	for s := range setA {
+		if !setB[s] {
+			onlyA = append(onlyA, s)
+		}
+	}
+	for s := range setB {
+		if !setA[s] {
+			onlyB = append(onlyB, s)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
+
+func intPtrString(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
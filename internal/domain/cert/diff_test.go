@@ -0,0 +1,128 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func fieldNames(changes []FieldChange) string {
+	names := make([]string, len(changes))
+	for i, c := range changes {
+		names[i] = c.Field
+	}
+	return strings.Join(names, ",")
+}
+
+func TestDiffCerts(t *testing.T) {
+	_, inter, _ := newTestChain(t)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	now := time.Now()
+
+	// issue 使用同一把密钥由中间证书签发，模拟续期
+	issue := func(tmpl *x509.Certificate) string {
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, inter.cert, &key.PublicKey, inter.key)
+		if err != nil {
+			t.Fatalf("create certificate: %v", err)
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	base := func() *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: big.NewInt(100),
+			Subject:      pkix.Name{CommonName: "example.com", Organization: []string{"Example"}},
+			DNSNames:     []string{"example.com", "www.example.com"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(90 * 24 * time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		}
+	}
+	old := issue(base())
+
+	t.Run("renewal", func(t *testing.T) {
+		tmpl := base()
+		tmpl.SerialNumber = big.NewInt(101)
+		tmpl.NotAfter = now.Add(180 * 24 * time.Hour)
+		res, err := DiffCerts(old, issue(tmpl))
+		if err != nil {
+			t.Fatalf("DiffCerts() error = %v", err)
+		}
+		if !res.Match {
+			t.Errorf("renewal should match, got %+v", res.Pairs[0].Fields)
+		}
+		if got := fieldNames(res.Pairs[0].Fields); got != "serialNumber,notAfter" {
+			t.Errorf("fields = %q", got)
+		}
+	})
+
+	t.Run("changed fields", func(t *testing.T) {
+		tmpl := base()
+		tmpl.Subject.Organization = []string{"Other"}
+		tmpl.DNSNames = []string{"example.com", "api.example.com"}
+		tmpl.ExtKeyUsage = nil
+		res, err := DiffCerts(old, issue(tmpl))
+		if err != nil {
+			t.Fatalf("DiffCerts() error = %v", err)
+		}
+		if res.Match {
+			t.Errorf("expected mismatch")
+		}
+		fields := res.Pairs[0].Fields
+		if got := fieldNames(fields); got != "subject.O,san,extKeyUsage,extensions" {
+			t.Fatalf("fields = %q", got)
+		}
+		if san := fields[1]; san.Change != DiffChanged || strings.Join(san.Removed, ",") != "DNS:www.example.com" || strings.Join(san.Added, ",") != "DNS:api.example.com" {
+			t.Errorf("san = %+v", san)
+		}
+		if eku := fields[2]; eku.Change != DiffRemoved {
+			t.Errorf("extKeyUsage change = %q", eku.Change)
+		}
+	})
+
+	t.Run("chain length", func(t *testing.T) {
+		res, err := DiffCerts(old, old+inter.pem)
+		if err != nil {
+			t.Fatalf("DiffCerts() error = %v", err)
+		}
+		if len(res.Pairs) != 2 || res.Pairs[1].Change != DiffAdded || res.Match {
+			t.Errorf("unexpected result %+v", res)
+		}
+	})
+
+	t.Run("csr", func(t *testing.T) {
+		der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+			Subject:  pkix.Name{CommonName: "example.com", Organization: []string{"Example"}},
+			DNSNames: []string{"example.com", "www.example.com"},
+		}, key)
+		if err != nil {
+			t.Fatalf("create csr: %v", err)
+		}
+		csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+		res, err := DiffCerts(csrPEM, old)
+		if err != nil {
+			t.Fatalf("DiffCerts() error = %v", err)
+		}
+		// CSR 不带密钥用途，证书中由 CA 补充；签名算法相同，公钥一致
+		if got := fieldNames(res.Pairs[0].Fields); got != "keyUsage,extKeyUsage" {
+			t.Errorf("fields = %q", got)
+		}
+		if res.Pairs[0].LeftKind != DiffKindCSR || res.Pairs[0].RightKind != DiffKindCert {
+			t.Errorf("kinds = %s, %s", res.Pairs[0].LeftKind, res.Pairs[0].RightKind)
+		}
+	})
+
+	if _, err := DiffCerts("", old); err == nil || !strings.HasPrefix(err.Error(), "left:") {
+		t.Errorf("expected left error, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package cert
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"fmt"
+
+	domainkey "my-tools/internal/domain/key"
+)
+
+// CA/Browser Forum 定义的证书策略 OID
+var (
+	oidPolicyEV = asn1.ObjectIdentifier{2, 23, 140, 1, 1}
+	oidPolicyDV = asn1.ObjectIdentifier{2, 23, 140, 1, 2, 1}
+	oidPolicyOV = asn1.ObjectIdentifier{2, 23, 140, 1, 2, 2}
+	oidPolicyIV = asn1.ObjectIdentifier{2, 23, 140, 1, 2, 3}
+)
+
+var keyUsageNames = []struct {
+	usage x509.KeyUsage
+	name  string
+}{
+	{x509.KeyUsageDigitalSignature, "Digital Signature"},
+	{x509.KeyUsageContentCommitment, "Content Commitment"},
+	{x509.KeyUsageKeyEncipherment, "Key Encipherment"},
+	{x509.KeyUsageDataEncipherment, "Data Encipherment"},
+	{x509.KeyUsageKeyAgreement, "Key Agreement"},
+	{x509.KeyUsageCertSign, "Certificate Sign"},
+	{x509.KeyUsageCRLSign, "CRL Sign"},
+	{x509.KeyUsageEncipherOnly, "Encipher Only"},
+	{x509.KeyUsageDecipherOnly, "Decipher Only"},
+}
+
+var extKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "Any",
+	x509.ExtKeyUsageServerAuth:                     "TLS Web Server Authentication",
+	x509.ExtKeyUsageClientAuth:                     "TLS Web Client Authentication",
+	x509.ExtKeyUsageCodeSigning:                    "Code Signing",
+	x509.ExtKeyUsageEmailProtection:                "E-mail Protection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "IPSec End System",
+	x509.ExtKeyUsageIPSECTunnel:                    "IPSec Tunnel",
+	x509.ExtKeyUsageIPSECUser:                      "IPSec User",
+	x509.ExtKeyUsageTimeStamping:                   "Time Stamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSP Signing",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "Microsoft Server Gated Crypto",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "Netscape Server Gated Crypto",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "Microsoft Commercial Code Signing",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "Microsoft Kernel Code Signing",
+}
+
+// KeyUsageNames 返回 KeyUsage 中置位的用途名称
+func KeyUsageNames(ku x509.KeyUsage) []string {
+	names := []string{}
+	for _, k := range keyUsageNames {
+		if ku&k.usage != 0 {
+			names = append(names, k.name)
+		}
+	}
+	return names
+}
+
+// ExtKeyUsageNames 返回扩展密钥用途名称，未知用途按 OID 输出
+func ExtKeyUsageNames(ekus []x509.ExtKeyUsage, unknown []asn1.ObjectIdentifier) []string {
+	names := make([]string, 0, len(ekus)+len(unknown))
+	for _, e := range ekus {
+		if n, ok := extKeyUsageNames[e]; ok {
+			names = append(names, n)
+		} else {
+			names = append(names, fmt.Sprintf("Unknown (%d)", e))
+		}
+	}
+	for _, oid := range unknown {
+		names = append(names, oid.String())
+	}
+	return names
+}
+
+// ValidationLevel 根据证书策略 OID 判断验证级别（DV/OV/IV/EV），无法判断时返回空字符串
+func ValidationLevel(policies []asn1.ObjectIdentifier) string {
+	for _, p := range policies {
+		switch {
+		case p.Equal(oidPolicyEV):
+			return "EV"
+		case p.Equal(oidPolicyOV):
+			return "OV"
+		case p.Equal(oidPolicyIV):
+			return "IV"
+		case p.Equal(oidPolicyDV):
+			return "DV"
+		}
+	}
+	return ""
+}
+
+// fingerprint 计算 DER 的指纹，输出为冒号分隔的大写十六进制
+func fingerprint(der []byte, algorithm string) string {
+	switch algorithm {
+	case "sha1":
+		sum := sha1.Sum(der)
+		return domainkey.ColonHex(sum[:])
+	default:
+		sum := sha256.Sum256(der)
+		return domainkey.ColonHex(sum[:])
+	}
+}
+
+// pathLenConstraint 返回 BasicConstraints 中的路径长度限制，未限制时返回 nil
+func pathLenConstraint(cert *x509.Certificate) *int {
+	if !cert.BasicConstraintsValid || !cert.IsCA {
+		return nil
+	}
+	if cert.MaxPathLen > 0 || (cert.MaxPathLen == 0 && cert.MaxPathLenZero) {
+		n := cert.MaxPathLen
+		return &n
+	}
+	return nil
+}
+
+func oidStrings(oids []asn1.ObjectIdentifier) []string {
+	out := make([]string, len(oids))
+	for i, oid := range oids {
+		out[i] = oid.String()
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitCertChainWithInfoFields(t *testing.T) {
+	_, inter, leaf := newTestChain(t)
+
+	got, err := SplitCertChainWithInfo(leaf.pem + inter.pem)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d certs, want 2", len(got))
+	}
+
+	l := got[0]
+	if !reflect.DeepEqual(l.DNSNames, []string{"example.com", "www.example.com"}) {
+		t.Errorf("dnsNames = %v", l.DNSNames)
+	}
+	if !reflect.DeepEqual(l.KeyUsage, []string{"Digital Signature"}) {
+		t.Errorf("keyUsage = %v", l.KeyUsage)
+	}
+	if !reflect.DeepEqual(l.ExtKeyUsage, []string{"TLS Web Server Authentication"}) {
+		t.Errorf("extKeyUsage = %v", l.ExtKeyUsage)
+	}
+	if l.PublicKeyAlgorithm != "ECDSA" || l.PublicKeySize != 256 || l.PublicKeyCurve != "P-256" {
+		t.Errorf("public key = %s %d %s", l.PublicKeyAlgorithm, l.PublicKeySize, l.PublicKeyCurve)
+	}
+	if l.SignatureAlgorithm != "ECDSA-SHA256" {
+		t.Errorf("signatureAlgorithm = %s", l.SignatureAlgorithm)
+	}
+	if len(l.FingerprintSHA256) != 32*3-1 || len(l.FingerprintSHA1) != 20*3-1 {
+		t.Errorf("fingerprints = %s / %s", l.FingerprintSHA1, l.FingerprintSHA256)
+	}
+	if l.AuthorityKeyID == "" || l.AuthorityKeyID != got[1].SubjectKeyID {
+		t.Errorf("aki = %q, issuer ski = %q", l.AuthorityKeyID, got[1].SubjectKeyID)
+	}
+	if l.PathLenConstraint != nil {
+		t.Errorf("leaf pathLenConstraint = %v, want nil", *l.PathLenConstraint)
+	}
+}
+
+func TestSplitCertChainWithInfoRealCert(t *testing.T) {
+	const leaf = `-----BEGIN CERTIFICATE-----\nMIIE7DCCA9SgAwIBAgIFYQMWRggwDQYJKoZIhvcNAQELBQAwXTELMAkGA1UEBhMC\nQ04xMDAuBgNVBAoMJ0NoaW5hIEZpbmFuY2lhbCBDZXJ0aWZpY2F0aW9uIEF1dGhv\ncml0eTEcMBoGA1UEAwwTQ0ZDQSBBQ1MgVEVTVCBPQ0EzMTAeFw0yNTEyMTkwNjE1\nMDBaFw0yNjEyMTkwNjE0NTlaMIGPMQswCQYDVQQGEwJDTjERMA8GA1UECAwIc2hh\nbmdoYWkxETAPBgNVBAcMCHNoYW5naGFpMUIwQAYDVQQKDDnkuprmlbDkv6Hmga/n\np5HmioDvvIjkuIrmtbfvvInmnInpmZDlhazlj7jvvIhPViDmtYvor5XvvIkxFjAU\nBgNVBAMMDWxldHNmaXJlLmNsdWIwggEiMA0GCSqGSIb3DQEBAQUAA4IBDwAwggEK\nAoIBAQDTiBo16FXy4RSOE4tMyZXeX8np/4D031gHPYpX2QgxyqlYMrHQQ6ky+e1j\nW4WM8SeRSL1XmlY1xQqB3QhBz5LSo38dY2OFKjzERIAt+Gdp7cJq/SpTWO6ayccL\noQaI7nRlk247719ZWoGoZUDlQCEJqzFHX8UZSXi/hbPRLKnU45a2TbvLZK7Rfbga\njCoXOmO9RSZ33RgsosxbDd5w3+3PRP9NDFEe4Kvu9q0Hn6ECM9MyyQYyNOaoq4tC\nx2vuOBg4Yb3+PO1aLZqeCTTXTB5EQICfzX36cvrIlN2hVXoo3EZxS18cYWVAuvDR\nruiumFtZT2oncYRWT9Fc5NmdFTaTAgMBAAGjggF+MIIBejAJBgNVHRMEAjAAMD8G\nCCsGAQUFBwEBBDMwMTAvBggrBgEFBQcwAYYjaHR0cDovL29jc3B0ZXN0LmNmY2Eu\nY29tLmNuOjgwL29jc3AwDwYJKwYBBQUHMAEFBAIFADArBgNVHREEJDAigg1sZXRz\nZmlyZS5jbHVighF3d3cubGV0c2ZpcmUuY2x1YjALBgNVHQ8EBAMCBaAwHQYDVR0O\nBBYEFHM0jzyU3rJ3KgQ5feVrWXWaV6sCMB0GA1UdJQQWMBQGCCsGAQUFBwMCBggr\nBgEFBQcDATAfBgNVHSMEGDAWgBSaPbSuZVj7zloFeCagbSsEhrrG7DBIBgNVHSAE\nQTA/MD0GCGCBHIbvKgEEMDEwLwYIKwYBBQUHAgEWI2h0dHA6Ly93d3cuY2ZjYS5j\nb20uY24vdXMvdXMtMTQuaHRtMDgGA1UdHwQxMC8wLaAroCmGJ2h0dHA6Ly8yMTAu\nNzQuNDIuMy9PQ0EzMS9SU0EvY3JsNDI4LmNybDANBgkqhkiG9w0BAQsFAAOCAQEA\no3vaOLObputX7T4vAbJSwBBnsi7Gkdz2/zJcY3hOIXXaz5i5KddL6l4I7MKIChn5\nt/2s1XZv0kKjqo73I4sV2+Zz2G3H3FMROHyQ6SX8q90XnkXvT1AaQVxHFoqDR8eH\nPGOBxrgOrcpTk6um+MDA1BElyTQmivOMvEj3+hB2BMQAE9SixW3ZcuSNPH6aQtFK\nM+oa5HVSfKELFCygSgoWlP2YFkJD/QnJ3rQG+CyPQBAw1kaIqPKTyUQ4s1SYQmej\nVt7npsv4IHG+h9eRlwDL8b4AZdQgzniq/lqfhjXnWog5+OlhclIfTOJl1TqXtdMm\nJL/B/kd/zicUx3/BfRzCVQ==\n-----END CERTIFICATE-----`
+
+	got, err := SplitCertChainWithInfo(leaf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := got[0]
+	if !reflect.DeepEqual(c.DNSNames, []string{"letsfire.club", "www.letsfire.club"}) {
+		t.Errorf("dnsNames = %v", c.DNSNames)
+	}
+	if !reflect.DeepEqual(c.OCSPServers, []string{"http://ocsptest.cfca.com.cn:80/ocsp"}) {
+		t.Errorf("ocspServers = %v", c.OCSPServers)
+	}
+	if !reflect.DeepEqual(c.CRLDistributionPoints, []string{"http://210.74.42.3/OCA31/RSA/crl428.crl"}) {
+		t.Errorf("crlDistributionPoints = %v", c.CRLDistributionPoints)
+	}
+	if c.PublicKeyAlgorithm != "RSA" || c.PublicKeySize != 2048 {
+		t.Errorf("public key = %s %d", c.PublicKeyAlgorithm, c.PublicKeySize)
+	}
+	if !strings.HasPrefix(c.SubjectKeyID, "73:34:8F:3C") {
+		t.Errorf("subjectKeyId = %s", c.SubjectKeyID)
+	}
+	if len(c.PolicyOIDs) != 1 {
+		t.Errorf("policyOids = %v", c.PolicyOIDs)
+	}
+}
+
+func TestValidationLevel(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []asn1.ObjectIdentifier
+		want     string
+	}{
+		{"none", nil, ""},
+		{"dv", []asn1.ObjectIdentifier{oidPolicyDV}, "DV"},
+		{"ov", []asn1.ObjectIdentifier{{1, 2, 3}, oidPolicyOV}, "OV"},
+		{"ev", []asn1.ObjectIdentifier{oidPolicyEV}, "EV"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ValidationLevel(tt.policies); got != tt.want {
+				t.Errorf("ValidationLevel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeyUsageNames(t *testing.T) {
+	got := KeyUsageNames(x509.KeyUsageCertSign | x509.KeyUsageCRLSign)
+	want := []string{"Certificate Sign", "CRL Sign"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("KeyUsageNames() = %v, want %v", got, want)
+	}
+}
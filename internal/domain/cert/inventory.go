@@ -0,0 +1,172 @@
+package cert
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 到期状态
+const (
+	ExpiryStatusOK       = "ok"
+	ExpiryStatusWarning  = "warning"
+	ExpiryStatusCritical = "critical"
+	ExpiryStatusExpired  = "expired"
+)
+
+// ExpiryThresholds 到期告警阈值（天）
+type ExpiryThresholds struct {
+	WarningDays  int `json:"warningDays"`
+	CriticalDays int `json:"criticalDays"`
+}
+
+// DefaultExpiryThresholds 默认 30 天告警、7 天紧急
+var DefaultExpiryThresholds = ExpiryThresholds{WarningDays: 30, CriticalDays: 7}
+
+// Validate 校验阈值：非负且紧急阈值不大于告警阈值
+func (t ExpiryThresholds) Validate() error {
+	if t.WarningDays < 0 || t.CriticalDays < 0 {
+		return errors.New("thresholds must not be negative")
+	}
+	if t.CriticalDays > t.WarningDays {
+		return errors.New("criticalDays must not be greater than warningDays")
+	}
+	return nil
+}
+
+// InventoryEntry 清单中保存的一张证书
+type InventoryEntry struct {
+	ID string
+	// Label 为空时清单中显示证书主题
+	Label   string
+	PEM     string
+	AddedAt time.Time
+}
+
+// InventoryItem 带到期信息的清单条目
+type InventoryItem struct {
+	ID           string    `json:"id"`
+	Label        string    `json:"label"`
+	AddedAt      time.Time `json:"addedAt"`
+	DaysToExpiry int       `json:"daysToExpiry"`
+	Status       string    `json:"status"`
+	Cert         CertInfo  `json:"cert"`
+}
+
+// NewInventoryEntry 解析证书（证书链取第一个）生成清单条目，ID 为 SHA-256 指纹；
+// 不填标签时 Label 留空，由 BuildInventory 显示为证书主题，再次保存时不会覆盖已有的自定义标签
+func NewInventoryEntry(certPEM, label string, now time.Time) (InventoryEntry, error) {
+	pems, err := SplitCertChain(certPEM)
+	if err != nil {
+		return InventoryEntry{}, err
+	}
+	info, err := parseCertInfo(pems[0])
+	if err != nil {
+		return InventoryEntry{}, err
+	}
+
+	return InventoryEntry{
+		ID:      strings.ToLower(strings.ReplaceAll(info.FingerprintSHA256, ":", "")),
+		Label:   strings.TrimSpace(label),
+		PEM:     pems[0],
+		AddedAt: now,
+	}, nil
+}
+
+// BuildInventory 计算每张证书的剩余天数和状态，按到期时间升序（最紧急的在前）排列
+func BuildInventory(entries []InventoryEntry, thresholds ExpiryThresholds, now time.Time) ([]InventoryItem, error) {
+	if err := thresholds.Validate(); err != nil {
+		return nil, err
+	}
+
+	items := make([]InventoryItem, 0, len(entries))
+	for _, e := range entries {
+		info, err := parseCertInfo(e.PEM)
+		if err != nil {
+			return nil, errors.New("inventory entry " + e.ID + ": " + err.Error())
+		}
+		days := DaysToExpiry(info.NotAfter, now)
+		label := e.Label
+		if label == "" {
+			label = info.Subject
+		}
+		items = append(items, InventoryItem{
+			ID:           e.ID,
+			Label:        label,
+			AddedAt:      e.AddedAt,
+			DaysToExpiry: days,
+			Status:       ExpiryStatus(days, thresholds),
+			Cert:         info,
+		})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if !items[i].Cert.NotAfter.Equal(items[j].Cert.NotAfter) {
+			return items[i].Cert.NotAfter.Before(items[j].Cert.NotAfter)
+		}
+		return items[i].Label < items[j].Label
+	})
+	return items, nil
+}
+
+// DaysToExpiry 距到期的天数（向下取整），已过期为负数
+func DaysToExpiry(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
+
+// ExpiryStatus 根据剩余天数和阈值给出状态
+func ExpiryStatus(days int, t ExpiryThresholds) string {
+	switch {
+	case days < 0:
+		return ExpiryStatusExpired
+	case days <= t.CriticalDays:
+		return ExpiryStatusCritical
+	case days <= t.WarningDays:
+		return ExpiryStatusWarning
+	default:
+		return ExpiryStatusOK
+	}
+}
+
+// WriteInventoryCSV 以 CSV 导出清单
+func WriteInventoryCSV(w io.Writer, items []InventoryItem) error {
+	cw := csv.NewWriter(w)
+	header := []string{"id", "label", "status", "daysToExpiry", "notAfter", "notBefore", "subject", "issuer", "serialNumber", "dnsNames", "fingerprintSha256", "addedAt"}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, it := range items {
+		row := []string{
+			it.ID,
+			csvText(it.Label),
+			it.Status,
+			strconv.Itoa(it.DaysToExpiry),
+			it.Cert.NotAfter.UTC().Format(time.RFC3339),
+			it.Cert.NotBefore.UTC().Format(time.RFC3339),
+			csvText(it.Cert.Subject),
+			csvText(it.Cert.Issuer),
+			it.Cert.SerialNumber,
+			strings.Join(it.Cert.DNSNames, ";"),
+			it.Cert.FingerprintSHA256,
+			it.AddedAt.UTC().Format(time.RFC3339),
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText 防止以 = + - @ 开头的文本在表格软件中被当作公式执行
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
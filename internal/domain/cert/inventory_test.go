@@ -0,0 +1,158 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/csv"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newExpiringCert(t *testing.T, cn string, notAfter time.Time) *testCert {
+	t.Helper()
+	return newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		DNSNames:     []string{cn},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}, nil)
+}
+
+func TestExpiryStatus(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{-1, ExpiryStatusExpired},
+		{0, ExpiryStatusCritical},
+		{7, ExpiryStatusCritical},
+		{8, ExpiryStatusWarning},
+		{30, ExpiryStatusWarning},
+		{31, ExpiryStatusOK},
+	}
+
+	for _, tt := range tests {
+		if got := ExpiryStatus(tt.days, DefaultExpiryThresholds); got != tt.want {
+			t.Errorf("ExpiryStatus(%d) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
+
+func TestDaysToExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notAfter time.Time
+		want     int
+	}{
+		{now.Add(36 * time.Hour), 1},
+		{now.Add(23 * time.Hour), 0},
+		{now.Add(-time.Hour), -1},
+		{now.Add(-49 * time.Hour), -3},
+	}
+
+	for _, tt := range tests {
+		if got := DaysToExpiry(tt.notAfter, now); got != tt.want {
+			t.Errorf("DaysToExpiry(%v) = %d, want %d", tt.notAfter, got, tt.want)
+		}
+	}
+}
+
+func TestBuildInventory(t *testing.T) {
+	now := time.Now()
+	later := newExpiringCert(t, "later.example.com", now.Add(90*24*time.Hour))
+	soon := newExpiringCert(t, "soon.example.com", now.Add(20*24*time.Hour+time.Hour))
+	expired := newExpiringCert(t, "expired.example.com", now.Add(-2*24*time.Hour))
+
+	var entries []InventoryEntry
+	for _, c := range []*testCert{later, soon, expired} {
+		e, err := NewInventoryEntry(c.pem, "", now)
+		if err != nil {
+			t.Fatalf("NewInventoryEntry() error = %v", err)
+		}
+		entries = append(entries, e)
+	}
+	if entries[0].Label != "" || len(entries[0].ID) != 64 {
+		t.Errorf("entry = %+v", entries[0])
+	}
+
+	items, err := BuildInventory(entries, DefaultExpiryThresholds, now)
+	if err != nil {
+		t.Fatalf("BuildInventory() error = %v", err)
+	}
+
+	var got []string
+	for _, it := range items {
+		got = append(got, it.Cert.DNSNames[0]+"/"+it.Status)
+	}
+	want := []string{"expired.example.com/expired", "soon.example.com/warning", "later.example.com/ok"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("order = %v, want %v", got, want)
+	}
+	if items[1].DaysToExpiry != 20 {
+		t.Errorf("DaysToExpiry = %d, want 20", items[1].DaysToExpiry)
+	}
+	// 未填标签时显示证书主题
+	if items[2].Label != "CN=later.example.com" {
+		t.Errorf("label = %q, want subject", items[2].Label)
+	}
+
+	// 阈值可配置
+	items, err = BuildInventory(entries, ExpiryThresholds{WarningDays: 60, CriticalDays: 21}, now)
+	if err != nil {
+		t.Fatalf("BuildInventory() error = %v", err)
+	}
+	if items[1].Status != ExpiryStatusCritical {
+		t.Errorf("status = %q, want critical", items[1].Status)
+	}
+
+	if _, err := BuildInventory(entries, ExpiryThresholds{WarningDays: 7, CriticalDays: 30}, now); err == nil {
+		t.Errorf("expected error for criticalDays > warningDays")
+	}
+}
+
+func TestNewInventoryEntryChain(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	e, err := NewInventoryEntry(leaf.pem+inter.pem+root.pem, "  customer A  ", time.Now())
+	if err != nil {
+		t.Fatalf("NewInventoryEntry() error = %v", err)
+	}
+	if e.PEM != leaf.pem || e.Label != "customer A" {
+		t.Errorf("entry = %+v", e)
+	}
+
+	if _, err := NewInventoryEntry("not a cert", "", time.Now()); err == nil {
+		t.Errorf("expected error for invalid certificate")
+	}
+}
+
+func TestWriteInventoryCSV(t *testing.T) {
+	now := time.Now()
+	c := newExpiringCert(t, "csv.example.com", now.Add(10*24*time.Hour+time.Hour))
+	e, err := NewInventoryEntry(c.pem, "=HYPERLINK(\"x\")", now)
+	if err != nil {
+		t.Fatalf("NewInventoryEntry() error = %v", err)
+	}
+	items, err := BuildInventory([]InventoryEntry{e}, DefaultExpiryThresholds, now)
+	if err != nil {
+		t.Fatalf("BuildInventory() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteInventoryCSV(&buf, items); err != nil {
+		t.Fatalf("WriteInventoryCSV() error = %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	if len(rows) != 2 || rows[0][0] != "id" {
+		t.Fatalf("rows = %v", rows)
+	}
+	if rows[1][1] != "'=HYPERLINK(\"x\")" || rows[1][2] != ExpiryStatusWarning || rows[1][3] != "10" || rows[1][9] != "csv.example.com" {
+		t.Errorf("row = %v", rows[1])
+	}
+}
@@ -0,0 +1,233 @@
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	domainkey "my-tools/internal/domain/key"
+	"my-tools/internal/domain/pemscan"
+)
+
+// JWKOptions 证书 / 公钥转换为 JWK 的可选参数
+type JWKOptions struct {
+	// Use sig / enc，为空时省略
+	Use string
+	// Alg 原样写入每个 JWK，例如 RS256 / ES256，为空时省略
+	Alg string
+}
+
+// JWKInfo JWK Set 中单个密钥的解析结果
+type JWKInfo struct {
+	Kid string `json:"kid,omitempty"`
+	Kty string `json:"kty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	// PublicKeySHA256 SubjectPublicKeyInfo 的 SHA-256，与证书解析结果一致
+	PublicKeySHA256 string `json:"publicKeySha256"`
+	// Thumbprint RFC 7638 指纹
+	Thumbprint   string `json:"thumbprint"`
+	PublicKeyPEM string `json:"publicKeyPem"`
+	// Private JWK 带有私钥参数，输出仍只包含公钥
+	Private bool `json:"private"`
+
+	// Certs x5c 中的证书，叶子证书在前
+	Certs []CertInfo `json:"certs"`
+	// Warnings x5c / x5t#S256 与密钥不一致等问题
+	Warnings []string `json:"warnings"`
+}
+
+// CertsToJWKS 将 PEM 证书、证书链和公钥转换为 JWK Set；证书按签发关系分组，
+// 每条链生成一个 JWK，x5c 包含整条链，x5t#S256 取叶子证书指纹，kid 取 RFC 7638 指纹
+func CertsToJWKS(input string, opts JWKOptions) (domainkey.JWKS, error) {
+	if opts.Use != "" && opts.Use != "sig" && opts.Use != "enc" {
+		return domainkey.JWKS{}, errors.New("use must be sig or enc")
+	}
+
+	certs, pubs, err := decodeJWKSInput(input)
+	if err != nil {
+		return domainkey.JWKS{}, err
+	}
+	if len(certs) == 0 && len(pubs) == 0 {
+		return domainkey.JWKS{}, errors.New("no certificates or public keys found")
+	}
+
+	set := domainkey.JWKS{Keys: []domainkey.JWK{}}
+	for len(certs) > 0 {
+		order := buildPath(certs)
+		chain := make([]*x509.Certificate, len(order))
+		used := map[int]bool{}
+		for i, idx := range order {
+			chain[i] = certs[idx]
+			used[idx] = true
+		}
+
+		jwk, err := certChainJWK(chain, opts)
+		if err != nil {
+			return domainkey.JWKS{}, errors.New(chain[0].Subject.String() + ": " + err.Error())
+		}
+		set.Keys = append(set.Keys, jwk)
+
+		var rest []*x509.Certificate
+		for i, c := range certs {
+			if !used[i] {
+				rest = append(rest, c)
+			}
+		}
+		certs = rest
+	}
+
+	for _, pub := range pubs {
+		jwk, err := domainkey.NewJWK(pub)
+		if err != nil {
+			return domainkey.JWKS{}, err
+		}
+		jwk.Use, jwk.Alg = opts.Use, opts.Alg
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set, nil
+}
+
+// decodeJWKSInput 取出 PEM 中的证书和公钥；私钥不会写入 JWKS，直接报错
+func decodeJWKSInput(input string) ([]*x509.Certificate, []crypto.PublicKey, error) {
+	var certs []*x509.Certificate
+	var pubs []crypto.PublicKey
+	for _, block := range pemscan.Scan(input).Blocks {
+		if block.Error != "" && (block.Type == "CERTIFICATE" || strings.HasSuffix(block.Type, "PUBLIC KEY")) {
+			return nil, nil, fmt.Errorf("%s at line %d: %s", block.Type, block.Line, block.Error)
+		}
+		switch {
+		case block.Type == "CERTIFICATE":
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			certs = append(certs, c)
+		case block.Type == "PUBLIC KEY":
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			pubs = append(pubs, pub)
+		case block.Type == "RSA PUBLIC KEY":
+			pub, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, nil, err
+			}
+			pubs = append(pubs, pub)
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			return nil, nil, errors.New("private keys are not allowed, provide certificates or public keys")
+		}
+	}
+	return certs, pubs, nil
+}
+
+func certChainJWK(chain []*x509.Certificate, opts JWKOptions) (domainkey.JWK, error) {
+	jwk, err := domainkey.NewJWK(chain[0].PublicKey)
+	if err != nil {
+		return domainkey.JWK{}, err
+	}
+	jwk.Use, jwk.Alg = opts.Use, opts.Alg
+	for _, c := range chain {
+		jwk.X5c = append(jwk.X5c, base64.StdEncoding.EncodeToString(c.Raw))
+	}
+	sum := sha256.Sum256(chain[0].Raw)
+	jwk.X5tS256 = base64.RawURLEncoding.EncodeToString(sum[:])
+	return jwk, nil
+}
+
+// JWKSToPEM 解析 JWK Set 或单个 JWK，给出每个密钥的 PEM 公钥、x5c 证书，
+// 并检查 x5c 叶子证书与密钥、x5t#S256 是否一致
+func JWKSToPEM(input string) ([]JWKInfo, error) {
+	in := strings.TrimSpace(input)
+	if in == "" {
+		return nil, errors.New("jwks is empty")
+	}
+	set, err := domainkey.ParseJWKS([]byte(in))
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]JWKInfo, 0, len(set.Keys))
+	for i, j := range set.Keys {
+		info, err := newJWKInfo(j)
+		if err != nil {
+			return nil, errors.New("key " + strconv.Itoa(i+1) + ": " + err.Error())
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func newJWKInfo(j domainkey.JWK) (JWKInfo, error) {
+	k, err := j.Key()
+	if err != nil {
+		return JWKInfo{}, err
+	}
+	pub := k
+	if s, ok := k.(crypto.Signer); ok {
+		pub = s.Public()
+	}
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return JWKInfo{}, err
+	}
+
+	alg, size, curve := domainkey.PublicKeyInfo(pub)
+	sum := sha256.Sum256(spki)
+	info := JWKInfo{
+		Kid:                j.Kid,
+		Kty:                j.Kty,
+		Use:                j.Use,
+		Alg:                j.Alg,
+		PublicKeyAlgorithm: alg,
+		PublicKeySize:      size,
+		PublicKeyCurve:     curve,
+		PublicKeySHA256:    domainkey.ColonHex(sum[:]),
+		Thumbprint:         j.Thumbprint(),
+		PublicKeyPEM:       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})),
+		Private:            j.IsPrivate(),
+		Certs:              []CertInfo{},
+		Warnings:           []string{},
+	}
+	if info.Private {
+		info.Warnings = append(info.Warnings, "jwk contains private key parameters")
+	}
+
+	for i, s := range j.X5c {
+		der, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return JWKInfo{}, errors.New("x5c[" + strconv.Itoa(i) + "]: invalid base64")
+		}
+		c, err := x509.ParseCertificate(der)
+		if err != nil {
+			return JWKInfo{}, errors.New("x5c[" + strconv.Itoa(i) + "]: " + err.Error())
+		}
+		info.Certs = append(info.Certs, newCertInfo(encodeCertPEM(c), c))
+
+		if i > 0 {
+			continue
+		}
+		if !publicKeysEqual(c.PublicKey, pub) {
+			info.Warnings = append(info.Warnings, "x5c[0] public key does not match the jwk")
+		}
+		if j.X5tS256 != "" {
+			leafSum := sha256.Sum256(der)
+			if t, err := base64.RawURLEncoding.DecodeString(j.X5tS256); err != nil || !bytes.Equal(t, leafSum[:]) {
+				info.Warnings = append(info.Warnings, "x5t#S256 does not match x5c[0]")
+			}
+		}
+	}
+	return info, nil
+}
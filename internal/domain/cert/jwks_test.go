@@ -0,0 +1,128 @@
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"strings"
+	"testing"
+
+	domainkey "my-tools/internal/domain/key"
+)
+
+func TestCertsToJWKS(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	spki, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	pubPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+
+	// 链顺序打乱，并混入一个单独的公钥
+	set, err := CertsToJWKS(root.pem+pubPEM+leaf.pem+inter.pem, JWKOptions{Use: "sig", Alg: "ES256"})
+	if err != nil {
+		t.Fatalf("CertsToJWKS() error = %v", err)
+	}
+	if len(set.Keys) != 2 {
+		t.Fatalf("keys = %d, want 2", len(set.Keys))
+	}
+
+	k := set.Keys[0]
+	if k.Kty != "EC" || k.Use != "sig" || k.Alg != "ES256" || k.Kid != k.Thumbprint() || k.IsPrivate() {
+		t.Errorf("jwk = %+v", k)
+	}
+	want := [][]byte{leaf.cert.Raw, inter.cert.Raw, root.cert.Raw}
+	if len(k.X5c) != len(want) {
+		t.Fatalf("x5c = %d certs, want %d", len(k.X5c), len(want))
+	}
+	for i, der := range want {
+		if k.X5c[i] != base64.StdEncoding.EncodeToString(der) {
+			t.Errorf("x5c[%d] is not in leaf-first order", i)
+		}
+	}
+	sum := sha256.Sum256(leaf.cert.Raw)
+	if k.X5tS256 != base64.RawURLEncoding.EncodeToString(sum[:]) {
+		t.Errorf("x5t#S256 = %s", k.X5tS256)
+	}
+
+	if set.Keys[1].Kty != "RSA" || len(set.Keys[1].X5c) != 0 || set.Keys[1].Use != "sig" {
+		t.Errorf("public key jwk = %+v", set.Keys[1])
+	}
+}
+
+func TestCertsToJWKSInvalid(t *testing.T) {
+	_, _, leaf := newTestChain(t)
+	der, _ := x509.MarshalECPrivateKey(leaf.key)
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+
+	tests := []struct {
+		name  string
+		input string
+		opts  JWKOptions
+	}{
+		{"empty", "", JWKOptions{}},
+		{"private key", leaf.pem + keyPEM, JWKOptions{}},
+		{"bad use", leaf.pem, JWKOptions{Use: "auth"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CertsToJWKS(tt.input, tt.opts); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func TestJWKSToPEM(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	set, err := CertsToJWKS(leaf.pem+inter.pem+root.pem, JWKOptions{})
+	if err != nil {
+		t.Fatalf("CertsToJWKS() error = %v", err)
+	}
+
+	infos, err := JWKSToPEM(set.String())
+	if err != nil {
+		t.Fatalf("JWKSToPEM() error = %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("infos = %d, want 1", len(infos))
+	}
+	info := infos[0]
+	if len(info.Warnings) != 0 || len(info.Certs) != 3 || info.Certs[0].Subject != "CN=example.com" {
+		t.Errorf("info = %+v", info)
+	}
+	if info.Thumbprint != info.Kid || info.PublicKeyAlgorithm != "ECDSA" || info.PublicKeyCurve != "P-256" {
+		t.Errorf("info = %+v", info)
+	}
+	block, _ := pem.Decode([]byte(info.PublicKeyPEM))
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil || !publicKeysEqual(pub, leaf.cert.PublicKey) {
+		t.Errorf("public key pem does not match leaf: %v", err)
+	}
+
+	t.Run("mismatch warnings", func(t *testing.T) {
+		j := set.Keys[0]
+		j.X5c = j.X5c[1:]
+		privJWK, _ := domainkey.NewJWK(leaf.key)
+		j.D = privJWK.D
+		infos, err := JWKSToPEM(j.String())
+		if err != nil {
+			t.Fatalf("JWKSToPEM() error = %v", err)
+		}
+		if !infos[0].Private || len(infos[0].Warnings) != 3 {
+			t.Errorf("warnings = %v", infos[0].Warnings)
+		}
+		if strings.Contains(infos[0].PublicKeyPEM, "PRIVATE") {
+			t.Errorf("output must not contain the private key")
+		}
+	})
+}
+
+func TestJWKSToPEMInvalid(t *testing.T) {
+	for _, input := range []string{"", "{", `{"keys":[]}`, `{"keys":[{"kty":"EC","crv":"P-256","x":"AA","y":"AA"}]}`, `{"kty":"OKP","crv":"Ed25519","x":"` + strings.Repeat("A", 43) + `","x5c":["!!"]}`} {
+		if _, err := JWKSToPEM(input); err == nil {
+			t.Errorf("JWKSToPEM(%q) expected error", input)
+		}
+	}
+}
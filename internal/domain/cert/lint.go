@@ -0,0 +1,267 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+
+	domaincsr "my-tools/internal/domain/csr"
+)
+
+// 检查结果严重程度
+const (
+	LintError   = "error"
+	LintWarning = "warning"
+	LintInfo    = "info"
+)
+
+// LintFinding 一条检查结果
+type LintFinding struct {
+	// Code 检查项标识，如 validity_too_long / weak_rsa_key
+	Code     string `json:"code"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// LintReport 单个证书或 CSR 的检查报告
+type LintReport struct {
+	Subject  string        `json:"subject"`
+	IsCA     bool          `json:"isCA"`
+	Findings []LintFinding `json:"findings"`
+	Errors   int           `json:"errors"`
+	Warnings int           `json:"warnings"`
+	// Passed 没有 error 级别的问题
+	Passed bool `json:"passed"`
+}
+
+// brValidityLimits BR 对终端实体证书有效期的上限，按生效日期从新到旧排列；
+// 2026 年起的逐步缩短来自 CA/Browser Forum ballot SC-081
+var brValidityLimits = []struct {
+	since   time.Time
+	maxDays int
+}{
+	{time.Date(2029, 3, 15, 0, 0, 0, 0, time.UTC), 47},
+	{time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC), 100},
+	{time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), 200},
+	{time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), 398},
+	{time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), 825},
+}
+
+// BRMaxValidityDays 返回 notBefore 时签发的终端实体证书允许的最长有效期（天）；
+// 早于所有限制生效日期时 ok 为 false
+func BRMaxValidityDays(notBefore time.Time) (maxDays int, ok bool) {
+	for _, l := range brValidityLimits {
+		if !notBefore.Before(l.since) {
+			return l.maxDays, true
+		}
+	}
+	return 0, false
+}
+
+// LintCertChain 对证书链中的每个证书做 CA/Browser Forum Baseline Requirements 常见问题检查；
+// 有效期、SAN、通配符等只针对终端实体证书
+func LintCertChain(input string) ([]LintReport, error) {
+	pems, err := SplitCertChain(input)
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]LintReport, 0, len(pems))
+	for _, p := range pems {
+		c, err := parseCertPEM(p)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, LintCert(c))
+	}
+	return reports, nil
+}
+
+// LintCert 检查单个证书
+func LintCert(c *x509.Certificate) LintReport {
+	var f []LintFinding
+	selfSigned := isSelfSigned(c)
+
+	if !c.IsCA {
+		f = append(f, lintValidity(c.NotBefore, c.NotAfter)...)
+		f = append(f, lintSANs(c.Subject.CommonName, c.DNSNames, c.IPAddresses, c.EmailAddresses, len(c.URIs) > 0, LintError)...)
+		f = append(f, lintWildcards(c.DNSNames, ValidationLevel(c.PolicyIdentifiers) == "EV")...)
+	}
+	f = append(f, lintPublicKey(c.PublicKey)...)
+	// 自签名根证书的自身签名不参与校验，SHA-1 不构成问题
+	if !selfSigned {
+		f = append(f, lintSignatureAlgorithm(c.SignatureAlgorithm, LintError)...)
+		if len(c.AuthorityKeyId) == 0 {
+			f = append(f, LintFinding{Code: "missing_aki", Severity: LintError, Message: "authority key identifier extension is missing"})
+		}
+	}
+	if !c.IsCA && len(c.SubjectKeyId) == 0 {
+		f = append(f, LintFinding{Code: "missing_ski", Severity: LintInfo, Message: "subject key identifier extension is missing (recommended)"})
+	}
+	f = append(f, lintSerial(c)...)
+
+	return newLintReport(c.Subject.String(), c.IsCA, f)
+}
+
+// LintCSR 对 CSR 做证书检查中适用的部分：SAN、CN、通配符、密钥强度和签名算法
+func LintCSR(input string) (LintReport, error) {
+	normalized, err := domaincsr.NormalizeCSRPEM(input)
+	if err != nil {
+		return LintReport{}, err
+	}
+	req, err := domaincsr.ParseCSRPEM(normalized)
+	if err != nil {
+		return LintReport{}, err
+	}
+
+	var f []LintFinding
+	// CSR 中缺少 SAN 时多数 CA 会用 CN 补上，因此只给出 warning
+	f = append(f, lintSANs(req.Subject.CommonName, req.DNSNames, req.IPAddresses, req.EmailAddresses, len(req.URIs) > 0, LintWarning)...)
+	f = append(f, lintWildcards(req.DNSNames, false)...)
+	f = append(f, lintPublicKey(req.PublicKey)...)
+	f = append(f, lintSignatureAlgorithm(req.SignatureAlgorithm, LintWarning)...)
+
+	return newLintReport(req.Subject.String(), false, f), nil
+}
+
+func newLintReport(subject string, isCA bool, findings []LintFinding) LintReport {
+	r := LintReport{Subject: subject, IsCA: isCA, Findings: findings}
+	if r.Findings == nil {
+		r.Findings = []LintFinding{}
+	}
+	for _, f := range r.Findings {
+		switch f.Severity {
+		case LintError:
+			r.Errors++
+		case LintWarning:
+			r.Warnings++
+		}
+	}
+	r.Passed = r.Errors == 0
+	return r
+}
+
+func lintValidity(notBefore, notAfter time.Time) []LintFinding {
+	maxDays, ok := BRMaxValidityDays(notBefore)
+	if !ok {
+		return nil
+	}
+
+	if notAfter.Sub(notBefore) > time.Duration(maxDays)*24*time.Hour {
+		days := int(notAfter.Sub(notBefore).Hours() / 24)
+		return []LintFinding{{
+			Code:     "validity_too_long",
+			Severity: LintError,
+			Message:  "validity period is " + strconv.Itoa(days) + " days, exceeds the " + strconv.Itoa(maxDays) + "-day limit",
+		}}
+	}
+	return nil
+}
+
+func lintSANs(cn string, dnsNames []string, ips []net.IP, emails []string, hasURIs bool, missingSeverity string) []LintFinding {
+	var f []LintFinding
+	if len(dnsNames) == 0 && len(ips) == 0 && len(emails) == 0 && !hasURIs {
+		f = append(f, LintFinding{Code: "missing_san", Severity: missingSeverity, Message: "subject alternative name extension is missing"})
+	}
+
+	if cn != "" {
+		found := false
+		for _, d := range dnsNames {
+			if strings.EqualFold(d, cn) {
+				found = true
+			}
+		}
+		if ip := net.ParseIP(cn); ip != nil {
+			for _, sanIP := range ips {
+				if sanIP.Equal(ip) {
+					found = true
+				}
+			}
+		}
+		if !found && len(dnsNames)+len(ips) > 0 {
+			f = append(f, LintFinding{Code: "cn_not_in_san", Severity: missingSeverity, Message: "common name " + cn + " is not present in subject alternative names"})
+		}
+	}
+	return f
+}
+
+func lintWildcards(dnsNames []string, ev bool) []LintFinding {
+	var f []LintFinding
+	for _, name := range dnsNames {
+		if !strings.Contains(name, "*") {
+			continue
+		}
+		labels := strings.Split(name, ".")
+		switch {
+		case labels[0] != "*" || strings.Count(name, "*") > 1 || len(labels) < 2:
+			f = append(f, LintFinding{Code: "wildcard_misplaced", Severity: LintError, Message: name + ": wildcard must be the entire left-most label"})
+			continue
+		case ev:
+			f = append(f, LintFinding{Code: "wildcard_in_ev", Severity: LintError, Message: name + ": wildcards are not allowed in EV certificates"})
+		}
+
+		base := strings.ToLower(strings.Join(labels[1:], "."))
+		if suffix, icann := publicsuffix.PublicSuffix(base); suffix == base {
+			sev := LintWarning
+			if icann {
+				sev = LintError
+			}
+			f = append(f, LintFinding{Code: "wildcard_public_suffix", Severity: sev, Message: name + ": wildcard directly under public suffix " + base})
+		}
+	}
+	return f
+}
+
+func lintPublicKey(pub interface{}) []LintFinding {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		bits := k.N.BitLen()
+		if bits < 2048 {
+			return []LintFinding{{Code: "weak_rsa_key", Severity: LintError, Message: "RSA key is " + strconv.Itoa(bits) + " bits, at least 2048 is required"}}
+		}
+		if bits%8 != 0 {
+			return []LintFinding{{Code: "rsa_key_size", Severity: LintError, Message: "RSA modulus size must be divisible by 8"}}
+		}
+		if k.E%2 == 0 || k.E < 3 {
+			return []LintFinding{{Code: "rsa_exponent", Severity: LintError, Message: "RSA public exponent must be an odd number >= 3"}}
+		}
+	case *ecdsa.PublicKey:
+		switch k.Curve.Params().Name {
+		case "P-256", "P-384", "P-521":
+		default:
+			return []LintFinding{{Code: "ec_curve", Severity: LintError, Message: "ECDSA curve " + k.Curve.Params().Name + " is not allowed"}}
+		}
+	case ed25519.PublicKey:
+		return []LintFinding{{Code: "ed25519_key", Severity: LintWarning, Message: "Ed25519 keys are not accepted by publicly trusted CAs"}}
+	}
+	return nil
+}
+
+func lintSignatureAlgorithm(alg x509.SignatureAlgorithm, severity string) []LintFinding {
+	switch alg {
+	case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return []LintFinding{{Code: "weak_signature_algorithm", Severity: severity, Message: alg.String() + " signatures are no longer accepted"}}
+	}
+	return nil
+}
+
+func lintSerial(c *x509.Certificate) []LintFinding {
+	sn := c.SerialNumber
+	switch {
+	case sn == nil || sn.Sign() <= 0:
+		return []LintFinding{{Code: "serial_not_positive", Severity: LintError, Message: "serial number must be a positive integer"}}
+	case len(sn.Bytes()) > 20:
+		return []LintFinding{{Code: "serial_too_long", Severity: LintError, Message: "serial number must not exceed 20 octets"}}
+	case len(sn.Bytes()) < 8:
+		// BR 要求序列号包含至少 64 位 CSPRNG 随机数，不足 8 字节的序列号不可能满足
+		return []LintFinding{{Code: "serial_low_entropy", Severity: LintError, Message: "serial number is " + strconv.Itoa(len(sn.Bytes())) + " octets, at least 64 bits of entropy are required"}}
+	}
+	return nil
+}
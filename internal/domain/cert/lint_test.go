@@ -0,0 +1,211 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func lintCodes(r LintReport) string {
+	codes := make([]string, 0, len(r.Findings))
+	for _, f := range r.Findings {
+		if f.Severity != LintInfo {
+			codes = append(codes, f.Code)
+		}
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ",")
+}
+
+func TestLintCert(t *testing.T) {
+	_, inter, _ := newTestChain(t)
+	now := time.Now()
+	serial := new(big.Int).Lsh(big.NewInt(1), 100)
+
+	base := func() *x509.Certificate {
+		return &x509.Certificate{
+			SerialNumber: serial,
+			Subject:      pkix.Name{CommonName: "example.com"},
+			DNSNames:     []string{"example.com", "*.example.com"},
+			NotBefore:    now.Add(-time.Hour),
+			NotAfter:     now.Add(30 * 24 * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(c *x509.Certificate)
+		want   string
+	}{
+		{"clean", func(c *x509.Certificate) {}, ""},
+		{"validity too long", func(c *x509.Certificate) { c.NotAfter = c.NotBefore.Add(399 * 24 * time.Hour) }, "validity_too_long"},
+		{"missing san", func(c *x509.Certificate) { c.DNSNames = nil }, "missing_san"},
+		{"cn not in san", func(c *x509.Certificate) { c.Subject.CommonName = "other.com" }, "cn_not_in_san"},
+		{"low entropy serial", func(c *x509.Certificate) { c.SerialNumber = big.NewInt(12345) }, "serial_low_entropy"},
+		{"misplaced wildcard", func(c *x509.Certificate) { c.DNSNames = []string{"example.com", "www.*.example.com"} }, "wildcard_misplaced"},
+		{"partial wildcard", func(c *x509.Certificate) { c.DNSNames = []string{"example.com", "w*.example.com"} }, "wildcard_misplaced"},
+		{"public suffix wildcard", func(c *x509.Certificate) { c.DNSNames = []string{"example.com", "*.co.uk"} }, "wildcard_public_suffix"},
+		{"ev wildcard", func(c *x509.Certificate) {
+			c.PolicyIdentifiers = []asn1.ObjectIdentifier{oidPolicyEV}
+		}, "wildcard_in_ev"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := base()
+			tt.modify(tmpl)
+			c := newTestCert(t, tmpl, inter)
+			got := LintCert(c.cert)
+			if codes := lintCodes(got); codes != tt.want {
+				t.Errorf("findings = %q, want %q", codes, tt.want)
+			}
+			if got.Passed != (tt.want == "") {
+				t.Errorf("Passed = %v", got.Passed)
+			}
+		})
+	}
+}
+
+func TestLintValidityCutovers(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		name      string
+		notBefore time.Time
+		days      int
+		want      bool
+	}{
+		{"825 天之前不限制", time.Date(2018, 2, 28, 23, 59, 59, 0, time.UTC), 1000, false},
+		{"2018-03-01 起 825 天", time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), 825, false},
+		{"2018-03-01 起超过 825 天", time.Date(2018, 3, 1, 0, 0, 0, 0, time.UTC), 826, true},
+		{"2020-09-01 前一刻仍为 825 天", time.Date(2020, 8, 31, 23, 59, 59, 0, time.UTC), 825, false},
+		{"2020-09-01 起超过 398 天", time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), 399, true},
+		{"2026-03-15 前一刻仍为 398 天", time.Date(2026, 3, 14, 23, 59, 59, 0, time.UTC), 398, false},
+		{"2026-03-15 起 200 天", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), 200, false},
+		{"2026-03-15 起 397 天", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC), 397, true},
+		{"2027-03-15 前一刻仍为 200 天", time.Date(2027, 3, 14, 23, 59, 59, 0, time.UTC), 200, false},
+		{"2027-03-15 起 100 天", time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC), 100, false},
+		{"2027-03-15 起 101 天", time.Date(2027, 3, 15, 0, 0, 0, 0, time.UTC), 101, true},
+		{"2029-03-15 前一刻仍为 100 天", time.Date(2029, 3, 14, 23, 59, 59, 0, time.UTC), 100, false},
+		{"2029-03-15 起 47 天", time.Date(2029, 3, 15, 0, 0, 0, 0, time.UTC), 47, false},
+		{"2029-03-15 起 48 天", time.Date(2029, 3, 15, 0, 0, 0, 0, time.UTC), 48, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := lintValidity(tt.notBefore, tt.notBefore.Add(time.Duration(tt.days)*day))
+			if got := len(f) > 0; got != tt.want {
+				t.Errorf("lintValidity() = %+v, want finding %v", f, tt.want)
+			}
+		})
+	}
+}
+
+func TestLintCertKeysAndSignatures(t *testing.T) {
+	now := time.Now()
+	serial := new(big.Int).Lsh(big.NewInt(1), 100)
+	tmpl := &x509.Certificate{
+		SerialNumber:   serial,
+		Subject:        pkix.Name{CommonName: "example.com"},
+		DNSNames:       []string{"example.com"},
+		NotBefore:      now.Add(-time.Hour),
+		NotAfter:       now.Add(30 * 24 * time.Hour),
+		AuthorityKeyId: []byte{1, 2, 3, 4},
+	}
+
+	weak, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	issuer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	// 1024 位 RSA 公钥，由另一把 ECDSA 密钥签发，使其不是自签名
+	parent := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "Issuer"}}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &weak.PublicKey, issuer)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	if got := lintCodes(LintCert(c)); got != "weak_rsa_key" {
+		t.Errorf("findings = %q, want weak_rsa_key", got)
+	}
+
+	// Go 已不支持生成 SHA-1 签名，直接修改解析结果模拟 SHA-1 签名且缺少 AKI 的证书
+	c.SignatureAlgorithm = x509.ECDSAWithSHA1
+	c.AuthorityKeyId = nil
+	c.PublicKey = &issuer.PublicKey
+	if got := lintCodes(LintCert(c)); got != "missing_aki,weak_signature_algorithm" {
+		t.Errorf("findings = %q", got)
+	}
+}
+
+func TestLintCertChainRoot(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	reports, err := LintCertChain(leaf.pem + inter.pem + root.pem)
+	if err != nil {
+		t.Fatalf("LintCertChain() error = %v", err)
+	}
+	if len(reports) != 3 {
+		t.Fatalf("got %d reports", len(reports))
+	}
+	// 测试链使用小序列号，三张证书都应报告熵不足；CA 证书不检查 SAN 和有效期
+	for i, r := range reports {
+		if got := lintCodes(r); got != "serial_low_entropy" {
+			t.Errorf("report %d findings = %q", i, got)
+		}
+	}
+	if !reports[2].IsCA {
+		t.Errorf("root should be CA")
+	}
+}
+
+func TestLintCSR(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl *x509.CertificateRequest
+		want string
+	}{
+		{"clean", &x509.CertificateRequest{Subject: pkix.Name{CommonName: "example.com"}, DNSNames: []string{"example.com"}}, ""},
+		{"missing san", &x509.CertificateRequest{Subject: pkix.Name{CommonName: "example.com"}}, "missing_san"},
+		{"cn not in san", &x509.CertificateRequest{Subject: pkix.Name{CommonName: "a.com"}, DNSNames: []string{"b.com"}}, "cn_not_in_san"},
+		{"bad wildcard", &x509.CertificateRequest{Subject: pkix.Name{CommonName: "*.com"}, DNSNames: []string{"*.com"}}, "wildcard_public_suffix"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			if err != nil {
+				t.Fatalf("generate key: %v", err)
+			}
+			der, err := x509.CreateCertificateRequest(rand.Reader, tt.tmpl, key)
+			if err != nil {
+				t.Fatalf("create csr: %v", err)
+			}
+			got, err := LintCSR(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))
+			if err != nil {
+				t.Fatalf("LintCSR() error = %v", err)
+			}
+			if codes := lintCodes(got); codes != tt.want {
+				t.Errorf("findings = %q, want %q", codes, tt.want)
+			}
+		})
+	}
+
+	if _, err := LintCSR("garbage"); err == nil {
+		t.Errorf("expected error for invalid csr")
+	}
+}
@@ -0,0 +1,276 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"sort"
+	"strings"
+
+	domaincsr "my-tools/internal/domain/csr"
+	domainkey "my-tools/internal/domain/key"
+)
+
+// 私钥格式
+const (
+	KeyFormatPKCS1   = domainkey.FormatPKCS1
+	KeyFormatPKCS8   = domainkey.FormatPKCS8
+	KeyFormatSEC1    = domainkey.FormatSEC1
+	KeyFormatOpenSSH = domainkey.FormatOpenSSH
+)
+
+// MatchInput 待比对的证书、CSR 和私钥，至少提供两项
+type MatchInput struct {
+	CertPEM    string
+	CSRPEM     string
+	KeyPEM     string
+	Passphrase string
+}
+
+// MatchItem 单个输入的公钥摘要
+type MatchItem struct {
+	Subject            string `json:"subject,omitempty"`
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	// PublicKeySHA256 SubjectPublicKeyInfo 的 SHA-256，可用于人工比对
+	PublicKeySHA256 string `json:"publicKeySha256"`
+	// KeyFormat 仅私钥有值：PKCS#1 / PKCS#8 / SEC1
+	KeyFormat string `json:"keyFormat,omitempty"`
+	Encrypted bool   `json:"encrypted,omitempty"`
+}
+
+// MatchPair 两两比对结果，Pair 取值 cert-key / cert-csr / csr-key
+type MatchPair struct {
+	Pair  string `json:"pair"`
+	Match bool   `json:"match"`
+}
+
+// FieldDiff CSR 与证书某个主题字段的差异
+type FieldDiff struct {
+	Field string `json:"field"`
+	CSR   string `json:"csr"`
+	Cert  string `json:"cert"`
+}
+
+// MatchResult 证书、CSR、私钥比对结果
+type MatchResult struct {
+	Cert  *MatchItem  `json:"cert,omitempty"`
+	CSR   *MatchItem  `json:"csr,omitempty"`
+	Key   *MatchItem  `json:"key,omitempty"`
+	Pairs []MatchPair `json:"pairs"`
+	// AllMatch 所有提供的输入两两匹配
+	AllMatch bool `json:"allMatch"`
+
+	// 以下仅在同时提供 CSR 和证书时有值
+	SubjectDiffs []FieldDiff `json:"subjectDiffs"`
+	// SANsMissingInCert CSR 中申请了、但证书中没有的 SAN
+	SANsMissingInCert []string `json:"sansMissingInCert"`
+	// SANsAddedInCert 证书中有、但 CSR 没有申请的 SAN（CA 常会自动补充 www 等）
+	SANsAddedInCert []string `json:"sansAddedInCert"`
+}
+
+// MatchCertCSRKey 比对证书、CSR 和私钥的公钥是否一致，并给出 CSR 与证书的主题/SAN 差异
+func MatchCertCSRKey(in MatchInput) (MatchResult, error) {
+	provided := 0
+	for _, s := range []string{in.CertPEM, in.CSRPEM, in.KeyPEM} {
+		if strings.TrimSpace(s) != "" {
+			provided++
+		}
+	}
+	if provided < 2 {
+		return MatchResult{}, errors.New("at least two of certificate, csr and private key are required")
+	}
+
+	res := MatchResult{
+		Pairs:             []MatchPair{},
+		SubjectDiffs:      []FieldDiff{},
+		SANsMissingInCert: []string{},
+		SANsAddedInCert:   []string{},
+	}
+
+	var cert *x509.Certificate
+	if strings.TrimSpace(in.CertPEM) != "" {
+		pems, err := SplitCertChain(in.CertPEM)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		// 证书链只取第一个（叶子）证书
+		cert, err = parseCertPEM(pems[0])
+		if err != nil {
+			return MatchResult{}, err
+		}
+		item, err := newMatchItem(cert.PublicKey)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		item.Subject = cert.Subject.String()
+		res.Cert = &item
+	}
+
+	var req *x509.CertificateRequest
+	if strings.TrimSpace(in.CSRPEM) != "" {
+		normalized, err := domaincsr.NormalizeCSRPEM(in.CSRPEM)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		req, err = domaincsr.ParseCSRPEM(normalized)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		item, err := newMatchItem(req.PublicKey)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		item.Subject = req.Subject.String()
+		res.CSR = &item
+	}
+
+	var keyPub crypto.PublicKey
+	if strings.TrimSpace(in.KeyPEM) != "" {
+		key, format, encrypted, err := ParsePrivateKeyPEM(in.KeyPEM, in.Passphrase)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return MatchResult{}, errors.New("unsupported private key type")
+		}
+		keyPub = signer.Public()
+		item, err := newMatchItem(keyPub)
+		if err != nil {
+			return MatchResult{}, err
+		}
+		item.KeyFormat = format
+		item.Encrypted = encrypted
+		res.Key = &item
+	}
+
+	if cert != nil && keyPub != nil {
+		res.Pairs = append(res.Pairs, MatchPair{Pair: "cert-key", Match: publicKeysEqual(cert.PublicKey, keyPub)})
+	}
+	if cert != nil && req != nil {
+		res.Pairs = append(res.Pairs, MatchPair{Pair: "cert-csr", Match: publicKeysEqual(cert.PublicKey, req.PublicKey)})
+		res.SubjectDiffs = subjectDiffs(req.Subject, cert.Subject)
+		res.SANsMissingInCert, res.SANsAddedInCert = sanDiff(requestSANs(req), certSANs(cert))
+	}
+	if req != nil && keyPub != nil {
+		res.Pairs = append(res.Pairs, MatchPair{Pair: "csr-key", Match: publicKeysEqual(req.PublicKey, keyPub)})
+	}
+
+	res.AllMatch = true
+	for _, p := range res.Pairs {
+		if !p.Match {
+			res.AllMatch = false
+		}
+	}
+
+	return res, nil
+}
+
+// ParsePrivateKeyPEM 解析 PKCS#1 / PKCS#8 / SEC1 / OpenSSH 私钥 PEM，支持加密的 PKCS#8、
+// 传统 OpenSSL 加密 PEM 和加密的 OpenSSH 私钥；返回私钥、格式以及是否加密
+func ParsePrivateKeyPEM(input, passphrase string) (crypto.PrivateKey, string, bool, error) {
+	return domainkey.ParsePrivateKeyPEM(input, passphrase)
+}
+
+func newMatchItem(pub crypto.PublicKey) (MatchItem, error) {
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return MatchItem{}, err
+	}
+	alg, size, _ := domainkey.PublicKeyInfo(pub)
+	sum := sha256.Sum256(spki)
+	return MatchItem{
+		PublicKeyAlgorithm: alg,
+		PublicKeySize:      size,
+		PublicKeySHA256:    domainkey.ColonHex(sum[:]),
+	}, nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	ka, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && ka.Equal(b)
+}
+
+func subjectDiffs(csrName, certName pkix.Name) []FieldDiff {
+	fields := []struct {
+		name       string
+		csr, certV []string
+	}{
+		{"CN", nonEmptyList(csrName.CommonName), nonEmptyList(certName.CommonName)},
+		{"O", csrName.Organization, certName.Organization},
+		{"OU", csrName.OrganizationalUnit, certName.OrganizationalUnit},
+		{"L", csrName.Locality, certName.Locality},
+		{"ST", csrName.Province, certName.Province},
+		{"C", csrName.Country, certName.Country},
+	}
+
+	diffs := []FieldDiff{}
+	for _, f := range fields {
+		a, b := strings.Join(f.csr, ", "), strings.Join(f.certV, ", ")
+		if a != b {
+			diffs = append(diffs, FieldDiff{Field: f.name, CSR: a, Cert: b})
+		}
+	}
+	return diffs
+}
+
+func requestSANs(req *x509.CertificateRequest) []string {
+	out := append([]string{}, prefixed("DNS:", req.DNSNames)...)
+	for _, ip := range req.IPAddresses {
+		out = append(out, "IP:"+ip.String())
+	}
+	out = append(out, prefixed("email:", req.EmailAddresses)...)
+	return out
+}
+
+func certSANs(cert *x509.Certificate) []string {
+	out := append([]string{}, prefixed("DNS:", cert.DNSNames)...)
+	for _, ip := range cert.IPAddresses {
+		out = append(out, "IP:"+ip.String())
+	}
+	out = append(out, prefixed("email:", cert.EmailAddresses)...)
+	return out
+}
+
+// sanDiff 返回只在 a 中、只在 b 中的 SAN（DNS 名称不区分大小写）
+func sanDiff(a, b []string) (onlyA, onlyB []string) {
+	setA, setB := map[string]string{}, map[string]string{}
+	for _, s := range a {
+		setA[strings.ToLower(s)] = s
+	}
+	for _, s := range b {
+		setB[strings.ToLower(s)] = s
+	}
+	onlyA, onlyB = []string{}, []string{}
+	for k, s := range setA {
+		if _, ok := setB[k]; !ok {
+			onlyA = append(onlyA, s)
+		}
+	}
+	for k, s := range setB {
+		if _, ok := setA[k]; !ok {
+			onlyB = append(onlyB, s)
+		}
+	}
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+	return onlyA, onlyB
+}
+
+func prefixed(prefix string, values []string) []string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = prefix + v
+	}
+	return out
+}
+
+func nonEmptyList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,105 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+
+	"my-tools/internal/domain/pkcs8"
+)
+
+func TestMatchCertCSRKey(t *testing.T) {
+	_, _, leaf := newTestChain(t)
+
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "example.com", Organization: []string{"Example Ltd"}},
+		DNSNames: []string{"example.com"},
+	}, leaf.key)
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	csrPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+
+	pkcs8DER, _ := x509.MarshalPKCS8PrivateKey(leaf.key)
+	sec1DER, _ := x509.MarshalECPrivateKey(leaf.key)
+	encryptedDER, err := pkcs8.Encrypt(pkcs8DER, "secret")
+	if err != nil {
+		t.Fatalf("encrypt key: %v", err)
+	}
+	keyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8DER}))
+
+	t.Run("all match", func(t *testing.T) {
+		got, err := MatchCertCSRKey(MatchInput{CertPEM: leaf.pem, CSRPEM: csrPEM, KeyPEM: keyPEM})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.AllMatch || len(got.Pairs) != 3 {
+			t.Errorf("pairs = %+v", got.Pairs)
+		}
+		if got.Key.KeyFormat != KeyFormatPKCS8 || got.Cert.PublicKeySHA256 != got.Key.PublicKeySHA256 {
+			t.Errorf("key = %+v, cert = %+v", got.Key, got.Cert)
+		}
+		want := []FieldDiff{{Field: "O", CSR: "Example Ltd", Cert: ""}}
+		if !reflect.DeepEqual(got.SubjectDiffs, want) {
+			t.Errorf("subjectDiffs = %+v, want %+v", got.SubjectDiffs, want)
+		}
+		if !reflect.DeepEqual(got.SANsAddedInCert, []string{"DNS:www.example.com"}) || len(got.SANsMissingInCert) != 0 {
+			t.Errorf("san diff = %v / %v", got.SANsMissingInCert, got.SANsAddedInCert)
+		}
+	})
+
+	t.Run("key mismatch", func(t *testing.T) {
+		other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		otherDER, _ := x509.MarshalECPrivateKey(other)
+		got, err := MatchCertCSRKey(MatchInput{
+			CertPEM: leaf.pem,
+			KeyPEM:  string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: otherDER})),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.AllMatch || got.Pairs[0].Match {
+			t.Errorf("expected mismatch, got %+v", got.Pairs)
+		}
+	})
+
+	t.Run("too few inputs", func(t *testing.T) {
+		if _, err := MatchCertCSRKey(MatchInput{CertPEM: leaf.pem}); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+
+	t.Run("key formats", func(t *testing.T) {
+		tests := []struct {
+			name       string
+			pem        string
+			passphrase string
+			wantFormat string
+			wantEnc    bool
+			wantErr    bool
+		}{
+			{"sec1", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1DER})), "", KeyFormatSEC1, false, false},
+			{"escaped pkcs8", strings.ReplaceAll(keyPEM, "\n", "\\n"), "", KeyFormatPKCS8, false, false},
+			{"encrypted pkcs8", string(pem.EncodeToMemory(&pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encryptedDER})), "secret", KeyFormatPKCS8, true, false},
+			{"encrypted without passphrase", string(pem.EncodeToMemory(&pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encryptedDER})), "", "", true, true},
+			{"not a key", leaf.pem, "", "", false, true},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				_, format, enc, err := ParsePrivateKeyPEM(tt.pem, tt.passphrase)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("ParsePrivateKeyPEM() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if format != tt.wantFormat || enc != tt.wantEnc {
+					t.Errorf("format = %q encrypted = %v, want %q %v", format, enc, tt.wantFormat, tt.wantEnc)
+				}
+			})
+		}
+	})
+}
@@ -4,88 +4,81 @@ import (
 	"crypto/x509"
 	"encoding/pem"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
-)
 
-const (
-	certPEMHeader = "-----BEGIN CERTIFICATE-----"
-	certPEMFooter = "-----END CERTIFICATE-----"
+	domaincsr "my-tools/internal/domain/csr"
+	domainkey "my-tools/internal/domain/key"
+	"my-tools/internal/domain/pemscan"
 )
 
-// NormalizeCertPEM 格式化单个证书PEM
+// certPEMTypes 证书 PEM 块的类型，X509 CERTIFICATE 为旧式写法
+var certPEMTypes = []string{"CERTIFICATE", "X509 CERTIFICATE"}
+
+// NormalizeCertPEM 格式化单个证书PEM；输入可以带各种转义和引用前缀，也可以是不带 BEGIN / END 行的 base64
 func NormalizeCertPEM(input string) (string, error) {
 	in := strings.TrimSpace(input)
 	if in == "" {
 		return "", errors.New("certificate is empty")
 	}
 
-	in = strings.ReplaceAll(in, "\\r\\n", "\n")
-	in = strings.ReplaceAll(in, "\\n", "\n")
-	in = strings.ReplaceAll(in, "\r\n", "\n")
-	in = strings.ReplaceAll(in, "\r", "\n")
+	res := pemscan.Scan(in)
+	if blocks := res.Find(certPEMTypes...); len(blocks) > 0 {
+		if blocks[0].Empty() {
+			return "", errors.New("certificate body is empty")
+		}
+		return blocks[0].As("CERTIFICATE").PEM, nil
+	}
+	if w := res.WarningsFor(certPEMTypes...); len(w) > 0 {
+		return "", errors.New("incomplete certificate found: " + w[0].String())
+	}
+	if len(res.Blocks) > 0 {
+		return "", errors.New("no certificate found, input contains " + strings.Join(res.Types(), ", "))
+	}
 
-	body := extractCertBody(in)
-	body = stripWhitespace(body)
-	if body == "" {
+	b := pemscan.Decode("CERTIFICATE", in)
+	if b.Empty() {
 		return "", errors.New("certificate body is empty")
 	}
-
-	wrapped := wrap64(body)
-	return certPEMHeader + "\n" + wrapped + certPEMFooter + "\n", nil
+	return b.PEM, nil
 }
 
-// SplitCertChain 将证书链拆分成多个独立的证书
+// SplitCertChain 将证书链拆分成多个独立的证书，忽略输入中其他类型的 PEM 块
 func SplitCertChain(input string) ([]string, error) {
 	in := strings.TrimSpace(input)
 	if in == "" {
 		return nil, errors.New("certificate chain is empty")
 	}
 
-	// 处理转义字符
-	in = strings.ReplaceAll(in, "\\r\\n", "\n")
-	in = strings.ReplaceAll(in, "\\n", "\n")
-	in = strings.ReplaceAll(in, "\r\n", "\n")
-	in = strings.ReplaceAll(in, "\r", "\n")
-
-	var certs []string
-	current := 0
-
-	for {
-		// 查找下一个证书开始位置
-		start := strings.Index(in[current:], certPEMHeader)
-		if start == -1 {
-			break
-		}
-		start += current
-
-		// 查找对应的结束位置
-		end := strings.Index(in[start+len(certPEMHeader):], certPEMFooter)
-		if end == -1 {
-			return nil, errors.New("incomplete certificate found: missing footer")
-		}
-		end = start + len(certPEMHeader) + end + len(certPEMFooter)
-
-		// 提取证书内容
-		certPEM := strings.TrimSpace(in[start:end])
-
-		// 格式化证书
-		normalized, err := NormalizeCertPEM(certPEM)
-		if err != nil {
-			return nil, err
-		}
-
-		certs = append(certs, normalized)
-		current = end
+	res := pemscan.Scan(in)
+	if w := res.WarningsFor(certPEMTypes...); len(w) > 0 {
+		return nil, errors.New("incomplete certificate found: " + w[0].String())
 	}
-
-	if len(certs) == 0 {
+	blocks := res.Find(certPEMTypes...)
+	if len(blocks) == 0 {
+		if len(res.Blocks) > 0 {
+			return nil, errors.New("no valid certificates found, input contains " + strings.Join(res.Types(), ", "))
+		}
 		return nil, errors.New("no valid certificates found")
 	}
 
+	certs := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		if b.Error != "" {
+			return nil, fmt.Errorf("certificate at line %d: %s", b.Line, b.Error)
+		}
+		certs = append(certs, b.As("CERTIFICATE").PEM)
+	}
 	return certs, nil
 }
 
+// containsCertPEM 输入中是否有证书 PEM 块（包括不完整的）
+func containsCertPEM(input string) bool {
+	res := pemscan.Scan(input)
+	return len(res.Find(certPEMTypes...)) > 0 || len(res.WarningsFor(certPEMTypes...)) > 0
+}
+
 // CertInfo 证书信息
 type CertInfo struct {
 	PEM          string    `json:"pem"`
@@ -96,75 +89,94 @@ type CertInfo struct {
 	SerialNumber string    `json:"serialNumber"`
 	Version      int       `json:"version"`
 	IsCA         bool      `json:"isCA"`
+
+	// 使用者可选名称（SAN）
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	URIs           []string `json:"uris"`
+
+	KeyUsage    []string `json:"keyUsage"`
+	ExtKeyUsage []string `json:"extKeyUsage"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+
+	FingerprintSHA1   string `json:"fingerprintSha1"`
+	FingerprintSHA256 string `json:"fingerprintSha256"`
+	SubjectKeyID      string `json:"subjectKeyId,omitempty"`
+	AuthorityKeyID    string `json:"authorityKeyId,omitempty"`
+
+	CRLDistributionPoints []string `json:"crlDistributionPoints"`
+	OCSPServers           []string `json:"ocspServers"`
+	IssuingCertificateURL []string `json:"issuingCertificateUrl"`
+
+	// PolicyOIDs 证书策略 OID，ValidationLevel 为据此判断的 DV/OV/IV/EV
+	PolicyOIDs      []string `json:"policyOids"`
+	ValidationLevel string   `json:"validationLevel,omitempty"`
+	// PathLenConstraint BasicConstraints 路径长度限制，未限制时为空
+	PathLenConstraint *int `json:"pathLenConstraint,omitempty"`
+
+	// SCTs 嵌入的证书透明度时间戳，SCTError 为 SCT 扩展解析失败的原因
+	SCTs     []SCT  `json:"scts"`
+	SCTError string `json:"sctError,omitempty"`
+	// Precertificate 带有 CT poison 扩展的预证书
+	Precertificate bool `json:"precertificate"`
 }
 
 // SplitCertChainWithInfo 将证书链拆分成多个独立的证书并解析信息
 func SplitCertChainWithInfo(input string) ([]CertInfo, error) {
-	in := strings.TrimSpace(input)
-	if in == "" {
-		return nil, errors.New("certificate chain is empty")
+	pems, err := SplitCertChain(input)
+	if err != nil {
+		return nil, err
 	}
 
-	// 处理转义字符
-	in = strings.ReplaceAll(in, "\\r\\n", "\n")
-	in = strings.ReplaceAll(in, "\\n", "\n")
-	in = strings.ReplaceAll(in, "\r\n", "\n")
-	in = strings.ReplaceAll(in, "\r", "\n")
-
-	var certInfos []CertInfo
-	current := 0
-
-	for {
-		// 查找下一个证书开始位置
-		start := strings.Index(in[current:], certPEMHeader)
-		if start == -1 {
-			break
-		}
-		start += current
-
-		// 查找对应的结束位置
-		end := strings.Index(in[start+len(certPEMHeader):], certPEMFooter)
-		if end == -1 {
-			return nil, errors.New("incomplete certificate found: missing footer")
-		}
-		end = start + len(certPEMHeader) + end + len(certPEMFooter)
-
-		// 提取证书内容
-		certPEM := strings.TrimSpace(in[start:end])
-
-		// 格式化证书
-		normalized, err := NormalizeCertPEM(certPEM)
-		if err != nil {
-			return nil, err
-		}
-
-		// 解析证书信息
-		info, err := parseCertInfo(normalized)
+	certInfos := make([]CertInfo, 0, len(pems))
+	for _, p := range pems {
+		info, err := parseCertInfo(p)
 		if err != nil {
 			return nil, err
 		}
-
 		certInfos = append(certInfos, info)
-		current = end
-	}
-
-	if len(certInfos) == 0 {
-		return nil, errors.New("no valid certificates found")
 	}
-
 	return certInfos, nil
 }
 
 // parseCertInfo 解析证书信息
 func parseCertInfo(certPEM string) (CertInfo, error) {
+	cert, err := parseCertPEM(certPEM)
+	if err != nil {
+		return CertInfo{}, err
+	}
+	return newCertInfo(certPEM, cert), nil
+}
+
+// parseCertPEM 将单个证书 PEM 解析为 x509.Certificate
+func parseCertPEM(certPEM string) (*x509.Certificate, error) {
 	block, _ := pem.Decode([]byte(certPEM))
 	if block == nil {
-		return CertInfo{}, errors.New("failed to decode PEM block")
+		return nil, errors.New("failed to decode PEM block")
 	}
+	return x509.ParseCertificate(block.Bytes)
+}
 
-	cert, err := x509.ParseCertificate(block.Bytes)
+// newCertInfo 从已解析的证书构造 CertInfo
+func newCertInfo(certPEM string, cert *x509.Certificate) CertInfo {
+	ips := make([]string, len(cert.IPAddresses))
+	for i, ip := range cert.IPAddresses {
+		ips[i] = ip.String()
+	}
+	uris := make([]string, len(cert.URIs))
+	for i, u := range cert.URIs {
+		uris[i] = u.String()
+	}
+	keyAlg, keySize, curve := domainkey.PublicKeyInfo(cert.PublicKey)
+	scts, err := certSCTs(cert)
+	sctError := ""
 	if err != nil {
-		return CertInfo{}, err
+		scts, sctError = []SCT{}, err.Error()
 	}
 
 	return CertInfo{
@@ -176,47 +188,35 @@ func parseCertInfo(certPEM string) (CertInfo, error) {
 		SerialNumber: cert.SerialNumber.String(),
 		Version:      cert.Version,
 		IsCA:         cert.IsCA,
-	}, nil
-}
 
-func extractCertBody(in string) string {
-	if !strings.Contains(in, certPEMHeader) || !strings.Contains(in, certPEMFooter) {
-		return in
-	}
-	start := strings.Index(in, certPEMHeader)
-	if start == -1 {
-		return in
-	}
-	start += len(certPEMHeader)
+		DNSNames:       domaincsr.EmptyIfNil(cert.DNSNames),
+		IPAddresses:    ips,
+		EmailAddresses: domaincsr.EmptyIfNil(cert.EmailAddresses),
+		URIs:           uris,
 
-	end := strings.Index(in[start:], certPEMFooter)
-	if end == -1 {
-		return in
-	}
-	end = start + end
+		KeyUsage:    KeyUsageNames(cert.KeyUsage),
+		ExtKeyUsage: ExtKeyUsageNames(cert.ExtKeyUsage, cert.UnknownExtKeyUsage),
 
-	return strings.TrimSpace(in[start:end])
-}
+		PublicKeyAlgorithm: keyAlg,
+		PublicKeySize:      keySize,
+		PublicKeyCurve:     curve,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
 
-func stripWhitespace(s string) string {
-	replacer := strings.NewReplacer(
-		"\n", "",
-		"\t", "",
-		" ", "",
-	)
-	return replacer.Replace(strings.TrimSpace(s))
-}
+		FingerprintSHA1:   fingerprint(cert.Raw, "sha1"),
+		FingerprintSHA256: fingerprint(cert.Raw, "sha256"),
+		SubjectKeyID:      domainkey.ColonHex(cert.SubjectKeyId),
+		AuthorityKeyID:    domainkey.ColonHex(cert.AuthorityKeyId),
 
-func wrap64(s string) string {
-	const line = 64
-	var b strings.Builder
-	for i := 0; i < len(s); i += line {
-		end := i + line
-		if end > len(s) {
-			end = len(s)
-		}
-		b.WriteString(s[i:end])
-		b.WriteString("\n")
+		CRLDistributionPoints: domaincsr.EmptyIfNil(cert.CRLDistributionPoints),
+		OCSPServers:           domaincsr.EmptyIfNil(cert.OCSPServer),
+		IssuingCertificateURL: domaincsr.EmptyIfNil(cert.IssuingCertificateURL),
+
+		PolicyOIDs:        oidStrings(cert.PolicyIdentifiers),
+		ValidationLevel:   ValidationLevel(cert.PolicyIdentifiers),
+		PathLenConstraint: pathLenConstraint(cert),
+
+		SCTs:           scts,
+		SCTError:       sctError,
+		Precertificate: IsPrecertificate(cert),
 	}
-	return b.String()
 }
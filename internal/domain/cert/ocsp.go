@@ -0,0 +1,151 @@
+package cert
+
+import (
+	"crypto"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+
+	domainkey "my-tools/internal/domain/key"
+)
+
+// ocspResponseStatuses RFC 6960 OCSPResponseStatus
+var ocspResponseStatuses = map[ocsp.ResponseStatus]string{
+	ocsp.Success:           "successful",
+	ocsp.Malformed:         "malformedRequest",
+	ocsp.InternalError:     "internalError",
+	ocsp.TryLater:          "tryLater",
+	ocsp.SignatureRequired: "sigRequired",
+	ocsp.Unauthorized:      "unauthorized",
+}
+
+// OCSPInfo OCSP 响应解析结果
+type OCSPInfo struct {
+	// ResponseStatus 不是 successful 时，其余字段均为空
+	ResponseStatus string `json:"responseStatus"`
+
+	// CertStatus good / revoked / unknown
+	CertStatus       string     `json:"certStatus,omitempty"`
+	SerialNumber     string     `json:"serialNumber,omitempty"`
+	SerialHex        string     `json:"serialHex,omitempty"`
+	ProducedAt       *time.Time `json:"producedAt,omitempty"`
+	ThisUpdate       *time.Time `json:"thisUpdate,omitempty"`
+	NextUpdate       *time.Time `json:"nextUpdate,omitempty"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+	// Expired 当前时间已超过 nextUpdate
+	Expired bool `json:"expired"`
+
+	// ResponderName 与 ResponderKeyHash 二选一
+	ResponderName      string    `json:"responderName,omitempty"`
+	ResponderKeyHash   string    `json:"responderKeyHash,omitempty"`
+	IssuerHash         string    `json:"issuerHash,omitempty"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm,omitempty"`
+	ResponderCert      *CertInfo `json:"responderCert,omitempty"`
+
+	SignatureChecked bool   `json:"signatureChecked"`
+	SignatureValid   bool   `json:"signatureValid"`
+	SignatureError   string `json:"signatureError,omitempty"`
+}
+
+// DecodeOCSPResponse 解析 DER / base64 / PEM 格式的 OCSP 响应；填写 issuerPEM 时校验响应签名
+func DecodeOCSPResponse(data []byte, issuerPEM string) (OCSPInfo, error) {
+	der, err := decodeDERInput(data, "OCSP RESPONSE")
+	if err != nil {
+		return OCSPInfo{}, err
+	}
+
+	resp, err := ocsp.ParseResponse(der, nil)
+	if err != nil {
+		var re ocsp.ResponseError
+		if errors.As(err, &re) {
+			return OCSPInfo{ResponseStatus: ocspResponseStatusName(re.Status)}, nil
+		}
+		return OCSPInfo{}, errors.New("invalid ocsp response: " + err.Error())
+	}
+
+	info := OCSPInfo{
+		ResponseStatus:     ocspResponseStatusName(ocsp.Success),
+		CertStatus:         ocspCertStatusName(resp.Status),
+		SerialNumber:       resp.SerialNumber.String(),
+		SerialHex:          domainkey.ColonHex(resp.SerialNumber.Bytes()),
+		ProducedAt:         timePtr(resp.ProducedAt),
+		ThisUpdate:         timePtr(resp.ThisUpdate),
+		NextUpdate:         timePtr(resp.NextUpdate),
+		ResponderKeyHash:   domainkey.ColonHex(resp.ResponderKeyHash),
+		IssuerHash:         hashName(resp.IssuerHash),
+		SignatureAlgorithm: resp.SignatureAlgorithm.String(),
+	}
+	if info.NextUpdate != nil {
+		info.Expired = time.Now().After(*info.NextUpdate)
+	}
+	if resp.Status == ocsp.Revoked {
+		info.RevokedAt = timePtr(resp.RevokedAt)
+		info.RevocationReason = revocationReasonName(resp.RevocationReason)
+	}
+	if len(resp.RawResponderName) > 0 {
+		var rdn pkix.RDNSequence
+		if _, err := asn1.Unmarshal(resp.RawResponderName, &rdn); err == nil {
+			var name pkix.Name
+			name.FillFromRDNSequence(&rdn)
+			info.ResponderName = name.String()
+		}
+	}
+	if resp.Certificate != nil {
+		ci := newCertInfo(encodeCertPEM(resp.Certificate), resp.Certificate)
+		info.ResponderCert = &ci
+	}
+
+	if strings.TrimSpace(issuerPEM) != "" {
+		issuer, err := parseFirstCert(issuerPEM)
+		if err != nil {
+			return OCSPInfo{}, err
+		}
+		info.SignatureChecked = true
+		// 带签发者再解析一次：响应内嵌了委托签名证书时，会同时校验该证书由签发者签发
+		if _, err := ocsp.ParseResponse(der, issuer); err != nil {
+			info.SignatureError = err.Error()
+		} else {
+			info.SignatureValid = true
+		}
+	}
+
+	return info, nil
+}
+
+func ocspResponseStatusName(s ocsp.ResponseStatus) string {
+	if name, ok := ocspResponseStatuses[s]; ok {
+		return name
+	}
+	return "unknown(" + strconv.Itoa(int(s)) + ")"
+}
+
+func ocspCertStatusName(s int) string {
+	switch s {
+	case ocsp.Good:
+		return "good"
+	case ocsp.Revoked:
+		return "revoked"
+	default:
+		return "unknown"
+	}
+}
+
+func hashName(h crypto.Hash) string {
+	if h == 0 {
+		return ""
+	}
+	return h.String()
+}
+
+func timePtr(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
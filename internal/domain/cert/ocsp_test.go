@@ -0,0 +1,103 @@
+package cert
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestDecodeOCSPResponse(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	now := time.Now().UTC().Truncate(time.Minute)
+
+	// 委托签名证书：由中间 CA 签发，带 OCSPSigning EKU
+	responder := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(10),
+		Subject:      pkix.Name{CommonName: "Test OCSP Responder"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(24 * time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning},
+	}, inter)
+
+	good, err := ocsp.CreateResponse(inter.cert, inter.cert, ocsp.Response{
+		Status:       ocsp.Good,
+		SerialNumber: leaf.cert.SerialNumber,
+		ThisUpdate:   now,
+		NextUpdate:   now.Add(24 * time.Hour),
+	}, inter.key)
+	if err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+	revoked, err := ocsp.CreateResponse(inter.cert, responder.cert, ocsp.Response{
+		Status:           ocsp.Revoked,
+		SerialNumber:     leaf.cert.SerialNumber,
+		ThisUpdate:       now,
+		RevokedAt:        now.Add(-time.Hour),
+		RevocationReason: ocsp.Superseded,
+		Certificate:      responder.cert,
+	}, responder.key)
+	if err != nil {
+		t.Fatalf("create response: %v", err)
+	}
+
+	t.Run("good signed by issuer", func(t *testing.T) {
+		got, err := DecodeOCSPResponse([]byte(base64.StdEncoding.EncodeToString(good)), inter.pem)
+		if err != nil {
+			t.Fatalf("DecodeOCSPResponse() error = %v", err)
+		}
+		if got.ResponseStatus != "successful" || got.CertStatus != "good" || got.SerialNumber != "3" {
+			t.Errorf("info = %+v", got)
+		}
+		if got.ResponderName != "CN=Test Intermediate CA" || got.NextUpdate == nil || got.Expired {
+			t.Errorf("responder = %q nextUpdate = %v", got.ResponderName, got.NextUpdate)
+		}
+		if !got.SignatureChecked || !got.SignatureValid {
+			t.Errorf("signature error = %q", got.SignatureError)
+		}
+	})
+
+	t.Run("good wrong issuer", func(t *testing.T) {
+		got, err := DecodeOCSPResponse(good, root.pem)
+		if err != nil {
+			t.Fatalf("DecodeOCSPResponse() error = %v", err)
+		}
+		if !got.SignatureChecked || got.SignatureValid || got.SignatureError == "" {
+			t.Errorf("expected signature failure, got %+v", got)
+		}
+	})
+
+	t.Run("revoked delegated responder", func(t *testing.T) {
+		got, err := DecodeOCSPResponse(revoked, inter.pem)
+		if err != nil {
+			t.Fatalf("DecodeOCSPResponse() error = %v", err)
+		}
+		if got.CertStatus != "revoked" || got.RevocationReason != "superseded" || got.RevokedAt == nil {
+			t.Errorf("info = %+v", got)
+		}
+		if got.ResponderCert == nil || got.ResponderCert.Subject != "CN=Test OCSP Responder" {
+			t.Errorf("responder cert = %+v", got.ResponderCert)
+		}
+		if !got.SignatureValid {
+			t.Errorf("signature error = %q", got.SignatureError)
+		}
+	})
+
+	t.Run("unauthorized", func(t *testing.T) {
+		got, err := DecodeOCSPResponse(ocsp.UnauthorizedErrorResponse, "")
+		if err != nil {
+			t.Fatalf("DecodeOCSPResponse() error = %v", err)
+		}
+		if got.ResponseStatus != "unauthorized" || got.CertStatus != "" {
+			t.Errorf("info = %+v", got)
+		}
+	})
+
+	if _, err := DecodeOCSPResponse([]byte("not ocsp"), ""); err == nil {
+		t.Errorf("expected error for invalid input")
+	}
+}
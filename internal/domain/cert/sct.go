@@ -0,0 +1,194 @@
+package cert
+
+import (
+	"crypto/x509"
+	_ "embed"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	oidSCTList         = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 2}
+	oidCTPrecertPoison = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 11129, 2, 4, 3}
+)
+
+// ctLogListJSON 离线 CT 日志列表，格式与 Google 发布的 log_list.json（v3）一致，
+// 可直接用官方文件替换以获得最新列表。当前日志取自 Chrome 140 内置的日志列表，
+// 另外保留了部分已退役的日志，以便识别旧证书中的 SCT
+//
+//go:embed ctlogs.json
+var ctLogListJSON []byte
+
+// RFC 5246 7.4.1.4.1 HashAlgorithm / SignatureAlgorithm
+var (
+	tlsHashAlgorithms      = []string{"none", "MD5", "SHA1", "SHA224", "SHA256", "SHA384", "SHA512"}
+	tlsSignatureAlgorithms = []string{"anonymous", "RSA", "DSA", "ECDSA"}
+)
+
+// SCT 证书中嵌入的 Signed Certificate Timestamp（RFC 6962 3.2）
+type SCT struct {
+	Version int `json:"version"`
+	// LogID 日志公钥的 SHA-256，base64 编码，与 log_list.json 中的 log_id 一致
+	LogID string `json:"logId"`
+	// LogName / LogOperator 在离线日志列表中找不到时为空
+	LogName            string    `json:"logName,omitempty"`
+	LogOperator        string    `json:"logOperator,omitempty"`
+	Timestamp          time.Time `json:"timestamp"`
+	HashAlgorithm      string    `json:"hashAlgorithm"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	Signature          string    `json:"signature"`
+	Extensions         string    `json:"extensions,omitempty"`
+}
+
+type ctLog struct {
+	description string
+	operator    string
+}
+
+var (
+	ctLogsOnce sync.Once
+	ctLogs     map[string]ctLog
+)
+
+// lookupCTLog 按 base64 log ID 查找离线日志列表中的日志
+func lookupCTLog(logID string) (ctLog, bool) {
+	ctLogsOnce.Do(func() {
+		ctLogs, _ = parseCTLogList(ctLogListJSON)
+	})
+	l, ok := ctLogs[logID]
+	return l, ok
+}
+
+// parseCTLogList 解析 log_list.json（v3），返回 log ID 到日志的映射
+func parseCTLogList(data []byte) (map[string]ctLog, error) {
+	var list struct {
+		Operators []struct {
+			Name string `json:"name"`
+			Logs []struct {
+				Description string `json:"description"`
+				LogID       string `json:"log_id"`
+			} `json:"logs"`
+		} `json:"operators"`
+	}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+
+	logs := map[string]ctLog{}
+	for _, op := range list.Operators {
+		for _, l := range op.Logs {
+			logs[l.LogID] = ctLog{description: l.Description, operator: op.Name}
+		}
+	}
+	return logs, nil
+}
+
+// ParseSCTList 解析 SCT 列表扩展的值（外层 OCTET STRING 包裹的 TLS 编码 SignedCertificateTimestampList）
+func ParseSCTList(extValue []byte) ([]SCT, error) {
+	var raw []byte
+	if rest, err := asn1.Unmarshal(extValue, &raw); err != nil || len(rest) > 0 {
+		return nil, errors.New("invalid sct list: not an octet string")
+	}
+
+	list, rest, ok := readTLSVector(raw)
+	if !ok || len(rest) > 0 {
+		return nil, errors.New("invalid sct list: bad length")
+	}
+
+	scts := []SCT{}
+	for len(list) > 0 {
+		var item []byte
+		item, list, ok = readTLSVector(list)
+		if !ok {
+			return nil, errors.New("invalid sct list: bad sct length")
+		}
+		sct, err := parseSCT(item)
+		if err != nil {
+			return nil, err
+		}
+		scts = append(scts, sct)
+	}
+	return scts, nil
+}
+
+// parseSCT 解析单个 v1 SCT：version(1) logID(32) timestamp(8) extensions<2> hash(1) sig(1) signature<2>
+func parseSCT(b []byte) (SCT, error) {
+	if len(b) < 1+32+8 {
+		return SCT{}, errors.New("invalid sct: too short")
+	}
+	if b[0] != 0 {
+		return SCT{}, errors.New("unsupported sct version " + strconv.Itoa(int(b[0])+1))
+	}
+
+	ms := binary.BigEndian.Uint64(b[33:41])
+	sct := SCT{
+		Version:   1,
+		LogID:     base64.StdEncoding.EncodeToString(b[1:33]),
+		Timestamp: time.UnixMilli(int64(ms)).UTC(),
+	}
+	exts, rest, ok := readTLSVector(b[41:])
+	if !ok || len(rest) < 2 {
+		return SCT{}, errors.New("invalid sct: truncated")
+	}
+	sct.Extensions = hex.EncodeToString(exts)
+	sct.HashAlgorithm = tlsAlgorithmName(tlsHashAlgorithms, rest[0])
+	sct.SignatureAlgorithm = tlsAlgorithmName(tlsSignatureAlgorithms, rest[1])
+
+	sig, rest, ok := readTLSVector(rest[2:])
+	if !ok || len(rest) > 0 {
+		return SCT{}, errors.New("invalid sct: bad signature")
+	}
+	sct.Signature = hex.EncodeToString(sig)
+
+	if l, ok := lookupCTLog(sct.LogID); ok {
+		sct.LogName = l.description
+		sct.LogOperator = l.operator
+	}
+	return sct, nil
+}
+
+// readTLSVector 读取 2 字节长度前缀的 TLS 向量
+func readTLSVector(b []byte) (body, rest []byte, ok bool) {
+	if len(b) < 2 {
+		return nil, nil, false
+	}
+	n := int(binary.BigEndian.Uint16(b))
+	if len(b) < 2+n {
+		return nil, nil, false
+	}
+	return b[2 : 2+n], b[2+n:], true
+}
+
+func tlsAlgorithmName(names []string, v byte) string {
+	if int(v) < len(names) {
+		return names[v]
+	}
+	return "unknown(" + strconv.Itoa(int(v)) + ")"
+}
+
+// certSCTs 返回证书中嵌入的 SCT；没有 SCT 扩展时返回空列表
+func certSCTs(c *x509.Certificate) ([]SCT, error) {
+	for _, ext := range c.Extensions {
+		if ext.Id.Equal(oidSCTList) {
+			return ParseSCTList(ext.Value)
+		}
+	}
+	return []SCT{}, nil
+}
+
+// IsPrecertificate 证书带有 CT poison 扩展时为预证书，不能用于 TLS
+func IsPrecertificate(c *x509.Certificate) bool {
+	for _, ext := range c.Extensions {
+		if ext.Id.Equal(oidCTPrecertPoison) {
+			return true
+		}
+	}
+	return false
+}
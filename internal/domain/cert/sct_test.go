@@ -0,0 +1,207 @@
+package cert
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// tlsVector 加上 2 字节长度前缀
+func tlsVector(b []byte) []byte {
+	out := make([]byte, 2, 2+len(b))
+	binary.BigEndian.PutUint16(out, uint16(len(b)))
+	return append(out, b...)
+}
+
+// newTestSCT 按 RFC 6962 编码一个 v1 SCT（ECDSA-SHA256 签名）
+func newTestSCT(t *testing.T, logID string, ts time.Time, sig []byte) []byte {
+	t.Helper()
+	id, err := base64.StdEncoding.DecodeString(logID)
+	if err != nil || len(id) != 32 {
+		t.Fatalf("bad log id %q", logID)
+	}
+	b := append([]byte{0}, id...)
+	b = binary.BigEndian.AppendUint64(b, uint64(ts.UnixMilli()))
+	b = append(b, tlsVector(nil)...)
+	b = append(b, 4, 3)
+	return append(b, tlsVector(sig)...)
+}
+
+func newSCTListExtension(t *testing.T, scts ...[]byte) []byte {
+	t.Helper()
+	var list []byte
+	for _, s := range scts {
+		list = append(list, tlsVector(s)...)
+	}
+	v, err := asn1.Marshal(tlsVector(list))
+	if err != nil {
+		t.Fatalf("marshal sct list: %v", err)
+	}
+	return v
+}
+
+func TestCertInfoSCTs(t *testing.T) {
+	_, inter, _ := newTestChain(t)
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 123e6, time.UTC)
+	known := "pLkJkLQYWBSHuxOizGdwCjw1mAT5G9+443fNDsgN3BA="
+	unknown := base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+	c := newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(10),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{{
+			Id:    oidSCTList,
+			Value: newSCTListExtension(t, newTestSCT(t, known, ts, []byte{1, 2, 3}), newTestSCT(t, unknown, ts, []byte{4})),
+		}},
+	}, inter)
+
+	infos, err := SplitCertChainWithInfo(c.pem)
+	if err != nil {
+		t.Fatalf("SplitCertChainWithInfo() error = %v", err)
+	}
+	info := infos[0]
+	if info.SCTError != "" || len(info.SCTs) != 2 {
+		t.Fatalf("scts = %+v, error = %q", info.SCTs, info.SCTError)
+	}
+
+	s := info.SCTs[0]
+	if s.LogID != known || s.LogOperator != "Google" || s.LogName != "Google 'Pilot' log" {
+		t.Errorf("log = %s / %s / %s", s.LogID, s.LogOperator, s.LogName)
+	}
+	if !s.Timestamp.Equal(ts) {
+		t.Errorf("timestamp = %v, want %v", s.Timestamp, ts)
+	}
+	if s.HashAlgorithm != "SHA256" || s.SignatureAlgorithm != "ECDSA" || s.Signature != "010203" {
+		t.Errorf("signature = %s %s %s", s.HashAlgorithm, s.SignatureAlgorithm, s.Signature)
+	}
+	if info.SCTs[1].LogName != "" {
+		t.Errorf("unknown log should have no name, got %q", info.SCTs[1].LogName)
+	}
+	if info.Precertificate {
+		t.Errorf("should not be a precertificate")
+	}
+}
+
+func TestPrecertificate(t *testing.T) {
+	_, inter, leaf := newTestChain(t)
+	poison, _ := asn1.Marshal(asn1.NullRawValue)
+	c := newTestCert(t, &x509.Certificate{
+		SerialNumber:    big.NewInt(11),
+		Subject:         pkix.Name{CommonName: "example.com"},
+		NotBefore:       time.Now().Add(-time.Hour),
+		NotAfter:        time.Now().Add(time.Hour),
+		ExtraExtensions: []pkix.Extension{{Id: oidCTPrecertPoison, Critical: true, Value: poison}},
+	}, inter)
+
+	if !IsPrecertificate(c.cert) {
+		t.Errorf("expected precertificate")
+	}
+	if IsPrecertificate(leaf.cert) {
+		t.Errorf("leaf is not a precertificate")
+	}
+	infos, err := SplitCertChainWithInfo(leaf.pem)
+	if err != nil {
+		t.Fatalf("SplitCertChainWithInfo() error = %v", err)
+	}
+	if infos[0].SCTs == nil || len(infos[0].SCTs) != 0 {
+		t.Errorf("scts should be empty list, got %v", infos[0].SCTs)
+	}
+}
+
+func TestParseSCTListInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		ext  []byte
+	}{
+		{"not octet string", []byte{0x02, 0x01, 0x00}},
+		{"bad list length", mustMarshal(t, []byte{0x00, 0x10, 0x00})},
+		{"truncated sct", mustMarshal(t, tlsVector(tlsVector([]byte{0, 1, 2})))},
+		{"unsupported version", mustMarshal(t, tlsVector(tlsVector(append([]byte{1}, make([]byte, 60)...))))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSCTList(tt.ext); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, b []byte) []byte {
+	t.Helper()
+	v, err := asn1.Marshal(b)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return v
+}
+
+func TestLookupCTLog(t *testing.T) {
+	tests := []struct {
+		logID    string
+		name     string
+		operator string
+	}{
+		{"GYbUxyiqb/66A294Kk0BkarOLXIxD67OXXBBLSVMx9Q=", "Let's Encrypt 'Oak2026h1'", "Let's Encrypt"},
+		{"FoMtq/CpJQ8P8DqlRf/Iv8gj0IdL9gQpJ/jnHzMT9fo=", "Sectigo 'Tiger2026h1'", "Sectigo"},
+		{"DleUvPOuqT4zGyyZB7P3kN+bwj1xMiXdIaklrGHFTiE=", "Google 'Argon2026h1' log", "Google"},
+		{"cX6V88I4im2x44RJPTHhWqliCHYtQgDgBQzQZ7WmYeI=", "Geomys 'Tuscolo2026h1'", "Geomys"},
+		// 已退役的日志仍要能识别，旧证书中的 SCT 引用它们
+		{"7ku9t3XOYLrhQmkfq+GeZqMPfl+wctiDAMR7iXqo/cs=", "Google 'Rocketeer' log", "Google"},
+	}
+	for _, tt := range tests {
+		l, ok := lookupCTLog(tt.logID)
+		if !ok || l.description != tt.name || l.operator != tt.operator {
+			t.Errorf("lookupCTLog(%s) = %+v, %v, want %s / %s", tt.logID, l, ok, tt.name, tt.operator)
+		}
+	}
+}
+
+// TestCTLogListKeys log ID 是日志公钥（DER）的 SHA-256，带有 key 的条目据此校验
+func TestCTLogListKeys(t *testing.T) {
+	var list struct {
+		Operators []struct {
+			Logs []struct {
+				Description string `json:"description"`
+				LogID       string `json:"log_id"`
+				Key         string `json:"key"`
+			} `json:"logs"`
+		} `json:"operators"`
+	}
+	if err := json.Unmarshal(ctLogListJSON, &list); err != nil {
+		t.Fatalf("ctlogs.json: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, op := range list.Operators {
+		for _, l := range op.Logs {
+			if seen[l.LogID] {
+				t.Errorf("duplicate log ID %s (%s)", l.LogID, l.Description)
+			}
+			seen[l.LogID] = true
+			if l.Key == "" {
+				continue
+			}
+			der, err := base64.StdEncoding.DecodeString(l.Key)
+			if err != nil {
+				t.Errorf("%s: invalid key: %v", l.Description, err)
+				continue
+			}
+			if _, err := x509.ParsePKIXPublicKey(der); err != nil {
+				t.Errorf("%s: invalid key: %v", l.Description, err)
+			}
+			sum := sha256.Sum256(der)
+			if id := base64.StdEncoding.EncodeToString(sum[:]); id != l.LogID {
+				t.Errorf("%s: log_id = %s, SHA-256 of key = %s", l.Description, l.LogID, id)
+			}
+		}
+	}
+}
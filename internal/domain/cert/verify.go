@@ -0,0 +1,338 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// TrustStoreSystem 使用操作系统的根证书库
+	TrustStoreSystem = "system"
+	// TrustStoreCustom 使用调用方上传的根证书
+	TrustStoreCustom = "custom"
+)
+
+// systemCertPool 加载系统根证书库，测试中替换为固定的证书池
+var systemCertPool = x509.SystemCertPool
+
+const (
+	issuerSourceChain      = "chain"
+	issuerSourceTrustStore = "trust_store"
+	issuerSourceSelf       = "self"
+)
+
+// VerifyOptions 证书链验证选项
+type VerifyOptions struct {
+	// TrustStore 取值 system / custom，为空时按 system 处理
+	TrustStore string
+	// RootsPEM 自定义根证书（可包含多个），TrustStore 为 custom 时必填
+	RootsPEM string
+	// CurrentTime 验证时使用的时间，零值表示当前时间
+	CurrentTime time.Time
+}
+
+// ChainLink 证书链中一环的签名校验结果
+type ChainLink struct {
+	Subject string `json:"subject"`
+	Issuer  string `json:"issuer"`
+	// IssuerSource 签发者来源：chain（输入的证书链）/ trust_store（信任库）/ self（自签名），为空表示未找到签发者
+	IssuerSource   string `json:"issuerSource"`
+	SignatureValid bool   `json:"signatureValid"`
+	Error          string `json:"error,omitempty"`
+}
+
+// ChainVerifyResult 证书链验证结果
+type ChainVerifyResult struct {
+	Verified    bool   `json:"verified"`
+	VerifyError string `json:"verifyError,omitempty"`
+	// Order 按 叶子 -> 中间 -> 根 排列后，每个证书在输入中的下标
+	Order   []int       `json:"order"`
+	Ordered []CertInfo  `json:"ordered"`
+	Links   []ChainLink `json:"links"`
+	// OutOfOrder 输入顺序与实际签发路径不一致
+	OutOfOrder bool `json:"outOfOrder"`
+	// MissingIntermediates 路径末端既不是自签名证书，也找不到可信的签发者
+	MissingIntermediates bool   `json:"missingIntermediates"`
+	MissingIssuer        string `json:"missingIssuer,omitempty"`
+	// Unused 不在签发路径上的输入证书下标
+	Unused []int `json:"unused"`
+	// FullChainPEM 重新排序后可直接部署的证书链（不含自签名根证书）
+	FullChainPEM string `json:"fullChainPem"`
+}
+
+// VerifyCertChain 构建 叶子 -> 中间 -> 根 的签发路径，并使用 x509.Verify 做信任校验
+func VerifyCertChain(input string, opts VerifyOptions) (ChainVerifyResult, error) {
+	pems, err := SplitCertChain(input)
+	if err != nil {
+		return ChainVerifyResult{}, err
+	}
+
+	certs := make([]*x509.Certificate, len(pems))
+	for i, p := range pems {
+		c, err := parseCertPEM(p)
+		if err != nil {
+			return ChainVerifyResult{}, err
+		}
+		certs[i] = c
+	}
+
+	roots, rootCerts, err := loadTrustStore(opts)
+	if err != nil {
+		return ChainVerifyResult{}, err
+	}
+
+	order := buildPath(certs)
+
+	res := ChainVerifyResult{
+		Order:   order,
+		Ordered: make([]CertInfo, 0, len(order)),
+		Links:   make([]ChainLink, 0, len(order)),
+		Unused:  []int{},
+	}
+
+	// 只比较路径上证书的相对顺序，夹在中间的无关证书不算乱序
+	onPath := make(map[int]bool, len(order))
+	for pos, idx := range order {
+		onPath[idx] = true
+		if pos > 0 && idx < order[pos-1] {
+			res.OutOfOrder = true
+		}
+		res.Ordered = append(res.Ordered, newCertInfo(pems[idx], certs[idx]))
+	}
+	for i := range certs {
+		if !onPath[i] {
+			res.Unused = append(res.Unused, i)
+		}
+	}
+
+	// x509.Verify：除叶子外的所有输入证书都作为中间证书候选
+	leaf := certs[order[0]]
+	intermediates := x509.NewCertPool()
+	for i, c := range certs {
+		if i != order[0] {
+			intermediates.AddCert(c)
+		}
+	}
+	chains, verr := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   opts.CurrentTime,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if verr != nil {
+		res.VerifyError = verr.Error()
+	} else {
+		res.Verified = true
+	}
+
+	// 逐环校验签名
+	for pos, idx := range order {
+		child := certs[idx]
+		link := ChainLink{
+			Subject: child.Subject.String(),
+			Issuer:  child.Issuer.String(),
+		}
+
+		if pos+1 < len(order) {
+			link.IssuerSource = issuerSourceChain
+			setSignatureResult(&link, certs[order[pos+1]], child)
+		} else if isSelfSigned(child) {
+			link.IssuerSource = issuerSourceSelf
+			setSignatureResult(&link, child, child)
+		} else if anchor, aerr := findAnchor(child, chains, roots, rootCerts, opts.CurrentTime); anchor != nil {
+			link.IssuerSource = issuerSourceTrustStore
+			setSignatureResult(&link, anchor, child)
+		} else if isUnknownAuthority(verr) || isUnknownAuthority(aerr) {
+			link.Error = "issuer certificate not found"
+			res.MissingIntermediates = true
+			res.MissingIssuer = child.Issuer.String()
+		} else if aerr != nil {
+			link.Error = aerr.Error()
+		}
+
+		res.Links = append(res.Links, link)
+	}
+
+	var b strings.Builder
+	for _, idx := range order {
+		if isSelfSigned(certs[idx]) {
+			continue
+		}
+		b.WriteString(pems[idx])
+	}
+	res.FullChainPEM = b.String()
+
+	return res, nil
+}
+
+// loadTrustStore 根据选项加载信任库，custom 模式下同时返回根证书列表用于查找签发者
+func loadTrustStore(opts VerifyOptions) (*x509.CertPool, []*x509.Certificate, error) {
+	switch opts.TrustStore {
+	case "", TrustStoreSystem:
+		pool, err := systemCertPool()
+		if err != nil {
+			return nil, nil, err
+		}
+		return pool, nil, nil
+	case TrustStoreCustom:
+		if strings.TrimSpace(opts.RootsPEM) == "" {
+			return nil, nil, errors.New("root certificates are empty")
+		}
+		pems, err := SplitCertChain(opts.RootsPEM)
+		if err != nil {
+			return nil, nil, err
+		}
+		pool := x509.NewCertPool()
+		rootCerts := make([]*x509.Certificate, 0, len(pems))
+		for _, p := range pems {
+			c, err := parseCertPEM(p)
+			if err != nil {
+				return nil, nil, err
+			}
+			pool.AddCert(c)
+			rootCerts = append(rootCerts, c)
+		}
+		return pool, rootCerts, nil
+	default:
+		return nil, nil, errors.New("unknown trust store: " + opts.TrustStore)
+	}
+}
+
+// buildPath 从叶子证书开始，依次在输入中查找签发者，返回路径上各证书的输入下标
+func buildPath(certs []*x509.Certificate) []int {
+	leaf := findLeaf(certs)
+	order := []int{leaf}
+	used := map[int]bool{leaf: true}
+
+	cur := certs[leaf]
+	for !isSelfSigned(cur) {
+		next := findIssuer(cur, certs, used)
+		if next == -1 {
+			break
+		}
+		order = append(order, next)
+		used[next] = true
+		cur = certs[next]
+	}
+	return order
+}
+
+// findLeaf 找出没有签发过其他输入证书的证书，优先选择非 CA 证书
+func findLeaf(certs []*x509.Certificate) int {
+	candidate := -1
+	for i, c := range certs {
+		issuesOther := false
+		for j, o := range certs {
+			if i != j && issuedBy(o, c) {
+				issuesOther = true
+				break
+			}
+		}
+		if issuesOther {
+			continue
+		}
+		if !c.IsCA {
+			return i
+		}
+		if candidate == -1 {
+			candidate = i
+		}
+	}
+	if candidate == -1 {
+		return 0
+	}
+	return candidate
+}
+
+// findIssuer 在未使用的输入证书中查找 child 的签发者；
+// 优先返回签名校验通过的证书，其次返回仅名称匹配的证书，找不到返回 -1
+func findIssuer(child *x509.Certificate, certs []*x509.Certificate, used map[int]bool) int {
+	nameOnly := -1
+	for i, c := range certs {
+		if used[i] || !bytes.Equal(child.RawIssuer, c.RawSubject) {
+			continue
+		}
+		if checkSignature(c, child) == nil {
+			return i
+		}
+		if nameOnly == -1 {
+			nameOnly = i
+		}
+	}
+	return nameOnly
+}
+
+// findAnchor 查找路径末端证书在信任库中的签发者。
+// 叶子过期、EKU 或名称约束不满足时 x509.Verify 不返回证书链，此时单独校验末端证书，
+// 并把时间限制在它自己的有效期内，只为在信任库（系统库无法遍历）中按 AKID / 主题找到签发者；
+// 找不到时返回这次校验的错误
+func findAnchor(child *x509.Certificate, chains [][]*x509.Certificate, roots *x509.CertPool, rootCerts []*x509.Certificate, at time.Time) (*x509.Certificate, error) {
+	for _, chain := range chains {
+		for i, c := range chain {
+			if c.Equal(child) && i+1 < len(chain) {
+				return chain[i+1], nil
+			}
+		}
+	}
+	for _, r := range rootCerts {
+		if issuedBy(child, r) {
+			return r, nil
+		}
+	}
+	if roots == nil {
+		return nil, nil
+	}
+
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if at.Before(child.NotBefore) {
+		at = child.NotBefore
+	} else if at.After(child.NotAfter) {
+		at = child.NotAfter
+	}
+	probe, err := child.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: at,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, chain := range probe {
+		if len(chain) > 1 {
+			return chain[1], nil
+		}
+	}
+	return nil, nil
+}
+
+// isUnknownAuthority 判断 x509.Verify 的错误是否由找不到签发者引起
+func isUnknownAuthority(err error) bool {
+	var uae x509.UnknownAuthorityError
+	return errors.As(err, &uae)
+}
+
+func setSignatureResult(link *ChainLink, parent, child *x509.Certificate) {
+	if err := checkSignature(parent, child); err != nil {
+		link.Error = err.Error()
+		return
+	}
+	link.SignatureValid = true
+}
+
+// checkSignature 只校验签名本身，不检查 CA 标志等约束
+func checkSignature(parent, child *x509.Certificate) error {
+	return parent.CheckSignature(child.SignatureAlgorithm, child.RawTBSCertificate, child.Signature)
+}
+
+func issuedBy(child, parent *x509.Certificate) bool {
+	return bytes.Equal(child.RawIssuer, parent.RawSubject) && checkSignature(parent, child) == nil
+}
+
+func isSelfSigned(c *x509.Certificate) bool {
+	return issuedBy(c, c)
+}
@@ -0,0 +1,224 @@
+package cert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+// newTestCert 生成测试证书，parent 为 nil 时生成自签名证书
+func newTestCert(t *testing.T, tmpl *x509.Certificate, parent *testCert) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+
+	signer, signerKey := tmpl, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	c, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+
+	return &testCert{
+		cert: c,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+// newTestChain 生成 根 -> 中间 -> 叶子 三级证书
+func newTestChain(t *testing.T) (root, inter, leaf *testCert) {
+	t.Helper()
+
+	now := time.Now()
+	root = newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, nil)
+	inter = newTestCert(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate CA"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(5 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, root)
+	leaf = newTestCert(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com", "www.example.com"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(90 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}, inter)
+	return root, inter, leaf
+}
+
+func TestVerifyCertChain(t *testing.T) {
+	root, inter, leaf := newTestChain(t)
+	custom := VerifyOptions{TrustStore: TrustStoreCustom, RootsPEM: root.pem}
+
+	t.Run("ordered chain", func(t *testing.T) {
+		got, err := VerifyCertChain(leaf.pem+inter.pem, custom)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.Verified {
+			t.Fatalf("expected verified, got error %q", got.VerifyError)
+		}
+		if got.OutOfOrder || got.MissingIntermediates {
+			t.Errorf("unexpected flags: outOfOrder=%v missing=%v", got.OutOfOrder, got.MissingIntermediates)
+		}
+		if len(got.Links) != 2 {
+			t.Fatalf("links = %d, want 2", len(got.Links))
+		}
+		if got.Links[1].IssuerSource != issuerSourceTrustStore || !got.Links[1].SignatureValid {
+			t.Errorf("last link = %+v, want valid trust_store link", got.Links[1])
+		}
+	})
+
+	t.Run("out of order with root", func(t *testing.T) {
+		got, err := VerifyCertChain(root.pem+leaf.pem+inter.pem, custom)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.OutOfOrder {
+			t.Errorf("expected outOfOrder")
+		}
+		if want := []int{1, 2, 0}; !equalInts(got.Order, want) {
+			t.Errorf("order = %v, want %v", got.Order, want)
+		}
+		if got.Links[2].IssuerSource != issuerSourceSelf {
+			t.Errorf("root link source = %q, want self", got.Links[2].IssuerSource)
+		}
+		// 部署链不含自签名根证书
+		if got.FullChainPEM != leaf.pem+inter.pem {
+			t.Errorf("fullChainPem = %q", got.FullChainPEM)
+		}
+	})
+
+	t.Run("missing intermediate", func(t *testing.T) {
+		got, err := VerifyCertChain(leaf.pem, custom)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Verified {
+			t.Errorf("expected verification failure")
+		}
+		if !got.MissingIntermediates || !strings.Contains(got.MissingIssuer, "Test Intermediate CA") {
+			t.Errorf("missing = %v, issuer = %q", got.MissingIntermediates, got.MissingIssuer)
+		}
+	})
+
+	t.Run("unrelated certificate between path certificates", func(t *testing.T) {
+		other := newTestCert(t, &x509.Certificate{
+			SerialNumber: big.NewInt(8),
+			Subject:      pkix.Name{CommonName: "other.example.com"},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+		}, root)
+		got, err := VerifyCertChain(leaf.pem+other.pem+inter.pem, custom)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.OutOfOrder || !equalInts(got.Unused, []int{1}) {
+			t.Errorf("outOfOrder = %v unused = %v", got.OutOfOrder, got.Unused)
+		}
+	})
+
+	t.Run("expired leaf with system store", func(t *testing.T) {
+		useSystemRoots(t, root)
+		expired := newTestCert(t, &x509.Certificate{
+			SerialNumber: big.NewInt(4),
+			Subject:      pkix.Name{CommonName: "expired.example.com"},
+			NotBefore:    time.Now().Add(-48 * time.Hour),
+			NotAfter:     time.Now().Add(-24 * time.Hour),
+		}, inter)
+		got, err := VerifyCertChain(expired.pem+inter.pem, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Verified || got.VerifyError == "" {
+			t.Errorf("expected verification failure")
+		}
+		if got.MissingIntermediates || got.MissingIssuer != "" {
+			t.Errorf("missing = %v, issuer = %q", got.MissingIntermediates, got.MissingIssuer)
+		}
+		if got.Links[1].IssuerSource != issuerSourceTrustStore || !got.Links[1].SignatureValid {
+			t.Errorf("last link = %+v, want valid trust_store link", got.Links[1])
+		}
+	})
+
+	t.Run("missing intermediate with system store", func(t *testing.T) {
+		useSystemRoots(t, root)
+		got, err := VerifyCertChain(leaf.pem, VerifyOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !got.MissingIntermediates || got.MissingIssuer != "CN=Test Intermediate CA" {
+			t.Errorf("missing = %v, issuer = %q", got.MissingIntermediates, got.MissingIssuer)
+		}
+	})
+
+	t.Run("custom trust store without roots", func(t *testing.T) {
+		if _, err := VerifyCertChain(leaf.pem, VerifyOptions{TrustStore: TrustStoreCustom}); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
+
+// useSystemRoots 在当前测试中用给定的根证书代替系统根证书库
+func useSystemRoots(t *testing.T, roots ...*testCert) {
+	t.Helper()
+	orig := systemCertPool
+	systemCertPool = func() (*x509.CertPool, error) {
+		pool := x509.NewCertPool()
+		for _, r := range roots {
+			pool.AddCert(r.cert)
+		}
+		return pool, nil
+	}
+	t.Cleanup(func() { systemCertPool = orig })
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
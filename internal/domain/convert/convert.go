@@ -0,0 +1,138 @@
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	domainjson "my-tools/internal/domain/json"
+)
+
+// 支持的格式
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+	FormatXML  = "xml"
+	FormatCSV  = "csv"
+)
+
+// ErrUnsupportedFormat 不支持的源格式或目标格式
+var ErrUnsupportedFormat = errors.New("unsupported format")
+
+// jsonNumber JSON 数字的语法
+var jsonNumber = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][-+]?[0-9]+)?$`)
+
+// Options 转换选项
+type Options struct {
+	// Indent JSON、YAML、XML 输出的缩进空格数；JSON 和 XML 为 0 时不换行，YAML 为 0 时按 2
+	Indent int
+	// RootName 转为 XML 时，数据不是只有一个键的对象时使用的根元素名，默认 root
+	RootName string
+	// Delimiter CSV 分隔符，默认逗号
+	Delimiter string
+	// RawStrings CSV 转出时所有单元格都作为字符串，不识别数字、布尔值、null 和 JSON 数组 / 对象
+	RawStrings bool
+}
+
+// Result 转换结果
+type Result struct {
+	From   string
+	To     string
+	Output string
+}
+
+// Convert 在 JSON、YAML、TOML、XML、CSV 之间转换。
+// 先把输入解析为与 JSON 相同的数据结构（数字保持为 json.Number），再输出为目标格式，
+// 所以任意两种格式之间都可以转换；对象的键按字母顺序输出，与 FormatJSON 一致
+func Convert(input, from, to string, opts Options) (Result, error) {
+	from, err := normalizeFormat(from)
+	if err != nil {
+		return Result{}, err
+	}
+	to, err = normalizeFormat(to)
+	if err != nil {
+		return Result{}, err
+	}
+	if strings.TrimSpace(input) == "" {
+		return Result{}, errors.New("input is empty")
+	}
+
+	data, err := decode(input, from, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	out, err := encode(data, to, opts)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{From: from, To: to, Output: out}, nil
+}
+
+func normalizeFormat(format string) (string, error) {
+	f := strings.ToLower(strings.TrimSpace(format))
+	if f == "yml" {
+		f = FormatYAML
+	}
+	switch f {
+	case FormatJSON, FormatYAML, FormatTOML, FormatXML, FormatCSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w %q (use json, yaml, toml, xml or csv)", ErrUnsupportedFormat, format)
+}
+
+func decode(input, format string, opts Options) (interface{}, error) {
+	switch format {
+	case FormatYAML:
+		return decodeYAML(input)
+	case FormatTOML:
+		return decodeTOML(input)
+	case FormatXML:
+		return decodeXML(input)
+	case FormatCSV:
+		return decodeCSV(input, opts)
+	}
+	return domainjson.DecodeJSON(input)
+}
+
+func encode(data interface{}, format string, opts Options) (string, error) {
+	switch format {
+	case FormatYAML:
+		return encodeYAML(data, opts.Indent)
+	case FormatTOML:
+		return encodeTOML(data)
+	case FormatXML:
+		return encodeXML(data, opts)
+	case FormatCSV:
+		return encodeCSV(data, opts)
+	}
+	return domainjson.EncodeJSON(data, opts.Indent)
+}
+
+// typeName 错误信息中的 JSON 类型名
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return "number"
+}
+
+// formatFloat 把解析出的浮点数写成 JSON 数字，整数值保留 .0 以免与整数混淆
+func formatFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package convert
+
+import (
+	"errors"
+	"testing"
+)
+
+// convertOK 转换并检查没有错误
+func convertOK(t *testing.T, input, from, to string, opts Options) string {
+	t.Helper()
+	res, err := Convert(input, from, to, opts)
+	if err != nil {
+		t.Fatalf("Convert(%s -> %s) error = %v", from, to, err)
+	}
+	return res.Output
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	input := `{"big":12345678901234567890,"f":0.30000000000000001,"e":1E5,"s":"123","b":true,"n":null,"list":[1,"a",{"x":[]}],"obj":{"k":"v"}}`
+	want := `{"b":true,"big":12345678901234567890,"e":1E5,"f":0.30000000000000001,"list":[1,"a",{"x":[]}],"n":null,"obj":{"k":"v"},"s":"123"}`
+	for _, format := range []string{FormatJSON, FormatYAML} {
+		out := convertOK(t, input, FormatJSON, format, Options{Indent: 2})
+		if back := convertOK(t, out, format, FormatJSON, Options{}); back != want {
+			t.Errorf("%s round trip =\n%s\nwant\n%s", format, back, want)
+		}
+	}
+}
+
+func TestConvertFormats(t *testing.T) {
+	res, err := Convert("a: 1", " YML ", "JSON", Options{})
+	if err != nil || res.From != FormatYAML || res.To != FormatJSON || res.Output != `{"a":1}` {
+		t.Errorf("Convert() = %+v, %v", res, err)
+	}
+	if _, err := Convert(`{}`, "json", "ini", Options{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("不支持的格式应返回 ErrUnsupportedFormat：%v", err)
+	}
+	if _, err := Convert(" \n", "yaml", "json", Options{}); err == nil {
+		t.Error("空输入应报错")
+	}
+	if _, err := Convert(`{"a":`, "json", "yaml", Options{}); err == nil || errors.Is(err, ErrUnsupportedFormat) {
+		t.Errorf("无效 JSON 应报错：%v", err)
+	}
+}
@@ -0,0 +1,252 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	domainjson "my-tools/internal/domain/json"
+)
+
+// csvPathSeparator 嵌套对象展开为列时键之间的分隔符：{"a":{"b":1}} 对应列 a.b
+const csvPathSeparator = "."
+
+func csvDelimiter(opts Options) (rune, error) {
+	if opts.Delimiter == "" {
+		return ',', nil
+	}
+	r, size := utf8.DecodeRuneInString(opts.Delimiter)
+	if size != len(opts.Delimiter) || r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return 0, fmt.Errorf("invalid CSV delimiter %q", opts.Delimiter)
+	}
+	return r, nil
+}
+
+// encodeCSV 输入为对象数组（单个对象视为一行）。列为所有对象展开后的键的并集，按首次出现的顺序排列；
+// 嵌套对象按 a.b 展开，数组和空对象写成 JSON 文本，null 和缺少的键为空单元格；
+// 同一行中展开后的列名重复（如 {"a":{"b":1},"a.b":2}）时报错
+func encodeCSV(data interface{}, opts Options) (string, error) {
+	comma, err := csvDelimiter(opts)
+	if err != nil {
+		return "", err
+	}
+
+	var items []interface{}
+	switch x := data.(type) {
+	case []interface{}:
+		items = x
+	case map[string]interface{}:
+		items = []interface{}{x}
+	default:
+		return "", fmt.Errorf("CSV requires an array of objects, got %s", typeName(data))
+	}
+
+	var header []string
+	seen := map[string]bool{}
+	rows := make([]map[string]string, 0, len(items))
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return "", fmt.Errorf("CSV requires an array of objects, item %d is %s", i, typeName(item))
+		}
+		row := map[string]string{}
+		var cols []string
+		if err := flattenCSV("", obj, row, &cols); err != nil {
+			return "", fmt.Errorf("item %d: %v", i, err)
+		}
+		for _, c := range cols {
+			if !seen[c] {
+				seen[c] = true
+				header = append(header, c)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = comma
+	names := make([]string, len(header))
+	for i, c := range header {
+		names[i] = csvText(c)
+	}
+	if err := w.Write(names); err != nil {
+		return "", err
+	}
+	record := make([]string, len(header))
+	for _, row := range rows {
+		for i, c := range header {
+			record[i] = row[c]
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+func flattenCSV(prefix string, v interface{}, row map[string]string, cols *[]string) error {
+	if obj, ok := v.(map[string]interface{}); ok && len(obj) > 0 {
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := k
+			if prefix != "" {
+				p = prefix + csvPathSeparator + k
+			}
+			if err := flattenCSV(p, obj[k], row, cols); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, exists := row[prefix]; exists {
+		return fmt.Errorf("duplicate column %q", prefix)
+	}
+	var cell string
+	switch x := v.(type) {
+	case string:
+		cell = csvText(x)
+	case json.Number:
+		cell = string(x)
+	case bool:
+		cell = fmt.Sprint(x)
+	case nil:
+	default:
+		s, err := domainjson.EncodeJSON(x, 0)
+		if err != nil {
+			return err
+		}
+		cell = s
+	}
+	row[prefix] = cell
+	*cols = append(*cols, prefix)
+	return nil
+}
+
+// csvText 防止以 = + - @ 开头的文本在表格软件中被当作公式执行；数字不经过这里，负数保持原样
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// decodeCSV 第一行为表头，每行转为一个对象，列名 a.b 还原为嵌套对象；空单元格不生成键。
+// 默认识别数字（保持原文）、true / false、null 和 JSON 数组 / 对象，RawStrings 时都作为字符串
+func decodeCSV(input string, opts Options) (interface{}, error) {
+	comma, err := csvDelimiter(opts)
+	if err != nil {
+		return nil, err
+	}
+	r := csv.NewReader(strings.NewReader(strings.TrimPrefix(input, "\ufeff")))
+	r.Comma = comma
+
+	header, err := r.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("input is empty")
+		}
+		return nil, fmt.Errorf("invalid CSV: %v", err)
+	}
+	seen := map[string]bool{}
+	for _, h := range header {
+		if seen[h] {
+			return nil, fmt.Errorf("invalid CSV: duplicate column %q", h)
+		}
+		seen[h] = true
+	}
+
+	rows := []interface{}{}
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := r.FieldPos(0)
+		obj := map[string]interface{}{}
+		for i, cell := range record {
+			if cell == "" {
+				continue
+			}
+			var v interface{} = cell
+			if !opts.RawStrings {
+				v = csvValue(cell)
+			}
+			if err := setCSVPath(obj, header[i], v); err != nil {
+				return nil, fmt.Errorf("line %d: %v", line, err)
+			}
+		}
+		rows = append(rows, obj)
+	}
+	return rows, nil
+}
+
+// csvValue 识别单元格的类型，无法识别的保持为字符串
+func csvValue(cell string) interface{} {
+	switch cell {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if jsonNumber.MatchString(cell) {
+		return json.Number(cell)
+	}
+	if strings.HasPrefix(cell, "[") || strings.HasPrefix(cell, "{") {
+		dec := json.NewDecoder(strings.NewReader(cell))
+		dec.UseNumber()
+		var v interface{}
+		if dec.Decode(&v) == nil && !dec.More() {
+			if _, err := dec.Token(); errors.Is(err, io.EOF) {
+				return v
+			}
+		}
+	}
+	return cell
+}
+
+// setCSVPath 按列名中的 . 写入嵌套对象
+func setCSVPath(obj map[string]interface{}, column string, v interface{}) error {
+	parts := strings.Split(column, csvPathSeparator)
+	cur := obj
+	for i, p := range parts[:len(parts)-1] {
+		next, exists := cur[p]
+		if !exists {
+			m := map[string]interface{}{}
+			cur[p] = m
+			cur = m
+			continue
+		}
+		m, ok := next.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("column %q conflicts with column %q", column, strings.Join(parts[:i+1], csvPathSeparator))
+		}
+		cur = m
+	}
+	last := parts[len(parts)-1]
+	if _, exists := cur[last]; exists {
+		return fmt.Errorf("column %q conflicts with another column under the same path", column)
+	}
+	cur[last] = v
+	return nil
+}
@@ -0,0 +1,64 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJSONToCSV(t *testing.T) {
+	input := `[{"id":1,"user":{"name":"a","tags":["x"]},"price":12345678901234567890},{"id":2,"note":"a,b","user":{"name":"b"},"extra":null},{"id":3,"user":{}}]`
+	want := "id,price,user.name,user.tags,extra,note,user\n" +
+		"1,12345678901234567890,a,\"[\"\"x\"\"]\",,,\n" +
+		"2,,b,,,\"a,b\",\n" +
+		"3,,,,,,{}"
+	if got := convertOK(t, input, FormatJSON, FormatCSV, Options{}); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := convertOK(t, `{"a":1,"b":2}`, FormatJSON, FormatCSV, Options{Delimiter: "\t"}); got != "a\tb\n1\t2" {
+		t.Errorf("单个对象 got %q", got)
+	}
+	input = `[{"=cmd":"=1+2","n":-3,"s":"-3","t":"@x","u":"\tx","v":"a=b"}]`
+	want = "'=cmd,n,s,t,u,v\n'=1+2,-3,'-3,'@x,'\tx,a=b"
+	if got := convertOK(t, input, FormatJSON, FormatCSV, Options{}); got != want {
+		t.Errorf("公式注入 got %q, want %q", got, want)
+	}
+}
+
+func TestCSVToJSON(t *testing.T) {
+	input := "\ufeffid,user.name,user.tags,flag,zip,n\n1,a,\"[\"\"x\"\"]\",true,007,0.30000000000000001\n2,,,false,,null\n"
+	want := `[{"flag":true,"id":1,"n":0.30000000000000001,"user":{"name":"a","tags":["x"]},"zip":"007"},{"flag":false,"id":2,"n":null}]`
+	if got := convertOK(t, input, FormatCSV, FormatJSON, Options{}); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	want = `[{"flag":"true","id":"1","n":"0.30000000000000001","user":{"name":"a","tags":"[\"x\"]"},"zip":"007"},{"flag":"false","id":"2","n":"null"}]`
+	if got := convertOK(t, input, FormatCSV, FormatJSON, Options{RawStrings: true}); got != want {
+		t.Errorf("RawStrings got %s, want %s", got, want)
+	}
+
+	if got := convertOK(t, "a;b\n1;2\n", FormatCSV, FormatJSON, Options{Delimiter: ";"}); got != `[{"a":1,"b":2}]` {
+		t.Errorf("分号分隔 got %s", got)
+	}
+}
+
+func TestCSVErrors(t *testing.T) {
+	tests := []struct {
+		input, from, to string
+		opts            Options
+		want            string
+	}{
+		{`[1]`, FormatJSON, FormatCSV, Options{}, "item 0 is number"},
+		{`"x"`, FormatJSON, FormatCSV, Options{}, "got string"},
+		{`[{"a":{"b":1},"a.b":2}]`, FormatJSON, FormatCSV, Options{}, `item 0: duplicate column "a.b"`},
+		{"a,a\n1,2\n", FormatCSV, FormatJSON, Options{}, "duplicate column"},
+		{"a,a.b\n1,2\n", FormatCSV, FormatJSON, Options{}, `line 2: column "a.b" conflicts with column "a"`},
+		{"a,b\n1\n", FormatCSV, FormatJSON, Options{}, "wrong number of fields"},
+		{"a\n1\n", FormatCSV, FormatJSON, Options{Delimiter: "::"}, "invalid CSV delimiter"},
+	}
+	for _, tt := range tests {
+		_, err := Convert(tt.input, tt.from, tt.to, tt.opts)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Convert(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// decodeTOML 解析 TOML。TOML 规定整数为 int64、小数为 float64，按这两种类型转为 json.Number 不会再损失精度；
+// 日期时间转为字符串
+func decodeTOML(input string) (interface{}, error) {
+	var doc map[string]interface{}
+	if err := toml.Unmarshal([]byte(input), &doc); err != nil {
+		return nil, fmt.Errorf("invalid TOML: %v", err)
+	}
+	return fromTOML(doc, "")
+}
+
+func fromTOML(v interface{}, path string) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			converted, err := fromTOML(val, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = converted
+		}
+		return obj, nil
+	case []interface{}:
+		list := make([]interface{}, len(x))
+		for i, val := range x {
+			converted, err := fromTOML(val, joinPath(path, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case int64:
+		return json.Number(strconv.FormatInt(x, 10)), nil
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, fmt.Errorf("%s: TOML value %v cannot be represented in JSON", path, x)
+		}
+		return json.Number(formatFloat(x)), nil
+	case time.Time:
+		return x.Format(time.RFC3339Nano), nil
+	case toml.LocalDate:
+		return x.String(), nil
+	case toml.LocalTime:
+		return x.String(), nil
+	case toml.LocalDateTime:
+		return x.String(), nil
+	}
+	return v, nil
+}
+
+// encodeTOML TOML 文档必须是表，所以根只能是对象；TOML 没有 null，对象中值为 null 的键被省略
+func encodeTOML(data interface{}) (string, error) {
+	obj, ok := data.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("TOML requires an object at the top level, got %s", typeName(data))
+	}
+	doc, err := toTOML(obj, "")
+	if err != nil {
+		return "", err
+	}
+	out, err := toml.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(out), "\n"), nil
+}
+
+func toTOML(v interface{}, path string) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		obj := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			if val == nil {
+				continue
+			}
+			converted, err := toTOML(val, joinPath(path, k))
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = converted
+		}
+		return obj, nil
+	case []interface{}:
+		list := make([]interface{}, len(x))
+		for i, val := range x {
+			p := joinPath(path, strconv.Itoa(i))
+			if val == nil {
+				return nil, fmt.Errorf("%s: TOML has no null value", p)
+			}
+			converted, err := toTOML(val, p)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = converted
+		}
+		return list, nil
+	case json.Number:
+		return tomlNumber(x, path)
+	}
+	return v, nil
+}
+
+// tomlNumber 能写成 int64 的写成整数，否则写成 float64；两者都会丢精度时报错
+func tomlNumber(n json.Number, path string) (interface{}, error) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err == nil && sameDecimal(string(n), strconv.FormatFloat(f, 'g', -1, 64)) {
+		return f, nil
+	}
+	return nil, fmt.Errorf("%s: number %s cannot be represented in TOML without losing precision", path, n)
+}
+
+// sameDecimal 比较两个十进制数字的值是否相等（1.50 与 1.5e0 相等）
+func sameDecimal(a, b string) bool {
+	da, ea, oka := decimalParts(a)
+	db, eb, okb := decimalParts(b)
+	return oka && okb && da == db && ea == eb
+}
+
+// decimalParts 把十进制数字规范化为 符号+有效数字 和指数，值为 0.d1d2... × 10^exp
+func decimalParts(s string) (digits string, exp int, ok bool) {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if err != nil {
+			return "", 0, false
+		}
+		exp, s = e, s[:i]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i+1:]
+	}
+	digits = intPart + frac
+	exp += len(intPart)
+	trimmed := strings.TrimLeft(digits, "0")
+	exp -= len(digits) - len(trimmed)
+	digits = strings.TrimRight(trimmed, "0")
+	if digits == "" {
+		// 0 和 -0 都视为 0
+		return "0", 0, true
+	}
+	return sign + digits, exp, true
+}
+
+// joinPath 错误信息中的位置，用点号分隔
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
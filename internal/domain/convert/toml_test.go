@@ -0,0 +1,59 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTOMLToJSON(t *testing.T) {
+	input := "title = \"x\"\nint = 9223372036854775807\nf = 1.0\ng = 0.1\nd = 1979-05-27\ndt = 1979-05-27T07:32:00Z\n\n[server]\nports = [80, 443]\n\n[[items]]\nid = 1\n"
+	want := `{"d":"1979-05-27","dt":"1979-05-27T07:32:00Z","f":1.0,"g":0.1,"int":9223372036854775807,"items":[{"id":1}],"server":{"ports":[80,443]},"title":"x"}`
+	if got := convertOK(t, input, FormatTOML, FormatJSON, Options{}); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestJSONToTOML(t *testing.T) {
+	got := convertOK(t, `{"a":1.5,"i":-3,"whole":2.0,"skip":null,"s":"x","t":{"k":true},"arr":[{"x":1},{"x":2}]}`, FormatJSON, FormatTOML, Options{})
+	want := "a = 1.5\ni = -3\ns = 'x'\nwhole = 2.0\n\n[[arr]]\nx = 1\n\n[[arr]]\nx = 2\n\n[t]\nk = true"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestJSONToTOMLErrors(t *testing.T) {
+	tests := map[string]string{
+		`[1]`:                             "top level",
+		`{"n":12345678901234567890}`:      "n: number 12345678901234567890",
+		`{"a":{"f":0.30000000000000001}}`: "a.f: number",
+		`{"a":[1,null]}`:                  "a.1: TOML has no null",
+	}
+	for input, want := range tests {
+		_, err := Convert(input, FormatJSON, FormatTOML, Options{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Convert(%s) error = %v, want %q", input, err, want)
+		}
+	}
+	if _, err := Convert("a = ", FormatTOML, FormatJSON, Options{}); err == nil {
+		t.Error("无效 TOML 应报错")
+	}
+}
+
+func TestSameDecimal(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1.50", "1.5", true},
+		{"150e-2", "1.5", true},
+		{"0.001", "1e-3", true},
+		{"-0", "0", true},
+		{"0.30000000000000001", "0.3", false},
+		{"-1", "1", false},
+	}
+	for _, tt := range tests {
+		if got := sameDecimal(tt.a, tt.b); got != tt.want {
+			t.Errorf("sameDecimal(%s, %s) = %v", tt.a, tt.b, got)
+		}
+	}
+}
@@ -0,0 +1,260 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// XML 与 JSON 互转的约定：属性写成 "@名字" 键，元素同时有属性或子元素时文本写成 "#text" 键
+const (
+	xmlAttrPrefix = "@"
+	xmlTextKey    = "#text"
+	// xmlItemName 数组中嵌套的数组没有键名，元素名用 item
+	xmlItemName = "item"
+)
+
+// xmlElement 解析过程中的元素
+type xmlElement struct {
+	name     string
+	attrs    map[string]interface{}
+	children map[string][]interface{}
+	order    []string
+	text     strings.Builder
+}
+
+// decodeXML 解析 XML，结果为 {"根元素名": 值}：
+// 只有文本的元素为字符串；有属性或子元素时为对象，属性为 "@名字"，文本为 "#text"；
+// 同名的子元素合并为数组。文本不做类型推断，数字保持为字符串
+func decodeXML(input string) (interface{}, error) {
+	dec := xml.NewDecoder(strings.NewReader(input))
+	var stack []*xmlElement
+	var root interface{}
+	var rootName string
+
+	for {
+		// RawToken 保留命名空间前缀，元素名写成 prefix:local
+		tok, err := dec.RawToken()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid XML: %v", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if len(stack) == 0 && rootName != "" {
+				return nil, errors.New("invalid XML: multiple root elements")
+			}
+			e := &xmlElement{name: xmlQName(t.Name), attrs: map[string]interface{}{}, children: map[string][]interface{}{}}
+			for _, a := range t.Attr {
+				e.attrs[xmlAttrPrefix+xmlQName(a.Name)] = a.Value
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			if len(stack) == 0 || stack[len(stack)-1].name != xmlQName(t.Name) {
+				return nil, fmt.Errorf("invalid XML: unexpected end element </%s>", xmlQName(t.Name))
+			}
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 {
+				rootName, root = e.name, e.value()
+				continue
+			}
+			parent := stack[len(stack)-1]
+			if _, ok := parent.children[e.name]; !ok {
+				parent.order = append(parent.order, e.name)
+			}
+			parent.children[e.name] = append(parent.children[e.name], e.value())
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			} else if strings.TrimSpace(string(t)) != "" {
+				return nil, errors.New("invalid XML: text outside the root element")
+			}
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("invalid XML: element <%s> is not closed", stack[len(stack)-1].name)
+	}
+	if rootName == "" {
+		return nil, errors.New("invalid XML: no root element")
+	}
+	return map[string]interface{}{rootName: root}, nil
+}
+
+func (e *xmlElement) value() interface{} {
+	text := strings.TrimSpace(e.text.String())
+	if len(e.attrs) == 0 && len(e.children) == 0 {
+		return text
+	}
+	obj := e.attrs
+	for _, name := range e.order {
+		list := e.children[name]
+		if len(list) == 1 {
+			obj[name] = list[0]
+		} else {
+			obj[name] = list
+		}
+	}
+	if text != "" {
+		obj[xmlTextKey] = text
+	}
+	return obj
+}
+
+func xmlQName(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+// encodeXML 只有一个键且值不是数组的对象，以该键为根元素；否则包在 RootName（默认 root）中
+func encodeXML(data interface{}, opts Options) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+	if opts.Indent > 0 {
+		enc.Indent("", strings.Repeat(" ", opts.Indent))
+	}
+
+	name, value := opts.RootName, data
+	if strings.TrimSpace(name) == "" {
+		name = "root"
+	}
+	if obj, ok := data.(map[string]interface{}); ok && len(obj) == 1 {
+		for k, v := range obj {
+			if _, isList := v.([]interface{}); !isList && !strings.HasPrefix(k, xmlAttrPrefix) && k != xmlTextKey {
+				name, value = k, v
+			}
+		}
+	}
+
+	if err := xmlWrite(enc, name, value); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func xmlWrite(enc *xml.Encoder, name string, v interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: xmlName(name)}}
+	var text string
+	var children []string
+	obj, isObject := v.(map[string]interface{})
+
+	switch x := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch {
+			case strings.HasPrefix(k, xmlAttrPrefix):
+				s, ok := xmlScalar(x[k])
+				if !ok {
+					return fmt.Errorf("attribute %s of <%s> must be a string, number, boolean or null", k, name)
+				}
+				start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: xmlName(k[len(xmlAttrPrefix):])}, Value: s})
+			case k == xmlTextKey:
+				s, ok := xmlScalar(x[k])
+				if !ok {
+					return fmt.Errorf("%s of <%s> must be a string, number, boolean or null", xmlTextKey, name)
+				}
+				text = s
+			default:
+				children = append(children, k)
+			}
+		}
+	case []interface{}:
+		// 数组中嵌套的数组：外层一个元素，每项为一个 item 子元素
+		if err := enc.EncodeToken(start); err != nil {
+			return err
+		}
+		for _, item := range x {
+			if err := xmlWrite(enc, xmlItemName, item); err != nil {
+				return err
+			}
+		}
+		return enc.EncodeToken(start.End())
+	default:
+		text, _ = xmlScalar(v)
+	}
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if text != "" {
+		if err := enc.EncodeToken(xml.CharData(text)); err != nil {
+			return err
+		}
+	}
+	if isObject {
+		for _, k := range children {
+			// 数组展开为多个同名元素
+			items, ok := obj[k].([]interface{})
+			if !ok {
+				items = []interface{}{obj[k]}
+			}
+			for _, item := range items {
+				if err := xmlWrite(enc, k, item); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// xmlScalar 标量转为文本，null 为空文本
+func xmlScalar(v interface{}) (string, bool) {
+	switch x := v.(type) {
+	case string:
+		return x, true
+	case json.Number:
+		return string(x), true
+	case bool:
+		if x {
+			return "true", true
+		}
+		return "false", true
+	case nil:
+		return "", true
+	}
+	return "", false
+}
+
+// xmlName 把键名改成合法的 XML 名字：非法字符替换为 _，不能开头的字符前加 _
+func xmlName(key string) string {
+	var b strings.Builder
+	for i, r := range key {
+		valid := unicode.IsLetter(r) || r == '_' || r == ':'
+		if i > 0 {
+			valid = valid || unicode.IsDigit(r) || r == '-' || r == '.'
+		} else if unicode.IsDigit(r) || r == '-' || r == '.' {
+			b.WriteByte('_')
+			valid = true
+		}
+		if valid {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "_"
+	}
+	return b.String()
+}
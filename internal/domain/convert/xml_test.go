@@ -0,0 +1,49 @@
+package convert
+
+import "testing"
+
+func TestXMLToJSON(t *testing.T) {
+	input := `<?xml version="1.0"?>
+<!-- comment -->
+<order xmlns:x="urn:x" id="7">
+  <x:note>hi</x:note>
+  <item sku="a">1</item>
+  <item sku="b">2</item>
+  <total>12345678901234567890</total>
+  <empty/>
+</order>`
+	want := `{"order":{"@id":"7","@xmlns:x":"urn:x","empty":"","item":[{"#text":"1","@sku":"a"},{"#text":"2","@sku":"b"}],"total":"12345678901234567890","x:note":"hi"}}`
+	if got := convertOK(t, input, FormatXML, FormatJSON, Options{}); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestJSONToXML(t *testing.T) {
+	got := convertOK(t, `{"order":{"@id":7,"#text":"t","item":[1,2],"n":null,"1st":"a<b"}}`, FormatJSON, FormatXML, Options{})
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<order id="7">t<_1st>a&lt;b</_1st><item>1</item><item>2</item><n></n></order>`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got = convertOK(t, `[[1],{"a":true}]`, FormatJSON, FormatXML, Options{Indent: 2, RootName: "list"})
+	want = `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + "<list>\n  <item>\n    <item>1</item>\n  </item>\n  <item>\n    <a>true</a>\n  </item>\n</list>"
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestXMLErrors(t *testing.T) {
+	for _, input := range []string{
+		`<a><b></a>`,
+		`<a></a><b></b>`,
+		`<a>`,
+		`text`,
+	} {
+		if _, err := Convert(input, FormatXML, FormatJSON, Options{}); err == nil {
+			t.Errorf("Convert(%q) 应报错", input)
+		}
+	}
+	if _, err := Convert(`{"a":{"@b":[1]}}`, FormatJSON, FormatXML, Options{}); err == nil {
+		t.Error("属性值不是标量应报错")
+	}
+}
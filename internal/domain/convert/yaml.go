@@ -0,0 +1,262 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxYAMLNodes 展开别名后允许的最多节点数，防止 "billion laughs" 式的输入
+const maxYAMLNodes = 1000000
+
+// maxYAMLDepth 允许的最大嵌套深度，也用于发现引用自身的别名
+const maxYAMLDepth = 1000
+
+// yamlFloat YAML 小数的写法：可以省略整数或小数部分（.5、1.）
+var yamlFloat = regexp.MustCompile(`^([-+]?)([0-9]*)(?:\.([0-9]*))?([eE][-+]?[0-9]+)?$`)
+
+// decodeYAML 解析 YAML。通过 yaml.Node 读取原始文本，整数和小数按原样转为 json.Number，不经过 float64；
+// 包含多个文档时返回各文档组成的数组
+func decodeYAML(input string) (interface{}, error) {
+	dec := yaml.NewDecoder(strings.NewReader(input))
+	var docs []interface{}
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %v", err)
+		}
+		d := &yamlDecoder{}
+		v, err := d.value(&doc, 0)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, v)
+	}
+	switch len(docs) {
+	case 0:
+		return nil, errors.New("input is empty")
+	case 1:
+		return docs[0], nil
+	}
+	return docs, nil
+}
+
+type yamlDecoder struct {
+	nodes int
+}
+
+func (d *yamlDecoder) value(n *yaml.Node, depth int) (interface{}, error) {
+	d.nodes++
+	if d.nodes > maxYAMLNodes {
+		return nil, errors.New("YAML has too many nodes after expanding aliases")
+	}
+	if depth > maxYAMLDepth {
+		return nil, fmt.Errorf("line %d: YAML is nested too deeply or an alias refers to itself", n.Line)
+	}
+
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return d.value(n.Content[0], depth+1)
+	case yaml.AliasNode:
+		return d.value(n.Alias, depth+1)
+	case yaml.SequenceNode:
+		list := make([]interface{}, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := d.value(c, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case yaml.MappingNode:
+		obj := map[string]interface{}{}
+		if err := d.mapping(obj, n, depth); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	}
+	return d.scalar(n)
+}
+
+// mapping 把映射的键值写入 obj。合并键 << 只补充 obj 中还没有的键，所以显式写出的键总是优先
+func (d *yamlDecoder) mapping(obj map[string]interface{}, n *yaml.Node, depth int) error {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Kind == yaml.AliasNode {
+			k = k.Alias
+		}
+		if k.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: only scalar mapping keys can be converted", k.Line)
+		}
+
+		if k.ShortTag() == "!!merge" {
+			if err := d.merge(obj, v, depth); err != nil {
+				return err
+			}
+			continue
+		}
+		val, err := d.value(v, depth+1)
+		if err != nil {
+			return err
+		}
+		obj[k.Value] = val
+	}
+	return nil
+}
+
+func (d *yamlDecoder) merge(obj map[string]interface{}, v *yaml.Node, depth int) error {
+	if depth > maxYAMLDepth {
+		return fmt.Errorf("line %d: YAML is nested too deeply or an alias refers to itself", v.Line)
+	}
+	if v.Kind == yaml.AliasNode {
+		v = v.Alias
+	}
+	sources := []*yaml.Node{v}
+	if v.Kind == yaml.SequenceNode {
+		sources = v.Content
+	}
+	for _, src := range sources {
+		if src.Kind == yaml.AliasNode {
+			src = src.Alias
+		}
+		if src.Kind != yaml.MappingNode {
+			return fmt.Errorf("line %d: merge key << requires a mapping or a list of mappings", v.Line)
+		}
+		merged := map[string]interface{}{}
+		if err := d.mapping(merged, src, depth+1); err != nil {
+			return err
+		}
+		for k, val := range merged {
+			if _, ok := obj[k]; !ok {
+				obj[k] = val
+			}
+		}
+	}
+	return nil
+}
+
+func (d *yamlDecoder) scalar(n *yaml.Node) (interface{}, error) {
+	switch n.ShortTag() {
+	case "!!null":
+		return nil, nil
+	case "!!bool":
+		var b bool
+		if err := n.Decode(&b); err != nil {
+			return nil, fmt.Errorf("line %d: %v", n.Line, err)
+		}
+		return b, nil
+	case "!!int":
+		return yamlInt(n)
+	case "!!float":
+		return yamlFloatNumber(n)
+	}
+	// 字符串、时间戳、二进制和自定义标签都保留原文
+	return n.Value, nil
+}
+
+// yamlInt 十进制整数原样保留，超出 int64 也不丢精度；0x、0o、0b 等写法换算为十进制
+func yamlInt(n *yaml.Node) (interface{}, error) {
+	s := strings.ReplaceAll(n.Value, "_", "")
+	if t := strings.TrimPrefix(s, "+"); jsonNumber.MatchString(t) {
+		return json.Number(t), nil
+	}
+	// 与 yaml.v3 一致按 Go 的规则识别进制：0x、0o、0b 前缀，0 开头为八进制
+	i, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return n.Value, nil
+	}
+	return json.Number(i.String()), nil
+}
+
+// yamlFloatNumber 小数按原文转为 JSON 数字，.5、1. 之类补全为 0.5、1.0；.inf 和 .nan 在 JSON 中无法表示
+func yamlFloatNumber(n *yaml.Node) (interface{}, error) {
+	s := strings.ReplaceAll(n.Value, "_", "")
+	if t := strings.TrimPrefix(s, "+"); jsonNumber.MatchString(t) {
+		return json.Number(t), nil
+	}
+	m := yamlFloat.FindStringSubmatch(s)
+	if m == nil || (m[2] == "" && m[3] == "") {
+		return nil, fmt.Errorf("line %d: YAML value %s cannot be represented in JSON", n.Line, n.Value)
+	}
+	sign, intPart, frac, exp := m[1], strings.TrimLeft(m[2], "0"), m[3], m[4]
+	if sign == "+" {
+		sign = ""
+	}
+	if intPart == "" {
+		intPart = "0"
+	}
+	if frac == "" && exp == "" {
+		frac = "0"
+	}
+	num := sign + intPart
+	if frac != "" {
+		num += "." + frac
+	}
+	return json.Number(num + exp), nil
+}
+
+// encodeYAML 构造 yaml.Node 输出，数字写原文，保持精度
+func encodeYAML(data interface{}, indent int) (string, error) {
+	if indent <= 0 {
+		indent = 2
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+	if err := enc.Encode(yamlNode(data)); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return strings.TrimRight(buf.String(), "\n"), nil
+}
+
+func yamlNode(v interface{}) *yaml.Node {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			n.Content = append(n.Content, yamlNode(k), yamlNode(x[k]))
+		}
+		return n
+	case []interface{}:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range x {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case string:
+		// 标为 !!str 后，像数字、布尔值的字符串会自动加引号
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: x}
+	case json.Number:
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: string(x)}
+	case bool:
+		if x {
+			return &yaml.Node{Kind: yaml.ScalarNode, Value: "true"}
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: "false"}
+	}
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+}
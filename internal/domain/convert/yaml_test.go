@@ -0,0 +1,45 @@
+package convert
+
+import "testing"
+
+func TestYAMLToJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"数字保持原文", "a: 12345678901234567890\nb: 0.30000000000000001\nc: 1.50\n", `{"a":12345678901234567890,"b":0.30000000000000001,"c":1.50}`},
+		{"其他进制与省略写法", "h: 0x1F\no: 0o17\nu: 1_000\nf: .5\ng: -1.\n", `{"f":0.5,"g":-1.0,"h":31,"o":15,"u":1000}`},
+		{"标量类型", "s: '1'\nb: true\nn: ~\nt: 2024-01-01\ny: yes\n", `{"b":true,"n":null,"s":"1","t":"2024-01-01","y":"yes"}`},
+		{"锚点与合并键", "base: &b {p: 1, q: 2}\nx:\n  q: 3\n  <<: *b\n", `{"base":{"p":1,"q":2},"x":{"p":1,"q":3}}`},
+		{"多个文档", "a: 1\n---\n- 2\n", `[{"a":1},[2]]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertOK(t, tt.input, FormatYAML, FormatJSON, Options{}); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONToYAML(t *testing.T) {
+	got := convertOK(t, `{"s":"true","n":"007","m":"l1\nl2","num":1.0,"list":[{"a":null}],"e":{}}`, FormatJSON, FormatYAML, Options{})
+	want := "e: {}\nlist:\n  - a: null\nm: |-\n  l1\n  l2\nn: \"007\"\nnum: 1.0\ns: \"true\""
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestYAMLErrors(t *testing.T) {
+	for _, input := range []string{
+		"a: [1",
+		"a: .nan",
+		"? [1]\n: x\n",
+		"a: &a [*a]\n",
+	} {
+		if _, err := Convert(input, FormatYAML, FormatJSON, Options{}); err == nil {
+			t.Errorf("Convert(%q) 应报错", input)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package csr
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"net"
+	"strings"
+
+	"my-tools/internal/domain/pkcs8"
+)
+
+// 支持的密钥规格
+const (
+	KeySpecRSA2048   = "RSA-2048"
+	KeySpecRSA3072   = "RSA-3072"
+	KeySpecRSA4096   = "RSA-4096"
+	KeySpecECDSAP256 = "ECDSA-P256"
+	KeySpecECDSAP384 = "ECDSA-P384"
+	KeySpecEd25519   = "Ed25519"
+)
+
+// GenerateOptions 生成 CSR 的参数
+type GenerateOptions struct {
+	CommonName         string
+	Organization       string
+	OrganizationalUnit string
+	Locality           string
+	Province           string
+	Country            string
+
+	DNSNames       []string
+	IPAddresses    []string
+	EmailAddresses []string
+
+	// KeySpec 为空时使用 RSA-2048
+	KeySpec string
+	// Passphrase 不为空时，私钥以 PKCS#8 加密输出
+	Passphrase string
+}
+
+// GenerateResult 生成的 CSR 与私钥
+type GenerateResult struct {
+	CSRPEM        string `json:"csrPem"`
+	PrivateKeyPEM string `json:"privateKeyPem"`
+	KeySpec       string `json:"keySpec"`
+	Encrypted     bool   `json:"encrypted"`
+}
+
+// GenerateCSR 生成私钥和 CSR；CSR 输出经过 NormalizeCSRPEM，与 /csr/format 的格式一致
+func GenerateCSR(opts GenerateOptions) (GenerateResult, error) {
+	cn := strings.TrimSpace(opts.CommonName)
+	if cn == "" {
+		return GenerateResult{}, errors.New("common name is empty")
+	}
+	country := strings.ToUpper(strings.TrimSpace(opts.Country))
+	if country != "" && len(country) != 2 {
+		return GenerateResult{}, errors.New("country must be a 2-letter code")
+	}
+
+	ips := make([]net.IP, 0, len(opts.IPAddresses))
+	for _, s := range trimAll(opts.IPAddresses) {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return GenerateResult{}, errors.New("invalid ip address: " + s)
+		}
+		ips = append(ips, ip)
+	}
+
+	spec := opts.KeySpec
+	if spec == "" {
+		spec = KeySpecRSA2048
+	}
+	key, err := GenerateKey(spec)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	tmpl := &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:         cn,
+			Organization:       nonEmpty(opts.Organization),
+			OrganizationalUnit: nonEmpty(opts.OrganizationalUnit),
+			Locality:           nonEmpty(opts.Locality),
+			Province:           nonEmpty(opts.Province),
+			Country:            nonEmpty(country),
+		},
+		DNSNames:       trimAll(opts.DNSNames),
+		IPAddresses:    ips,
+		EmailAddresses: trimAll(opts.EmailAddresses),
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	csrPEM, err := NormalizeCSRPEM(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))
+	if err != nil {
+		return GenerateResult{}, err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return GenerateResult{}, err
+	}
+	keyBlock := &pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}
+	if opts.Passphrase != "" {
+		encrypted, err := pkcs8.Encrypt(keyDER, opts.Passphrase)
+		if err != nil {
+			return GenerateResult{}, err
+		}
+		keyBlock = &pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encrypted}
+	}
+
+	return GenerateResult{
+		CSRPEM:        csrPEM,
+		PrivateKeyPEM: string(pem.EncodeToMemory(keyBlock)),
+		KeySpec:       spec,
+		Encrypted:     opts.Passphrase != "",
+	}, nil
+}
+
+// GenerateKey 按密钥规格生成私钥
+func GenerateKey(spec string) (crypto.Signer, error) {
+	switch spec {
+	case KeySpecRSA2048:
+		return rsa.GenerateKey(rand.Reader, 2048)
+	case KeySpecRSA3072:
+		return rsa.GenerateKey(rand.Reader, 3072)
+	case KeySpecRSA4096:
+		return rsa.GenerateKey(rand.Reader, 4096)
+	case KeySpecECDSAP256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case KeySpecECDSAP384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case KeySpecEd25519:
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		return priv, err
+	default:
+		return nil, errors.New("unsupported key spec: " + spec)
+	}
+}
+
+func trimAll(in []string) []string {
+	out := make([]string, 0, len(in))
+	for _, s := range in {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func nonEmpty(s string) []string {
+	if s = strings.TrimSpace(s); s == "" {
+		return nil
+	}
+	return []string{s}
+}
@@ -0,0 +1,99 @@
+package csr
+
+import (
+	"encoding/pem"
+	"reflect"
+	"testing"
+
+	"my-tools/internal/domain/pkcs8"
+)
+
+func TestGenerateCSR(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       GenerateOptions
+		wantAlg    string
+		wantSize   int
+		wantKeyPEM string
+		wantErr    bool
+	}{
+		{
+			name:       "default rsa",
+			opts:       GenerateOptions{CommonName: "example.com", Country: "cn", DNSNames: []string{"example.com", " "}},
+			wantAlg:    "RSA",
+			wantSize:   2048,
+			wantKeyPEM: "PRIVATE KEY",
+		},
+		{
+			name:       "ecdsa encrypted",
+			opts:       GenerateOptions{CommonName: "example.com", KeySpec: KeySpecECDSAP384, Passphrase: "secret", IPAddresses: []string{"10.0.0.1"}},
+			wantAlg:    "ECDSA",
+			wantSize:   384,
+			wantKeyPEM: pkcs8.EncryptedPEMType,
+		},
+		{
+			name:       "ed25519",
+			opts:       GenerateOptions{CommonName: "example.com", KeySpec: KeySpecEd25519},
+			wantAlg:    "Ed25519",
+			wantSize:   256,
+			wantKeyPEM: "PRIVATE KEY",
+		},
+		{
+			name:    "missing common name",
+			opts:    GenerateOptions{KeySpec: KeySpecECDSAP256},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported key spec",
+			opts:    GenerateOptions{CommonName: "example.com", KeySpec: "DSA-1024"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid ip",
+			opts:    GenerateOptions{CommonName: "example.com", KeySpec: KeySpecECDSAP256, IPAddresses: []string{"not-an-ip"}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateCSR(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateCSR() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			// 输出应与 /csr/format 的规范化结果完全一致
+			normalized, err := NormalizeCSRPEM(got.CSRPEM)
+			if err != nil || normalized != got.CSRPEM {
+				t.Errorf("csr is not normalized: %q", got.CSRPEM)
+			}
+
+			info, err := ParseCSR(got.CSRPEM)
+			if err != nil {
+				t.Fatalf("ParseCSR() error = %v", err)
+			}
+			if info.Fields.CommonName != "example.com" || !info.SignatureValid {
+				t.Errorf("info = %+v", info)
+			}
+			if info.PublicKeyAlgorithm != tt.wantAlg || info.PublicKeySize != tt.wantSize {
+				t.Errorf("public key = %s %d, want %s %d", info.PublicKeyAlgorithm, info.PublicKeySize, tt.wantAlg, tt.wantSize)
+			}
+			if tt.opts.Country != "" && !reflect.DeepEqual(info.Fields.Country, []string{"CN"}) {
+				t.Errorf("country = %v", info.Fields.Country)
+			}
+
+			block, _ := pem.Decode([]byte(got.PrivateKeyPEM))
+			if block == nil || block.Type != tt.wantKeyPEM {
+				t.Fatalf("private key pem type = %v, want %s", block, tt.wantKeyPEM)
+			}
+			if got.Encrypted {
+				if _, err := pkcs8.Decrypt(block.Bytes, tt.opts.Passphrase); err != nil {
+					t.Errorf("decrypt private key: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -3,73 +3,37 @@ package csr
 import (
 	"errors"
 	"strings"
-)
 
-const (
-	pemHeader = "-----BEGIN CERTIFICATE REQUEST-----"
-	pemFooter = "-----END CERTIFICATE REQUEST-----"
+	"my-tools/internal/domain/pemscan"
 )
 
+// csrPEMTypes CSR PEM 块的类型，NEW CERTIFICATE REQUEST 为旧版 Netscape / IIS 的写法
+var csrPEMTypes = []string{"CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST"}
+
+// NormalizeCSRPEM 格式化 CSR PEM；输入可以带各种转义和引用前缀，也可以是不带 BEGIN / END 行的 base64
 func NormalizeCSRPEM(input string) (string, error) {
 	in := strings.TrimSpace(input)
 	if in == "" {
 		return "", errors.New("csr is empty")
 	}
 
-	in = strings.ReplaceAll(in, "\\r\\n", "\n")
-	in = strings.ReplaceAll(in, "\\n", "\n")
-	in = strings.ReplaceAll(in, "\r\n", "\n")
-	in = strings.ReplaceAll(in, "\r", "\n")
-
-	body := extractCSRBody(in)
-	body = stripWhitespace(body)
-	if body == "" {
-		return "", errors.New("csr body is empty")
-	}
-
-	wrapped := wrap64(body)
-	return pemHeader + "\n" + wrapped + pemFooter + "\n", nil
-}
-
-func extractCSRBody(in string) string {
-	if !strings.Contains(in, pemHeader) || !strings.Contains(in, pemFooter) {
-		return in
+	res := pemscan.Scan(in)
+	if blocks := res.Find(csrPEMTypes...); len(blocks) > 0 {
+		if blocks[0].Empty() {
+			return "", errors.New("csr body is empty")
+		}
+		return blocks[0].As("CERTIFICATE REQUEST").PEM, nil
 	}
-	start := strings.Index(in, pemHeader)
-	if start == -1 {
-		return in
+	if w := res.WarningsFor(csrPEMTypes...); len(w) > 0 {
+		return "", errors.New("incomplete csr: " + w[0].String())
 	}
-	start += len(pemHeader)
-
-	end := strings.Index(in[start:], pemFooter)
-	if end == -1 {
-		return in
+	if len(res.Blocks) > 0 {
+		return "", errors.New("no csr found, input contains " + strings.Join(res.Types(), ", "))
 	}
-	end = start + end
-
-	return strings.TrimSpace(in[start:end])
-}
 
-func stripWhitespace(s string) string {
-	// Remove spaces, tabs and newlines.
-	replacer := strings.NewReplacer(
-		"\n", "",
-		"\t", "",
-		" ", "",
-	)
-	return replacer.Replace(strings.TrimSpace(s))
-}
-
-func wrap64(s string) string {
-	const line = 64
-	var b strings.Builder
-	for i := 0; i < len(s); i += line {
-		end := i + line
-		if end > len(s) {
-			end = len(s)
-		}
-		b.WriteString(s[i:end])
-		b.WriteString("\n")
+	b := pemscan.Decode("CERTIFICATE REQUEST", in)
+	if b.Empty() {
+		return "", errors.New("csr body is empty")
 	}
-	return b.String()
+	return b.PEM, nil
 }
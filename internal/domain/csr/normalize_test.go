@@ -5,6 +5,11 @@ import (
 	"testing"
 )
 
+const (
+	pemHeader = "-----BEGIN CERTIFICATE REQUEST-----"
+	pemFooter = "-----END CERTIFICATE REQUEST-----"
+)
+
 func TestNormalizeCSRPEM(t *testing.T) {
 	t.Parallel()
 
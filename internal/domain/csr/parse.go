@@ -0,0 +1,312 @@
+package csr
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+
+	domainkey "my-tools/internal/domain/key"
+)
+
+var (
+	oidChallengePassword = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 7}
+	oidExtensionRequest  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+	oidExtKeyUsage       = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtExtKeyUsage    = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+// oidNames 常见属性与扩展的 OID 名称
+var oidNames = map[string]string{
+	"1.2.840.113549.1.9.1":   "emailAddress",
+	"1.2.840.113549.1.9.2":   "unstructuredName",
+	"1.2.840.113549.1.9.7":   "challengePassword",
+	"1.2.840.113549.1.9.14":  "extensionRequest",
+	"1.3.6.1.4.1.311.13.2.3": "osVersion",
+	"1.3.6.1.4.1.311.21.20":  "requestClientInfo",
+	"1.3.6.1.4.1.311.13.2.2": "enrollmentCSP",
+	"2.5.29.14":              "subjectKeyIdentifier",
+	"2.5.29.15":              "keyUsage",
+	"2.5.29.17":              "subjectAltName",
+	"2.5.29.19":              "basicConstraints",
+	"2.5.29.32":              "certificatePolicies",
+	"2.5.29.37":              "extKeyUsage",
+	"1.3.6.1.5.5.7.1.1":      "authorityInfoAccess",
+	"1.3.6.1.5.5.7.1.24":     "tlsFeature",
+}
+
+var keyUsageBitNames = []string{
+	"Digital Signature",
+	"Content Commitment",
+	"Key Encipherment",
+	"Data Encipherment",
+	"Key Agreement",
+	"Certificate Sign",
+	"CRL Sign",
+	"Encipher Only",
+	"Decipher Only",
+}
+
+var extKeyUsageOIDNames = map[string]string{
+	"2.5.29.37.0":       "Any",
+	"1.3.6.1.5.5.7.3.1": "TLS Web Server Authentication",
+	"1.3.6.1.5.5.7.3.2": "TLS Web Client Authentication",
+	"1.3.6.1.5.5.7.3.3": "Code Signing",
+	"1.3.6.1.5.5.7.3.4": "E-mail Protection",
+	"1.3.6.1.5.5.7.3.8": "Time Stamping",
+	"1.3.6.1.5.5.7.3.9": "OCSP Signing",
+}
+
+// CSRSubject CSR 主题中的常用字段
+type CSRSubject struct {
+	CommonName         string   `json:"commonName"`
+	Organization       []string `json:"organization"`
+	OrganizationalUnit []string `json:"organizationalUnit"`
+	Locality           []string `json:"locality"`
+	Province           []string `json:"province"`
+	Country            []string `json:"country"`
+}
+
+// CSRExtension CSR 中请求的扩展
+type CSRExtension struct {
+	OID      string `json:"oid"`
+	Name     string `json:"name"`
+	Critical bool   `json:"critical"`
+	Value    string `json:"value"`
+}
+
+// CSRAttribute CSR 属性（challengePassword 等）
+type CSRAttribute struct {
+	OID    string   `json:"oid"`
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// CSRInfo CSR 解析结果
+type CSRInfo struct {
+	PEM     string     `json:"pem"`
+	Subject string     `json:"subject"`
+	Fields  CSRSubject `json:"fields"`
+
+	DNSNames       []string `json:"dnsNames"`
+	IPAddresses    []string `json:"ipAddresses"`
+	EmailAddresses []string `json:"emailAddresses"`
+	URIs           []string `json:"uris"`
+
+	KeyUsage    []string `json:"keyUsage"`
+	ExtKeyUsage []string `json:"extKeyUsage"`
+
+	PublicKeyAlgorithm string `json:"publicKeyAlgorithm"`
+	PublicKeySize      int    `json:"publicKeySize"`
+	PublicKeyCurve     string `json:"publicKeyCurve,omitempty"`
+	SignatureAlgorithm string `json:"signatureAlgorithm"`
+	SignatureValid     bool   `json:"signatureValid"`
+	SignatureError     string `json:"signatureError,omitempty"`
+
+	Extensions        []CSRExtension `json:"extensions"`
+	Attributes        []CSRAttribute `json:"attributes"`
+	ChallengePassword string         `json:"challengePassword,omitempty"`
+}
+
+// ParseCSR 规范化并解析 CSR
+func ParseCSR(input string) (CSRInfo, error) {
+	normalized, err := NormalizeCSRPEM(input)
+	if err != nil {
+		return CSRInfo{}, err
+	}
+
+	req, err := ParseCSRPEM(normalized)
+	if err != nil {
+		return CSRInfo{}, err
+	}
+
+	return newCSRInfo(normalized, req), nil
+}
+
+// ParseCSRPEM 将单个 CSR PEM 解析为 x509.CertificateRequest
+func ParseCSRPEM(csrPEM string) (*x509.CertificateRequest, error) {
+	block, _ := pem.Decode([]byte(csrPEM))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, errors.New("invalid csr: " + err.Error())
+	}
+	return req, nil
+}
+
+func newCSRInfo(csrPEM string, req *x509.CertificateRequest) CSRInfo {
+	ips := make([]string, len(req.IPAddresses))
+	for i, ip := range req.IPAddresses {
+		ips[i] = ip.String()
+	}
+	uris := make([]string, len(req.URIs))
+	for i, u := range req.URIs {
+		uris[i] = u.String()
+	}
+	keyAlg, keySize, curve := domainkey.PublicKeyInfo(req.PublicKey)
+
+	info := CSRInfo{
+		PEM:     csrPEM,
+		Subject: req.Subject.String(),
+		Fields:  newCSRSubject(req.Subject),
+
+		DNSNames:       EmptyIfNil(req.DNSNames),
+		IPAddresses:    ips,
+		EmailAddresses: EmptyIfNil(req.EmailAddresses),
+		URIs:           uris,
+
+		KeyUsage:    []string{},
+		ExtKeyUsage: []string{},
+
+		PublicKeyAlgorithm: keyAlg,
+		PublicKeySize:      keySize,
+		PublicKeyCurve:     curve,
+		SignatureAlgorithm: req.SignatureAlgorithm.String(),
+
+		Extensions: make([]CSRExtension, 0, len(req.Extensions)),
+		Attributes: []CSRAttribute{},
+	}
+
+	if err := req.CheckSignature(); err != nil {
+		info.SignatureError = err.Error()
+	} else {
+		info.SignatureValid = true
+	}
+
+	for _, ext := range req.Extensions {
+		e := CSRExtension{
+			OID:      ext.Id.String(),
+			Name:     oidName(ext.Id),
+			Critical: ext.Critical,
+			Value:    hex.EncodeToString(ext.Value),
+		}
+		switch {
+		case ext.Id.Equal(oidExtKeyUsage):
+			info.KeyUsage = parseKeyUsage(ext.Value)
+		case ext.Id.Equal(oidExtExtKeyUsage):
+			info.ExtKeyUsage = parseExtKeyUsage(ext.Value)
+		}
+		info.Extensions = append(info.Extensions, e)
+	}
+
+	for _, attr := range parseAttributes(req.RawTBSCertificateRequest) {
+		if attr.OID == oidChallengePassword.String() && len(attr.Values) > 0 {
+			info.ChallengePassword = attr.Values[0]
+		}
+		info.Attributes = append(info.Attributes, attr)
+	}
+
+	return info
+}
+
+func newCSRSubject(name pkix.Name) CSRSubject {
+	return CSRSubject{
+		CommonName:         name.CommonName,
+		Organization:       EmptyIfNil(name.Organization),
+		OrganizationalUnit: EmptyIfNil(name.OrganizationalUnit),
+		Locality:           EmptyIfNil(name.Locality),
+		Province:           EmptyIfNil(name.Province),
+		Country:            EmptyIfNil(name.Country),
+	}
+}
+
+// tbsCertificateRequest 与 RFC 2986 CertificationRequestInfo 对应，只用于读取原始属性
+type tbsCertificateRequest struct {
+	Raw           asn1.RawContent
+	Version       int
+	Subject       asn1.RawValue
+	PublicKey     asn1.RawValue
+	RawAttributes []asn1.RawValue `asn1:"tag:0"`
+}
+
+type rawAttribute struct {
+	Type   asn1.ObjectIdentifier
+	Values []asn1.RawValue `asn1:"set"`
+}
+
+// parseAttributes 读取 CSR 属性；x509 包会跳过 challengePassword 这类非扩展属性，因此这里自行解析
+func parseAttributes(rawTBS []byte) []CSRAttribute {
+	var tbs tbsCertificateRequest
+	if _, err := asn1.Unmarshal(rawTBS, &tbs); err != nil {
+		return nil
+	}
+
+	out := make([]CSRAttribute, 0, len(tbs.RawAttributes))
+	for _, raw := range tbs.RawAttributes {
+		var attr rawAttribute
+		if _, err := asn1.Unmarshal(raw.FullBytes, &attr); err != nil {
+			continue
+		}
+		a := CSRAttribute{
+			OID:    attr.Type.String(),
+			Name:   oidName(attr.Type),
+			Values: []string{},
+		}
+		// 扩展请求已经在 Extensions 中展示
+		if !attr.Type.Equal(oidExtensionRequest) {
+			for _, v := range attr.Values {
+				a.Values = append(a.Values, attributeValueString(v))
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+func attributeValueString(v asn1.RawValue) string {
+	var s string
+	if _, err := asn1.Unmarshal(v.FullBytes, &s); err == nil {
+		return s
+	}
+	return hex.EncodeToString(v.FullBytes)
+}
+
+func parseKeyUsage(der []byte) []string {
+	var bits asn1.BitString
+	if _, err := asn1.Unmarshal(der, &bits); err != nil {
+		return []string{}
+	}
+	names := []string{}
+	for i, name := range keyUsageBitNames {
+		if bits.At(i) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func parseExtKeyUsage(der []byte) []string {
+	var oids []asn1.ObjectIdentifier
+	if _, err := asn1.Unmarshal(der, &oids); err != nil {
+		return []string{}
+	}
+	names := make([]string, len(oids))
+	for i, oid := range oids {
+		if n, ok := extKeyUsageOIDNames[oid.String()]; ok {
+			names[i] = n
+		} else {
+			names[i] = oid.String()
+		}
+	}
+	return names
+}
+
+func oidName(oid asn1.ObjectIdentifier) string {
+	if n, ok := oidNames[oid.String()]; ok {
+		return n
+	}
+	return oid.String()
+}
+
+// EmptyIfNil 把 nil 切片换成空切片，JSON 输出为 [] 而不是 null
+func EmptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
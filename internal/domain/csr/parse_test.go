@@ -0,0 +1,93 @@
+package csr
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// newTestCSRPEM 生成测试 CSR
+func newTestCSRPEM(t *testing.T, tmpl *x509.CertificateRequest) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, tmpl, key)
+	if err != nil {
+		t.Fatalf("create csr: %v", err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}))
+}
+
+func TestParseCSR(t *testing.T) {
+	ekuValue, _ := asn1.Marshal([]asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 1}})
+	csrPEM := newTestCSRPEM(t, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   "example.com",
+			Organization: []string{"Example Ltd"},
+			Country:      []string{"CN"},
+		},
+		DNSNames: []string{"example.com", "www.example.com"},
+		ExtraExtensions: []pkix.Extension{
+			{Id: oidExtExtKeyUsage, Value: ekuValue},
+		},
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		// 模拟 JSON 中带转义换行的 CSR
+		in := strings.ReplaceAll(csrPEM, "\n", "\\r\\n")
+		got, err := ParseCSR(in)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Fields.CommonName != "example.com" || !reflect.DeepEqual(got.Fields.Organization, []string{"Example Ltd"}) {
+			t.Errorf("fields = %+v", got.Fields)
+		}
+		if !reflect.DeepEqual(got.DNSNames, []string{"example.com", "www.example.com"}) {
+			t.Errorf("dnsNames = %v", got.DNSNames)
+		}
+		if !reflect.DeepEqual(got.ExtKeyUsage, []string{"TLS Web Server Authentication"}) {
+			t.Errorf("extKeyUsage = %v", got.ExtKeyUsage)
+		}
+		if got.PublicKeyAlgorithm != "ECDSA" || got.PublicKeyCurve != "P-256" {
+			t.Errorf("public key = %s %s", got.PublicKeyAlgorithm, got.PublicKeyCurve)
+		}
+		if !got.SignatureValid {
+			t.Errorf("signature invalid: %s", got.SignatureError)
+		}
+		if len(got.Extensions) != 2 {
+			t.Errorf("extensions = %+v", got.Extensions)
+		}
+		if got.PEM != csrPEM {
+			t.Errorf("pem = %q, want %q", got.PEM, csrPEM)
+		}
+	})
+
+	t.Run("bad signature", func(t *testing.T) {
+		block, _ := pem.Decode([]byte(csrPEM))
+		der := append([]byte(nil), block.Bytes...)
+		der[len(der)-1] ^= 0xff
+		got, err := ParseCSR(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.SignatureValid {
+			t.Errorf("expected invalid signature")
+		}
+	})
+
+	t.Run("not a csr", func(t *testing.T) {
+		if _, err := ParseCSR(pemHeader + "\nMIIDCDCCAfACAQAwgZwxCzAJBgNVBAYTAkNO\n" + pemFooter); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
@@ -0,0 +1,337 @@
+package json
+
+import (
+	"fmt"
+	"go/format"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// 代码生成的目标语言
+const (
+	CodegenGo         = "go"
+	CodegenTypeScript = "typescript"
+)
+
+// CodegenOptions 代码生成选项
+type CodegenOptions struct {
+	// Language go / typescript
+	Language string
+	// RootName 根类型名，默认 Root
+	RootName string
+}
+
+// CodegenResult 生成结果
+type CodegenResult struct {
+	Language string
+	Code     string
+	// Types 生成的类型名，根类型在前
+	Types []string
+}
+
+// goInitialisms 按 Go 的命名习惯整体大写的缩写
+var goInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true, "GUID": true,
+	"HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true, "LHS": true, "QPS": true,
+	"RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// GenerateCode 根据 JSON 生成 Go 结构体或 TypeScript 接口。
+// 数组中各元素的结构合并为一个类型；不是每个对象都有的键，Go 中为指针加 omitempty，TypeScript 中为可选属性
+func GenerateCode(input string, opts CodegenOptions) (CodegenResult, error) {
+	language := opts.Language
+	if language == "" {
+		language = CodegenGo
+	}
+	if language != CodegenGo && language != CodegenTypeScript {
+		return CodegenResult{}, fmt.Errorf("unsupported language %q (use %s or %s)", language, CodegenGo, CodegenTypeScript)
+	}
+	rootName := exportedName(opts.RootName)
+	if strings.TrimSpace(opts.RootName) == "" {
+		rootName = "Root"
+	}
+
+	data, err := decodeJSON(input)
+	if err != nil {
+		return CodegenResult{}, err
+	}
+	root := newShape(0)
+	root.add(data)
+
+	g := &codegen{language: language, used: map[string]bool{}}
+	g.declare(rootName, root)
+	code := strings.Join(g.decls, "\n\n") + "\n"
+	if language == CodegenGo {
+		code, err = gofmtDecls(code)
+		if err != nil {
+			return CodegenResult{}, err
+		}
+	}
+	return CodegenResult{Language: language, Code: code, Types: g.names}, nil
+}
+
+type codegen struct {
+	language string
+	used     map[string]bool
+	names    []string
+	decls    []string
+}
+
+// uniqueName 类型名重复时加数字后缀
+func (g *codegen) uniqueName(name string) string {
+	unique := name
+	for i := 2; g.used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	g.used[unique] = true
+	g.names = append(g.names, unique)
+	return unique
+}
+
+// declare 声明名为 name 的类型；对象生成结构体 / 接口，其他生成类型别名
+func (g *codegen) declare(name string, s *shape) {
+	name = g.uniqueName(name)
+	idx := len(g.decls)
+	g.decls = append(g.decls, "")
+
+	if s.isObject() {
+		g.decls[idx] = g.object(name, s)
+		return
+	}
+	if g.language == CodegenGo {
+		g.decls[idx] = fmt.Sprintf("type %s %s", name, g.goType(name, s))
+	} else {
+		g.decls[idx] = fmt.Sprintf("export type %s = %s;", name, g.tsType(name, s))
+	}
+}
+
+func (g *codegen) object(name string, s *shape) string {
+	var b strings.Builder
+	if g.language == CodegenGo {
+		fmt.Fprintf(&b, "type %s struct {\n", name)
+	} else {
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+	}
+
+	fields := map[string]bool{}
+	for _, k := range sortedShapeKeys(s.props) {
+		p := s.props[k]
+		optional := p.count() < s.objects
+		if g.language == CodegenTypeScript {
+			prop := k
+			if !tsIdentifier.MatchString(k) {
+				prop = strconv.Quote(k)
+			}
+			if optional {
+				prop += "?"
+			}
+			fmt.Fprintf(&b, "  %s: %s;\n", prop, g.tsType(exportedName(k), p))
+			continue
+		}
+
+		field := exportedName(k)
+		for i := 2; fields[field]; i++ {
+			field = exportedName(k) + strconv.Itoa(i)
+		}
+		fields[field] = true
+		typ := g.goType(field, p)
+		tag := k
+		// 缺省或出现过 null 的标量和结构体用指针区分零值；切片、map、interface{} 本身可以为 nil
+		if (optional || p.types["null"] > 0) && !strings.HasPrefix(typ, "[]") && typ != "interface{}" {
+			typ = "*" + typ
+		}
+		if !validJSONTagName(k) {
+			// encoding/json 会忽略这样的标签名，改为按字段名匹配，不如明确跳过
+			fmt.Fprintf(&b, "\t// key %s cannot be expressed in a json tag\n", strconv.Quote(k))
+			fmt.Fprintf(&b, "\t%s %s `json:\"-\"`\n", field, typ)
+			continue
+		}
+		if optional {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", field, typ, tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// validJSONTagName 与 encoding/json 的规则一致：标签名只能包含字母、数字和部分标点，不能为空
+func validJSONTagName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		if !strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c) && !unicode.IsLetter(c) && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// isObject 除 null 外只出现过对象
+func (s *shape) isObject() bool {
+	return s.objects > 0 && s.objects+s.types["null"] == s.count()
+}
+
+// nonNullTypes 出现过的非 null 类型；整数和小数混合时只保留 number
+func (s *shape) nonNullTypes() []string {
+	var types []string
+	for _, t := range []string{"object", "array", "string", "integer", "number", "boolean"} {
+		if s.types[t] > 0 && !(t == "integer" && s.types["number"] > 0) {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// goType name 为需要新声明结构体时使用的名字
+func (g *codegen) goType(name string, s *shape) string {
+	types := s.nonNullTypes()
+	if len(types) != 1 {
+		return "interface{}"
+	}
+	switch types[0] {
+	case "object":
+		return g.nested(name, s)
+	case "array":
+		if s.items == nil || s.items.count() == 0 {
+			return "[]interface{}"
+		}
+		elem := g.goType(singular(name), s.items)
+		// 元素中出现过 null 的结构体和标量用指针
+		if s.items.types["null"] > 0 && elem != "interface{}" && !strings.HasPrefix(elem, "[]") {
+			elem = "*" + elem
+		}
+		return "[]" + elem
+	case "string":
+		return "string"
+	case "boolean":
+		return "bool"
+	case "integer":
+		if s.nonInt64 > 0 {
+			return "json.Number"
+		}
+		return "int64"
+	}
+	if s.lossyFloat > 0 {
+		return "json.Number"
+	}
+	return "float64"
+}
+
+func (g *codegen) tsType(name string, s *shape) string {
+	var parts []string
+	for _, t := range s.nonNullTypes() {
+		switch t {
+		case "object":
+			parts = append(parts, g.nested(name, s))
+		case "array":
+			elem := "unknown"
+			if s.items != nil && s.items.count() > 0 {
+				elem = g.tsType(singular(name), s.items)
+			}
+			if strings.ContainsAny(elem, "| ") {
+				elem = "(" + elem + ")"
+			}
+			parts = append(parts, elem+"[]")
+		case "string":
+			parts = append(parts, "string")
+		case "boolean":
+			parts = append(parts, "boolean")
+		default:
+			parts = append(parts, "number")
+		}
+	}
+	if s.types["null"] > 0 {
+		parts = append(parts, "null")
+	}
+	if len(parts) == 0 {
+		return "unknown"
+	}
+	return strings.Join(parts, " | ")
+}
+
+// nested 为嵌套对象声明新类型并返回类型名
+func (g *codegen) nested(name string, s *shape) string {
+	before := len(g.names)
+	g.declare(name, s)
+	return g.names[before]
+}
+
+// exportedName 键名转为导出的标识符：user_id、user-id、userId 都转为 UserID
+func exportedName(key string) string {
+	var words []string
+	var cur []rune
+	flush := func() {
+		if len(cur) > 0 {
+			words = append(words, string(cur))
+			cur = nil
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(cur) > 0:
+			// userID、HTTPServer：小写后接大写，或连续大写后接小写时断开
+			prev := cur[len(cur)-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+
+	var b strings.Builder
+	for _, w := range words {
+		upper := strings.ToUpper(w)
+		if goInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		r := []rune(w)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	// 以数字或没有大小写的文字（如中文）开头时不是导出的标识符，encoding/json 会忽略
+	if !unicode.IsUpper([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// singular 数组元素的类型名：Items -> Item、Categories -> Category，不是复数时加 Item
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies") && len(name) > 3:
+		return name[:len(name)-3] + "y"
+	case strings.HasSuffix(name, "sses") || strings.HasSuffix(name, "uses") || strings.HasSuffix(name, "xes") || strings.HasSuffix(name, "ches"):
+		return name[:len(name)-2]
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss") && !strings.HasSuffix(name, "us") && len(name) > 1:
+		return name[:len(name)-1]
+	}
+	return name + "Item"
+}
+
+// gofmtDecls 用 gofmt 对齐字段和标签
+func gofmtDecls(code string) (string, error) {
+	const header = "package p\n\n"
+	out, err := format.Source([]byte(header + code))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(string(out), header), nil
+}
@@ -0,0 +1,167 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+const codegenInput = `{
+  "user_id": 1,
+  "name": "x",
+  "big": 12345678901234567890,
+  "ratio": 0.5,
+  "precise": 0.30000000000000001,
+  "tags": ["a"],
+  "items": [{"id": 1, "url": "u"}, {"id": 2, "note": null, "price": 2}, {"id": 3, "price": 2.5}],
+  "owner": {"email": "a@example.com"},
+  "meta": null,
+  "mixed": [1, "a"],
+  "empty": [],
+  "HTTPServer": true,
+  "1st": 1,
+  "content-type": "json"
+}`
+
+func TestGenerateGo(t *testing.T) {
+	res, err := GenerateCode(codegenInput, CodegenOptions{})
+	if err != nil {
+		t.Fatalf("GenerateCode() error = %v", err)
+	}
+	want := "type Root struct {\n" +
+		"\tX1st        int64         `json:\"1st\"`\n" +
+		"\tHTTPServer  bool          `json:\"HTTPServer\"`\n" +
+		"\tBig         json.Number   `json:\"big\"`\n" +
+		"\tContentType string        `json:\"content-type\"`\n" +
+		"\tEmpty       []interface{} `json:\"empty\"`\n" +
+		"\tItems       []Item        `json:\"items\"`\n" +
+		"\tMeta        interface{}   `json:\"meta\"`\n" +
+		"\tMixed       []interface{} `json:\"mixed\"`\n" +
+		"\tName        string        `json:\"name\"`\n" +
+		"\tOwner       Owner         `json:\"owner\"`\n" +
+		"\tPrecise     json.Number   `json:\"precise\"`\n" +
+		"\tRatio       float64       `json:\"ratio\"`\n" +
+		"\tTags        []string      `json:\"tags\"`\n" +
+		"\tUserID      int64         `json:\"user_id\"`\n" +
+		"}\n\n" +
+		"type Item struct {\n" +
+		"\tID    int64       `json:\"id\"`\n" +
+		"\tNote  interface{} `json:\"note,omitempty\"`\n" +
+		"\tPrice *float64    `json:\"price,omitempty\"`\n" +
+		"\tURL   *string     `json:\"url,omitempty\"`\n" +
+		"}\n\n" +
+		"type Owner struct {\n" +
+		"\tEmail string `json:\"email\"`\n" +
+		"}\n"
+	if res.Code != want {
+		t.Errorf("code =\n%s\nwant\n%s", res.Code, want)
+	}
+	if got := strings.Join(res.Types, ","); got != "Root,Item,Owner" {
+		t.Errorf("Types = %s", got)
+	}
+}
+
+func TestGenerateTypeScript(t *testing.T) {
+	res, err := GenerateCode(codegenInput, CodegenOptions{Language: CodegenTypeScript, RootName: "api_response"})
+	if err != nil {
+		t.Fatalf("GenerateCode() error = %v", err)
+	}
+	want := `export interface APIResponse {
+  "1st": number;
+  HTTPServer: boolean;
+  big: number;
+  "content-type": string;
+  empty: unknown[];
+  items: Item[];
+  meta: null;
+  mixed: (string | number)[];
+  name: string;
+  owner: Owner;
+  precise: number;
+  ratio: number;
+  tags: string[];
+  user_id: number;
+}
+
+export interface Item {
+  id: number;
+  note?: null;
+  price?: number;
+  url?: string;
+}
+
+export interface Owner {
+  email: string;
+}
+`
+	if res.Code != want {
+		t.Errorf("code =\n%s\nwant\n%s", res.Code, want)
+	}
+}
+
+func TestGenerateCodeShapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		language string
+		want     string
+	}{
+		{"根数组合并元素", `[{"a":1},{"a":null,"b":"x"}]`, CodegenGo,
+			"type Root []RootItem\n\ntype RootItem struct {\n\tA *int64  `json:\"a\"`\n\tB *string `json:\"b,omitempty\"`\n}\n"},
+		{"可为 null 的元素", `{"list":[{"x":1},null]}`, CodegenGo,
+			"type Root struct {\n\tList []*ListItem `json:\"list\"`\n}\n\ntype ListItem struct {\n\tX int64 `json:\"x\"`\n}\n"},
+		{"类型名去重与单数", `{"statuses":[{"owner":{"a":1}}],"owner":{"b":true}}`, CodegenGo,
+			"type Root struct {\n\tOwner    Owner    `json:\"owner\"`\n\tStatuses []Status `json:\"statuses\"`\n}\n\n" +
+				"type Owner struct {\n\tB bool `json:\"b\"`\n}\n\n" +
+				"type Status struct {\n\tOwner Owner2 `json:\"owner\"`\n}\n\n" +
+				"type Owner2 struct {\n\tA int64 `json:\"a\"`\n}\n"},
+		{"整数写成小数", `{"n":[1,2.0]}`, CodegenGo,
+			"type Root struct {\n\tN []json.Number `json:\"n\"`\n}\n"},
+		{"无法写进 json 标签的键", `{"a\"b":1,"c,d":"x","":true,"ok key":1}`, CodegenGo,
+			"type Root struct {\n\t// key \"\" cannot be expressed in a json tag\n\tField bool `json:\"-\"`\n" +
+				"\t// key \"a\\\"b\" cannot be expressed in a json tag\n\tAB int64 `json:\"-\"`\n" +
+				"\t// key \"c,d\" cannot be expressed in a json tag\n\tCD    string `json:\"-\"`\n" +
+				"\tOkKey int64  `json:\"ok key\"`\n}\n"},
+		{"标量根", `"x"`, CodegenTypeScript, "export type Root = string;\n"},
+		{"联合与 null", `[{"v":[1,null]},{"v":"s"}]`, CodegenTypeScript,
+			"export type Root = RootItem[];\n\nexport interface RootItem {\n  v: (number | null)[] | string;\n}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := GenerateCode(tt.input, CodegenOptions{Language: tt.language})
+			if err != nil {
+				t.Fatalf("GenerateCode() error = %v", err)
+			}
+			if res.Code != tt.want {
+				t.Errorf("code =\n%s\nwant\n%s", res.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportedName(t *testing.T) {
+	tests := map[string]string{
+		"user_id":    "UserID",
+		"userId":     "UserID",
+		"user-name":  "UserName",
+		"HTTPServer": "HTTPServer",
+		"apiURL":     "APIURL",
+		"2fa":        "X2fa",
+		"名字":         "X名字",
+		"---":        "Field",
+		"v2Token":    "V2Token",
+	}
+	for in, want := range tests {
+		if got := exportedName(in); got != want {
+			t.Errorf("exportedName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestGenerateCodeErrors(t *testing.T) {
+	if _, err := GenerateCode(`{"a":`, CodegenOptions{}); err == nil {
+		t.Error("无效 JSON 应报错")
+	}
+	if _, err := GenerateCode(`{}`, CodegenOptions{Language: "rust"}); err == nil {
+		t.Error("不支持的语言应报错")
+	}
+}
@@ -0,0 +1,383 @@
+package json
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 变更类型
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+// maxLCSCells 数组按 LCS 对齐的规模上限，超过时按下标逐个比较
+const maxLCSCells = 4000000
+
+// DiffOptions 比较选项
+type DiffOptions struct {
+	// IgnoreArrayOrder 数组按多重集合比较，元素顺序不同不算差异
+	IgnoreArrayOrder bool
+	// IgnoreKeys 不参与比较的键：普通键名在任意层级忽略；
+	// 以 / 开头的按 JSON Pointer 匹配，* 匹配任意一段，如 /items/*/updatedAt
+	IgnoreKeys []string
+}
+
+// PatchOp RFC 6902 JSON Patch 操作，remove 没有 Value
+type PatchOp struct {
+	Op    string
+	Path  string
+	Value interface{}
+}
+
+// Change 一处差异；删除时 Path 为左侧文档中的位置，新增和修改为右侧文档中的位置
+type Change struct {
+	Type string
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// DiffResult 比较结果
+type DiffResult struct {
+	Equal bool
+	// Patch 依次应用到左侧文档即可得到右侧文档（忽略的键除外）
+	Patch   []PatchOp
+	Changes []Change
+}
+
+// Diff 结构化比较两个 JSON，输入的提取规则与格式化相同
+func Diff(left, right string, opts DiffOptions) (DiffResult, error) {
+	a, err := decodeJSON(left)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("left: %v", err)
+	}
+	b, err := decodeJSON(right)
+	if err != nil {
+		return DiffResult{}, fmt.Errorf("right: %v", err)
+	}
+
+	d := &differ{opts: opts, ignoreNames: map[string]bool{}}
+	for _, k := range opts.IgnoreKeys {
+		k = strings.TrimSpace(k)
+		switch {
+		case k == "":
+		case strings.HasPrefix(k, "/"):
+			d.ignorePaths = append(d.ignorePaths, strings.Split(k[1:], "/"))
+		default:
+			d.ignoreNames[k] = true
+		}
+	}
+	a, b = d.strip(a, nil), d.strip(b, nil)
+	d.diff(a, b, "", "", "")
+	return DiffResult{Equal: len(d.changes) == 0, Patch: d.patch, Changes: d.changes}, nil
+}
+
+type differ struct {
+	opts        DiffOptions
+	ignoreNames map[string]bool
+	ignorePaths [][]string
+	patch       []PatchOp
+	changes     []Change
+}
+
+// strip 去掉忽略的键；path 为未转义的各段
+func (d *differ) strip(v interface{}, path []string) interface{} {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, val := range x {
+			p := append(path[:len(path):len(path)], k)
+			if d.ignoreNames[k] || d.ignoredPath(p) {
+				continue
+			}
+			out[k] = d.strip(val, p)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, val := range x {
+			out[i] = d.strip(val, append(path[:len(path):len(path)], strconv.Itoa(i)))
+		}
+		return out
+	}
+	return v
+}
+
+func (d *differ) ignoredPath(path []string) bool {
+	for _, p := range d.ignorePaths {
+		if len(p) != len(path) {
+			continue
+		}
+		match := true
+		for i, seg := range p {
+			seg = strings.NewReplacer("~1", "/", "~0", "~").Replace(seg)
+			if seg != "*" && seg != path[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// diff 比较 a、b；patchPath 为应用补丁时的当前位置（数组下标会随前面的增删移动）
+func (d *differ) diff(a, b interface{}, patchPath, oldPath, newPath string) {
+	switch x := a.(type) {
+	case map[string]interface{}:
+		if y, ok := b.(map[string]interface{}); ok {
+			d.diffObject(x, y, patchPath, oldPath, newPath)
+			return
+		}
+	case []interface{}:
+		if y, ok := b.([]interface{}); ok {
+			if d.opts.IgnoreArrayOrder {
+				d.diffUnordered(x, y, patchPath, oldPath, newPath)
+			} else {
+				d.diffArray(x, y, patchPath, oldPath, newPath)
+			}
+			return
+		}
+	}
+	if !d.equal(a, b) {
+		d.patch = append(d.patch, PatchOp{Op: "replace", Path: patchPath, Value: b})
+		d.changes = append(d.changes, Change{Type: ChangeChanged, Path: newPath, Old: a, New: b})
+	}
+}
+
+func (d *differ) diffObject(a, b map[string]interface{}, patchPath, oldPath, newPath string) {
+	keys := sortedKeys(a)
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		seg := "/" + escapePointer(k)
+		av, inA := a[k]
+		bv, inB := b[k]
+		switch {
+		case !inB:
+			d.patch = append(d.patch, PatchOp{Op: "remove", Path: patchPath + seg})
+			d.changes = append(d.changes, Change{Type: ChangeRemoved, Path: oldPath + seg, Old: av})
+		case !inA:
+			d.patch = append(d.patch, PatchOp{Op: "add", Path: patchPath + seg, Value: bv})
+			d.changes = append(d.changes, Change{Type: ChangeAdded, Path: newPath + seg, New: bv})
+		default:
+			d.diff(av, bv, patchPath+seg, oldPath+seg, newPath+seg)
+		}
+	}
+}
+
+// diffArray 用 LCS 对齐数组，插入或删除一个元素不会导致后面的元素全部显示为修改
+func (d *differ) diffArray(a, b []interface{}, patchPath, oldPath, newPath string) {
+	type edit struct {
+		del, ins []int
+	}
+	// runs 依次为变更段；空段表示一个保持不变的元素
+	var runs []edit
+	var cur edit
+	flush := func() {
+		if len(cur.del) > 0 || len(cur.ins) > 0 {
+			runs = append(runs, cur)
+		}
+		cur = edit{}
+	}
+	if len(a)*len(b) > maxLCSCells {
+		n := len(a)
+		if len(b) < n {
+			n = len(b)
+		}
+		for i := 0; i < n; i++ {
+			if d.equal(a[i], b[i]) {
+				flush()
+				runs = append(runs, edit{})
+				continue
+			}
+			cur.del, cur.ins = append(cur.del, i), append(cur.ins, i)
+		}
+		for i := n; i < len(a); i++ {
+			cur.del = append(cur.del, i)
+		}
+		for i := n; i < len(b); i++ {
+			cur.ins = append(cur.ins, i)
+		}
+		flush()
+	} else {
+		// lcs[i][j] 为 a[i:] 与 b[j:] 的最长公共子序列长度
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				switch {
+				case d.equal(a[i], b[j]):
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				case lcs[i+1][j] >= lcs[i][j+1]:
+					lcs[i][j] = lcs[i+1][j]
+				default:
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		i, j := 0, 0
+		for i < len(a) || j < len(b) {
+			switch {
+			case i < len(a) && j < len(b) && d.equal(a[i], b[j]):
+				flush()
+				runs = append(runs, edit{})
+				i, j = i+1, j+1
+			case j >= len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+				cur.del = append(cur.del, i)
+				i++
+			default:
+				cur.ins = append(cur.ins, j)
+				j++
+			}
+		}
+		flush()
+	}
+
+	// 同一段里的删除和插入两两配对为修改，多出来的才是删除或新增
+	k := 0
+	for _, r := range runs {
+		if len(r.del) == 0 && len(r.ins) == 0 {
+			k++
+			continue
+		}
+		n := len(r.del)
+		if len(r.ins) < n {
+			n = len(r.ins)
+		}
+		for x := 0; x < n; x++ {
+			oi, ni := r.del[x], r.ins[x]
+			d.diff(a[oi], b[ni], patchPath+"/"+strconv.Itoa(k), oldPath+"/"+strconv.Itoa(oi), newPath+"/"+strconv.Itoa(ni))
+			k++
+		}
+		for _, oi := range r.del[n:] {
+			d.patch = append(d.patch, PatchOp{Op: "remove", Path: patchPath + "/" + strconv.Itoa(k)})
+			d.changes = append(d.changes, Change{Type: ChangeRemoved, Path: oldPath + "/" + strconv.Itoa(oi), Old: a[oi]})
+		}
+		for _, ni := range r.ins[n:] {
+			d.patch = append(d.patch, PatchOp{Op: "add", Path: patchPath + "/" + strconv.Itoa(k), Value: b[ni]})
+			d.changes = append(d.changes, Change{Type: ChangeAdded, Path: newPath + "/" + strconv.Itoa(ni), New: b[ni]})
+			k++
+		}
+	}
+}
+
+// diffUnordered 忽略顺序：相等的元素一一配对，剩下的分别是删除和新增
+func (d *differ) diffUnordered(a, b []interface{}, patchPath, oldPath, newPath string) {
+	used := make([]bool, len(b))
+	var removed []int
+	for i, av := range a {
+		found := false
+		for j, bv := range b {
+			if !used[j] && d.equal(av, bv) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			removed = append(removed, i)
+		}
+	}
+	// 从后往前删除，前面的下标不受影响；新增追加到末尾
+	sort.Sort(sort.Reverse(sort.IntSlice(removed)))
+	for _, i := range removed {
+		d.patch = append(d.patch, PatchOp{Op: "remove", Path: patchPath + "/" + strconv.Itoa(i)})
+	}
+	sort.Ints(removed)
+	for _, i := range removed {
+		d.changes = append(d.changes, Change{Type: ChangeRemoved, Path: oldPath + "/" + strconv.Itoa(i), Old: a[i]})
+	}
+	for j, bv := range b {
+		if !used[j] {
+			d.patch = append(d.patch, PatchOp{Op: "add", Path: patchPath + "/-", Value: bv})
+			d.changes = append(d.changes, Change{Type: ChangeAdded, Path: newPath + "/" + strconv.Itoa(j), New: bv})
+		}
+	}
+}
+
+// equal 深度比较；数字按数值比较（1 与 1.0 相等），忽略顺序时数组按多重集合比较
+func (d *differ) equal(a, b interface{}) bool {
+	if !d.opts.IgnoreArrayOrder {
+		return compareValues(a, b) == 0
+	}
+	switch x := a.(type) {
+	case []interface{}:
+		y, ok := b.([]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		used := make([]bool, len(y))
+	next:
+		for _, av := range x {
+			for j, bv := range y {
+				if !used[j] && d.equal(av, bv) {
+					used[j] = true
+					continue next
+				}
+			}
+			return false
+		}
+		return true
+	case map[string]interface{}:
+		y, ok := b.(map[string]interface{})
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, av := range x {
+			bv, ok := y[k]
+			if !ok || !d.equal(av, bv) {
+				return false
+			}
+		}
+		return true
+	}
+	return compareValues(a, b) == 0
+}
+
+// escapePointer RFC 6901：~ 写作 ~0，/ 写作 ~1
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// FormatChanges 每处差异一行：+ 新增、- 删除、~ 修改
+func FormatChanges(changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		switch c.Type {
+		case ChangeAdded:
+			fmt.Fprintf(&b, "+ %s: %s\n", path, compactValue(c.New))
+		case ChangeRemoved:
+			fmt.Fprintf(&b, "- %s: %s\n", path, compactValue(c.Old))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s\n", path, compactValue(c.Old), compactValue(c.New))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// compactValue 单行显示的值，过长时截断
+func compactValue(v interface{}) string {
+	s, err := encodeJSON(v, 0)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	if r := []rune(s); len(r) > 120 {
+		return string(r[:117]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,197 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// applyPatch 按 RFC 6902 依次应用 add/remove/replace，用来校验生成的补丁
+func applyPatch(t *testing.T, doc interface{}, patch []PatchOp) interface{} {
+	t.Helper()
+	for _, op := range patch {
+		if op.Path == "" {
+			doc = op.Value
+			continue
+		}
+		segs := strings.Split(op.Path[1:], "/")
+		for i, s := range segs {
+			segs[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+		}
+		doc = applyOp(t, doc, segs, op)
+	}
+	return doc
+}
+
+func applyOp(t *testing.T, v interface{}, segs []string, op PatchOp) interface{} {
+	t.Helper()
+	key := segs[0]
+	switch x := v.(type) {
+	case map[string]interface{}:
+		if len(segs) > 1 {
+			x[key] = applyOp(t, x[key], segs[1:], op)
+		} else if op.Op == "remove" {
+			delete(x, key)
+		} else {
+			x[key] = op.Value
+		}
+		return x
+	case []interface{}:
+		if key == "-" {
+			return append(x, op.Value)
+		}
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i > len(x) {
+			t.Fatalf("bad array index in %s", op.Path)
+		}
+		if len(segs) > 1 {
+			x[i] = applyOp(t, x[i], segs[1:], op)
+			return x
+		}
+		switch op.Op {
+		case "add":
+			x = append(x[:i], append([]interface{}{op.Value}, x[i:]...)...)
+		case "remove":
+			x = append(x[:i], x[i+1:]...)
+		default:
+			x[i] = op.Value
+		}
+		return x
+	}
+	t.Fatalf("path %s does not exist", op.Path)
+	return nil
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		left    string
+		right   string
+		opts    DiffOptions
+		changes string
+		patch   int
+	}{
+		{
+			name:    "相同",
+			left:    `{"a":1,"b":[1,2]}`,
+			right:   `{"b":[1,2],"a":1.0}`,
+			changes: "",
+		},
+		{
+			name:    "对象增删改",
+			left:    `{"a":1,"b":{"c":"x"},"d":true}`,
+			right:   `{"a":2,"b":{"c":"x","e":null}}`,
+			changes: "~ /a: 1 -> 2\n+ /b/e: null\n- /d: true",
+			patch:   3,
+		},
+		{
+			name:    "数组中间插入",
+			left:    `[1,2,3,4]`,
+			right:   `[1,9,2,3,4]`,
+			changes: "+ /1: 9",
+			patch:   1,
+		},
+		{
+			name:    "数组删除与修改",
+			left:    `[{"id":1,"v":"a"},{"id":2},{"id":3}]`,
+			right:   `[{"id":1,"v":"b"},{"id":3}]`,
+			changes: "~ /0/v: \"a\" -> \"b\"\n- /1: {\"id\":2}",
+			patch:   2,
+		},
+		{
+			name:    "类型变化",
+			left:    `{"a":[1]}`,
+			right:   `{"a":{"0":1}}`,
+			changes: "~ /a: [1] -> {\"0\":1}",
+			patch:   1,
+		},
+		{
+			name:    "根替换",
+			left:    `1`,
+			right:   `"1"`,
+			changes: "~ /: 1 -> \"1\"",
+			patch:   1,
+		},
+		{
+			name:    "键名转义",
+			left:    `{"a/b":1,"c~d":1}`,
+			right:   `{"a/b":2}`,
+			changes: "~ /a~1b: 1 -> 2\n- /c~0d: 1",
+			patch:   2,
+		},
+		{
+			name:    "忽略数组顺序",
+			left:    `{"tags":["a","b","c"],"m":[[1,2],[3]]}`,
+			right:   `{"tags":["c","a","d"],"m":[[3],[2,1]]}`,
+			opts:    DiffOptions{IgnoreArrayOrder: true},
+			changes: "- /tags/1: \"b\"\n+ /tags/2: \"d\"",
+			patch:   2,
+		},
+		{
+			name:    "忽略键名",
+			left:    `{"id":1,"updatedAt":"x","items":[{"n":1,"updatedAt":"y"}]}`,
+			right:   `{"id":1,"updatedAt":"z","items":[{"n":1,"updatedAt":"w"}]}`,
+			opts:    DiffOptions{IgnoreKeys: []string{"updatedAt"}},
+			changes: "",
+		},
+		{
+			name:    "忽略路径",
+			left:    `{"meta":{"ts":1},"items":[{"ts":1,"n":1}],"ts":1}`,
+			right:   `{"meta":{"ts":2},"items":[{"ts":2,"n":2}],"ts":2}`,
+			opts:    DiffOptions{IgnoreKeys: []string{"/meta/ts", "/items/*/ts"}},
+			changes: "~ /items/0/n: 1 -> 2\n~ /ts: 1 -> 2",
+			patch:   2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Diff(tt.left, tt.right, tt.opts)
+			if err != nil {
+				t.Fatalf("Diff() error = %v", err)
+			}
+			if s := FormatChanges(got.Changes); s != tt.changes {
+				t.Errorf("changes =\n%s\nwant\n%s", s, tt.changes)
+			}
+			if got.Equal != (tt.changes == "") || len(got.Patch) != tt.patch {
+				t.Errorf("Equal = %v, patch = %+v", got.Equal, got.Patch)
+			}
+
+			d := &differ{opts: tt.opts}
+			left, _ := decodeJSON(tt.left)
+			right, _ := decodeJSON(tt.right)
+			if len(tt.opts.IgnoreKeys) == 0 && !d.equal(applyPatch(t, left, got.Patch), right) {
+				t.Errorf("应用补丁后与右侧不一致：%+v", got.Patch)
+			}
+		})
+	}
+}
+
+func TestDiffPatchRoundTrip(t *testing.T) {
+	pairs := [][2]string{
+		{`[1,2,3,4,5]`, `[5,4,3,2,1]`},
+		{`[1,2,3]`, `[]`},
+		{`[]`, `[1,2,3]`},
+		{`["a","b","c","d"]`, `["x","b","y","d","z"]`},
+		{`{"a":[{"b":[1,2]},{"c":3}]}`, `{"a":[{"c":3},{"b":[2,1,0]}],"d":[]}`},
+	}
+	for _, p := range pairs {
+		got, err := Diff(p[0], p[1], DiffOptions{})
+		if err != nil {
+			t.Fatalf("Diff(%s, %s) error = %v", p[0], p[1], err)
+		}
+		left, _ := decodeJSON(p[0])
+		right, _ := decodeJSON(p[1])
+		if applied := applyPatch(t, left, got.Patch); compareValues(applied, right) != 0 {
+			t.Errorf("Diff(%s, %s) 补丁应用后为 %v", p[0], p[1], applied)
+		}
+	}
+}
+
+func TestDiffErrors(t *testing.T) {
+	if _, err := Diff(`{"a":`, `{}`, DiffOptions{}); err == nil || !strings.HasPrefix(err.Error(), "left: ") {
+		t.Errorf("左侧无效应报错：%v", err)
+	}
+	if _, err := Diff(`{}`, `nope`, DiffOptions{}); err == nil || !strings.HasPrefix(err.Error(), "right: ") {
+		t.Errorf("右侧无效应报错：%v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"strings"
+	"unicode"
 )
 
 // extractJSON 从字符串中提取有效的JSON部分（对象或数组）
@@ -69,26 +70,36 @@ func extractJSON(input string) string {
 	return input
 }
 
-// FormatJSON 格式化JSON字符串
-func FormatJSON(input string, indent int) (string, error) {
+// decodeJSON 提取并解析输入中的 JSON，数字保持为 json.Number
+func decodeJSON(input string) (interface{}, error) {
+	data, _, _, err := decodeJSONText(input)
+	return data, err
+}
+
+// decodeJSONText 同 decodeJSON，另外返回提取出的 JSON 文本及其在 input 中的字节偏移，用于定位行列
+func decodeJSONText(input string) (data interface{}, text string, offset int, err error) {
 	in := strings.TrimSpace(input)
 	if in == "" {
-		return "", errors.New("input is empty")
+		return nil, "", 0, errors.New("input is empty")
 	}
+	offset = len(input) - len(strings.TrimLeftFunc(input, unicode.IsSpace))
 
-	// 自动提取有效的JSON部分
-	in = extractJSON(in)
+	// 自动提取有效的JSON部分；提取结果从第一个 { 或 [ 开始，Index 即其位置
+	text = extractJSON(in)
+	offset += strings.Index(in, text)
 
 	// 尝试解析JSON
-	var data interface{}
-	decoder := json.NewDecoder(strings.NewReader(in))
+	decoder := json.NewDecoder(strings.NewReader(text))
 	decoder.UseNumber() // 使用json.Number保持数字精度
 
 	if err := decoder.Decode(&data); err != nil {
-		return "", errors.New("invalid JSON: " + err.Error())
+		return nil, "", 0, errors.New("invalid JSON: " + err.Error())
 	}
+	return data, text, offset, nil
+}
 
-	// 格式化输出
+// encodeJSON 序列化为 JSON，indent 为 0 时输出压缩格式
+func encodeJSON(data interface{}, indent int) (string, error) {
 	var buf bytes.Buffer
 	encoder := json.NewEncoder(&buf)
 	encoder.SetEscapeHTML(false) // 不转义HTML字符
@@ -106,35 +117,34 @@ func FormatJSON(input string, indent int) (string, error) {
 	return strings.TrimRight(result, "\n"), nil
 }
 
-// MinifyJSON 压缩JSON字符串
-func MinifyJSON(input string) (string, error) {
-	in := strings.TrimSpace(input)
-	if in == "" {
-		return "", errors.New("input is empty")
-	}
-
-	// 自动提取有效的JSON部分
-	in = extractJSON(in)
+// DecodeJSON 提取并解析输入中的 JSON，数字保持为 json.Number，供其他格式转换使用
+func DecodeJSON(input string) (interface{}, error) {
+	return decodeJSON(input)
+}
 
-	// 尝试解析JSON
-	var data interface{}
-	decoder := json.NewDecoder(strings.NewReader(in))
-	decoder.UseNumber()
+// EncodeJSON 按 FormatJSON 的规则序列化，indent 为 0 时输出压缩格式
+func EncodeJSON(data interface{}, indent int) (string, error) {
+	return encodeJSON(data, indent)
+}
 
-	if err := decoder.Decode(&data); err != nil {
-		return "", errors.New("invalid JSON: " + err.Error())
+// FormatJSON 格式化JSON字符串
+func FormatJSON(input string, indent int) (string, error) {
+	data, err := decodeJSON(input)
+	if err != nil {
+		return "", err
 	}
 
-	// 压缩输出
-	var buf bytes.Buffer
-	encoder := json.NewEncoder(&buf)
-	encoder.SetEscapeHTML(false)
+	// 格式化输出
+	return encodeJSON(data, indent)
+}
 
-	if err := encoder.Encode(data); err != nil {
+// MinifyJSON 压缩JSON字符串
+func MinifyJSON(input string) (string, error) {
+	data, err := decodeJSON(input)
+	if err != nil {
 		return "", err
 	}
 
-	// 移除末尾的换行符
-	result := buf.String()
-	return strings.TrimRight(result, "\n"), nil
+	// 压缩输出
+	return encodeJSON(data, 0)
 }
@@ -0,0 +1,310 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultEnumMaxValues 推断 enum 时允许的最多不同取值
+const defaultEnumMaxValues = 5
+
+// schemaURIs 推断结果中 $schema 的取值
+var schemaURIs = map[string]string{
+	SchemaDraft7:    "http://json-schema.org/draft-07/schema#",
+	SchemaDraft2020: "https://json-schema.org/draft/2020-12/schema",
+}
+
+// inferFormats 按顺序尝试的 format，某个位置上的字符串全部符合时输出 format
+var inferFormats = []string{"date-time", "date", "uuid", "email", "ipv4", "ipv6", "uri"}
+
+// InferOptions 推断选项
+type InferOptions struct {
+	// EnumMaxValues 字符串不同取值不超过该数量、且每个取值平均至少出现两次时生成 enum；0 为默认值 5，负数不生成
+	EnumMaxValues int
+	// Draft 输出的 $schema 版本，默认 2020-12
+	Draft string
+}
+
+// InferResult 推断结果
+type InferResult struct {
+	Draft string
+	// Schema 推断出的 schema，关键字按 $schema、type、format、enum、properties、required、items 的顺序输出
+	Schema  string
+	Samples int
+}
+
+// InferSchema 根据一个或多个样本推断 JSON Schema：
+// 同一位置的类型取并集，出现 null 时类型加上 null；对象的 required 为所有样本中都出现的键；
+// 整数和小数混合时为 number；字符串全部符合某种 format 时给出 format
+func InferSchema(samples []string, opts InferOptions, indent int) (InferResult, error) {
+	if len(samples) == 0 {
+		return InferResult{}, errors.New("no samples")
+	}
+	draft := opts.Draft
+	if draft == "" {
+		draft = SchemaDraft2020
+	}
+	uri, ok := schemaURIs[draft]
+	if !ok {
+		return InferResult{}, fmt.Errorf("unsupported draft %q (use %s or %s)", draft, SchemaDraft7, SchemaDraft2020)
+	}
+	enumMax := opts.EnumMaxValues
+	if enumMax == 0 {
+		enumMax = defaultEnumMaxValues
+	}
+
+	root := newShape(enumMax)
+	for i, s := range samples {
+		v, err := decodeJSON(s)
+		if err != nil {
+			return InferResult{}, fmt.Errorf("sample %d: %v", i+1, err)
+		}
+		root.add(v)
+	}
+
+	schema := root.schema()
+	schema.keys = append([]string{"$schema"}, schema.keys...)
+	schema.values["$schema"] = uri
+	out, err := encodeJSON(schema, indent)
+	if err != nil {
+		return InferResult{}, err
+	}
+	return InferResult{Draft: draft, Schema: out, Samples: len(samples)}, nil
+}
+
+// shape 同一位置上见过的所有值的汇总
+type shape struct {
+	enumMax int
+	types   map[string]int
+
+	// 对象：objects 为对象出现的次数，props 中每个键的 count 等于它时为必填
+	objects int
+	props   map[string]*shape
+
+	// 数组：所有数组的元素合并到 items
+	items *shape
+
+	// 字符串：values 记录不同取值的出现次数，超过 enumMax 个后不再记录
+	stringCount int
+	values      map[string]int
+	tooMany     bool
+	formatCount map[string]int
+
+	// 数字：nonInt64 为无法直接解析为 int64 的整数个数（超出范围或写成 1.0），lossyFloat 为转成 float64 会丢精度的个数
+	nonInt64   int
+	lossyFloat int
+}
+
+func newShape(enumMax int) *shape {
+	return &shape{
+		enumMax:     enumMax,
+		types:       map[string]int{},
+		props:       map[string]*shape{},
+		values:      map[string]int{},
+		formatCount: map[string]int{},
+	}
+}
+
+func (s *shape) add(v interface{}) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		s.types["object"]++
+		s.objects++
+		for k, val := range x {
+			p, ok := s.props[k]
+			if !ok {
+				p = newShape(s.enumMax)
+				s.props[k] = p
+			}
+			p.add(val)
+		}
+	case []interface{}:
+		s.types["array"]++
+		if s.items == nil {
+			s.items = newShape(s.enumMax)
+		}
+		for _, val := range x {
+			s.items.add(val)
+		}
+	case string:
+		s.types["string"]++
+		s.stringCount++
+		if !s.tooMany {
+			s.values[x]++
+			if len(s.values) > s.enumMax {
+				s.tooMany = true
+			}
+		}
+		for _, f := range inferFormats {
+			if matchesFormat(f, x) {
+				s.formatCount[f]++
+			}
+		}
+	case json.Number:
+		if isInteger(x) {
+			s.types["integer"]++
+			if _, err := strconv.ParseInt(string(x), 10, 64); err != nil {
+				s.nonInt64++
+			}
+		} else {
+			s.types["number"]++
+		}
+		if f, err := strconv.ParseFloat(string(x), 64); err != nil || compareNumbers(x, json.Number(strconv.FormatFloat(f, 'g', -1, 64))) != 0 {
+			s.lossyFloat++
+		}
+	default:
+		s.types[typeOf(v)]++
+	}
+}
+
+// count 该位置出现的次数
+func (s *shape) count() int {
+	n := 0
+	for _, c := range s.types {
+		n += c
+	}
+	return n
+}
+
+// matchesFormat 比校验时更严格：uri 只认 http(s)，避免 "note: x" 之类的普通文本被当成 URI
+func matchesFormat(format, s string) bool {
+	if format == "uri" && !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return false
+	}
+	return validFormat(format, s)
+}
+
+func (s *shape) schema() *schemaObject {
+	out := newSchemaObject()
+
+	var types []string
+	for _, t := range []string{"object", "array", "string", "integer", "number", "boolean", "null"} {
+		if s.types[t] > 0 {
+			types = append(types, t)
+		}
+	}
+	// 整数和小数混合时统一为 number
+	if s.types["integer"] > 0 && s.types["number"] > 0 {
+		for i, t := range types {
+			if t == "integer" {
+				types = append(types[:i], types[i+1:]...)
+				break
+			}
+		}
+	}
+	switch len(types) {
+	case 0:
+		// 只见过空数组的元素，不限制
+		return out
+	case 1:
+		out.set("type", types[0])
+	default:
+		list := make([]interface{}, len(types))
+		for i, t := range types {
+			list[i] = t
+		}
+		out.set("type", list)
+	}
+
+	if s.stringCount > 0 {
+		format := ""
+		for _, f := range inferFormats {
+			if s.formatCount[f] == s.stringCount {
+				format = f
+				break
+			}
+		}
+		if format != "" {
+			out.set("format", format)
+		} else if s.enumMax > 0 && !s.tooMany && s.stringCount >= 2*len(s.values) {
+			values := make([]string, 0, len(s.values))
+			for v := range s.values {
+				values = append(values, v)
+			}
+			sort.Strings(values)
+			enum := make([]interface{}, 0, len(values)+1)
+			for _, v := range values {
+				enum = append(enum, v)
+			}
+			// enum 同时约束其他类型的值，可为 null 时要把 null 加进去；还有其他类型时不生成 enum
+			if len(types) == 1 || (len(types) == 2 && s.types["null"] > 0) {
+				if s.types["null"] > 0 {
+					enum = append(enum, nil)
+				}
+				out.set("enum", enum)
+			}
+		}
+	}
+
+	if s.objects > 0 {
+		props := newSchemaObject()
+		var required []interface{}
+		for _, k := range sortedShapeKeys(s.props) {
+			p := s.props[k]
+			props.set(k, p.schema())
+			if p.count() == s.objects {
+				required = append(required, k)
+			}
+		}
+		out.set("properties", props)
+		if len(required) > 0 {
+			out.set("required", required)
+		}
+	}
+	if s.items != nil && s.items.count() > 0 {
+		out.set("items", s.items.schema())
+	}
+	return out
+}
+
+func sortedShapeKeys(m map[string]*shape) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// schemaObject 按插入顺序输出键的 JSON 对象，让 type 等关键字排在 properties 前面
+type schemaObject struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newSchemaObject() *schemaObject {
+	return &schemaObject{values: map[string]interface{}{}}
+}
+
+func (o *schemaObject) set(k string, v interface{}) {
+	if _, ok := o.values[k]; !ok {
+		o.keys = append(o.keys, k)
+	}
+	o.values[k] = v
+}
+
+func (o *schemaObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := enc.Encode(k); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := enc.Encode(o.values[k]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
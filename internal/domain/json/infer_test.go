@@ -0,0 +1,85 @@
+package json
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestInferSchema(t *testing.T) {
+	samples := []string{
+		`{"id":"3b241101-e2bb-4255-8caf-4136c566a962","status":"active","score":1,"tags":["a"],"createdAt":"2024-01-01T10:00:00Z","owner":{"email":"a@example.com"},"note":null}`,
+		`{"id":"9c858901-8a57-4791-81fe-4c455b099bc9","status":"inactive","score":2.5,"tags":[],"createdAt":"2024-02-01T10:00:00+08:00","owner":{"email":"b@example.com","site":"https://example.com"},"note":"x"}`,
+		`resp: {"id":"1f0e3dad-9990-4b1d-8f2b-6a3c1b1f0b6e","status":"active","score":3,"tags":["b",1],"createdAt":"2024-03-01T00:00:00Z","owner":{"email":"c@example.com"}}`,
+		`{"id":"5e3c0a1e-4b9a-4c55-9f0d-2b1c9c8f7a11","status":"active","score":4,"createdAt":"2024-04-01T00:00:00Z","owner":{"email":"d@example.com"},"note":null}`,
+	}
+	res, err := InferSchema(samples, InferOptions{}, 0)
+	if err != nil {
+		t.Fatalf("InferSchema() error = %v", err)
+	}
+	want := `{"$schema":"https://json-schema.org/draft/2020-12/schema","type":"object","properties":{` +
+		`"createdAt":{"type":"string","format":"date-time"},` +
+		`"id":{"type":"string","format":"uuid"},` +
+		`"note":{"type":["string","null"]},` +
+		`"owner":{"type":"object","properties":{"email":{"type":"string","format":"email"},"site":{"type":"string","format":"uri"}},"required":["email"]},` +
+		`"score":{"type":"number"},` +
+		`"status":{"type":"string","enum":["active","inactive"]},` +
+		`"tags":{"type":"array","items":{"type":["string","integer"]}}},` +
+		`"required":["createdAt","id","owner","score","status"]}`
+	if res.Schema != want {
+		t.Errorf("schema =\n%s\nwant\n%s", res.Schema, want)
+	}
+	if res.Samples != 4 || res.Draft != SchemaDraft2020 {
+		t.Errorf("Samples = %d, Draft = %q", res.Samples, res.Draft)
+	}
+
+	// 推断出的 schema 应当能通过所有样本
+	for i, s := range samples {
+		v, err := Validate(s, res.Schema, "")
+		if err != nil || !v.Valid {
+			t.Errorf("sample %d: %v %+v", i+1, err, v.Violations)
+		}
+	}
+}
+
+func TestInferSchemaOptions(t *testing.T) {
+	samples := []string{`[{"k":"a"},{"k":"a"},{"k":"b"},{"k":null},{"k":"b"}]`}
+
+	res, err := InferSchema(samples, InferOptions{Draft: SchemaDraft7}, 0)
+	if err != nil {
+		t.Fatalf("InferSchema() error = %v", err)
+	}
+	want := `{"$schema":"http://json-schema.org/draft-07/schema#","type":"array","items":{"type":"object","properties":{"k":{"type":["string","null"],"enum":["a","b",null]}},"required":["k"]}}`
+	if res.Schema != want {
+		t.Errorf("schema =\n%s\nwant\n%s", res.Schema, want)
+	}
+
+	res, _ = InferSchema(samples, InferOptions{EnumMaxValues: -1}, 0)
+	if strings.Contains(res.Schema, "enum") {
+		t.Errorf("EnumMaxValues < 0 时不应生成 enum：%s", res.Schema)
+	}
+	res, _ = InferSchema(samples, InferOptions{EnumMaxValues: 1}, 0)
+	if strings.Contains(res.Schema, "enum") {
+		t.Errorf("取值超过 EnumMaxValues 时不应生成 enum：%s", res.Schema)
+	}
+	res, _ = InferSchema([]string{`{"k":"a"}`}, InferOptions{}, 0)
+	if strings.Contains(res.Schema, "enum") {
+		t.Errorf("每个取值只出现一次时不应生成 enum：%s", res.Schema)
+	}
+
+	res, _ = InferSchema([]string{`{"a":[]}`}, InferOptions{}, 2)
+	if want := "{\n  \"$schema\": \"https://json-schema.org/draft/2020-12/schema\",\n  \"type\": \"object\",\n  \"properties\": {\n    \"a\": {\n      \"type\": \"array\"\n    }\n  },\n  \"required\": [\n    \"a\"\n  ]\n}"; res.Schema != want {
+		t.Errorf("schema =\n%s\nwant\n%s", res.Schema, want)
+	}
+}
+
+func TestInferSchemaErrors(t *testing.T) {
+	if _, err := InferSchema(nil, InferOptions{}, 0); err == nil {
+		t.Error("没有样本应报错")
+	}
+	if _, err := InferSchema([]string{`{}`, `{"a":`}, InferOptions{}, 0); err == nil || !strings.HasPrefix(err.Error(), "sample 2: ") {
+		t.Errorf("无效样本应报错并指出序号：%v", err)
+	}
+	if _, err := InferSchema([]string{`{}`}, InferOptions{Draft: "draft-04"}, 0); err == nil {
+		t.Error("不支持的 draft 应报错")
+	}
+}
@@ -0,0 +1,729 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jqFilter 编译后的 jq 过滤器：一个输入产生零到多个输出。
+// 出错时同时返回出错前已产生的输出，? 据此实现
+type jqFilter func(v interface{}, b *queryBudget) ([]interface{}, error)
+
+// compileJQ 编译 jq 表达式，支持的子集：
+// .、.a.b、."a b"、.[0]、.[-1]、.[1:3]、.[]、..、?、|、,、//、and、or、not、
+// == != < <= > >=、字面量、[...]、{a: .x, b}，以及 length、keys、has、type、select、map、first、last、empty
+func compileJQ(expr string) (jqFilter, error) {
+	toks, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	s := &tokenStream{toks: toks}
+	f, err := parseJQPipe(s)
+	if err != nil {
+		return nil, err
+	}
+	if s.peek().kind != tokEOF {
+		return nil, s.unexpected("expected end of query")
+	}
+	return f, nil
+}
+
+func parseJQPipe(s *tokenStream) (jqFilter, error) {
+	left, err := parseJQComma(s)
+	if err != nil || !s.accept("|") {
+		return left, err
+	}
+	right, err := parseJQPipe(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		in, err := left(v, b)
+		var out []interface{}
+		for _, x := range in {
+			res, rerr := right(x, b)
+			out = append(out, res...)
+			if rerr == nil {
+				rerr = b.spend(len(res))
+			}
+			if rerr != nil {
+				return out, rerr
+			}
+		}
+		return out, err
+	}, nil
+}
+
+func parseJQComma(s *tokenStream) (jqFilter, error) {
+	left, err := parseJQAlt(s)
+	if err != nil {
+		return nil, err
+	}
+	for s.accept(",") {
+		right, err := parseJQAlt(s)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			out, err := l(v, b)
+			if err != nil {
+				return out, err
+			}
+			res, err := right(v, b)
+			if err == nil {
+				err = b.spend(len(res))
+			}
+			return append(out, res...), err
+		}
+	}
+	return left, nil
+}
+
+// parseJQAlt a // b：a 的输出中为真的部分，没有时取 b
+func parseJQAlt(s *tokenStream) (jqFilter, error) {
+	left, err := parseJQOr(s)
+	if err != nil || !s.accept("//") {
+		return left, err
+	}
+	right, err := parseJQAlt(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		in, err := left(v, b)
+		if b.exceeded() {
+			return nil, err
+		}
+		var out []interface{}
+		for _, x := range in {
+			if truthy(x) {
+				out = append(out, x)
+			}
+		}
+		if len(out) > 0 {
+			return out, nil
+		}
+		return right(v, b)
+	}, nil
+}
+
+func parseJQOr(s *tokenStream) (jqFilter, error) {
+	return parseJQLogic(s, "or", parseJQAnd)
+}
+
+func parseJQAnd(s *tokenStream) (jqFilter, error) {
+	return parseJQLogic(s, "and", parseJQCompare)
+}
+
+func parseJQLogic(s *tokenStream, op string, operand func(*tokenStream) (jqFilter, error)) (jqFilter, error) {
+	left, err := operand(s)
+	if err != nil {
+		return nil, err
+	}
+	for s.accept(op) {
+		right, err := operand(s)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			in, err := l(v, b)
+			if err != nil {
+				return nil, err
+			}
+			var out []interface{}
+			for _, a := range in {
+				// 短路：and 左边为假、or 左边为真时不再计算右边
+				if truthy(a) == (op == "or") {
+					out = append(out, op == "or")
+					continue
+				}
+				res, err := right(v, b)
+				if err != nil {
+					return out, err
+				}
+				for _, b := range res {
+					out = append(out, truthy(b))
+				}
+			}
+			return out, nil
+		}
+	}
+	return left, nil
+}
+
+func parseJQCompare(s *tokenStream) (jqFilter, error) {
+	left, err := parseJQPostfix(s)
+	if err != nil {
+		return nil, err
+	}
+	op := s.peek()
+	if op.kind != tokPunct || !isComparison(op.text) || op.text == "=~" {
+		return left, nil
+	}
+	s.next()
+	right, err := parseJQPostfix(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		return cartesian(v, b, left, right, func(x, y interface{}) (interface{}, error) {
+			c := compareValues(x, y)
+			switch op.text {
+			case "==":
+				return c == 0, nil
+			case "!=":
+				return c != 0, nil
+			case "<":
+				return c < 0, nil
+			case "<=":
+				return c <= 0, nil
+			case ">":
+				return c > 0, nil
+			}
+			return c >= 0, nil
+		})
+	}, nil
+}
+
+// cartesian 对两个过滤器的输出做笛卡尔积（右边在外层，与 jq 一致）
+func cartesian(v interface{}, b *queryBudget, left, right jqFilter, fn func(x, y interface{}) (interface{}, error)) ([]interface{}, error) {
+	rs, err := right(v, b)
+	if err != nil {
+		return nil, err
+	}
+	var out []interface{}
+	for _, y := range rs {
+		ls, err := left(v, b)
+		if err != nil {
+			return out, err
+		}
+		if err := b.spend(len(ls)); err != nil {
+			return out, err
+		}
+		for _, x := range ls {
+			res, err := fn(x, y)
+			if err != nil {
+				return out, err
+			}
+			out = append(out, res)
+		}
+	}
+	return out, nil
+}
+
+func parseJQPostfix(s *tokenStream) (jqFilter, error) {
+	f, err := parseJQPrimary(s)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case s.is(".") && s.toks[s.pos+1].text == "[":
+			// .a.[0] 与 .a[0] 相同
+			s.next()
+		case s.is("."):
+			dot := s.next()
+			name, ok := fieldName(s, dot)
+			if !ok {
+				return nil, s.unexpected("expected a field name")
+			}
+			f = then(f, fieldFilter(name))
+		case s.is("["):
+			idx, err := parseJQBracket(s)
+			if err != nil {
+				return nil, err
+			}
+			f = then(f, idx)
+		case s.accept("?"):
+			f = optional(f)
+		default:
+			return f, nil
+		}
+	}
+}
+
+// fieldName 读取紧跟在 . 后面的字段名（.foo 或 ."foo"），. 与字段名之间不能有空格
+func fieldName(s *tokenStream, dot token) (string, bool) {
+	t := s.peek()
+	if (t.kind == tokIdent || t.kind == tokString) && t.pos == dot.pos+1 {
+		s.next()
+		return t.text, true
+	}
+	return "", false
+}
+
+func parseJQPrimary(s *tokenStream) (jqFilter, error) {
+	t := s.peek()
+	switch {
+	case s.accept(".."):
+		return recurseFilter, nil
+	case s.is("."):
+		dot := s.next()
+		if name, ok := fieldName(s, dot); ok {
+			return fieldFilter(name), nil
+		}
+		return identity, nil
+	case t.kind == tokString:
+		s.next()
+		return constant(t.text), nil
+	case t.kind == tokNumber:
+		s.next()
+		return constant(json.Number(t.text)), nil
+	case s.is("-"):
+		s.next()
+		if n := s.peek(); n.kind == tokNumber {
+			s.next()
+			return constant(json.Number("-" + n.text)), nil
+		}
+		return nil, s.unexpected("expected a number")
+	case s.accept("("):
+		f, err := parseJQPipe(s)
+		if err != nil {
+			return nil, err
+		}
+		return f, s.expect(")")
+	case s.accept("["):
+		if s.accept("]") {
+			return constant([]interface{}{}), nil
+		}
+		f, err := parseJQPipe(s)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.expect("]"); err != nil {
+			return nil, err
+		}
+		return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			out, err := f(v, b)
+			if out == nil {
+				out = []interface{}{}
+			}
+			return []interface{}{out}, err
+		}, nil
+	case s.accept("{"):
+		return parseJQObject(s)
+	case t.kind == tokIdent:
+		s.next()
+		return parseJQFunction(s, t)
+	}
+	return nil, s.unexpected("expected a filter")
+}
+
+// parseJQBracket 解析 [] / [e] / [a:b]，e、a、b 相对于当前输入计算
+func parseJQBracket(s *tokenStream) (jqFilter, error) {
+	s.next()
+	if s.accept("]") {
+		return iterateFilter, nil
+	}
+	var start, end jqFilter
+	var err error
+	if !s.is(":") {
+		if start, err = parseJQPipe(s); err != nil {
+			return nil, err
+		}
+	}
+	if !s.accept(":") {
+		if err := s.expect("]"); err != nil {
+			return nil, err
+		}
+		return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			keys, err := start(v, b)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]interface{}, 0, len(keys))
+			for _, k := range keys {
+				res, err := index(v, k)
+				if err != nil {
+					return out, err
+				}
+				out = append(out, res)
+			}
+			return out, nil
+		}, nil
+	}
+	if !s.is("]") {
+		if end, err = parseJQPipe(s); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.expect("]"); err != nil {
+		return nil, err
+	}
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		bound := func(f jqFilter) (*int, error) {
+			if f == nil {
+				return nil, nil
+			}
+			res, err := f(v, b)
+			if err != nil || len(res) == 0 || res[0] == nil {
+				return nil, err
+			}
+			n, ok := res[0].(json.Number)
+			if !ok {
+				return nil, fmt.Errorf("cannot slice with %s", typeOf(res[0]))
+			}
+			f64, _ := n.Float64()
+			i := int(f64)
+			return &i, nil
+		}
+		lo, err := bound(start)
+		if err != nil {
+			return nil, err
+		}
+		hi, err := bound(end)
+		if err != nil {
+			return nil, err
+		}
+		switch x := v.(type) {
+		case nil:
+			return []interface{}{nil}, nil
+		case []interface{}:
+			out := []interface{}{}
+			for _, i := range sliceBounds(len(x), lo, hi, 1) {
+				out = append(out, x[i])
+			}
+			return []interface{}{out}, nil
+		case string:
+			r := []rune(x)
+			var b2 strings.Builder
+			for _, i := range sliceBounds(len(r), lo, hi, 1) {
+				b2.WriteRune(r[i])
+			}
+			return []interface{}{b2.String()}, nil
+		}
+		return nil, fmt.Errorf("cannot slice %s", typeOf(v))
+	}, nil
+}
+
+// parseJQObject 解析 {a: f, "b": g, c}，c 是 c: .c 的简写
+func parseJQObject(s *tokenStream) (jqFilter, error) {
+	type entry struct {
+		key   string
+		value jqFilter
+	}
+	var entries []entry
+	for !s.accept("}") {
+		if len(entries) > 0 {
+			if err := s.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		t := s.peek()
+		if t.kind != tokIdent && t.kind != tokString {
+			return nil, s.unexpected("expected an object key")
+		}
+		s.next()
+		e := entry{key: t.text, value: fieldFilter(t.text)}
+		if s.accept(":") {
+			v, err := parseJQAlt(s)
+			if err != nil {
+				return nil, err
+			}
+			e.value = v
+		}
+		entries = append(entries, e)
+	}
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		objs := []map[string]interface{}{{}}
+		for _, e := range entries {
+			vals, err := e.value(v, b)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.spend(len(objs) * len(vals)); err != nil {
+				return nil, err
+			}
+			next := make([]map[string]interface{}, 0, len(objs)*len(vals))
+			for _, o := range objs {
+				for _, val := range vals {
+					c := make(map[string]interface{}, len(o)+1)
+					for k, x := range o {
+						c[k] = x
+					}
+					c[e.key] = val
+					next = append(next, c)
+				}
+			}
+			objs = next
+		}
+		out := make([]interface{}, len(objs))
+		for i, o := range objs {
+			out[i] = o
+		}
+		return out, nil
+	}, nil
+}
+
+func parseJQFunction(s *tokenStream, name token) (jqFilter, error) {
+	var args []jqFilter
+	if s.accept("(") {
+		for {
+			f, err := parseJQPipe(s)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, f)
+			if !s.accept(";") {
+				break
+			}
+		}
+		if err := s.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	arity := func(n int) error {
+		if len(args) != n {
+			return fmt.Errorf("%s/%d is not defined", name.text, len(args))
+		}
+		return nil
+	}
+	switch name.text {
+	case "true", "false":
+		return constant(name.text == "true"), arity(0)
+	case "null":
+		return constant(nil), arity(0)
+	case "empty":
+		return func(interface{}, *queryBudget) ([]interface{}, error) { return nil, nil }, arity(0)
+	case "not":
+		return mapValue(func(v interface{}) (interface{}, error) { return !truthy(v), nil }), arity(0)
+	case "type":
+		return mapValue(func(v interface{}) (interface{}, error) { return typeOf(v), nil }), arity(0)
+	case "length":
+		return mapValue(length), arity(0)
+	case "keys":
+		return mapValue(keys), arity(0)
+	case "first":
+		return mapValue(func(v interface{}) (interface{}, error) { return index(v, json.Number("0")) }), arity(0)
+	case "last":
+		return mapValue(func(v interface{}) (interface{}, error) { return index(v, json.Number("-1")) }), arity(0)
+	case "select":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			conds, err := args[0](v, b)
+			var out []interface{}
+			for _, c := range conds {
+				if truthy(c) {
+					out = append(out, v)
+				}
+			}
+			return out, err
+		}, nil
+	case "map":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			items, err := iterate(v)
+			if err != nil {
+				return nil, err
+			}
+			out := []interface{}{}
+			for _, x := range items {
+				res, err := args[0](x, b)
+				if err != nil {
+					return nil, err
+				}
+				out = append(out, res...)
+			}
+			return []interface{}{out}, nil
+		}, nil
+	case "has":
+		if err := arity(1); err != nil {
+			return nil, err
+		}
+		return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+			keys, err := args[0](v, b)
+			if err != nil {
+				return nil, err
+			}
+			out := make([]interface{}, 0, len(keys))
+			for _, k := range keys {
+				switch x := v.(type) {
+				case map[string]interface{}:
+					if ks, ok := k.(string); ok {
+						_, found := x[ks]
+						out = append(out, found)
+						continue
+					}
+				case []interface{}:
+					if n, ok := k.(json.Number); ok {
+						f, _ := n.Float64()
+						out = append(out, f >= 0 && int(f) < len(x))
+						continue
+					}
+				}
+				return out, fmt.Errorf("cannot check whether %s has a %s key", typeOf(v), typeOf(k))
+			}
+			return out, nil
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown function %q at position %d", name.text, name.pos)
+}
+
+func identity(v interface{}, b *queryBudget) ([]interface{}, error) {
+	return []interface{}{v}, nil
+}
+
+func constant(c interface{}) jqFilter {
+	return func(interface{}, *queryBudget) ([]interface{}, error) { return []interface{}{c}, nil }
+}
+
+func mapValue(fn func(interface{}) (interface{}, error)) jqFilter {
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		res, err := fn(v)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{res}, nil
+	}
+}
+
+// then 依次执行 f 和 g，相当于 f | g
+func then(f, g jqFilter) jqFilter {
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		in, err := f(v, b)
+		var out []interface{}
+		for _, x := range in {
+			res, gerr := g(x, b)
+			out = append(out, res...)
+			if gerr == nil {
+				gerr = b.spend(len(res))
+			}
+			if gerr != nil {
+				return out, gerr
+			}
+		}
+		return out, err
+	}
+}
+
+// optional f? 忽略错误，保留出错前的输出；超出计算上限的错误不能忽略
+func optional(f jqFilter) jqFilter {
+	return func(v interface{}, b *queryBudget) ([]interface{}, error) {
+		out, err := f(v, b)
+		if b.exceeded() {
+			return out, err
+		}
+		return out, nil
+	}
+}
+
+func fieldFilter(name string) jqFilter {
+	return mapValue(func(v interface{}) (interface{}, error) { return index(v, name) })
+}
+
+// index 实现 .[k]：对象按字符串取值，数组按数字取值，null 上取值得到 null
+func index(v, k interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		if ks, ok := k.(string); ok {
+			return x[ks], nil
+		}
+	case []interface{}:
+		if n, ok := k.(json.Number); ok {
+			if i, ok := arrayIndex(n, len(x)); ok {
+				return x[i], nil
+			}
+			return nil, nil
+		}
+	}
+	if ks, ok := k.(string); ok {
+		return nil, fmt.Errorf("cannot index %s with %q", typeOf(v), ks)
+	}
+	return nil, fmt.Errorf("cannot index %s with %s", typeOf(v), typeOf(k))
+}
+
+// iterate 实现 .[]：数组的元素或对象的值（按键名排序）
+func iterate(v interface{}) ([]interface{}, error) {
+	switch x := v.(type) {
+	case []interface{}:
+		return x, nil
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(x))
+		for _, k := range sortedKeys(x) {
+			out = append(out, x[k])
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("cannot iterate over %s", typeOf(v))
+}
+
+// iterateFilter 把 iterate 用作过滤器 .[]
+func iterateFilter(v interface{}, b *queryBudget) ([]interface{}, error) {
+	out, err := iterate(v)
+	if err != nil {
+		return nil, err
+	}
+	return out, b.spend(len(out))
+}
+
+// recurseFilter 实现 ..：自身及所有后代，先序遍历
+func recurseFilter(v interface{}, b *queryBudget) ([]interface{}, error) {
+	var out []interface{}
+	return out, recurseInto(v, b, &out)
+}
+
+func recurseInto(v interface{}, b *queryBudget, out *[]interface{}) error {
+	if err := b.spend(1); err != nil {
+		return err
+	}
+	*out = append(*out, v)
+	items, err := iterate(v)
+	if err != nil {
+		// 标量没有后代
+		return nil
+	}
+	for _, x := range items {
+		if err := recurseInto(x, b, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func length(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case nil:
+		return json.Number("0"), nil
+	case bool:
+		return nil, fmt.Errorf("boolean (%v) has no length", x)
+	case json.Number:
+		return json.Number(strings.TrimPrefix(string(x), "-")), nil
+	case string:
+		return json.Number(strconv.Itoa(utf8.RuneCountInString(x))), nil
+	case []interface{}:
+		return json.Number(strconv.Itoa(len(x))), nil
+	case map[string]interface{}:
+		return json.Number(strconv.Itoa(len(x))), nil
+	}
+	return nil, fmt.Errorf("%s has no length", typeOf(v))
+}
+
+func keys(v interface{}) (interface{}, error) {
+	switch x := v.(type) {
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(x))
+		for _, k := range sortedKeys(x) {
+			out = append(out, k)
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i := range x {
+			out[i] = json.Number(strconv.Itoa(i))
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("%s has no keys", typeOf(v))
+}
@@ -0,0 +1,130 @@
+package json
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestJQ(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "字段路径",
+			query: ".store.bicycle.color",
+			want:  `"red"`,
+		},
+		{
+			name:  "带引号的字段与下标",
+			query: `."store".book[1].title`,
+			want:  `"Sword of Honour"`,
+		},
+		{
+			name:  "迭代与管道",
+			query: ".store.book[] | .author",
+			want:  "\"Nigel Rees\"\n\"Evelyn Waugh\"\n\"Herman Melville\"",
+		},
+		{
+			name:  "select",
+			query: ".store.book[] | select(.price < 10 and has(\"isbn\")) | .title",
+			want:  `"Moby Dick"`,
+		},
+		{
+			name:  "map",
+			query: ".store.book | map(.price)",
+			want:  `[8.95,12.99,8.99]`,
+		},
+		{
+			name:  "keys 与 length",
+			query: ".store | keys, length",
+			want:  "[\"bicycle\",\"book\"]\n2",
+		},
+		{
+			name:  "切片",
+			query: ".store.book[1:] | map(.title[0:4])",
+			want:  `["Swor","Moby"]`,
+		},
+		{
+			name:  "数组与对象构造",
+			query: "[.store.book[] | {title, cheap: (.price < 10)}]",
+			want:  `[{"cheap":true,"title":"Sayings of the Century"},{"cheap":false,"title":"Sword of Honour"},{"cheap":true,"title":"Moby Dick"}]`,
+		},
+		{
+			name:  "递归与可选",
+			query: "[..|.isbn?] | map(select(. != null))",
+			want:  `["0-553-21311-3"]`,
+		},
+		{
+			name:  "默认值",
+			query: ".store.book[0].isbn // \"none\"",
+			want:  `"none"`,
+		},
+		{
+			name:  "first、last 与 type",
+			query: ".store.book | first.price, (last | .isbn | type)",
+			want:  "8.95\n\"string\"",
+		},
+		{
+			name:  "大整数保持精度",
+			query: ".id",
+			want:  `12345678901234567890123`,
+		},
+		{
+			name:  "大整数比较",
+			query: ".id > 12345678901234567890122",
+			want:  `true`,
+		},
+		{
+			name:  "null 上取字段",
+			query: ".missing.field",
+			want:  `null`,
+		},
+		{
+			name:  "没有输出",
+			query: ".store.book[] | select(.price > 100)",
+			want:  ``,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Query(storeJSON, tt.query, "", 0)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got.Language != QueryJQ || got.Output != tt.want {
+				t.Errorf("Query() = %s %q, want %q", got.Language, got.Output, tt.want)
+			}
+		})
+	}
+}
+
+func TestJQErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"对数组取字段", ".store.book.title", `cannot index array with "title"`},
+		{"迭代字符串", ".store.bicycle.color[]", "cannot iterate over string"},
+		{"未知函数", ".store | values", `unknown function "values" at position 9`},
+		{"参数个数", "select", "select/0 is not defined"},
+		{"语法错误", ".store |", "unexpected end of query, expected a filter"},
+		{"括号不匹配", "(.store", `unexpected end of query, expected ")"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Query(storeJSON, tt.query, QueryJQ, 0)
+			if !errors.Is(err, ErrInvalidQuery) || err.Error() != "invalid query: "+tt.want {
+				t.Errorf("Query() error = %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	// ? 忽略错误
+	got, err := Query(storeJSON, ".store.book.title?", QueryJQ, 0)
+	if err != nil || got.Output != "" {
+		t.Errorf("Query(?) = %q, %v", got.Output, err)
+	}
+}
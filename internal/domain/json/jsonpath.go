@@ -0,0 +1,468 @@
+package json
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// jsonPath 编译后的 JSONPath（RFC 9535 的常用部分）：
+// $.a.b、$['a']、$[0]、$[-1]、$[0,2]、$[1:3]、$[::-1]、$.*、$..a、$[?(@.price < 10)]
+type jsonPath []pathSegment
+
+// pathSegment 一段路径；descendant 对应 ..，选择器之间是并集
+type pathSegment struct {
+	descendant bool
+	selectors  []pathSelector
+}
+
+type selectorKind int
+
+const (
+	selName selectorKind = iota
+	selWildcard
+	selIndex
+	selSlice
+	selFilter
+)
+
+type pathSelector struct {
+	kind       selectorKind
+	name       string
+	index      int
+	start, end *int
+	step       int
+	filter     pathFilter
+}
+
+// pathNode 匹配到的值及其规范化路径
+type pathNode struct {
+	value interface{}
+	path  string
+}
+
+// pathFilter 过滤表达式，root 为文档根（$），cur 为当前元素（@）
+type pathFilter func(root, cur interface{}, b *queryBudget) bool
+
+// pathOperand 过滤表达式中的操作数；路径没有匹配时 ok 为 false
+type pathOperand func(root, cur interface{}, b *queryBudget) (v interface{}, ok bool)
+
+func compileJSONPath(expr string) (jsonPath, error) {
+	toks, err := tokenize(expr)
+	if err != nil {
+		return nil, err
+	}
+	s := &tokenStream{toks: toks}
+	if err := s.expect("$"); err != nil {
+		return nil, err
+	}
+	path, err := parseSegments(s)
+	if err != nil {
+		return nil, err
+	}
+	if s.peek().kind != tokEOF {
+		return nil, s.unexpected("expected end of query")
+	}
+	return path, nil
+}
+
+// parseSegments 解析 $ 或 @ 之后的各段
+func parseSegments(s *tokenStream) (jsonPath, error) {
+	var path jsonPath
+	for {
+		var seg pathSegment
+		switch {
+		case s.accept(".."):
+			seg.descendant = true
+			if s.is("[") {
+				break
+			}
+			sel, err := parseDotSelector(s)
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []pathSelector{sel}
+			path = append(path, seg)
+			continue
+		case s.accept("."):
+			sel, err := parseDotSelector(s)
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = []pathSelector{sel}
+			path = append(path, seg)
+			continue
+		case s.is("["):
+		default:
+			return path, nil
+		}
+
+		s.next()
+		for {
+			sel, err := parseBracketSelector(s)
+			if err != nil {
+				return nil, err
+			}
+			seg.selectors = append(seg.selectors, sel)
+			if !s.accept(",") {
+				break
+			}
+		}
+		if err := s.expect("]"); err != nil {
+			return nil, err
+		}
+		path = append(path, seg)
+	}
+}
+
+func parseDotSelector(s *tokenStream) (pathSelector, error) {
+	if s.accept("*") {
+		return pathSelector{kind: selWildcard}, nil
+	}
+	t := s.peek()
+	if t.kind != tokIdent {
+		return pathSelector{}, s.unexpected("expected a member name or *")
+	}
+	s.next()
+	return pathSelector{kind: selName, name: t.text}, nil
+}
+
+func parseBracketSelector(s *tokenStream) (pathSelector, error) {
+	t := s.peek()
+	switch {
+	case t.kind == tokString:
+		s.next()
+		return pathSelector{kind: selName, name: t.text}, nil
+	case s.accept("*"):
+		return pathSelector{kind: selWildcard}, nil
+	case s.accept("?"):
+		// 兼容 ?(...) 与 RFC 9535 的 ?...
+		f, err := parseFilterOr(s)
+		if err != nil {
+			return pathSelector{}, err
+		}
+		return pathSelector{kind: selFilter, filter: f}, nil
+	}
+
+	// 下标或切片
+	var nums [3]*int
+	part := 0
+	for {
+		if n, ok, err := parseInt(s); err != nil {
+			return pathSelector{}, err
+		} else if ok {
+			nums[part] = &n
+		}
+		if part == 2 || !s.accept(":") {
+			break
+		}
+		part++
+	}
+	if part == 0 {
+		if nums[0] == nil {
+			return pathSelector{}, s.unexpected("expected a selector")
+		}
+		return pathSelector{kind: selIndex, index: *nums[0]}, nil
+	}
+	step := 1
+	if nums[2] != nil {
+		step = *nums[2]
+	}
+	return pathSelector{kind: selSlice, start: nums[0], end: nums[1], step: step}, nil
+}
+
+func parseInt(s *tokenStream) (int, bool, error) {
+	neg := s.accept("-")
+	t := s.peek()
+	if t.kind != tokNumber {
+		if neg {
+			return 0, false, s.unexpected("expected a number")
+		}
+		return 0, false, nil
+	}
+	s.next()
+	n, err := strconv.Atoi(t.text)
+	if err != nil {
+		return 0, false, s.unexpected("expected an integer")
+	}
+	if neg {
+		n = -n
+	}
+	return n, true, nil
+}
+
+func parseFilterOr(s *tokenStream) (pathFilter, error) {
+	left, err := parseFilterAnd(s)
+	if err != nil {
+		return nil, err
+	}
+	for s.accept("||") {
+		right, err := parseFilterAnd(s)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(root, cur interface{}, b *queryBudget) bool { return l(root, cur, b) || right(root, cur, b) }
+	}
+	return left, nil
+}
+
+func parseFilterAnd(s *tokenStream) (pathFilter, error) {
+	left, err := parseFilterUnary(s)
+	if err != nil {
+		return nil, err
+	}
+	for s.accept("&&") {
+		right, err := parseFilterUnary(s)
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(root, cur interface{}, b *queryBudget) bool { return l(root, cur, b) && right(root, cur, b) }
+	}
+	return left, nil
+}
+
+func parseFilterUnary(s *tokenStream) (pathFilter, error) {
+	if s.accept("!") {
+		f, err := parseFilterUnary(s)
+		if err != nil {
+			return nil, err
+		}
+		return func(root, cur interface{}, b *queryBudget) bool { return !f(root, cur, b) }, nil
+	}
+	if s.accept("(") {
+		f, err := parseFilterOr(s)
+		if err != nil {
+			return nil, err
+		}
+		return f, s.expect(")")
+	}
+
+	left, isPath, err := parseOperand(s)
+	if err != nil {
+		return nil, err
+	}
+	op := s.peek()
+	if op.kind != tokPunct || !isComparison(op.text) {
+		if !isPath {
+			return nil, s.unexpected("expected a comparison")
+		}
+		// 只有路径时是存在性判断
+		return func(root, cur interface{}, b *queryBudget) bool {
+			_, ok := left(root, cur, b)
+			return ok
+		}, nil
+	}
+	s.next()
+
+	if op.text == "=~" {
+		t := s.next()
+		if t.kind != tokRegex && t.kind != tokString {
+			return nil, s.unexpected("expected a regular expression")
+		}
+		re, err := regexp.Compile(t.text)
+		if err != nil {
+			return nil, err
+		}
+		return func(root, cur interface{}, b *queryBudget) bool {
+			v, _ := left(root, cur, b)
+			str, ok := v.(string)
+			return ok && re.MatchString(str)
+		}, nil
+	}
+
+	right, _, err := parseOperand(s)
+	if err != nil {
+		return nil, err
+	}
+	return func(root, cur interface{}, b *queryBudget) bool {
+		x, xok := left(root, cur, b)
+		y, yok := right(root, cur, b)
+		return comparePath(op.text, x, xok, y, yok)
+	}, nil
+}
+
+func isComparison(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "=~":
+		return true
+	}
+	return false
+}
+
+// comparePath RFC 9535 的比较语义：不存在只等于不存在；大小比较只在数字之间或字符串之间成立
+func comparePath(op string, a interface{}, aok bool, b interface{}, bok bool) bool {
+	eq := aok == bok && (!aok || compareValues(a, b) == 0)
+	switch op {
+	case "==":
+		return eq
+	case "!=":
+		return !eq
+	}
+	if !aok || !bok || typeRank(a) != typeRank(b) || (typeOf(a) != "number" && typeOf(a) != "string") {
+		return false
+	}
+	c := compareValues(a, b)
+	switch op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+// parseOperand 解析 @...、$...、字面量；isPath 表示操作数是路径
+func parseOperand(s *tokenStream) (pathOperand, bool, error) {
+	t := s.peek()
+	switch {
+	case s.is("@") || s.is("$"):
+		s.next()
+		path, err := parseSegments(s)
+		if err != nil {
+			return nil, false, err
+		}
+		relative := t.text == "@"
+		return func(root, cur interface{}, b *queryBudget) (interface{}, bool) {
+			start := root
+			if relative {
+				start = cur
+			}
+			nodes := path.evalFrom(root, pathNode{value: start}, b)
+			if len(nodes) == 0 {
+				return nil, false
+			}
+			return nodes[0].value, true
+		}, true, nil
+	case t.kind == tokString:
+		s.next()
+		return literal(t.text), false, nil
+	case t.kind == tokNumber:
+		s.next()
+		return literal(json.Number(t.text)), false, nil
+	case s.is("-"):
+		s.next()
+		if n := s.peek(); n.kind == tokNumber {
+			s.next()
+			return literal(json.Number("-" + n.text)), false, nil
+		}
+		return nil, false, s.unexpected("expected a number")
+	case s.is("true"), s.is("false"), s.is("null"):
+		s.next()
+		var v interface{}
+		if t.text != "null" {
+			v = t.text == "true"
+		}
+		return literal(v), false, nil
+	}
+	return nil, false, s.unexpected("expected @, $ or a literal")
+}
+
+func literal(v interface{}) pathOperand {
+	return func(root, cur interface{}, b *queryBudget) (interface{}, bool) { return v, true }
+}
+
+// eval 求值；超出 b 的上限时提前结束，调用方通过 b.exceeded() 判断
+func (p jsonPath) eval(root interface{}, b *queryBudget) []pathNode {
+	return p.evalFrom(root, pathNode{value: root, path: "$"}, b)
+}
+
+func (p jsonPath) evalFrom(root interface{}, start pathNode, b *queryBudget) []pathNode {
+	nodes := []pathNode{start}
+	for _, seg := range p {
+		var next []pathNode
+		for _, n := range nodes {
+			targets := []pathNode{n}
+			if seg.descendant {
+				targets = descendants(n, nil, b)
+			}
+			for _, t := range targets {
+				for _, sel := range seg.selectors {
+					l := len(next)
+					next = sel.apply(root, t, next, b)
+					if b.spend(len(next)-l) != nil {
+						return nil
+					}
+				}
+			}
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// descendants 自身及所有后代，先序遍历，对象按键名排序
+func descendants(n pathNode, out []pathNode, b *queryBudget) []pathNode {
+	if b.spend(1) != nil {
+		return out
+	}
+	out = append(out, n)
+	for _, c := range children(n) {
+		out = descendants(c, out, b)
+	}
+	return out
+}
+
+func children(n pathNode) []pathNode {
+	switch x := n.value.(type) {
+	case []interface{}:
+		out := make([]pathNode, len(x))
+		for i, v := range x {
+			out[i] = pathNode{v, n.path + "[" + strconv.Itoa(i) + "]"}
+		}
+		return out
+	case map[string]interface{}:
+		keys := sortedKeys(x)
+		out := make([]pathNode, len(keys))
+		for i, k := range keys {
+			out[i] = pathNode{x[k], n.path + memberPath(k)}
+		}
+		return out
+	}
+	return nil
+}
+
+// memberPath 规范化路径中的成员名：['name']
+func memberPath(name string) string {
+	return "['" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(name) + "']"
+}
+
+func (sel pathSelector) apply(root interface{}, n pathNode, out []pathNode, b *queryBudget) []pathNode {
+	switch sel.kind {
+	case selName:
+		if m, ok := n.value.(map[string]interface{}); ok {
+			if v, ok := m[sel.name]; ok {
+				out = append(out, pathNode{v, n.path + memberPath(sel.name)})
+			}
+		}
+	case selWildcard:
+		out = append(out, children(n)...)
+	case selIndex:
+		if arr, ok := n.value.([]interface{}); ok {
+			i := sel.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i >= 0 && i < len(arr) {
+				out = append(out, pathNode{arr[i], n.path + "[" + strconv.Itoa(i) + "]"})
+			}
+		}
+	case selSlice:
+		if arr, ok := n.value.([]interface{}); ok {
+			for _, i := range sliceBounds(len(arr), sel.start, sel.end, sel.step) {
+				out = append(out, pathNode{arr[i], n.path + "[" + strconv.Itoa(i) + "]"})
+			}
+		}
+	case selFilter:
+		for _, c := range children(n) {
+			if sel.filter(root, c.value, b) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
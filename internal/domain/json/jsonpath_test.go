@@ -0,0 +1,129 @@
+package json
+
+import (
+	"reflect"
+	"testing"
+)
+
+const storeJSON = `{
+  "store": {
+    "book": [
+      {"category": "reference", "author": "Nigel Rees", "title": "Sayings of the Century", "price": 8.95},
+      {"category": "fiction", "author": "Evelyn Waugh", "title": "Sword of Honour", "price": 12.99},
+      {"category": "fiction", "author": "Herman Melville", "title": "Moby Dick", "isbn": "0-553-21311-3", "price": 8.99}
+    ],
+    "bicycle": {"color": "red", "price": 19.95}
+  },
+  "id": 12345678901234567890123
+}`
+
+func TestJSONPath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  string
+		want  string
+		paths []string
+	}{
+		{
+			name:  "点号路径",
+			path:  "$.store.bicycle.color",
+			want:  `["red"]`,
+			paths: []string{"$['store']['bicycle']['color']"},
+		},
+		{
+			name:  "方括号与引号",
+			path:  `$['store']["bicycle"]['price']`,
+			want:  `[19.95]`,
+			paths: []string{"$['store']['bicycle']['price']"},
+		},
+		{
+			name: "通配符",
+			path: "$.store.book[*].author",
+			want: `["Nigel Rees","Evelyn Waugh","Herman Melville"]`,
+		},
+		{
+			name: "递归下降",
+			path: "$..price",
+			want: `[19.95,8.95,12.99,8.99]`,
+		},
+		{
+			name:  "负数下标",
+			path:  "$.store.book[-1].title",
+			want:  `["Moby Dick"]`,
+			paths: []string{"$['store']['book'][2]['title']"},
+		},
+		{
+			name: "多个下标",
+			path: "$.store.book[0,2].price",
+			want: `[8.95,8.99]`,
+		},
+		{
+			name: "切片",
+			path: "$.store.book[1:].price",
+			want: `[12.99,8.99]`,
+		},
+		{
+			name: "倒序切片",
+			path: "$.store.book[::-1].price",
+			want: `[8.99,12.99,8.95]`,
+		},
+		{
+			name: "过滤：比较",
+			path: "$.store.book[?(@.price < 10)].title",
+			want: `["Sayings of the Century","Moby Dick"]`,
+		},
+		{
+			name: "过滤：存在性",
+			path: "$..book[?(@.isbn)].author",
+			want: `["Herman Melville"]`,
+		},
+		{
+			name: "过滤：逻辑运算（RFC 9535 写法）",
+			path: "$.store.book[?@.category == 'fiction' && !(@.price > 10)].title",
+			want: `["Moby Dick"]`,
+		},
+		{
+			name: "过滤：正则",
+			path: "$.store.book[?(@.author =~ /.*rees/i)].price",
+			want: `[8.95]`,
+		},
+		{
+			name: "过滤：引用根节点",
+			path: "$.store.book[?(@.price > $.store.bicycle.price)]",
+			want: `[]`,
+		},
+		{
+			name:  "大整数保持精度",
+			path:  "$.id",
+			want:  `[12345678901234567890123]`,
+			paths: []string{"$['id']"},
+		},
+		{
+			name: "不存在的路径",
+			path: "$.store.car",
+			want: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Query(storeJSON, tt.path, "", 0)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got.Language != QueryJSONPath || got.Output != tt.want {
+				t.Errorf("Query() = %s %s, want %s", got.Language, got.Output, tt.want)
+			}
+			if tt.paths != nil && !reflect.DeepEqual(got.Paths, tt.paths) {
+				t.Errorf("Query() paths = %v, want %v", got.Paths, tt.paths)
+			}
+		})
+	}
+}
+
+func TestJSONPathErrors(t *testing.T) {
+	for _, path := range []string{"$.", "$[", "$.a[?(@.b <)]", "$[1:2:3:4]", "$.a b", "$..book[?(@.a =~ /[/)]"} {
+		if _, err := Query(storeJSON, path, QueryJSONPath, 0); err == nil {
+			t.Errorf("Query(%q) error = nil", path)
+		}
+	}
+}
@@ -0,0 +1,495 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 查询语言
+const (
+	QueryJSONPath = "jsonpath"
+	QueryJQ       = "jq"
+)
+
+// ErrInvalidQuery 表达式有语法错误或无法作用于输入（如对数组取字段）
+var ErrInvalidQuery = errors.New("invalid query")
+
+// maxQueryResults 查询结果数量上限，防止 .. 之类的表达式在大文档上输出过多
+const maxQueryResults = 10000
+
+// maxQueryValues 一次查询在计算过程中最多产生的值（含中间结果）。
+// 中间结果可以合理地多于最终结果（如 [.[] | .a] | length），所以比 maxQueryResults 宽松
+const maxQueryValues = 100 * maxQueryResults
+
+// errQueryTooLarge 计算过程中产生的值超过 maxQueryValues
+var errQueryTooLarge = fmt.Errorf("too many results (max %d values while evaluating)", maxQueryValues)
+
+// queryBudget 一次查询共用的计数，在 ..|..|.. 之类的表达式生成全部结果之前就中止计算
+type queryBudget struct {
+	left int
+}
+
+func newQueryBudget() *queryBudget {
+	return &queryBudget{left: maxQueryValues}
+}
+
+// spend 记入新产生的 n 个值，超出上限后返回 errQueryTooLarge
+func (b *queryBudget) spend(n int) error {
+	b.left -= n
+	if b.left < 0 {
+		return errQueryTooLarge
+	}
+	return nil
+}
+
+// exceeded 是否已超出上限；? 和 // 会忽略错误，最终结果据此判断
+func (b *queryBudget) exceeded() bool {
+	return b.left < 0
+}
+
+// QueryResult 查询结果
+type QueryResult struct {
+	Language string
+	// Values 匹配到的值，数字保持为 json.Number
+	Values []interface{}
+	// Paths 每个值的规范化路径（如 $['store']['book'][0]），仅 JSONPath 有
+	Paths []string
+	// Output JSONPath 输出为结果数组，jq 每个结果一行（与 jq 命令行一致）
+	Output string
+}
+
+// Query 对 JSON 执行 JSONPath 或 jq 表达式；language 为空时以 $ 开头的按 JSONPath，否则按 jq
+func Query(input, expr, language string, indent int) (QueryResult, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return QueryResult{}, fmt.Errorf("%w: query is empty", ErrInvalidQuery)
+	}
+	if language == "" {
+		language = QueryJQ
+		if strings.HasPrefix(expr, "$") {
+			language = QueryJSONPath
+		}
+	}
+
+	res := QueryResult{Language: language}
+	switch language {
+	case QueryJSONPath:
+		path, err := compileJSONPath(expr)
+		if err != nil {
+			return res, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		data, err := decodeJSON(input)
+		if err != nil {
+			return res, err
+		}
+		budget := newQueryBudget()
+		nodes := path.eval(data, budget)
+		if budget.exceeded() {
+			return res, fmt.Errorf("%w: %v", ErrInvalidQuery, errQueryTooLarge)
+		}
+		if len(nodes) > maxQueryResults {
+			return res, fmt.Errorf("%w: too many results (max %d)", ErrInvalidQuery, maxQueryResults)
+		}
+		res.Values, res.Paths = make([]interface{}, len(nodes)), make([]string, len(nodes))
+		for i, n := range nodes {
+			res.Values[i], res.Paths[i] = n.value, n.path
+		}
+		res.Output, err = encodeJSON(res.Values, indent)
+		return res, err
+	case QueryJQ:
+		filter, err := compileJQ(expr)
+		if err != nil {
+			return res, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		data, err := decodeJSON(input)
+		if err != nil {
+			return res, err
+		}
+		budget := newQueryBudget()
+		if res.Values, err = filter(data, budget); err != nil {
+			return res, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+		}
+		if budget.exceeded() {
+			return res, fmt.Errorf("%w: %v", ErrInvalidQuery, errQueryTooLarge)
+		}
+		if len(res.Values) > maxQueryResults {
+			return res, fmt.Errorf("%w: too many results (max %d)", ErrInvalidQuery, maxQueryResults)
+		}
+		lines := make([]string, len(res.Values))
+		for i, v := range res.Values {
+			if lines[i], err = encodeJSON(v, indent); err != nil {
+				return res, err
+			}
+		}
+		res.Output = strings.Join(lines, "\n")
+		return res, nil
+	}
+	return res, fmt.Errorf("%w: unsupported query language %q", ErrInvalidQuery, language)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokPunct
+	tokRegex
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// puncts 按长度从长到短匹配
+var puncts = []string{"..", "==", "!=", "<=", ">=", "&&", "||", "=~", "//",
+	".", "[", "]", "(", ")", "{", "}", ",", ":", ";", "|", "?", "*", "$", "@", "!", "<", ">", "-"}
+
+// tokenize JSONPath 与 jq 共用的词法分析
+func tokenize(expr string) ([]token, error) {
+	var toks []token
+	for i := 0; i < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '/' && len(toks) > 0 && toks[len(toks)-1].text == "=~":
+			// =~ 右侧的 /pattern/flags
+			j := i + 1
+			for j < len(expr) && expr[j] != '/' {
+				if expr[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(expr) {
+				return nil, fmt.Errorf("unterminated regular expression at position %d", i)
+			}
+			pattern := expr[i+1 : j]
+			j++
+			for j < len(expr) && expr[j] >= 'a' && expr[j] <= 'z' {
+				if expr[j] == 'i' {
+					pattern = "(?i)" + pattern
+				}
+				j++
+			}
+			toks = append(toks, token{tokRegex, pattern, i})
+			i = j
+		case r == '"' || r == '\'':
+			s, n, err := readString(expr[i:])
+			if err != nil {
+				return nil, fmt.Errorf("%v at position %d", err, i)
+			}
+			toks = append(toks, token{tokString, s, i})
+			i += n
+		case r >= '0' && r <= '9':
+			j := i
+			for j < len(expr) && strings.IndexByte("0123456789.eE", expr[j]) >= 0 {
+				// 1.e 之类的不是数字的一部分；.. 也不是
+				if expr[j] == '.' && (j+1 >= len(expr) || expr[j+1] < '0' || expr[j+1] > '9') {
+					break
+				}
+				if (expr[j] == 'e' || expr[j] == 'E') && j+1 < len(expr) && (expr[j+1] == '+' || expr[j+1] == '-') {
+					j++
+				}
+				j++
+			}
+			text := expr[i:j]
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, fmt.Errorf("invalid number %q at position %d", text, i)
+			}
+			toks = append(toks, token{tokNumber, text, i})
+			i = j
+		case r == '_' || unicode.IsLetter(r):
+			j := i + size
+			for j < len(expr) {
+				r, size := utf8.DecodeRuneInString(expr[j:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				j += size
+			}
+			toks = append(toks, token{tokIdent, expr[i:j], i})
+			i = j
+		default:
+			matched := false
+			for _, p := range puncts {
+				if strings.HasPrefix(expr[i:], p) {
+					toks = append(toks, token{tokPunct, p, i})
+					i += len(p)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i)
+			}
+		}
+	}
+	return append(toks, token{tokEOF, "", len(expr)}), nil
+}
+
+// readString 读取单引号或双引号字符串，返回内容和消耗的字节数
+func readString(s string) (string, int, error) {
+	quote := s[0]
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c != '\\':
+			b.WriteByte(c)
+		case i+1 >= len(s):
+			return "", 0, errors.New("unterminated string")
+		default:
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'u':
+				if i+4 >= len(s) {
+					return "", 0, errors.New("invalid \\u escape")
+				}
+				v, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+				if err != nil {
+					return "", 0, errors.New("invalid \\u escape")
+				}
+				b.WriteRune(rune(v))
+				i += 4
+			default:
+				b.WriteByte(s[i])
+			}
+		}
+	}
+	return "", 0, errors.New("unterminated string")
+}
+
+// tokenStream 递归下降解析器的输入
+type tokenStream struct {
+	toks []token
+	pos  int
+}
+
+func (s *tokenStream) peek() token {
+	return s.toks[s.pos]
+}
+
+func (s *tokenStream) next() token {
+	t := s.toks[s.pos]
+	if t.kind != tokEOF {
+		s.pos++
+	}
+	return t
+}
+
+// is 当前 token 是否为给定的符号或关键字
+func (s *tokenStream) is(text string) bool {
+	t := s.peek()
+	return (t.kind == tokPunct || t.kind == tokIdent) && t.text == text
+}
+
+func (s *tokenStream) accept(text string) bool {
+	if s.is(text) {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+func (s *tokenStream) expect(text string) error {
+	if !s.accept(text) {
+		return s.unexpected("expected " + strconv.Quote(text))
+	}
+	return nil
+}
+
+func (s *tokenStream) unexpected(hint string) error {
+	t := s.peek()
+	if t.kind == tokEOF {
+		return fmt.Errorf("unexpected end of query, %s", hint)
+	}
+	return fmt.Errorf("unexpected %q at position %d, %s", t.text, t.pos, hint)
+}
+
+// 值的排序规则与 jq 一致：null < false < true < 数字 < 字符串 < 数组 < 对象
+func typeRank(v interface{}) int {
+	switch x := v.(type) {
+	case nil:
+		return 0
+	case bool:
+		if x {
+			return 2
+		}
+		return 1
+	case json.Number:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	}
+	return 6
+}
+
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+// bigNumber 用高精度浮点比较 json.Number，避免大整数转 float64 丢精度
+func bigNumber(n json.Number) *big.Float {
+	f, _, err := big.ParseFloat(string(n), 10, 256, big.ToNearestEven)
+	if err != nil {
+		return new(big.Float)
+	}
+	return f
+}
+
+func compareValues(a, b interface{}) int {
+	ra, rb := typeRank(a), typeRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	switch x := a.(type) {
+	case json.Number:
+		return bigNumber(x).Cmp(bigNumber(b.(json.Number)))
+	case string:
+		return strings.Compare(x, b.(string))
+	case []interface{}:
+		y := b.([]interface{})
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if c := compareValues(x[i], y[i]); c != 0 {
+				return c
+			}
+		}
+		return len(x) - len(y)
+	case map[string]interface{}:
+		y := b.(map[string]interface{})
+		kx, ky := sortedKeys(x), sortedKeys(y)
+		for i := 0; i < len(kx) && i < len(ky); i++ {
+			if c := strings.Compare(kx[i], ky[i]); c != 0 {
+				return c
+			}
+		}
+		if len(kx) != len(ky) {
+			return len(kx) - len(ky)
+		}
+		for _, k := range kx {
+			if c := compareValues(x[k], y[k]); c != 0 {
+				return c
+			}
+		}
+	}
+	return 0
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// truthy false 和 null 为假，其余为真
+func truthy(v interface{}) bool {
+	b, ok := v.(bool)
+	return v != nil && (!ok || b)
+}
+
+// arrayIndex 把 json.Number 转成数组下标，负数从末尾计，小数向下取整
+func arrayIndex(n json.Number, length int) (int, bool) {
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+	i := int(f)
+	if float64(i) > f {
+		i--
+	}
+	if i < 0 {
+		i += length
+	}
+	return i, i >= 0 && i < length
+}
+
+// sliceBounds RFC 9535 的切片规则（jq 的 .[a:b] 等同于 step 为 1）
+func sliceBounds(length int, start, end *int, step int) []int {
+	if step == 0 {
+		return nil
+	}
+	norm := func(i int) int {
+		if i < 0 {
+			return i + length
+		}
+		return i
+	}
+	clamp := func(i, lo, hi int) int {
+		if i < lo {
+			return lo
+		}
+		if i > hi {
+			return hi
+		}
+		return i
+	}
+	var out []int
+	if step > 0 {
+		lo, hi := 0, length
+		if start != nil {
+			lo = clamp(norm(*start), 0, length)
+		}
+		if end != nil {
+			hi = clamp(norm(*end), 0, length)
+		}
+		for i := lo; i < hi; i += step {
+			out = append(out, i)
+		}
+		return out
+	}
+	hi, lo := length-1, -1
+	if start != nil {
+		hi = clamp(norm(*start), -1, length-1)
+	}
+	if end != nil {
+		lo = clamp(norm(*end), -1, length-1)
+	}
+	for i := hi; i > lo; i += step {
+		out = append(out, i)
+	}
+	return out
+}
@@ -0,0 +1,60 @@
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	t.Run("缩进输出", func(t *testing.T) {
+		got, err := Query(`{"a":{"b":1}}`, ".a", "", 2)
+		if err != nil || got.Output != "{\n  \"b\": 1\n}" {
+			t.Errorf("Query() = %q, %v", got.Output, err)
+		}
+	})
+
+	t.Run("自动提取 JSON", func(t *testing.T) {
+		got, err := Query(`response: {"a":[1,2]} done`, "$.a[1]", "", 0)
+		if err != nil || got.Output != "[2]" {
+			t.Errorf("Query() = %q, %v", got.Output, err)
+		}
+	})
+
+	t.Run("指定语言", func(t *testing.T) {
+		if _, err := Query(`{"a":1}`, "$.a", QueryJQ, 0); err == nil {
+			t.Error("jq 不应接受 JSONPath 表达式")
+		}
+		if _, err := Query(`{"a":1}`, ".a", "xpath", 0); err == nil {
+			t.Error("未知语言应报错")
+		}
+	})
+
+	t.Run("输入错误", func(t *testing.T) {
+		if _, err := Query(`{"a":`, ".a", "", 0); err == nil || errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("无效 JSON 应报错且不是表达式错误：%v", err)
+		}
+		if _, err := Query(`{"a":1}`, "  ", "", 0); !errors.Is(err, ErrInvalidQuery) {
+			t.Error("空表达式应报错")
+		}
+	})
+
+	t.Run("计算过程中超出上限", func(t *testing.T) {
+		// 400 层嵌套数组：..|..|.. 会产生约一千万个值，应在生成全部结果之前中止
+		nested := strings.Repeat("[", 400) + strings.Repeat("]", 400)
+		for _, q := range []string{"..|..|..", "[..|..|..] | length", "(..|..|..)?", "(..|..|..) // 1", "$..*..*..*"} {
+			_, err := Query(nested, q, "", 0)
+			if !errors.Is(err, ErrInvalidQuery) || !strings.Contains(err.Error(), "too many results") {
+				t.Errorf("Query(%s) error = %v", q, err)
+			}
+		}
+	})
+
+	t.Run("中间结果可以多于最终结果上限", func(t *testing.T) {
+		items := strings.TrimSuffix(strings.Repeat("1,", maxQueryResults*2), ",")
+		got, err := Query("["+items+"]", "[.[]] | length", QueryJQ, 0)
+		if err != nil || got.Output != "20000" {
+			t.Errorf("Query() = %q, %v", got.Output, err)
+		}
+	})
+}
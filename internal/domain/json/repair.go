@@ -0,0 +1,535 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 修复的种类
+const (
+	RepairSingleQuotes  = "single_quotes"
+	RepairUnquotedKey   = "unquoted_key"
+	RepairTrailingComma = "trailing_comma"
+	RepairExtraComma    = "extra_comma"
+	RepairMissingComma  = "missing_comma"
+	RepairMissingColon  = "missing_colon"
+	RepairComment       = "comment"
+	RepairLiteral       = "literal"
+	RepairNonFinite     = "non_finite"
+	RepairNumber        = "number"
+	RepairEscape        = "escape"
+	RepairUnclosed      = "unclosed"
+)
+
+// maxRepairDepth 与 encoding/json 的嵌套上限一致
+const maxRepairDepth = 10000
+
+// Repair 一处修复，Line / Column 为输入中的位置（从 1 开始，列按字符计）
+type Repair struct {
+	Kind    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// RepairResult 修复结果
+type RepairResult struct {
+	// JSON 修复并按 indent 格式化后的 JSON
+	JSON    string
+	Repairs []Repair
+}
+
+// literalRepairs 其他语言的字面量及对应的 JSON 值
+var literalRepairs = map[string]string{
+	"True":      "true",
+	"False":     "false",
+	"None":      "null",
+	"undefined": "null",
+}
+
+// RepairJSON 宽松地解析接近 JSON 的输入（JSON5、JavaScript 对象字面量、Python repr 等），
+// 输出修复后的 JSON 并列出每处修复：单引号字符串、未加引号的键、多余或缺少的逗号、注释、
+// True / False / None、NaN / Infinity（改为 null）、十六进制和 +1、.5 之类的数字、无效的转义和字符串中的控制字符、
+// 末尾未闭合的字符串和括号。提取规则与 FormatJSON 相同，数字精度也与其一致
+func RepairJSON(input string, indent int) (RepairResult, error) {
+	if strings.TrimSpace(input) == "" {
+		return RepairResult{}, errors.New("input is empty")
+	}
+
+	p := &repairer{src: input, starts: lineStarts(input)}
+	// 与 extractJSON 一致，从第一个 { 或 [ 开始；没有时按标量解析
+	if i := strings.IndexAny(input, "{["); i >= 0 {
+		p.pos = i
+	}
+	if err := p.value(0); err != nil {
+		return RepairResult{}, err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(p.out.String()))
+	dec.UseNumber()
+	var data interface{}
+	if err := dec.Decode(&data); err != nil {
+		return RepairResult{}, errors.New("invalid JSON: " + err.Error())
+	}
+	out, err := encodeJSON(data, indent)
+	if err != nil {
+		return RepairResult{}, err
+	}
+	// 闭合括号等修复是在回溯时记录的，按位置排序
+	sort.SliceStable(p.repairs, func(i, j int) bool {
+		a, b := p.repairs[i], p.repairs[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+	return RepairResult{JSON: out, Repairs: p.repairs}, nil
+}
+
+type repairer struct {
+	src     string
+	pos     int
+	starts  []int
+	out     strings.Builder
+	repairs []Repair
+}
+
+func (p *repairer) record(kind string, offset int, format string, args ...interface{}) {
+	line, col := lineColumn(p.src, p.starts, offset)
+	p.repairs = append(p.repairs, Repair{Kind: kind, Line: line, Column: col, Message: fmt.Sprintf(format, args...)})
+}
+
+func (p *repairer) errorf(offset int, format string, args ...interface{}) error {
+	line, col := lineColumn(p.src, p.starts, offset)
+	return fmt.Errorf("invalid JSON: line %d, column %d: %s", line, col, fmt.Sprintf(format, args...))
+}
+
+// unexpected 当前位置的字符不能出现在这里
+func (p *repairer) unexpected() error {
+	if p.pos >= len(p.src) {
+		return p.errorf(p.pos, "unexpected end of input")
+	}
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return p.errorf(p.pos, "unexpected %q", r)
+}
+
+func (p *repairer) peek() byte {
+	if p.pos < len(p.src) {
+		return p.src[p.pos]
+	}
+	return 0
+}
+
+func (p *repairer) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+// skipSpace 跳过空白和注释，注释记为修复
+func (p *repairer) skipSpace() {
+	for !p.eof() {
+		switch c := p.src[p.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			start := p.pos
+			if i := strings.IndexByte(p.src[p.pos:], '\n'); i >= 0 {
+				p.pos += i
+			} else {
+				p.pos = len(p.src)
+			}
+			p.record(RepairComment, start, "removed line comment")
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			start := p.pos
+			if i := strings.Index(p.src[p.pos+2:], "*/"); i >= 0 {
+				p.pos += i + 4
+			} else {
+				p.pos = len(p.src)
+			}
+			p.record(RepairComment, start, "removed block comment")
+		default:
+			return
+		}
+	}
+}
+
+func (p *repairer) value(depth int) error {
+	if depth > maxRepairDepth {
+		return p.errorf(p.pos, "exceeded max depth")
+	}
+	p.skipSpace()
+	c := p.peek()
+	switch {
+	case p.eof():
+		return p.unexpected()
+	case c == '{':
+		return p.object(depth)
+	case c == '[':
+		return p.array(depth)
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9'):
+		return p.number()
+	case isIdentStart(c):
+		return p.literal()
+	}
+	return p.unexpected()
+}
+
+// object 与 array 共用逗号的处理：缺少的补上，多余的去掉，遇到输入结尾补上右括号
+func (p *repairer) object(depth int) error {
+	return p.container(depth, '{', '}', func() error {
+		if err := p.key(); err != nil {
+			return err
+		}
+		p.skipSpace()
+		switch {
+		case p.peek() == ':':
+			p.pos++
+		case p.eof():
+		case isValueStart(p.peek()):
+			p.record(RepairMissingColon, p.pos, "inserted missing colon")
+		default:
+			return p.unexpected()
+		}
+		p.out.WriteByte(':')
+		p.skipSpace()
+		if p.eof() {
+			// 输入在键之后截断
+			p.record(RepairUnclosed, p.pos, "inserted null for missing value")
+			p.out.WriteString("null")
+			return nil
+		}
+		return p.value(depth + 1)
+	})
+}
+
+func (p *repairer) array(depth int) error {
+	return p.container(depth, '[', ']', func() error {
+		return p.value(depth + 1)
+	})
+}
+
+func (p *repairer) container(depth int, open, close byte, element func() error) error {
+	start := p.pos
+	p.pos++
+	p.out.WriteByte(open)
+	first := true
+	for {
+		p.skipSpace()
+		if p.eof() {
+			p.record(RepairUnclosed, start, "closed %c opened here", open)
+			p.out.WriteByte(close)
+			return nil
+		}
+
+		// 逗号：元素之间的保留一个，开头、连续和结尾的去掉
+		comma := -1
+		for p.peek() == ',' {
+			if comma >= 0 || first {
+				p.record(RepairExtraComma, p.pos, "removed extra comma")
+			} else {
+				comma = p.pos
+			}
+			p.pos++
+			p.skipSpace()
+		}
+		c := p.peek()
+		if c == close || p.eof() {
+			if comma >= 0 {
+				p.record(RepairTrailingComma, comma, "removed trailing comma")
+			}
+			if p.eof() {
+				continue
+			}
+			p.pos++
+			p.out.WriteByte(close)
+			return nil
+		}
+		if c == '}' || c == ']' {
+			return p.unexpected()
+		}
+
+		if !first {
+			if comma < 0 {
+				p.record(RepairMissingComma, p.pos, "inserted missing comma")
+			}
+			p.out.WriteByte(',')
+		}
+		if err := element(); err != nil {
+			return err
+		}
+		first = false
+	}
+}
+
+// key 对象的键：可以是单引号字符串或不加引号的标识符（包括数字开头的 1st 之类）
+func (p *repairer) key() error {
+	c := p.peek()
+	if c == '"' || c == '\'' {
+		return p.str()
+	}
+	start := p.pos
+	for !p.eof() {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		if r != '_' && r != '$' && r != '-' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return p.unexpected()
+	}
+	p.record(RepairUnquotedKey, start, "quoted key %s", p.src[start:p.pos])
+	p.writeString(p.src[start:p.pos])
+	return nil
+}
+
+func (p *repairer) writeString(s string) {
+	b, _ := json.Marshal(s)
+	p.out.Write(b)
+}
+
+// str 字符串：单引号改为双引号，修正无效的转义，转义控制字符，输入结尾时补上引号
+func (p *repairer) str() error {
+	start := p.pos
+	quote := p.src[p.pos]
+	if quote == '\'' {
+		p.record(RepairSingleQuotes, start, "replaced single quotes with double quotes")
+	}
+	p.pos++
+	p.out.WriteByte('"')
+	control := false
+	for {
+		if p.eof() {
+			p.record(RepairUnclosed, start, "closed string opened here")
+			p.out.WriteByte('"')
+			return nil
+		}
+		c := p.src[p.pos]
+		switch {
+		case c == quote:
+			p.pos++
+			p.out.WriteByte('"')
+			return nil
+		case c == '"':
+			// 单引号字符串中的双引号
+			p.out.WriteString(`\"`)
+			p.pos++
+		case c == '\\':
+			p.escape(quote)
+		case c < 0x20:
+			if !control {
+				control = true
+				p.record(RepairEscape, p.pos, "escaped control characters in string")
+			}
+			switch c {
+			case '\n':
+				p.out.WriteString(`\n`)
+			case '\r':
+				p.out.WriteString(`\r`)
+			case '\t':
+				p.out.WriteString(`\t`)
+			default:
+				fmt.Fprintf(&p.out, `\u%04x`, c)
+			}
+			p.pos++
+		default:
+			p.out.WriteByte(c)
+			p.pos++
+		}
+	}
+}
+
+// escape 处理反斜杠开头的转义；JSON 不支持的 \' \x41 和行尾续行按 JSON5 的含义转换，其他无效转义保留反斜杠本身
+func (p *repairer) escape(quote byte) {
+	start := p.pos
+	p.pos++
+	if p.eof() {
+		return
+	}
+	c := p.src[p.pos]
+	switch {
+	case strings.IndexByte(`"\/bfnrt`, c) >= 0:
+		p.out.WriteByte('\\')
+		p.out.WriteByte(c)
+		p.pos++
+	case c == 'u' && isHex(p.src[p.pos+1:], 4):
+		p.out.WriteString(p.src[start : p.pos+5])
+		p.pos += 5
+	case c == '\'':
+		if quote != '\'' {
+			p.record(RepairEscape, start, `replaced \' with '`)
+		}
+		p.out.WriteByte('\'')
+		p.pos++
+	case c == 'x' && isHex(p.src[p.pos+1:], 2):
+		p.record(RepairEscape, start, `replaced \x%s with \u00%s`, p.src[p.pos+1:p.pos+3], p.src[p.pos+1:p.pos+3])
+		p.out.WriteString(`\u00` + p.src[p.pos+1:p.pos+3])
+		p.pos += 3
+	case c == '\n' || c == '\r':
+		p.record(RepairEscape, start, "removed line continuation")
+		p.pos++
+		if c == '\r' && p.peek() == '\n' {
+			p.pos++
+		}
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.record(RepairEscape, start, `escaped backslash in invalid escape \%c`, r)
+		p.out.WriteString(`\\`)
+	}
+}
+
+// number 数字：去掉 + 号和多余的前导 0，补全 .5 和 5.，十六进制转为十进制，NaN / Infinity 改为 null
+func (p *repairer) number() error {
+	start := p.pos
+	var b strings.Builder
+	changed := false
+
+	if c := p.peek(); c == '+' || c == '-' {
+		if c == '-' {
+			b.WriteByte('-')
+		} else {
+			changed = true
+		}
+		p.pos++
+	}
+	if isIdentStart(p.peek()) {
+		word := p.ident()
+		if word != "Infinity" && word != "NaN" {
+			p.pos = start
+			return p.unexpected()
+		}
+		p.record(RepairNonFinite, start, "replaced %s with null", p.src[start:p.pos])
+		p.out.WriteString("null")
+		return nil
+	}
+
+	if strings.HasPrefix(p.src[p.pos:], "0x") || strings.HasPrefix(p.src[p.pos:], "0X") {
+		p.pos += 2
+		digits := p.digits(isHexDigit)
+		n, ok := new(big.Int).SetString(digits, 16)
+		if !ok {
+			return p.unexpected()
+		}
+		b.WriteString(n.String())
+		p.record(RepairNumber, start, "converted %s to %s", p.src[start:p.pos], b.String())
+		p.out.WriteString(b.String())
+		return nil
+	}
+
+	intPart := p.digits(isDigit)
+	if trimmed := strings.TrimLeft(intPart, "0"); len(intPart) > 1 && len(trimmed) < len(intPart) {
+		changed = true
+		if trimmed == "" {
+			trimmed = "0"
+		}
+		intPart = trimmed
+	}
+	if intPart == "" {
+		if p.peek() != '.' {
+			return p.unexpected()
+		}
+		changed = true
+		intPart = "0"
+	}
+	b.WriteString(intPart)
+
+	if p.peek() == '.' {
+		p.pos++
+		frac := p.digits(isDigit)
+		if frac == "" {
+			changed = true
+			frac = "0"
+		}
+		b.WriteString("." + frac)
+	}
+	if c := p.peek(); c == 'e' || c == 'E' {
+		p.pos++
+		b.WriteByte(c)
+		if c := p.peek(); c == '+' || c == '-' {
+			b.WriteByte(c)
+			p.pos++
+		}
+		exp := p.digits(isDigit)
+		if exp == "" {
+			return p.unexpected()
+		}
+		b.WriteString(exp)
+	}
+
+	if changed {
+		p.record(RepairNumber, start, "converted %s to %s", p.src[start:p.pos], b.String())
+	}
+	p.out.WriteString(b.String())
+	return nil
+}
+
+func (p *repairer) digits(valid func(byte) bool) string {
+	start := p.pos
+	for !p.eof() && valid(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *repairer) ident() string {
+	start := p.pos
+	for !p.eof() && (isIdentStart(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// literal true / false / null 原样输出，其他语言的字面量转换，NaN / Infinity 改为 null
+func (p *repairer) literal() error {
+	start := p.pos
+	word := p.ident()
+	switch word {
+	case "true", "false", "null":
+		p.out.WriteString(word)
+		return nil
+	case "NaN", "Infinity":
+		p.record(RepairNonFinite, start, "replaced %s with null", word)
+		p.out.WriteString("null")
+		return nil
+	}
+	if v, ok := literalRepairs[word]; ok {
+		p.record(RepairLiteral, start, "replaced %s with %s", word, v)
+		p.out.WriteString(v)
+		return nil
+	}
+	p.pos = start
+	return p.unexpected()
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isValueStart(c byte) bool {
+	return strings.IndexByte(`{["'-+.`, c) >= 0 || isDigit(c) || isIdentStart(c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+// isHex s 以 n 个十六进制数字开头
+func isHex(s string, n int) bool {
+	if len(s) < n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if !isHexDigit(s[i]) {
+			return false
+		}
+	}
+	return true
+}
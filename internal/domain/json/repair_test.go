@@ -0,0 +1,88 @@
+package json
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// repairsOf 每处修复格式化为 "行:列 种类"，便于比较
+func repairsOf(rs []Repair) string {
+	out := make([]string, len(rs))
+	for i, r := range rs {
+		out[i] = strconv.Itoa(r.Line) + ":" + strconv.Itoa(r.Column) + " " + r.Kind
+	}
+	return strings.Join(out, ",")
+}
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		repairs string
+	}{
+		{"合法 JSON 不修改", `resp: {"a": [1, 2.50], "b": 12345678901234567890}`, `{"a":[1,2.50],"b":12345678901234567890}`, ""},
+		{"单引号与未加引号的键", `{'a': 'it\'s "x"', b_1: 1, 名字: 2}`, `{"a":"it's \"x\"","b_1":1,"名字":2}`,
+			"1:2 single_quotes,1:7 single_quotes,1:20 unquoted_key,1:28 unquoted_key"},
+		{"逗号", "[,1,,2 3,]", `[1,2,3]`,
+			"1:2 extra_comma,1:5 extra_comma,1:8 missing_comma,1:9 trailing_comma"},
+		{"注释", "{\n  // note\n  \"a\": 1, /* x */ \"b\": 2\n}", `{"a":1,"b":2}`,
+			"2:3 comment,3:11 comment"},
+		{"Python 字面量与非有限数", `{"t": True, "f": False, "n": None, "u": undefined, "x": NaN, "y": -Infinity}`,
+			`{"f":false,"n":null,"t":true,"u":null,"x":null,"y":null}`,
+			"1:7 literal,1:18 literal,1:30 literal,1:41 literal,1:57 non_finite,1:67 non_finite"},
+		{"数字写法", `[0x1F, +1, .5, 5., 007, 1e3]`, `[31,1,0.5,5.0,7,1e3]`,
+			"1:2 number,1:8 number,1:12 number,1:16 number,1:20 number"},
+		{"转义与控制字符", "[\"a\tb\nc\", \"\\d\", \"\\x41\", \"\\'\", \"\\u00e9\"]", `["a\tb\nc","\\d","A","'","é"]`,
+			"1:4 escape,2:6 escape,2:12 escape,2:20 escape"},
+		{"截断的输入", `{"a": {"b": [1, "x`, `{"a":{"b":[1,"x"]}}`,
+			"1:1 unclosed,1:7 unclosed,1:13 unclosed,1:17 unclosed"},
+		{"截断在键之后", `{"a": 1, "b":`, `{"a":1,"b":null}`, "1:1 unclosed,1:14 unclosed"},
+		{"缺少冒号", `{"a" 1}`, `{"a":1}`, "1:6 missing_colon"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := RepairJSON(tt.input, 0)
+			if err != nil {
+				t.Fatalf("RepairJSON() error = %v", err)
+			}
+			if res.JSON != tt.want {
+				t.Errorf("JSON = %s, want %s", res.JSON, tt.want)
+			}
+			if got := repairsOf(res.Repairs); got != tt.repairs {
+				t.Errorf("repairs = %q, want %q", got, tt.repairs)
+			}
+		})
+	}
+}
+
+func TestRepairJSONIndent(t *testing.T) {
+	res, err := RepairJSON("{a: [1,]}", 2)
+	if err != nil {
+		t.Fatalf("RepairJSON() error = %v", err)
+	}
+	if want := "{\n  \"a\": [\n    1\n  ]\n}"; res.JSON != want {
+		t.Errorf("JSON =\n%s\nwant\n%s", res.JSON, want)
+	}
+	if len(res.Repairs) != 2 || res.Repairs[0].Message != "quoted key a" {
+		t.Errorf("repairs = %+v", res.Repairs)
+	}
+}
+
+func TestRepairJSONErrors(t *testing.T) {
+	tests := map[string]string{
+		"":           "input is empty",
+		`{"a": foo}`: "line 1, column 7: unexpected 'f'",
+		"[1,\n  }":   "line 2, column 3: unexpected '}'",
+		`{"a": 1e}`:  "unexpected '}'",
+		`{"a": -x}`:  "column 7: unexpected '-'",
+		`{: 1}`:      "unexpected ':'",
+	}
+	for input, want := range tests {
+		_, err := RepairJSON(input, 0)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("RepairJSON(%q) error = %v, want %q", input, err, want)
+		}
+	}
+}
@@ -0,0 +1,884 @@
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// JSON Schema 版本
+const (
+	SchemaDraft7    = "draft-07"
+	SchemaDraft2020 = "2020-12"
+)
+
+// ErrInvalidSchema schema 本身有问题：不是合法 JSON、关键字类型不对、正则无法编译、$ref 无法解析等；
+// 关键字在作用于实例时才检查，不适用于当前实例类型的关键字不会报错
+var ErrInvalidSchema = errors.New("invalid schema")
+
+// maxViolations 返回的违规条数上限
+const maxViolations = 1000
+
+// Violation 一处违规
+type Violation struct {
+	// Path 实例中的位置（JSON Pointer），根为空字符串
+	Path string
+	// SchemaPath 触发违规的关键字在 schema 中的位置，经过 $ref 时记为 /$ref
+	SchemaPath string
+	Keyword    string
+	Message    string
+	// Line、Column 实例值在原始输入中的行列，从 1 开始，列按字符计
+	Line   int
+	Column int
+}
+
+// ValidateResult 校验结果
+type ValidateResult struct {
+	Draft      string
+	Valid      bool
+	Violations []Violation
+	// Truncated 违规超过 maxViolations 条时只返回前面的部分
+	Truncated bool
+}
+
+// Validate 按 JSON Schema 校验文档；draft 为空时按 $schema 判断，默认 2020-12。
+// 文档和 schema 的提取规则与格式化相同，数字按原文精确比较
+func Validate(document, schema, draft string) (ValidateResult, error) {
+	s, err := decodeJSON(schema)
+	if err != nil {
+		return ValidateResult{}, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+	doc, text, offset, err := decodeJSONText(document)
+	if err != nil {
+		return ValidateResult{}, err
+	}
+
+	switch draft {
+	case "":
+		draft = detectDraft(s)
+	case SchemaDraft7, SchemaDraft2020:
+	default:
+		return ValidateResult{}, fmt.Errorf("%w: unsupported draft %q (use %s or %s)", ErrInvalidSchema, draft, SchemaDraft7, SchemaDraft2020)
+	}
+
+	v := &validator{root: s, draft: draft, anchors: map[string]interface{}{}, ids: map[string]interface{}{}, regexps: map[string]*regexp.Regexp{}, active: map[refKey]string{}}
+	if m, ok := s.(map[string]interface{}); ok {
+		if id, ok := m["$id"].(string); ok {
+			v.base, _ = url.Parse(strings.SplitN(id, "#", 2)[0])
+		}
+	}
+	v.collect(s)
+	violations := v.validate(s, doc, "", "")
+	if v.err != nil {
+		return ValidateResult{}, v.err
+	}
+
+	res := ValidateResult{Draft: draft, Valid: len(violations) == 0}
+	if len(violations) > maxViolations {
+		violations, res.Truncated = violations[:maxViolations], true
+	}
+	if len(violations) > 0 {
+		positions := jsonPositions(text)
+		lines := lineStarts(document)
+		for i := range violations {
+			if off, ok := positions[violations[i].Path]; ok {
+				violations[i].Line, violations[i].Column = lineColumn(document, lines, offset+off)
+			}
+		}
+	}
+	res.Violations = violations
+	return res, nil
+}
+
+// detectDraft draft-07 及更早的版本按 Draft 7 规则，其余按 2020-12
+func detectDraft(schema interface{}) string {
+	if m, ok := schema.(map[string]interface{}); ok {
+		if s, ok := m["$schema"].(string); ok {
+			for _, old := range []string{"draft-03", "draft-04", "draft-06", "draft-07"} {
+				if strings.Contains(s, old) {
+					return SchemaDraft7
+				}
+			}
+		}
+	}
+	return SchemaDraft2020
+}
+
+type validator struct {
+	root  interface{}
+	draft string
+	// base 根 schema 的 $id，用于解析相对引用
+	base *url.URL
+	// anchors $anchor 以及 Draft 7 中 "$id": "#name" 形式的锚点
+	anchors map[string]interface{}
+	// ids 文档内子 schema 的 $id（已按 base 解析为绝对地址）
+	ids     map[string]interface{}
+	regexps map[string]*regexp.Regexp
+	// active 正在经 $ref 求值的 (schema, 实例位置)，同一对再次进入说明存在不消耗实例的循环引用
+	active map[refKey]string
+	err    error
+}
+
+// refKey $ref 目标 schema 对象与实例位置
+type refKey struct {
+	node uintptr
+	ptr  string
+}
+
+// fail 记录第一个 schema 错误，之后的校验结果不再有意义
+func (v *validator) fail(format string, args ...interface{}) {
+	if v.err == nil {
+		v.err = fmt.Errorf("%w: %s", ErrInvalidSchema, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v *validator) resolveID(id string) string {
+	if v.base == nil {
+		return id
+	}
+	u, err := url.Parse(id)
+	if err != nil {
+		return id
+	}
+	return v.base.ResolveReference(u).String()
+}
+
+// collect 收集 schema 中的锚点和 $id；enum、const 等取值不是 schema，跳过
+func (v *validator) collect(s interface{}) {
+	switch x := s.(type) {
+	case map[string]interface{}:
+		if id, ok := x["$id"].(string); ok {
+			if strings.HasPrefix(id, "#") {
+				v.anchors[id[1:]] = x
+			} else if id != "" {
+				v.ids[v.resolveID(strings.SplitN(id, "#", 2)[0])] = x
+			}
+		}
+		for _, kw := range []string{"$anchor", "$dynamicAnchor"} {
+			if a, ok := x[kw].(string); ok {
+				v.anchors[a] = x
+			}
+		}
+		for k, val := range x {
+			switch k {
+			case "enum", "const", "default", "examples":
+				continue
+			}
+			v.collect(val)
+		}
+	case []interface{}:
+		for _, val := range x {
+			v.collect(val)
+		}
+	}
+}
+
+// resolve 解析文档内的引用：#、#/JSON/Pointer、#anchor 以及指向文档内 $id 的地址
+func (v *validator) resolve(ref string) (interface{}, error) {
+	base, frag := ref, ""
+	if i := strings.IndexByte(ref, '#'); i >= 0 {
+		base, frag = ref[:i], ref[i+1:]
+	}
+	target := v.root
+	if base != "" {
+		t, ok := v.ids[v.resolveID(base)]
+		if !ok {
+			return nil, fmt.Errorf("$ref %q points outside the schema document, remote references are not supported", ref)
+		}
+		target = t
+	}
+	if frag == "" {
+		return target, nil
+	}
+	if !strings.HasPrefix(frag, "/") {
+		t, ok := v.anchors[frag]
+		if !ok {
+			return nil, fmt.Errorf("$ref %q: anchor not found", ref)
+		}
+		return t, nil
+	}
+
+	frag, err := url.PathUnescape(frag)
+	if err != nil {
+		return nil, fmt.Errorf("$ref %q: %v", ref, err)
+	}
+	for _, seg := range strings.Split(frag[1:], "/") {
+		seg = strings.NewReplacer("~1", "/", "~0", "~").Replace(seg)
+		switch x := target.(type) {
+		case map[string]interface{}:
+			t, ok := x[seg]
+			if !ok {
+				return nil, fmt.Errorf("$ref %q: %q not found", ref, seg)
+			}
+			target = t
+		case []interface{}:
+			i, err := strconv.Atoi(seg)
+			if err != nil || i < 0 || i >= len(x) {
+				return nil, fmt.Errorf("$ref %q: index %q out of range", ref, seg)
+			}
+			target = x[i]
+		default:
+			return nil, fmt.Errorf("$ref %q: %q not found", ref, seg)
+		}
+	}
+	return target, nil
+}
+
+func (v *validator) compile(pattern, spath string) *regexp.Regexp {
+	if re, ok := v.regexps[pattern]; ok {
+		return re
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		v.fail("%s: %v", spath, err)
+		return nil
+	}
+	v.regexps[pattern] = re
+	return re
+}
+
+// validate 用 schema 校验 inst；ptr 为实例位置，spath 为 schema 位置
+func (v *validator) validate(schema, inst interface{}, ptr, spath string) []Violation {
+	if v.err != nil {
+		return nil
+	}
+	switch s := schema.(type) {
+	case bool:
+		if s {
+			return nil
+		}
+		return []Violation{{Path: ptr, SchemaPath: spath, Keyword: "false", Message: "no value is allowed here"}}
+	case map[string]interface{}:
+		return v.validateSchema(s, inst, ptr, spath)
+	}
+	v.fail("%s: schema must be an object or a boolean", rootPath(spath))
+	return nil
+}
+
+// valid 只关心是否通过；ptr、spath 用于识别循环引用和报告 schema 错误的位置
+func (v *validator) valid(schema, inst interface{}, ptr, spath string) bool {
+	return len(v.validate(schema, inst, ptr, spath)) == 0
+}
+
+// report 收集一个 schema 对象产生的违规
+type report struct {
+	ptr, spath string
+	out        []Violation
+}
+
+// add 记录当前实例位置上由 keyword 产生的违规
+func (r *report) add(keyword, format string, args ...interface{}) {
+	r.out = append(r.out, Violation{Path: r.ptr, SchemaPath: r.spath + "/" + keyword, Keyword: keyword, Message: fmt.Sprintf(format, args...)})
+}
+
+func (r *report) merge(vs []Violation) {
+	r.out = append(r.out, vs...)
+}
+
+func (v *validator) validateSchema(s map[string]interface{}, inst interface{}, ptr, spath string) []Violation {
+	r := &report{ptr: ptr, spath: spath}
+
+	for _, kw := range []string{"$ref", "$dynamicRef"} {
+		ref, ok := s[kw]
+		if !ok {
+			continue
+		}
+		target, ok := ref.(string)
+		if !ok {
+			v.fail("%s/%s must be a string", spath, kw)
+			return nil
+		}
+		resolved, err := v.resolve(target)
+		if err != nil {
+			v.fail("%s/%s: %v", spath, kw, err)
+			return nil
+		}
+		node, ok := resolved.(map[string]interface{})
+		if !ok {
+			r.merge(v.validate(resolved, inst, ptr, spath+"/"+kw))
+			continue
+		}
+		key := refKey{node: reflect.ValueOf(node).Pointer(), ptr: ptr}
+		if first, ok := v.active[key]; ok {
+			v.fail("%s/%s: $ref %q re-enters %s at instance %q without consuming it", spath, kw, target, rootPath(first), ptr)
+			return nil
+		}
+		v.active[key] = spath + "/" + kw
+		r.merge(v.validate(resolved, inst, ptr, spath+"/"+kw))
+		delete(v.active, key)
+	}
+	// Draft 7 中 $ref 旁边的关键字会被忽略
+	if _, ok := s["$ref"]; ok && v.draft == SchemaDraft7 {
+		return r.out
+	}
+
+	if t, ok := s["type"]; ok {
+		var types []string
+		if name, ok := t.(string); ok {
+			types = []string{name}
+		} else if _, ok := t.([]interface{}); ok {
+			types = v.stringList(t, spath+"/type")
+		} else {
+			v.fail("%s/type must be a string or an array of strings", spath)
+		}
+		matched := false
+		for _, name := range types {
+			switch name {
+			case "null", "boolean", "object", "array", "number", "integer", "string":
+			default:
+				v.fail("%s/type: unknown type %q", spath, name)
+			}
+			if typeMatches(name, inst) {
+				matched = true
+			}
+		}
+		if !matched {
+			r.add("type", "expected %s, got %s", strings.Join(types, " or "), typeOf(inst))
+		}
+	}
+	if e, ok := s["enum"]; ok {
+		values, ok := e.([]interface{})
+		if !ok {
+			v.fail("%s/enum must be an array", spath)
+		}
+		found := false
+		for _, val := range values {
+			if compareValues(val, inst) == 0 {
+				found = true
+				break
+			}
+		}
+		if !found {
+			r.add("enum", "value must be one of %s", compactValue(values))
+		}
+	}
+	if c, ok := s["const"]; ok && compareValues(c, inst) != 0 {
+		r.add("const", "value must be %s", compactValue(c))
+	}
+
+	v.validateApplicators(s, inst, r)
+
+	switch x := inst.(type) {
+	case map[string]interface{}:
+		v.validateObject(s, x, r)
+	case []interface{}:
+		v.validateArray(s, x, r)
+	case string:
+		v.validateString(s, x, r)
+	case json.Number:
+		v.validateNumber(s, x, r)
+	}
+	return r.out
+}
+
+func (v *validator) validateApplicators(s map[string]interface{}, inst interface{}, r *report) {
+	ptr, spath := r.ptr, r.spath
+	if subs, ok := s["allOf"]; ok {
+		for i, sub := range v.schemas(subs, spath+"/allOf") {
+			r.merge(v.validate(sub, inst, ptr, spath+"/allOf/"+strconv.Itoa(i)))
+		}
+	}
+	if subs, ok := s["anyOf"]; ok {
+		matched := false
+		for i, sub := range v.schemas(subs, spath+"/anyOf") {
+			if v.valid(sub, inst, ptr, spath+"/anyOf/"+strconv.Itoa(i)) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			r.add("anyOf", "value does not match any schema in anyOf")
+		}
+	}
+	if subs, ok := s["oneOf"]; ok {
+		var matched []string
+		for i, sub := range v.schemas(subs, spath+"/oneOf") {
+			if v.valid(sub, inst, ptr, spath+"/oneOf/"+strconv.Itoa(i)) {
+				matched = append(matched, strconv.Itoa(i))
+			}
+		}
+		switch len(matched) {
+		case 0:
+			r.add("oneOf", "value does not match any schema in oneOf")
+		case 1:
+		default:
+			r.add("oneOf", "value matches %d schemas in oneOf (indexes %s), exactly one expected", len(matched), strings.Join(matched, ", "))
+		}
+	}
+	if sub, ok := s["not"]; ok && v.valid(sub, inst, ptr, spath+"/not") {
+		r.add("not", "value must not match the schema in not")
+	}
+	if cond, ok := s["if"]; ok {
+		if v.valid(cond, inst, ptr, spath+"/if") {
+			if then, ok := s["then"]; ok {
+				r.merge(v.validate(then, inst, ptr, spath+"/then"))
+			}
+		} else if els, ok := s["else"]; ok {
+			r.merge(v.validate(els, inst, ptr, spath+"/else"))
+		}
+	}
+}
+
+func (v *validator) validateObject(s map[string]interface{}, obj map[string]interface{}, r *report) {
+	ptr, spath := r.ptr, r.spath
+	keys := sortedKeys(obj)
+
+	for _, name := range v.stringList(s["required"], spath+"/required") {
+		if _, ok := obj[name]; !ok {
+			r.add("required", "missing required property %q", name)
+		}
+	}
+	if n, ok := v.count(s, "minProperties", spath); ok && len(obj) < n {
+		r.add("minProperties", "object has %d properties, at least %d required", len(obj), n)
+	}
+	if n, ok := v.count(s, "maxProperties", spath); ok && len(obj) > n {
+		r.add("maxProperties", "object has %d properties, at most %d allowed", len(obj), n)
+	}
+
+	evaluated := map[string]bool{}
+	props := v.object(s["properties"], spath+"/properties")
+	for _, k := range sortedKeys(props) {
+		if val, ok := obj[k]; ok {
+			evaluated[k] = true
+			seg := "/" + escapePointer(k)
+			r.merge(v.validate(props[k], val, ptr+seg, spath+"/properties"+seg))
+		}
+	}
+	patterns := v.object(s["patternProperties"], spath+"/patternProperties")
+	for _, p := range sortedKeys(patterns) {
+		re := v.compile(p, spath+"/patternProperties")
+		if re == nil {
+			return
+		}
+		for _, k := range keys {
+			if re.MatchString(k) {
+				evaluated[k] = true
+				r.merge(v.validate(patterns[p], obj[k], ptr+"/"+escapePointer(k), spath+"/patternProperties/"+escapePointer(p)))
+			}
+		}
+	}
+	if extra, ok := s["additionalProperties"]; ok {
+		for _, k := range keys {
+			if evaluated[k] {
+				continue
+			}
+			if extra == false {
+				r.out = append(r.out, Violation{Path: ptr + "/" + escapePointer(k), SchemaPath: spath + "/additionalProperties", Keyword: "additionalProperties", Message: fmt.Sprintf("additional property %q is not allowed", k)})
+				continue
+			}
+			r.merge(v.validate(extra, obj[k], ptr+"/"+escapePointer(k), spath+"/additionalProperties"))
+		}
+	}
+	if names, ok := s["propertyNames"]; ok {
+		for _, k := range keys {
+			if sub := v.validate(names, k, ptr+"/"+escapePointer(k), spath+"/propertyNames"); len(sub) > 0 {
+				r.out = append(r.out, Violation{Path: ptr + "/" + escapePointer(k), SchemaPath: sub[0].SchemaPath, Keyword: "propertyNames", Message: fmt.Sprintf("property name %q is invalid: %s", k, sub[0].Message)})
+			}
+		}
+	}
+
+	// Draft 7 的 dependencies 在 2019-09 之后拆成 dependentRequired 和 dependentSchemas，几种写法都接受
+	for _, kw := range []string{"dependencies", "dependentRequired", "dependentSchemas"} {
+		deps := v.object(s[kw], spath+"/"+kw)
+		for _, k := range sortedKeys(deps) {
+			if _, ok := obj[k]; !ok {
+				continue
+			}
+			kwPath := spath + "/" + kw + "/" + escapePointer(k)
+			if list, ok := deps[k].([]interface{}); ok && kw != "dependentSchemas" {
+				for _, name := range v.stringList(list, kwPath) {
+					if _, ok := obj[name]; !ok {
+						r.out = append(r.out, Violation{Path: ptr, SchemaPath: kwPath, Keyword: kw, Message: fmt.Sprintf("property %q requires property %q", k, name)})
+					}
+				}
+				continue
+			}
+			if kw == "dependentRequired" {
+				v.fail("%s must be an array of strings", kwPath)
+				continue
+			}
+			r.merge(v.validate(deps[k], obj, ptr, kwPath))
+		}
+	}
+}
+
+func (v *validator) validateArray(s map[string]interface{}, arr []interface{}, r *report) {
+	ptr, spath := r.ptr, r.spath
+	item := func(schema interface{}, i int, keyword, kwPath string) {
+		if schema == false {
+			r.out = append(r.out, Violation{Path: ptr + "/" + strconv.Itoa(i), SchemaPath: kwPath, Keyword: keyword, Message: fmt.Sprintf("item is not allowed, array may have at most %d items", i)})
+			return
+		}
+		r.merge(v.validate(schema, arr[i], ptr+"/"+strconv.Itoa(i), kwPath))
+	}
+
+	if n, ok := v.count(s, "minItems", spath); ok && len(arr) < n {
+		r.add("minItems", "array has %d items, at least %d required", len(arr), n)
+	}
+	if n, ok := v.count(s, "maxItems", spath); ok && len(arr) > n {
+		r.add("maxItems", "array has %d items, at most %d allowed", len(arr), n)
+	}
+	if unique, ok := s["uniqueItems"].(bool); ok && unique {
+	dup:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if compareValues(arr[i], arr[j]) == 0 {
+					r.add("uniqueItems", "items at index %d and %d are equal", i, j)
+					break dup
+				}
+			}
+		}
+	}
+
+	// 2020-12 用 prefixItems 描述元组，items 约束其余元素；Draft 7 的 items 可以是数组，其余元素由 additionalItems 约束
+	prefix := 0
+	if p, ok := s["prefixItems"]; ok && v.draft == SchemaDraft2020 {
+		subs := v.schemas(p, spath+"/prefixItems")
+		for i := 0; i < len(subs) && i < len(arr); i++ {
+			item(subs[i], i, "prefixItems", spath+"/prefixItems/"+strconv.Itoa(i))
+		}
+		prefix = len(subs)
+	}
+	if items, ok := s["items"]; ok {
+		if tuple, ok := items.([]interface{}); ok {
+			for i := 0; i < len(tuple) && i < len(arr); i++ {
+				item(tuple[i], i, "items", spath+"/items/"+strconv.Itoa(i))
+			}
+			if extra, ok := s["additionalItems"]; ok {
+				for i := len(tuple); i < len(arr); i++ {
+					item(extra, i, "additionalItems", spath+"/additionalItems")
+				}
+			}
+		} else {
+			for i := prefix; i < len(arr); i++ {
+				item(items, i, "items", spath+"/items")
+			}
+		}
+	}
+
+	if contains, ok := s["contains"]; ok {
+		matched := 0
+		for i, val := range arr {
+			if v.valid(contains, val, ptr+"/"+strconv.Itoa(i), spath+"/contains") {
+				matched++
+			}
+		}
+		min := 1
+		if n, ok := v.count(s, "minContains", spath); ok {
+			min = n
+		}
+		if matched < min {
+			r.add("contains", "array must contain at least %d matching item(s), found %d", min, matched)
+		}
+		if n, ok := v.count(s, "maxContains", spath); ok && matched > n {
+			r.add("maxContains", "array must contain at most %d matching item(s), found %d", n, matched)
+		}
+	}
+}
+
+func (v *validator) validateString(s map[string]interface{}, str string, r *report) {
+	spath := r.spath
+	length := utf8.RuneCountInString(str)
+	if n, ok := v.count(s, "minLength", spath); ok && length < n {
+		r.add("minLength", "string has %d characters, at least %d required", length, n)
+	}
+	if n, ok := v.count(s, "maxLength", spath); ok && length > n {
+		r.add("maxLength", "string has %d characters, at most %d allowed", length, n)
+	}
+	if p, ok := s["pattern"]; ok {
+		pattern, ok := p.(string)
+		if !ok {
+			v.fail("%s/pattern must be a string", spath)
+			return
+		}
+		if re := v.compile(pattern, spath+"/pattern"); re != nil && !re.MatchString(str) {
+			r.add("pattern", "string does not match pattern %q", pattern)
+		}
+	}
+	if f, ok := s["format"].(string); ok && !validFormat(f, str) {
+		r.add("format", "%q is not a valid %s", str, f)
+	}
+}
+
+func (v *validator) validateNumber(s map[string]interface{}, n json.Number, r *report) {
+	spath := r.spath
+	limit := func(kw string) (json.Number, bool) {
+		val, ok := s[kw]
+		if !ok {
+			return "", false
+		}
+		num, ok := val.(json.Number)
+		if !ok {
+			if _, isBool := val.(bool); !isBool || (kw != "exclusiveMinimum" && kw != "exclusiveMaximum") {
+				v.fail("%s/%s must be a number", spath, kw)
+			}
+			return "", false
+		}
+		return num, true
+	}
+	// Draft 4 的 exclusiveMinimum / exclusiveMaximum 是布尔值，修饰 minimum / maximum
+	exclusiveMin, _ := s["exclusiveMinimum"].(bool)
+	exclusiveMax, _ := s["exclusiveMaximum"].(bool)
+
+	if m, ok := limit("minimum"); ok {
+		if c := compareNumbers(n, m); c < 0 || (exclusiveMin && c == 0) {
+			r.add("minimum", "%s is less than minimum %s", n, m)
+		}
+	}
+	if m, ok := limit("maximum"); ok {
+		if c := compareNumbers(n, m); c > 0 || (exclusiveMax && c == 0) {
+			r.add("maximum", "%s is greater than maximum %s", n, m)
+		}
+	}
+	if m, ok := limit("exclusiveMinimum"); ok && compareNumbers(n, m) <= 0 {
+		r.add("exclusiveMinimum", "%s must be greater than %s", n, m)
+	}
+	if m, ok := limit("exclusiveMaximum"); ok && compareNumbers(n, m) >= 0 {
+		r.add("exclusiveMaximum", "%s must be less than %s", n, m)
+	}
+	if m, ok := limit("multipleOf"); ok {
+		if compareNumbers(m, "0") <= 0 {
+			v.fail("%s/multipleOf must be greater than 0", spath)
+		} else if !isMultipleOf(n, m) {
+			r.add("multipleOf", "%s is not a multiple of %s", n, m)
+		}
+	}
+}
+
+// schemas 读取 schema 数组（allOf、prefixItems 等）
+func (v *validator) schemas(val interface{}, spath string) []interface{} {
+	arr, ok := val.([]interface{})
+	if !ok {
+		v.fail("%s must be an array of schemas", spath)
+	}
+	return arr
+}
+
+// object 读取对象形式的关键字（properties 等），不存在时返回空对象
+func (v *validator) object(val interface{}, spath string) map[string]interface{} {
+	if val == nil {
+		return map[string]interface{}{}
+	}
+	m, ok := val.(map[string]interface{})
+	if !ok {
+		v.fail("%s must be an object", spath)
+		return map[string]interface{}{}
+	}
+	return m
+}
+
+// stringList 读取字符串数组（required、type 等），不存在时返回 nil
+func (v *validator) stringList(val interface{}, spath string) []string {
+	if val == nil {
+		return nil
+	}
+	arr, ok := val.([]interface{})
+	if !ok {
+		v.fail("%s must be an array of strings", spath)
+		return nil
+	}
+	out := make([]string, 0, len(arr))
+	for _, x := range arr {
+		s, ok := x.(string)
+		if !ok {
+			v.fail("%s must be an array of strings", spath)
+			return nil
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+// count 读取非负整数关键字（minLength 等）
+func (v *validator) count(s map[string]interface{}, kw, spath string) (int, bool) {
+	val, ok := s[kw]
+	if !ok {
+		return 0, false
+	}
+	n, ok := val.(json.Number)
+	if ok {
+		if r := numberRat(n); r != nil && r.IsInt() && r.Sign() >= 0 && r.Num().IsInt64() {
+			return int(r.Num().Int64()), true
+		}
+	}
+	v.fail("%s/%s must be a non-negative integer", spath, kw)
+	return 0, false
+}
+
+func typeMatches(name string, inst interface{}) bool {
+	switch name {
+	case "integer":
+		n, ok := inst.(json.Number)
+		return ok && isInteger(n)
+	case "number":
+		_, ok := inst.(json.Number)
+		return ok
+	}
+	return typeOf(inst) == name
+}
+
+// numberRat 精确值；指数过大时返回 nil，调用方改用高精度浮点，避免构造巨大的分数
+func numberRat(n json.Number) *big.Rat {
+	s := string(n)
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(s[i+1:])
+		if err != nil || exp > 10000 || exp < -10000 {
+			return nil
+		}
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return nil
+	}
+	return r
+}
+
+func compareNumbers(a, b json.Number) int {
+	if ra, rb := numberRat(a), numberRat(b); ra != nil && rb != nil {
+		return ra.Cmp(rb)
+	}
+	return bigNumber(a).Cmp(bigNumber(b))
+}
+
+// isInteger 1.0 也是整数
+func isInteger(n json.Number) bool {
+	if r := numberRat(n); r != nil {
+		return r.IsInt()
+	}
+	return bigNumber(n).IsInt()
+}
+
+func isMultipleOf(n, m json.Number) bool {
+	if rn, rm := numberRat(n), numberRat(m); rn != nil && rm != nil {
+		return new(big.Rat).Quo(rn, rm).IsInt()
+	}
+	return new(big.Float).Quo(bigNumber(n), bigNumber(m)).IsInt()
+}
+
+var (
+	uuidPattern     = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	hostnamePattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*\.?$`)
+	pointerPattern  = regexp.MustCompile(`^(/([^~/]|~[01])*)*$`)
+)
+
+// validFormat 校验常用 format，未知的 format 一律通过
+func validFormat(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(s))
+		return err == nil
+	case "date":
+		_, err := time.Parse("2006-01-02", s)
+		return err == nil
+	case "time":
+		_, err := time.Parse("15:04:05.999999999Z07:00", strings.ToUpper(s))
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "hostname":
+		return len(s) <= 253 && hostnamePattern.MatchString(s)
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && !strings.Contains(s, ":")
+	case "ipv6":
+		return net.ParseIP(s) != nil && strings.Contains(s, ":")
+	case "uri":
+		u, err := url.Parse(s)
+		return err == nil && u.IsAbs()
+	case "uri-reference":
+		_, err := url.Parse(s)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "regex":
+		_, err := regexp.Compile(s)
+		return err == nil
+	case "json-pointer":
+		return pointerPattern.MatchString(s)
+	}
+	return true
+}
+
+func rootPath(spath string) string {
+	if spath == "" {
+		return "/"
+	}
+	return spath
+}
+
+// jsonPositions 每个 JSON Pointer 对应的值在 text 中的起始字节偏移；重复的键以最后一个为准，与解析结果一致
+func jsonPositions(text string) map[string]int {
+	pos := map[string]int{}
+	dec := json.NewDecoder(strings.NewReader(text))
+	dec.UseNumber()
+	// InputOffset 指向上一个 token 之后，跳过空白和分隔符即为下一个值的开头
+	start := func() int {
+		off := int(dec.InputOffset())
+		for off < len(text) && strings.IndexByte(" \t\r\n,:", text[off]) >= 0 {
+			off++
+		}
+		return off
+	}
+
+	var walk func(ptr string) error
+	walk = func(ptr string) error {
+		pos[ptr] = start()
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				k, _ := key.(string)
+				if err := walk(ptr + "/" + escapePointer(k)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(ptr + "/" + strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	_ = walk("")
+	return pos
+}
+
+// lineStarts 每行第一个字节的偏移
+func lineStarts(s string) []int {
+	starts := []int{0}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// lineColumn 字节偏移转为从 1 开始的行号和列号，列按字符计
+func lineColumn(s string, starts []int, offset int) (int, int) {
+	line := sort.Search(len(starts), func(i int) bool { return starts[i] > offset }) - 1
+	return line + 1, utf8.RuneCountInString(s[starts[line]:offset]) + 1
+}
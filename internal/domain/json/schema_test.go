@@ -0,0 +1,160 @@
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// violationsOf 每条违规格式化为 "path keyword"，便于比较
+func violationsOf(t *testing.T, doc, schema string) []string {
+	t.Helper()
+	res, err := Validate(doc, schema, "")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if res.Valid != (len(res.Violations) == 0) {
+		t.Errorf("Valid = %v, violations = %+v", res.Valid, res.Violations)
+	}
+	out := make([]string, len(res.Violations))
+	for i, v := range res.Violations {
+		out[i] = v.Path + " " + v.Keyword
+	}
+	return out
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		doc    string
+		schema string
+		want   string
+	}{
+		{"类型", `{"a":"x","b":1.0,"c":1.5,"d":null}`,
+			`{"properties":{"a":{"type":"number"},"b":{"type":"integer"},"c":{"type":"integer"},"d":{"type":["string","null"]}}}`,
+			"/a type,/c type"},
+		{"必填与额外属性", `{"id":1,"extra":true}`,
+			`{"type":"object","required":["id","name"],"properties":{"id":{}},"additionalProperties":false}`,
+			" required,/extra additionalProperties"},
+		{"patternProperties", `{"x_a":1,"x_b":"s","y":1}`,
+			`{"patternProperties":{"^x_":{"type":"integer"}},"additionalProperties":{"type":"string"}}`,
+			"/x_b type,/y type"},
+		{"enum 与 const", `{"a":"c","b":2,"c":1.0}`,
+			`{"properties":{"a":{"enum":["a","b"]},"b":{"const":1},"c":{"const":1}}}`,
+			"/a enum,/b const"},
+		{"字符串", `["ab","日本語x","abc"]`,
+			`{"items":{"minLength":3,"maxLength":3,"pattern":"^[a-z]+$"}}`,
+			"/0 minLength,/1 maxLength,/1 pattern"},
+		{"数字精确比较", `[12345678901234567891, 0.30000000000000001, 0.7, 10]`,
+			`{"prefixItems":[{"maximum":12345678901234567890},{"exclusiveMaximum":0.3},{"multipleOf":0.1},{"exclusiveMinimum":10}]}`,
+			"/0 maximum,/1 exclusiveMaximum,/3 exclusiveMinimum"},
+		{"数组", `[1,2,2,"x"]`,
+			`{"minItems":5,"uniqueItems":true,"contains":{"type":"string"},"maxContains":0}`,
+			" minItems, uniqueItems, maxContains"},
+		{"prefixItems 与 items false", `[1,"a",true]`,
+			`{"prefixItems":[{"type":"integer"},{"type":"string"}],"items":false}`,
+			"/2 items"},
+		{"Draft 7 元组", `[1,"a",true]`,
+			`{"$schema":"http://json-schema.org/draft-07/schema#","items":[{"type":"integer"},{"type":"integer"}],"additionalItems":{"type":"string"}}`,
+			"/1 type,/2 type"},
+		{"format", `{"d":"2024-02-30","t":"2024-01-01T10:00:00Z","e":"a@b","ip":"1.2.3.256","u":"3b241101-e2bb-4255-8caf-4136c566a962","h":"-bad-"}`,
+			`{"properties":{"d":{"format":"date"},"t":{"format":"date-time"},"e":{"format":"email"},"ip":{"format":"ipv4"},"u":{"format":"uuid"},"h":{"format":"hostname"}}}`,
+			"/d format,/h format,/ip format"},
+		{"$ref 与 $defs", `{"billing":{"city":"x"},"shipping":{"street":"y","city":1}}`,
+			`{"$defs":{"addr":{"type":"object","required":["street"],"properties":{"city":{"type":"string"}}}},"properties":{"billing":{"$ref":"#/$defs/addr"},"shipping":{"$ref":"#/$defs/addr"}}}`,
+			"/billing required,/shipping/city type"},
+		{"递归 $ref", `{"v":1,"children":[{"v":2,"children":[{"v":"x"}]}]}`,
+			`{"$id":"https://example.com/tree","properties":{"v":{"type":"integer"},"children":{"items":{"$ref":"#"}}}}`,
+			"/children/0/children/0/v type"},
+		{"经 contains / propertyNames 递归的 $ref", `{"ab":[[1]]}`,
+			`{"$defs":{"t":{"contains":{"$ref":"#/$defs/t"},"propertyNames":{"$ref":"#/$defs/t"},"additionalProperties":{"$ref":"#/$defs/t"},"maxLength":1}},"$ref":"#/$defs/t"}`,
+			"/ab propertyNames"},
+		{"Draft 7 definitions 与锚点", `{"a":"x","b":"y"}`,
+			`{"$schema":"http://json-schema.org/draft-07/schema#","definitions":{"n":{"$id":"#num","type":"number"}},"properties":{"a":{"$ref":"#/definitions/n"},"b":{"$ref":"#num","type":"string"}}}`,
+			"/a type,/b type"},
+		{"组合", `[{"a":1},{"a":1,"b":1},{},"s"]`,
+			`{"items":{"oneOf":[{"required":["a"]},{"required":["b"]}],"anyOf":[{"type":"object"}],"not":{"type":"string"}}}`,
+			"/1 oneOf,/2 oneOf,/3 anyOf,/3 oneOf,/3 not"},
+		{"if/then/else", `[{"kind":"a","x":1},{"kind":"b"}]`,
+			`{"items":{"if":{"properties":{"kind":{"const":"a"}}},"then":{"required":["y"]},"else":{"required":["z"]}}}`,
+			"/0 required,/1 required"},
+		{"依赖", `{"card":1,"a":1}`,
+			`{"dependentRequired":{"card":["billing"]},"dependencies":{"a":{"required":["b"]}}}`,
+			" required, dependentRequired"},
+		{"布尔 schema", `{"a":1}`, `{"properties":{"a":false}}`, "/a false"},
+		{"通过", `{"a":[1,2]}`, `true`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Join(violationsOf(t, tt.doc, tt.schema), ",")
+			if got != tt.want {
+				t.Errorf("violations = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidatePositions(t *testing.T) {
+	doc := "log line\n{\n  \"name\": \"名字\", \"age\": \"x\",\n  \"tags\": [1,\n    true]\n}"
+	res, err := Validate(doc, `{"properties":{"age":{"type":"integer"},"tags":{"items":{"type":"integer"}}}}`, "")
+	if err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	want := map[string][2]int{"/age": {3, 24}, "/tags/1": {5, 5}}
+	if len(res.Violations) != len(want) {
+		t.Fatalf("violations = %+v", res.Violations)
+	}
+	for _, v := range res.Violations {
+		if pos := want[v.Path]; v.Line != pos[0] || v.Column != pos[1] {
+			t.Errorf("%s at %d:%d, want %d:%d", v.Path, v.Line, v.Column, pos[0], pos[1])
+		}
+	}
+}
+
+func TestValidateDraft(t *testing.T) {
+	res, err := Validate(`[1]`, `{"$schema":"http://json-schema.org/draft-07/schema#"}`, "")
+	if err != nil || res.Draft != SchemaDraft7 {
+		t.Errorf("Draft = %q, %v", res.Draft, err)
+	}
+	// Draft 7 中 $ref 旁边的关键字被忽略
+	schema := `{"definitions":{"s":{"type":"string"}},"$ref":"#/definitions/s","minLength":5}`
+	if res, _ := Validate(`"abc"`, schema, SchemaDraft7); !res.Valid {
+		t.Errorf("draft-07 violations = %+v", res.Violations)
+	}
+	if res, _ := Validate(`"abc"`, schema, SchemaDraft2020); res.Valid {
+		t.Error("2020-12 应同时检查 minLength")
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	// 关键字只在作用于对应类型的实例时检查，实例为 ["x", 1]
+	schemaErrors := []string{
+		`{"items":{"type":"text"}}`,
+		`{"items":{"maxLength":"a"}}`,
+		`{"items":{"pattern":"(?=x)"}}`,
+		`{"items":{"minLength":-1}}`,
+		`{"items":{"multipleOf":0}}`,
+		`{"$ref":"#/$defs/missing"}`,
+		`{"$ref":"https://example.com/other.json"}`,
+		`{"$defs":{"a":{"$ref":"#/$defs/a"}},"$ref":"#/$defs/a"}`,
+		`{"a":`,
+	}
+	for _, s := range schemaErrors {
+		if _, err := Validate(`["x", 1]`, s, ""); !errors.Is(err, ErrInvalidSchema) {
+			t.Errorf("Validate(schema=%s) error = %v, want ErrInvalidSchema", s, err)
+		}
+	}
+	// 循环引用只报告一次重复进入的位置，不随嵌套层数增长
+	for _, s := range []string{`{"allOf":[{"$ref":"#"}]}`, `{"$defs":{"a":{"anyOf":[{"$ref":"#/$defs/b"}]},"b":{"not":{"$ref":"#/$defs/a"}}},"$ref":"#/$defs/a"}`} {
+		_, err := Validate(`{"x":[1]}`, s, "")
+		if !errors.Is(err, ErrInvalidSchema) || !strings.Contains(err.Error(), "re-enters") || len(err.Error()) > 200 {
+			t.Errorf("Validate(schema=%s) error = %v, want short cycle error", s, err)
+		}
+	}
+	if _, err := Validate(`{"a":`, `{}`, ""); err == nil || errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("无效文档应返回 JSON 错误：%v", err)
+	}
+	if _, err := Validate(`{}`, `{}`, "draft-04"); !errors.Is(err, ErrInvalidSchema) {
+		t.Errorf("未知 draft 应报错：%v", err)
+	}
+}
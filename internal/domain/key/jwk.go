@@ -0,0 +1,283 @@
+package key
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+)
+
+// JWK RFC 7517 JSON Web Key，支持 RSA / EC / OKP(Ed25519)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	X string `json:"x,omitempty"`
+	Y string `json:"y,omitempty"`
+
+	// 私钥参数
+	D  string `json:"d,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+
+	// X5c 证书链，标准 base64 编码的 DER，叶子证书在前
+	X5c     []string `json:"x5c,omitempty"`
+	X5tS256 string   `json:"x5t#S256,omitempty"`
+}
+
+// JWKS RFC 7517 JWK Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+type jwkCurveInfo struct {
+	crv   string
+	curve elliptic.Curve
+	ecdh  ecdh.Curve
+}
+
+// jwkCurves JWK crv 与 Go 曲线的对应关系
+var jwkCurves = []jwkCurveInfo{
+	{"P-256", elliptic.P256(), ecdh.P256()},
+	{"P-384", elliptic.P384(), ecdh.P384()},
+	{"P-521", elliptic.P521(), ecdh.P521()},
+}
+
+var b64url = base64.RawURLEncoding
+
+// NewJWK 将公钥或私钥转换为 JWK，kid 取 RFC 7638 指纹
+func NewJWK(k interface{}) (JWK, error) {
+	var jwk JWK
+	switch key := k.(type) {
+	case *rsa.PublicKey:
+		jwk = JWK{Kty: "RSA", N: b64url.EncodeToString(key.N.Bytes()), E: b64url.EncodeToString(big.NewInt(int64(key.E)).Bytes())}
+	case *rsa.PrivateKey:
+		if len(key.Primes) != 2 {
+			return JWK{}, errors.New("multi-prime RSA keys are not supported")
+		}
+		key.Precompute()
+		jwk, _ = NewJWK(&key.PublicKey)
+		jwk.D = b64url.EncodeToString(key.D.Bytes())
+		jwk.P = b64url.EncodeToString(key.Primes[0].Bytes())
+		jwk.Q = b64url.EncodeToString(key.Primes[1].Bytes())
+		jwk.DP = b64url.EncodeToString(key.Precomputed.Dp.Bytes())
+		jwk.DQ = b64url.EncodeToString(key.Precomputed.Dq.Bytes())
+		jwk.QI = b64url.EncodeToString(key.Precomputed.Qinv.Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk = JWK{
+			Kty: "EC",
+			Crv: key.Curve.Params().Name,
+			X:   b64url.EncodeToString(key.X.FillBytes(make([]byte, size))),
+			Y:   b64url.EncodeToString(key.Y.FillBytes(make([]byte, size))),
+		}
+		if _, err := jwkCurve(jwk.Crv); err != nil {
+			return JWK{}, err
+		}
+	case *ecdsa.PrivateKey:
+		var err error
+		if jwk, err = NewJWK(&key.PublicKey); err != nil {
+			return JWK{}, err
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.D = b64url.EncodeToString(key.D.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk = JWK{Kty: "OKP", Crv: "Ed25519", X: b64url.EncodeToString(key)}
+	case ed25519.PrivateKey:
+		jwk, _ = NewJWK(key.Public())
+		jwk.D = b64url.EncodeToString(key.Seed())
+	default:
+		return JWK{}, errors.New("unsupported key type")
+	}
+
+	jwk.Kid = jwk.Thumbprint()
+	return jwk, nil
+}
+
+// Thumbprint RFC 7638 JWK 指纹：按字典序拼接必需成员后做 SHA-256，base64url 编码
+func (j JWK) Thumbprint() string {
+	var members string
+	switch j.Kty {
+	case "RSA":
+		members = `{"e":"` + j.E + `","kty":"RSA","n":"` + j.N + `"}`
+	case "EC":
+		members = `{"crv":"` + j.Crv + `","kty":"EC","x":"` + j.X + `","y":"` + j.Y + `"}`
+	case "OKP":
+		members = `{"crv":"` + j.Crv + `","kty":"OKP","x":"` + j.X + `"}`
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(members))
+	return b64url.EncodeToString(sum[:])
+}
+
+// IsPrivate JWK 是否带有私钥参数
+func (j JWK) IsPrivate() bool {
+	return j.D != ""
+}
+
+// String 输出缩进两个空格的 JSON
+func (j JWK) String() string {
+	return indentJSON(j)
+}
+
+// String 输出缩进两个空格的 JSON
+func (s JWKS) String() string {
+	return indentJSON(s)
+}
+
+func indentJSON(v interface{}) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+	return buf.String()
+}
+
+// ParseJWK 解析单个 JWK，带私钥参数时返回私钥，否则返回公钥
+func ParseJWK(data []byte) (interface{}, error) {
+	var j JWK
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, errors.New("invalid jwk: " + err.Error())
+	}
+	return j.Key()
+}
+
+// ParseJWKS 解析 JWK Set，也接受单个 JWK；只检查结构，不还原密钥
+func ParseJWKS(data []byte) (JWKS, error) {
+	var probe struct {
+		Keys json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return JWKS{}, errors.New("invalid jwks: " + err.Error())
+	}
+	if probe.Keys == nil {
+		var j JWK
+		if err := json.Unmarshal(data, &j); err != nil {
+			return JWKS{}, errors.New("invalid jwk: " + err.Error())
+		}
+		return JWKS{Keys: []JWK{j}}, nil
+	}
+
+	var set JWKS
+	if err := json.Unmarshal(data, &set); err != nil {
+		return JWKS{}, errors.New("invalid jwks: " + err.Error())
+	}
+	if len(set.Keys) == 0 {
+		return JWKS{}, errors.New("jwks contains no keys")
+	}
+	return set, nil
+}
+
+// Key 将 JWK 还原为 Go 公钥或私钥
+func (j JWK) Key() (interface{}, error) {
+	switch j.Kty {
+	case "RSA":
+		n, err1 := decodeJWKInt(j.N)
+		e, err2 := decodeJWKInt(j.E)
+		if err1 != nil || err2 != nil || !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA jwk: bad n or e")
+		}
+		pub := &rsa.PublicKey{N: n, E: int(e.Int64())}
+		if j.D == "" {
+			return pub, nil
+		}
+		d, err1 := decodeJWKInt(j.D)
+		p, err2 := decodeJWKInt(j.P)
+		q, err3 := decodeJWKInt(j.Q)
+		if err1 != nil || err2 != nil || err3 != nil {
+			return nil, errors.New("invalid RSA jwk: private key requires d, p and q")
+		}
+		priv := &rsa.PrivateKey{PublicKey: *pub, D: d, Primes: []*big.Int{p, q}}
+		if err := priv.Validate(); err != nil {
+			return nil, errors.New("invalid RSA jwk: " + err.Error())
+		}
+		priv.Precompute()
+		return priv, nil
+	case "EC":
+		c, err := jwkCurve(j.Crv)
+		if err != nil {
+			return nil, err
+		}
+		size := (c.curve.Params().BitSize + 7) / 8
+		x, err1 := b64url.DecodeString(j.X)
+		y, err2 := b64url.DecodeString(j.Y)
+		if err1 != nil || err2 != nil || len(x) != size || len(y) != size {
+			return nil, errors.New("invalid EC jwk: bad x or y")
+		}
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := c.ecdh.NewPublicKey(point); err != nil {
+			return nil, errors.New("invalid EC jwk: point is not on curve " + j.Crv)
+		}
+		pub := &ecdsa.PublicKey{Curve: c.curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if j.D == "" {
+			return pub, nil
+		}
+		d, err := b64url.DecodeString(j.D)
+		if err != nil || len(d) != size {
+			return nil, errors.New("invalid EC jwk: bad d")
+		}
+		ecdhPriv, err := c.ecdh.NewPrivateKey(d)
+		if err != nil || !bytes.Equal(ecdhPriv.PublicKey().Bytes(), point) {
+			return nil, errors.New("invalid EC jwk: d does not match x and y")
+		}
+		return &ecdsa.PrivateKey{PublicKey: *pub, D: new(big.Int).SetBytes(d)}, nil
+	case "OKP":
+		if j.Crv != "Ed25519" {
+			return nil, errors.New("unsupported OKP curve: " + j.Crv)
+		}
+		x, err := b64url.DecodeString(j.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 jwk: bad x")
+		}
+		if j.D == "" {
+			return ed25519.PublicKey(x), nil
+		}
+		seed, err := b64url.DecodeString(j.D)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, errors.New("invalid Ed25519 jwk: bad d")
+		}
+		priv := ed25519.NewKeyFromSeed(seed)
+		if !bytes.Equal(priv.Public().(ed25519.PublicKey), x) {
+			return nil, errors.New("invalid Ed25519 jwk: d does not match x")
+		}
+		return priv, nil
+	case "":
+		return nil, errors.New("invalid jwk: kty is missing")
+	default:
+		return nil, errors.New("unsupported jwk kty: " + j.Kty)
+	}
+}
+
+func jwkCurve(crv string) (jwkCurveInfo, error) {
+	for _, c := range jwkCurves {
+		if c.crv == crv {
+			return c, nil
+		}
+	}
+	return jwkCurveInfo{}, errors.New("unsupported EC curve: " + crv)
+}
+
+func decodeJWKInt(s string) (*big.Int, error) {
+	b, err := b64url.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return nil, errors.New("invalid integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,99 @@
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"math/big"
+	"testing"
+)
+
+func TestJWKRoundTrip(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name string
+		key  crypto.PrivateKey
+		kty  string
+	}{
+		{"rsa", rsaKey, "RSA"},
+		{"ecdsa", ecKey, "EC"},
+		{"ed25519", edKey, "OKP"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jwk, err := NewJWK(tt.key)
+			if err != nil {
+				t.Fatalf("NewJWK() error = %v", err)
+			}
+			if jwk.Kty != tt.kty || jwk.D == "" || jwk.Kid != jwk.Thumbprint() {
+				t.Errorf("jwk = %+v", jwk)
+			}
+			got, err := ParseJWK([]byte(jwk.String()))
+			if err != nil {
+				t.Fatalf("ParseJWK() error = %v", err)
+			}
+			if !tt.key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(got) {
+				t.Errorf("private key mismatch")
+			}
+
+			pubJWK, err := NewJWK(tt.key.(crypto.Signer).Public())
+			if err != nil {
+				t.Fatalf("NewJWK(public) error = %v", err)
+			}
+			if pubJWK.D != "" || pubJWK.Kid != jwk.Kid {
+				t.Errorf("public jwk = %+v", pubJWK)
+			}
+		})
+	}
+}
+
+// RFC 7638 3.1 示例
+func TestJWKThumbprint(t *testing.T) {
+	jwk := JWK{
+		Kty: "RSA",
+		N: "0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMs" +
+			"tn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n" +
+			"91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		E: "AQAB",
+	}
+	if got, want := jwk.Thumbprint(), "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"; got != want {
+		t.Errorf("Thumbprint() = %s, want %s", got, want)
+	}
+}
+
+func TestParseJWKInvalid(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	offCurve, _ := NewJWK(&ecKey.PublicKey)
+	offCurve.Y = b64url.EncodeToString(new(big.Int).Add(ecKey.Y, big.NewInt(1)).FillBytes(make([]byte, 32)))
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	mismatched, _ := NewJWK(ecKey)
+	otherJWK, _ := NewJWK(other)
+	mismatched.D = otherJWK.D
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"not json", "{"},
+		{"missing kty", `{"n":"AQAB"}`},
+		{"unsupported kty", `{"kty":"oct","k":"AAAA"}`},
+		{"unsupported curve", `{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}`},
+		{"bad rsa", `{"kty":"RSA","n":"","e":"AQAB"}`},
+		{"point not on curve", offCurve.String()},
+		{"d does not match", mismatched.String()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseJWK([]byte(tt.input)); err == nil {
+				t.Errorf("expected error")
+			}
+		})
+	}
+}
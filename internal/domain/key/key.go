@@ -0,0 +1,414 @@
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+
+	"my-tools/internal/domain/pkcs8"
+)
+
+// 密钥格式
+const (
+	FormatPKCS1       = "PKCS#1"
+	FormatPKCS8       = "PKCS#8"
+	FormatSEC1        = "SEC1"
+	FormatOpenSSH     = "OpenSSH"
+	FormatPKIX        = "PKIX"
+	FormatSSHPublic   = "OpenSSH public"
+	FormatJWK         = "JWK"
+	FormatPKCS1Public = "PKCS#1 public"
+)
+
+// 转换目标格式
+const (
+	TargetPKCS1     = "pkcs1"
+	TargetPKCS8     = "pkcs8"
+	TargetSEC1      = "sec1"
+	TargetOpenSSH   = "openssh"
+	TargetPublic    = "public"
+	TargetSSHPublic = "ssh-public"
+	TargetJWK       = "jwk"
+)
+
+// 密钥类型
+const (
+	TypePrivate = "private"
+	TypePublic  = "public"
+)
+
+// InspectOptions 密钥解析与转换参数
+type InspectOptions struct {
+	// Passphrase 加密私钥的口令
+	Passphrase string
+	// Target 转换目标格式，为空时只解析
+	Target string
+	// OutputPassphrase 输出 PKCS#8 时用于加密私钥
+	OutputPassphrase string
+}
+
+// KeyInfo 密钥解析结果
+type KeyInfo struct {
+	// Type private / public
+	Type      string `json:"type"`
+	Format    string `json:"format"`
+	Encrypted bool   `json:"encrypted"`
+	// Locked 私钥已加密且未提供口令，此时只有 OpenSSH 格式能给出公钥信息
+	Locked bool `json:"locked"`
+
+	Algorithm string `json:"algorithm,omitempty"`
+	Size      int    `json:"size,omitempty"`
+	Curve     string `json:"curve,omitempty"`
+
+	// PublicKeySHA256 SubjectPublicKeyInfo 的 SHA-256，与证书 / CSR 比对时使用
+	PublicKeySHA256 string `json:"publicKeySha256,omitempty"`
+	SSHFingerprint  string `json:"sshFingerprint,omitempty"`
+	PublicKeyPEM    string `json:"publicKeyPem,omitempty"`
+	SSHPublicKey    string `json:"sshPublicKey,omitempty"`
+
+	OutputFormat string `json:"outputFormat,omitempty"`
+	Output       string `json:"output,omitempty"`
+}
+
+// Inspect 识别 PKCS#1 / PKCS#8 / SEC1 / OpenSSH 私钥，PKIX / PKCS#1 / OpenSSH 公钥和 JWK，
+// 给出算法和长度，并按需转换为其他格式
+func Inspect(input string, opts InspectOptions) (KeyInfo, error) {
+	in := normalizeInput(input)
+	if in == "" {
+		return KeyInfo{}, errors.New("key is empty")
+	}
+
+	var (
+		info KeyInfo
+		priv crypto.PrivateKey
+		pub  crypto.PublicKey
+		err  error
+	)
+	switch {
+	case strings.HasPrefix(in, "{"):
+		var k interface{}
+		k, err = ParseJWK([]byte(in))
+		if err != nil {
+			return KeyInfo{}, err
+		}
+		info.Format = FormatJWK
+		if s, ok := k.(crypto.Signer); ok {
+			priv, pub = k, s.Public()
+		} else {
+			pub = k
+		}
+	case !strings.Contains(in, "-----BEGIN "):
+		pub, err = parseSSHPublicKey(in)
+		if err != nil {
+			return KeyInfo{}, err
+		}
+		info.Format = FormatSSHPublic
+	default:
+		info, priv, pub, err = parsePEMKey(in, opts.Passphrase)
+		if err != nil {
+			return KeyInfo{}, err
+		}
+	}
+
+	info.Type = TypePublic
+	if priv != nil || info.Locked {
+		info.Type = TypePrivate
+	}
+	if pub != nil {
+		if err := fillPublicInfo(&info, pub); err != nil {
+			return KeyInfo{}, err
+		}
+	}
+
+	if opts.Target == "" {
+		return info, nil
+	}
+	if info.Locked {
+		return KeyInfo{}, errors.New("private key is encrypted, passphrase is required")
+	}
+	info.Output, err = convert(priv, pub, opts.Target, opts.OutputPassphrase)
+	if err != nil {
+		return KeyInfo{}, err
+	}
+	info.OutputFormat = opts.Target
+	return info, nil
+}
+
+// ParsePrivateKeyPEM 解析 PKCS#1 / PKCS#8 / SEC1 / OpenSSH 私钥 PEM，支持加密的 PKCS#8、
+// 传统 OpenSSL 加密 PEM 和加密的 OpenSSH 私钥；返回私钥、格式以及是否加密
+func ParsePrivateKeyPEM(input, passphrase string) (crypto.PrivateKey, string, bool, error) {
+	in := normalizeInput(input)
+	if in == "" {
+		return nil, "", false, errors.New("private key is empty")
+	}
+
+	var block *pem.Block
+	rest := []byte(in)
+	for {
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, "", false, errors.New("no private key PEM block found")
+		}
+		if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+			break
+		}
+	}
+	return parsePrivateKeyBlock(block, passphrase)
+}
+
+func parsePrivateKeyBlock(block *pem.Block, passphrase string) (crypto.PrivateKey, string, bool, error) {
+	if block.Type == "OPENSSH PRIVATE KEY" {
+		return parseOpenSSHPrivateKey(block, passphrase)
+	}
+
+	der := block.Bytes
+	encrypted := false
+	// 传统 OpenSSL 加密 PEM（Proc-Type: 4,ENCRYPTED）只能用已废弃的 DecryptPEMBlock 解密
+	if x509.IsEncryptedPEMBlock(block) {
+		if passphrase == "" {
+			return nil, "", true, errors.New("private key is encrypted, passphrase is required")
+		}
+		d, err := x509.DecryptPEMBlock(block, []byte(passphrase))
+		if err != nil {
+			return nil, "", true, err
+		}
+		der = d
+		encrypted = true
+	}
+	if block.Type == pkcs8.EncryptedPEMType {
+		if passphrase == "" {
+			return nil, "", true, errors.New("private key is encrypted, passphrase is required")
+		}
+		d, err := pkcs8.Decrypt(der, passphrase)
+		if err != nil {
+			return nil, "", true, err
+		}
+		der = d
+		encrypted = true
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, FormatPKCS8, encrypted, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, FormatPKCS1, encrypted, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, FormatSEC1, encrypted, nil
+	}
+	return nil, "", encrypted, errors.New("unsupported private key format")
+}
+
+// parsePEMKey 解析第一个私钥或公钥 PEM 块；加密私钥未提供口令时返回 Locked 而不是错误
+func parsePEMKey(in, passphrase string) (KeyInfo, crypto.PrivateKey, crypto.PublicKey, error) {
+	rest := []byte(in)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return KeyInfo{}, nil, nil, errors.New("no private or public key PEM block found")
+		}
+
+		switch {
+		case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+			priv, format, encrypted, err := parsePrivateKeyBlock(block, passphrase)
+			info := KeyInfo{Format: format, Encrypted: encrypted}
+			if err != nil {
+				if !encrypted || passphrase != "" {
+					return KeyInfo{}, nil, nil, err
+				}
+				// 未提供口令：OpenSSH 私钥的公钥部分不加密，仍可给出算法信息
+				info.Locked = true
+				info.Format = lockedFormat(block)
+				var pub crypto.PublicKey
+				var missing *ssh.PassphraseMissingError
+				if errors.As(err, &missing) && missing.PublicKey != nil {
+					if cpk, ok := missing.PublicKey.(ssh.CryptoPublicKey); ok {
+						pub = cpk.CryptoPublicKey()
+					}
+				}
+				return info, nil, pub, nil
+			}
+			signer, ok := priv.(crypto.Signer)
+			if !ok {
+				return KeyInfo{}, nil, nil, errors.New("unsupported private key type")
+			}
+			return info, priv, signer.Public(), nil
+		case block.Type == "PUBLIC KEY":
+			pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+			if err != nil {
+				return KeyInfo{}, nil, nil, err
+			}
+			return KeyInfo{Format: FormatPKIX}, nil, pub, nil
+		case block.Type == "RSA PUBLIC KEY":
+			pub, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return KeyInfo{}, nil, nil, err
+			}
+			return KeyInfo{Format: FormatPKCS1Public}, nil, pub, nil
+		}
+	}
+}
+
+// lockedFormat 无法解密时根据 PEM 类型推断格式
+func lockedFormat(block *pem.Block) string {
+	switch block.Type {
+	case "OPENSSH PRIVATE KEY":
+		return FormatOpenSSH
+	case "RSA PRIVATE KEY":
+		return FormatPKCS1
+	case "EC PRIVATE KEY":
+		return FormatSEC1
+	default:
+		return FormatPKCS8
+	}
+}
+
+func parseSSHPublicKey(in string) (crypto.PublicKey, error) {
+	sshPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(in))
+	if err != nil {
+		return nil, errors.New("input is neither PEM, JWK nor an OpenSSH public key")
+	}
+	cpk, ok := sshPub.(ssh.CryptoPublicKey)
+	if !ok {
+		return nil, errors.New("unsupported ssh public key type: " + sshPub.Type())
+	}
+	return cpk.CryptoPublicKey(), nil
+}
+
+func fillPublicInfo(info *KeyInfo, pub crypto.PublicKey) error {
+	info.Algorithm, info.Size, info.Curve = PublicKeyInfo(pub)
+
+	spki, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(spki)
+	info.PublicKeySHA256 = ColonHex(sum[:])
+	info.PublicKeyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki}))
+
+	// OpenSSH 不支持的曲线（如 P-224）没有 SSH 公钥
+	if sshPub, err := ssh.NewPublicKey(pub); err == nil {
+		info.SSHFingerprint = ssh.FingerprintSHA256(sshPub)
+		info.SSHPublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(sshPub)))
+	}
+	return nil
+}
+
+// convert 按目标格式输出密钥；公钥输入只能输出公钥格式
+func convert(priv crypto.PrivateKey, pub crypto.PublicKey, target, outputPassphrase string) (string, error) {
+	switch target {
+	case TargetPublic:
+		spki, err := x509.MarshalPKIXPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: spki})), nil
+	case TargetSSHPublic:
+		sshPub, err := ssh.NewPublicKey(pub)
+		if err != nil {
+			return "", err
+		}
+		return string(ssh.MarshalAuthorizedKey(sshPub)), nil
+	case TargetJWK:
+		k := interface{}(pub)
+		if priv != nil {
+			k = priv
+		}
+		jwk, err := NewJWK(k)
+		if err != nil {
+			return "", err
+		}
+		return jwk.String(), nil
+	}
+
+	if priv == nil {
+		return "", errors.New("target format " + target + " requires a private key")
+	}
+	if outputPassphrase != "" && target != TargetPKCS8 {
+		return "", errors.New("output passphrase is only supported for pkcs8")
+	}
+
+	var block *pem.Block
+	switch target {
+	case TargetPKCS8:
+		der, err := x509.MarshalPKCS8PrivateKey(priv)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{Type: "PRIVATE KEY", Bytes: der}
+		if outputPassphrase != "" {
+			encrypted, err := pkcs8.Encrypt(der, outputPassphrase)
+			if err != nil {
+				return "", err
+			}
+			block = &pem.Block{Type: pkcs8.EncryptedPEMType, Bytes: encrypted}
+		}
+	case TargetPKCS1:
+		k, ok := priv.(*rsa.PrivateKey)
+		if !ok {
+			return "", errors.New("pkcs1 only supports RSA keys")
+		}
+		block = &pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(k)}
+	case TargetSEC1:
+		k, ok := priv.(*ecdsa.PrivateKey)
+		if !ok {
+			return "", errors.New("sec1 only supports ECDSA keys")
+		}
+		der, err := x509.MarshalECPrivateKey(k)
+		if err != nil {
+			return "", err
+		}
+		block = &pem.Block{Type: "EC PRIVATE KEY", Bytes: der}
+	case TargetOpenSSH:
+		b, err := marshalOpenSSHPrivateKey(priv, "")
+		if err != nil {
+			return "", err
+		}
+		block = b
+	default:
+		return "", errors.New("unsupported target format: " + target)
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// PublicKeyInfo 返回公钥算法、长度（bit）和曲线名称
+func PublicKeyInfo(pub crypto.PublicKey) (algorithm string, size int, curve string) {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return "RSA", k.N.BitLen(), ""
+	case *ecdsa.PublicKey:
+		return "ECDSA", k.Curve.Params().BitSize, k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519", 256, ""
+	default:
+		return "Unknown", 0, ""
+	}
+}
+
+// normalizeInput 还原 JSON 中转义的换行
+func normalizeInput(input string) string {
+	in := strings.TrimSpace(input)
+	in = strings.ReplaceAll(in, "\\r\\n", "\n")
+	in = strings.ReplaceAll(in, "\\n", "\n")
+	in = strings.ReplaceAll(in, "\r\n", "\n")
+	return strings.ReplaceAll(in, "\r", "\n")
+}
+
+// ColonHex 输出冒号分隔的大写十六进制，如 0A:1B:2C
+func ColonHex(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprintf("%02X", v)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,54 @@
+package key
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestMarshalOpenSSHPrivateKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name string
+		key  crypto.PrivateKey
+	}{
+		{"rsa", rsaKey},
+		{"ecdsa", ecKey},
+		{"ed25519", edKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			block, err := marshalOpenSSHPrivateKey(tt.key, "")
+			if err != nil {
+				t.Fatalf("marshalOpenSSHPrivateKey() error = %v", err)
+			}
+			got, err := ssh.ParseRawPrivateKey(pem.EncodeToMemory(block))
+			if err != nil {
+				t.Fatalf("ParseRawPrivateKey() error = %v", err)
+			}
+			if p, ok := got.(*ed25519.PrivateKey); ok {
+				got = *p
+			}
+			if !tt.key.(interface{ Equal(crypto.PrivateKey) bool }).Equal(got) {
+				t.Errorf("round trip key mismatch")
+			}
+		})
+	}
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		k, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+		if _, err := marshalOpenSSHPrivateKey(k, ""); err == nil {
+			t.Errorf("expected error")
+		}
+	})
+}
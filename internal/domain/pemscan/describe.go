@@ -0,0 +1,56 @@
+package pemscan
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"strings"
+)
+
+// Describe 一句话概括块的内容：证书 / CSR 的主题、CRL 的签发者、公钥算法；
+// 无法识别或解析失败时返回空字符串
+func Describe(b Block) string {
+	if b.Bytes == nil {
+		return ""
+	}
+	switch b.Type {
+	case "CERTIFICATE", "X509 CERTIFICATE":
+		if c, err := x509.ParseCertificate(b.Bytes); err == nil {
+			return "subject: " + c.Subject.String() + ", issuer: " + c.Issuer.String()
+		}
+	case "CERTIFICATE REQUEST", "NEW CERTIFICATE REQUEST":
+		if req, err := x509.ParseCertificateRequest(b.Bytes); err == nil {
+			return "subject: " + req.Subject.String()
+		}
+	case "X509 CRL":
+		if crl, err := x509.ParseRevocationList(b.Bytes); err == nil {
+			return fmt.Sprintf("issuer: %s, %d revoked", crl.Issuer, len(crl.RevokedCertificates))
+		}
+	case "PUBLIC KEY":
+		if pub, err := x509.ParsePKIXPublicKey(b.Bytes); err == nil {
+			return publicKeyName(pub)
+		}
+	case "RSA PUBLIC KEY":
+		if pub, err := x509.ParsePKCS1PublicKey(b.Bytes); err == nil {
+			return publicKeyName(pub)
+		}
+	}
+	if strings.HasSuffix(b.Type, "PRIVATE KEY") && b.Headers["Proc-Type"] != "" {
+		return "encrypted (" + b.Headers["DEK-Info"] + ")"
+	}
+	return ""
+}
+
+func publicKeyName(pub interface{}) string {
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	}
+	return ""
+}
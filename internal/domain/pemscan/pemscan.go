@@ -0,0 +1,293 @@
+package pemscan
+
+import (
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// 无法组成完整 PEM 块的情况
+const (
+	// WarningMissingEnd BEGIN 行之后（下一个 BEGIN 之前）没有 END 行
+	WarningMissingEnd = "missing_end"
+	// WarningMismatchedEnd END 行的类型与 BEGIN 行不一致
+	WarningMismatchedEnd = "mismatched_end"
+	// WarningUnmatchedEnd 没有对应 BEGIN 行的 END 行
+	WarningUnmatchedEnd = "unmatched_end"
+)
+
+var (
+	// 粘贴时破折号数量和两侧空格常被改动，这里放宽匹配；类型限定为大写字母、数字和空格
+	beginRe = regexp.MustCompile(`-{3,}[ \t]*BEGIN ([A-Z0-9][A-Z0-9 ]*?)[ \t]*-{3,}`)
+	endRe   = regexp.MustCompile(`-{3,}[ \t]*END ([A-Z0-9][A-Z0-9 ]*?)[ \t]*-{3,}`)
+	// concatRe 代码里拼接字符串的 + 号（"..." + 或行首的 + "..."），不能当作 base64 内容
+	concatRe = regexp.MustCompile("[\"'`][ \t]*\\+[ \t]*([\"'`]|$)|^[ \t]*\\+[ \t]*[\"'`]")
+)
+
+// Block 输入中找到的一个 PEM 块
+type Block struct {
+	// Type BEGIN 行中的类型，多余空格已合并
+	Type    string
+	Headers map[string]string
+	// Bytes base64 解码后的内容，解码失败时为 nil
+	Bytes []byte
+	// PEM 规范化后的 PEM：64 列换行、以 \n 结尾；解码失败时按清理后的 base64 原样排版
+	PEM string
+	// Offset / End 块在原始输入中的字节范围 [Offset, End)
+	Offset int
+	End    int
+	// Line / Column BEGIN 行在原始输入中的位置（从 1 开始，列按字节计算）
+	Line   int
+	Column int
+	// Error base64 解码失败等问题，为空表示内容可用
+	Error string
+
+	body string
+}
+
+// Empty BEGIN 和 END 之间没有任何 base64 内容
+func (b Block) Empty() bool {
+	return b.body == ""
+}
+
+// As 返回换成 typ 类型后的块，用于把 X509 CERTIFICATE、NEW CERTIFICATE REQUEST 等旧名称统一
+func (b Block) As(typ string) Block {
+	b.Type = typ
+	b.PEM = encode(typ, b.Headers, b.Bytes, b.body)
+	return b
+}
+
+// Warning 无法组成完整 PEM 块的问题
+type Warning struct {
+	// Kind 见 WarningMissingEnd 等常量
+	Kind string
+	// Type 出问题的 BEGIN 行类型；WarningUnmatchedEnd 时为 END 行类型
+	Type    string
+	Offset  int
+	Line    int
+	Column  int
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Message)
+}
+
+// Result 扫描结果，Blocks 按在输入中出现的顺序排列
+type Result struct {
+	Blocks   []Block
+	Warnings []Warning
+}
+
+// Find 返回类型属于 types 的块，types 为空时返回全部
+func (r Result) Find(types ...string) []Block {
+	if len(types) == 0 {
+		return r.Blocks
+	}
+	var out []Block
+	for _, b := range r.Blocks {
+		for _, t := range types {
+			if b.Type == t {
+				out = append(out, b)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Types 按出现顺序返回找到的块类型（去重）
+func (r Result) Types() []string {
+	var types []string
+	seen := map[string]bool{}
+	for _, b := range r.Blocks {
+		if !seen[b.Type] {
+			seen[b.Type] = true
+			types = append(types, b.Type)
+		}
+	}
+	return types
+}
+
+// WarningsFor 返回与 types 中任一类型有关的提示
+func (r Result) WarningsFor(types ...string) []Warning {
+	var out []Warning
+	for _, w := range r.Warnings {
+		for _, t := range types {
+			if w.Type == t {
+				out = append(out, w)
+				break
+			}
+		}
+	}
+	return out
+}
+
+// Scan 在杂乱的输入中查找所有 PEM 块，类型不限。
+// 输入可以来自 JSON 日志（\n、\r\n、\/、+ 及其双重转义）、带 > 引用前缀的邮件、
+// XML / HTML 实体（&#10;、&#43;）、<br> 标签、YAML 块标量或 URL 编码（%2B、%0A）；
+// BEGIN 与 END 之间除 RFC 1421 头部外只保留 base64 字符，其余装饰一律忽略
+func Scan(input string) Result {
+	text, offsets := unescape(input)
+	res := Result{Blocks: []Block{}, Warnings: []Warning{}}
+
+	begins := beginRe.FindAllStringSubmatchIndex(text, -1)
+	ends := endRe.FindAllStringSubmatchIndex(text, -1)
+	usedEnd := make([]bool, len(ends))
+
+	for i, m := range begins {
+		typ := normalizeType(text[m[2]:m[3]])
+		limit := len(text)
+		if i+1 < len(begins) {
+			limit = begins[i+1][0]
+		}
+
+		endIdx := -1
+		for j, e := range ends {
+			if !usedEnd[j] && e[0] >= m[1] && e[1] <= limit {
+				endIdx = j
+				break
+			}
+		}
+		if endIdx == -1 {
+			res.Warnings = append(res.Warnings, newWarning(input, offsets[m[0]], WarningMissingEnd, typ,
+				fmt.Sprintf("BEGIN %s has no matching END line", typ)))
+			continue
+		}
+		usedEnd[endIdx] = true
+		e := ends[endIdx]
+		if endType := normalizeType(text[e[2]:e[3]]); endType != typ {
+			res.Warnings = append(res.Warnings, newWarning(input, offsets[m[0]], WarningMismatchedEnd, typ,
+				fmt.Sprintf("BEGIN %s ends with END %s", typ, endType)))
+			continue
+		}
+
+		b := newBlock(typ, text[m[1]:e[0]])
+		b.Offset, b.End = offsets[m[0]], offsets[e[1]]
+		b.Line, b.Column = position(input, b.Offset)
+		res.Blocks = append(res.Blocks, b)
+	}
+
+	for j, e := range ends {
+		if !usedEnd[j] {
+			typ := normalizeType(text[e[2]:e[3]])
+			res.Warnings = append(res.Warnings, newWarning(input, offsets[e[0]], WarningUnmatchedEnd, typ,
+				fmt.Sprintf("END %s has no matching BEGIN line", typ)))
+		}
+	}
+	return res
+}
+
+// Decode 把不带 BEGIN / END 行的 base64 内容按 typ 排版成 PEM，清理规则与 Scan 相同
+func Decode(typ, input string) Block {
+	text, _ := unescape(input)
+	b := newBlock(typ, text)
+	b.Line, b.Column, b.End = 1, 1, len(input)
+	return b
+}
+
+// newBlock 从 BEGIN 与 END 之间的内容构造 Block
+func newBlock(typ, body string) Block {
+	b := Block{Type: typ}
+	b.Headers, b.body = parseBody(body)
+	switch {
+	case b.body == "":
+		b.Error = "PEM body is empty"
+	default:
+		data, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(b.body, "="))
+		if err != nil {
+			b.Error = "invalid base64: " + err.Error()
+		} else {
+			b.Bytes = data
+		}
+	}
+	b.PEM = encode(typ, b.Headers, b.Bytes, b.body)
+	return b
+}
+
+// parseBody 拆出 RFC 1421 头部（如 Proc-Type、DEK-Info）和 base64 内容。
+// base64 中不会出现冒号，含冒号的行在内容开始前视为头部，之后视为噪音丢弃
+func parseBody(body string) (map[string]string, string) {
+	var headers map[string]string
+	var b strings.Builder
+	for _, line := range strings.Split(body, "\n") {
+		line = concatRe.ReplaceAllString(line, "")
+		if i := strings.IndexByte(line, ':'); i >= 0 {
+			if b.Len() > 0 {
+				continue
+			}
+			key := strings.TrimFunc(line[:i], func(r rune) bool { return !isHeaderKeyChar(r) })
+			if key == "" {
+				continue
+			}
+			if headers == nil {
+				headers = map[string]string{}
+			}
+			headers[key] = strings.Trim(line[i+1:], " \t\"'`,;\\")
+			continue
+		}
+		for j := 0; j < len(line); j++ {
+			if isBase64Char(line[j]) {
+				b.WriteByte(line[j])
+			}
+		}
+	}
+	return headers, b.String()
+}
+
+// encode 输出规范 PEM；内容无法解码时保留清理后的 base64，交给调用方报错
+func encode(typ string, headers map[string]string, data []byte, body string) string {
+	if data != nil {
+		if out := pem.EncodeToMemory(&pem.Block{Type: typ, Headers: headers, Bytes: data}); out != nil {
+			return string(out)
+		}
+	}
+	var b strings.Builder
+	b.WriteString("-----BEGIN " + typ + "-----\n")
+	keys := make([]string, 0, len(headers))
+	for k := range headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.WriteString(k + ": " + headers[k] + "\n")
+	}
+	if len(headers) > 0 {
+		b.WriteString("\n")
+	}
+	for i := 0; i < len(body); i += 64 {
+		end := i + 64
+		if end > len(body) {
+			end = len(body)
+		}
+		b.WriteString(body[i:end] + "\n")
+	}
+	b.WriteString("-----END " + typ + "-----\n")
+	return b.String()
+}
+
+func newWarning(input string, offset int, kind, typ, msg string) Warning {
+	line, col := position(input, offset)
+	return Warning{Kind: kind, Type: typ, Offset: offset, Line: line, Column: col, Message: msg}
+}
+
+// position 计算 offset 在原始输入中的行列，只认真实换行
+func position(input string, offset int) (int, int) {
+	before := input[:offset]
+	return strings.Count(before, "\n") + 1, offset - strings.LastIndexByte(before, '\n')
+}
+
+func normalizeType(t string) string {
+	return strings.Join(strings.Fields(t), " ")
+}
+
+func isBase64Char(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '+' || c == '/' || c == '='
+}
+
+func isHeaderKeyChar(r rune) bool {
+	return r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-'
+}
@@ -0,0 +1,218 @@
+package pemscan
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertDER(t *testing.T) []byte {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pemscan.example.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return der
+}
+
+func TestScanMessyInput(t *testing.T) {
+	der := newTestCertDER(t)
+	certPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	lines := strings.Split(strings.TrimSuffix(certPEM, "\n"), "\n")
+	prefixed := func(prefix, sep string) string {
+		return prefix + strings.Join(lines, sep+prefix)
+	}
+
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"plain", certPEM},
+		{"json escaped", `{"cert":"` + strings.ReplaceAll(certPEM, "\n", `\n`) + `"}`},
+		{"json double escaped", `{"msg":"{\"cert\":\"` + strings.ReplaceAll(strings.ReplaceAll(certPEM, "/", `\/`), "\n", `\\n`) + `\"}"}`},
+		{"json unicode escapes", strings.NewReplacer("\n", `\r\n`, "+", `\u002B`, "/", `\u002f`, "=", `\u003D`).Replace(certPEM)},
+		{"email quoted", "On Monday someone wrote:\n" + prefixed("> > ", "\n") + "\n> thanks"},
+		{"xml entities", "<cert>" + strings.NewReplacer("\n", "&#13;&#10;", "+", "&#43;", "/", "&#x2F;").Replace(certPEM) + "</cert>"},
+		{"xml double escaped", strings.NewReplacer("\n", "&amp;#10;", "+", "&amp;#x2B;").Replace(certPEM)},
+		{"yaml block scalar", "tls:\n  cert: |\n" + prefixed("    ", "\n") + "\n  key: redacted\n"},
+		{"html br", "<p>" + prefixed("", "<br />\n") + "</p>"},
+		{"url encoded", strings.NewReplacer("\n", "%0A", "+", "%2B", "/", "%2F", "=", "%3D", " ", "%20").Replace(certPEM)},
+		{"quoted printable", strings.NewReplacer("=", "=3D").Replace(certPEM)},
+		{"string concatenation", prefixed(`"`, `\n" +`+"\n") + `"`},
+		{"single line", strings.Join(lines, " ")},
+		{"mangled dashes", strings.NewReplacer("-----BEGIN CERTIFICATE-----", "----BEGIN CERTIFICATE-----", "-----END CERTIFICATE-----", "----- END CERTIFICATE ----").Replace(certPEM)},
+		{"crlf and tabs", strings.ReplaceAll(prefixed("\t", "\r\n"), "\t", "\t  ")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Scan(tt.in)
+			if len(res.Blocks) != 1 || len(res.Warnings) != 0 {
+				t.Fatalf("blocks = %d warnings = %v", len(res.Blocks), res.Warnings)
+			}
+			b := res.Blocks[0]
+			if b.Type != "CERTIFICATE" || b.Error != "" || !bytes.Equal(b.Bytes, der) {
+				t.Fatalf("block type = %q error = %q", b.Type, b.Error)
+			}
+			if b.PEM != certPEM {
+				t.Errorf("PEM = %q, want %q", b.PEM, certPEM)
+			}
+		})
+	}
+}
+
+func TestScanMixedTypes(t *testing.T) {
+	der := newTestCertDER(t)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	key := pem.EncodeToMemory(&pem.Block{
+		Type:    "RSA PRIVATE KEY",
+		Headers: map[string]string{"Proc-Type": "4,ENCRYPTED", "DEK-Info": "AES-256-CBC,00112233445566778899AABBCCDDEEFF"},
+		Bytes:   []byte("encrypted key bytes"),
+	})
+	crl := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: []byte("crl")})
+	in := "server.crt:\n" + string(cert) + "\nserver.key:\n" + string(key) + "\r\n" + string(crl)
+
+	res := Scan(in)
+	if len(res.Blocks) != 3 || len(res.Warnings) != 0 {
+		t.Fatalf("blocks = %d warnings = %v", len(res.Blocks), res.Warnings)
+	}
+	want := []struct {
+		typ  string
+		line int
+		pem  []byte
+	}{
+		{"CERTIFICATE", 2, cert},
+		{"RSA PRIVATE KEY", bytes.Count(cert, []byte("\n")) + 4, key},
+		{"X509 CRL", bytes.Count(cert, []byte("\n")) + bytes.Count(key, []byte("\n")) + 5, crl},
+	}
+	for i, w := range want {
+		b := res.Blocks[i]
+		if b.Type != w.typ || b.Line != w.line || b.Column != 1 || b.PEM != string(w.pem) {
+			t.Errorf("block %d = %q line %d col %d pem %q", i, b.Type, b.Line, b.Column, b.PEM)
+		}
+		if got := in[b.Offset:b.End]; !strings.HasPrefix(got, "-----BEGIN "+w.typ) || !strings.HasSuffix(got, "-----END "+w.typ+"-----") {
+			t.Errorf("block %d range = %q", i, got)
+		}
+	}
+	if h := res.Blocks[1].Headers; h["Proc-Type"] != "4,ENCRYPTED" || len(h) != 2 {
+		t.Errorf("headers = %v", h)
+	}
+	if got := res.Find("X509 CRL", "CERTIFICATE"); len(got) != 2 || got[0].Type != "CERTIFICATE" {
+		t.Errorf("Find() = %d blocks", len(got))
+	}
+}
+
+func TestScanProblems(t *testing.T) {
+	der := newTestCertDER(t)
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	t.Run("missing end", func(t *testing.T) {
+		in := "-----BEGIN CERTIFICATE-----\nMIIB\n" + cert
+		res := Scan(in)
+		if len(res.Blocks) != 1 || res.Blocks[0].Line != 3 {
+			t.Fatalf("blocks = %+v", res.Blocks)
+		}
+		w := res.WarningsFor("CERTIFICATE")
+		if len(w) != 1 || w[0].Kind != WarningMissingEnd || w[0].Line != 1 {
+			t.Errorf("warnings = %+v", res.Warnings)
+		}
+	})
+
+	t.Run("mismatched and unmatched end", func(t *testing.T) {
+		in := "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE REQUEST-----\n-----END PRIVATE KEY-----"
+		res := Scan(in)
+		if len(res.Blocks) != 0 || len(res.Warnings) != 2 {
+			t.Fatalf("blocks = %d warnings = %+v", len(res.Blocks), res.Warnings)
+		}
+		if res.Warnings[0].Kind != WarningMismatchedEnd || res.Warnings[1].Kind != WarningUnmatchedEnd || res.Warnings[1].Line != 4 {
+			t.Errorf("warnings = %+v", res.Warnings)
+		}
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		res := Scan("-----BEGIN CERTIFICATE-----\nMIIBx\n-----END CERTIFICATE-----")
+		if len(res.Blocks) != 1 || res.Blocks[0].Error == "" || res.Blocks[0].Bytes != nil {
+			t.Fatalf("blocks = %+v", res.Blocks)
+		}
+		if want := "-----BEGIN CERTIFICATE-----\nMIIBx\n-----END CERTIFICATE-----\n"; res.Blocks[0].PEM != want {
+			t.Errorf("PEM = %q", res.Blocks[0].PEM)
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		res := Scan("-----BEGIN CERTIFICATE-----\n-----END CERTIFICATE-----")
+		if len(res.Blocks) != 1 || !res.Blocks[0].Empty() || res.Blocks[0].Error == "" {
+			t.Fatalf("blocks = %+v", res.Blocks)
+		}
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		res := Scan("just some text")
+		if len(res.Blocks) != 0 || len(res.Warnings) != 0 {
+			t.Errorf("result = %+v", res)
+		}
+	})
+}
+
+func TestDecodeAndAs(t *testing.T) {
+	der := newTestCertDER(t)
+	body := strings.NewReplacer("\n", `\r\n`).Replace(string(pem.EncodeToMemory(&pem.Block{Type: "X", Bytes: der})))
+	body = body[strings.Index(body, `\r\n`)+4 : strings.Index(body, "-----END")]
+
+	b := Decode("CERTIFICATE", body)
+	if b.Error != "" || !bytes.Equal(b.Bytes, der) {
+		t.Fatalf("Decode() error = %q", b.Error)
+	}
+
+	res := Scan("-----BEGIN X509 CERTIFICATE-----\n" + body + "\n-----END X509 CERTIFICATE-----")
+	if len(res.Blocks) != 1 {
+		t.Fatalf("blocks = %d", len(res.Blocks))
+	}
+	got := res.Blocks[0].As("CERTIFICATE")
+	if got.PEM != string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) {
+		t.Errorf("As() PEM = %q", got.PEM)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	der := newTestCertDER(t)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatalf("parse certificate: %v", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(cert.PublicKey)
+	if err != nil {
+		t.Fatalf("marshal public key: %v", err)
+	}
+	in := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "PGP SIGNATURE", Bytes: []byte("sig")}))
+
+	want := []string{"subject: CN=pemscan.example.test, issuer: CN=pemscan.example.test", "ECDSA P-256", ""}
+	res := Scan(in)
+	if len(res.Blocks) != len(want) {
+		t.Fatalf("blocks = %d", len(res.Blocks))
+	}
+	for i, b := range res.Blocks {
+		if got := Describe(b); got != want[i] {
+			t.Errorf("Describe(%s) = %q, want %q", b.Type, got, want[i])
+		}
+	}
+}
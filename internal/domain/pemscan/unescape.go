@@ -0,0 +1,165 @@
+package pemscan
+
+import (
+	"html"
+	"strconv"
+	"strings"
+)
+
+// backslashEscapes JSON / 代码字符串中的转义，双重转义（JSON 里再嵌一层 JSON）排在前面优先匹配
+var backslashEscapes = []struct{ from, to string }{
+	{`\\r\\n`, "\n"},
+	{`\\n`, "\n"},
+	{`\\r`, "\n"},
+	{`\r\n`, "\n"},
+	{`\n`, "\n"},
+	{`\r`, "\n"},
+	{`\t`, " "},
+	{`\/`, "/"},
+}
+
+// percentEscapes URL 编码中会破坏 base64 内容的字符
+var percentEscapes = map[string]string{
+	"0A": "\n",
+	"0D": "\n",
+	"20": " ",
+	"2B": "+",
+	"2F": "/",
+	"3D": "=",
+}
+
+// lineBreakTags 渲染为换行的 HTML 标签，其他标签直接去掉
+var lineBreakTags = map[string]bool{
+	"br": true, "p": true, "div": true, "li": true, "tr": true, "pre": true, "hr": true,
+}
+
+// unescape 还原输入中的各类转义；offsets[i] 为结果第 i 个字节在原始输入中的位置，
+// 末尾多一个 len(input) 方便计算结束位置
+func unescape(input string) (string, []int) {
+	var b strings.Builder
+	b.Grow(len(input))
+	offsets := make([]int, 0, len(input)+1)
+	for i := 0; i < len(input); {
+		s, n := decodeToken(input[i:])
+		if n == 0 {
+			s, n = input[i:i+1], 1
+		}
+		b.WriteString(s)
+		for j := 0; j < len(s); j++ {
+			offsets = append(offsets, i)
+		}
+		i += n
+	}
+	return b.String(), append(offsets, len(input))
+}
+
+// decodeToken 尝试在 s 开头解码一个转义，返回替换内容和消耗的字节数；不是转义时返回 0
+func decodeToken(s string) (string, int) {
+	switch s[0] {
+	case '\\':
+		return decodeBackslash(s)
+	case '\r':
+		if strings.HasPrefix(s, "\r\n") {
+			return "\n", 2
+		}
+		return "\n", 1
+	case '&':
+		return decodeEntity(s)
+	case '<':
+		return decodeTag(s)
+	case '=':
+		return decodeQuotedPrintable(s)
+	case '%':
+		if len(s) >= 3 {
+			if to, ok := percentEscapes[strings.ToUpper(s[1:3])]; ok {
+				return to, 3
+			}
+		}
+	}
+	return "", 0
+}
+
+func decodeBackslash(s string) (string, int) {
+	for _, e := range backslashEscapes {
+		if strings.HasPrefix(s, e.from) {
+			return e.to, len(e.from)
+		}
+	}
+	// +、= 等 Unicode 转义，兼容双重转义 \\u002B
+	u := s
+	if strings.HasPrefix(u, `\\u`) {
+		u = u[1:]
+	}
+	skip := len(s) - len(u)
+	if strings.HasPrefix(u, `\u`) && len(u) >= 6 {
+		if r, err := strconv.ParseUint(u[2:6], 16, 32); err == nil {
+			if r == '\r' {
+				r = '\n'
+			}
+			return string(rune(r)), skip + 6
+		}
+	}
+	return "", 0
+}
+
+// decodeQuotedPrintable 还原邮件 quoted-printable 编码中的 =3D 和软换行（行尾的 =）；
+// 行尾的 = 也可能是 base64 填充，去掉不影响解码
+func decodeQuotedPrintable(s string) (string, int) {
+	switch {
+	case len(s) >= 3 && strings.EqualFold(s[:3], "=3D"):
+		return "=", 3
+	case strings.HasPrefix(s, "=\r\n"):
+		return "", 3
+	case strings.HasPrefix(s, "=\n"):
+		return "", 2
+	}
+	return "", 0
+}
+
+// decodeEntity 解码 &#10;、&#x2B;、&amp; 等 XML / HTML 实体，双重转义（&amp;#10;）一并还原
+func decodeEntity(s string) (string, int) {
+	end := strings.IndexByte(s, ';')
+	if end == -1 || end > 12 {
+		return "", 0
+	}
+	tok := s[:end+1]
+	dec := html.UnescapeString(tok)
+	if dec == tok {
+		return "", 0
+	}
+	if dec == "&" {
+		// &amp;#10; 中的 & 与后面的内容组成下一层实体
+		if inner, n := decodeEntity("&" + s[end+1:]); n > 0 {
+			return inner, end + n
+		}
+	}
+	dec = strings.NewReplacer("\r\n", "\n", "\r", "\n", " ", " ").Replace(dec)
+	return dec, end + 1
+}
+
+// decodeTag 去掉 HTML 标签，<br>、<p> 等换行标签替换为换行
+func decodeTag(s string) (string, int) {
+	if len(s) < 3 {
+		return "", 0
+	}
+	name := strings.TrimPrefix(s[1:], "/")
+	if name == "" || !isLetter(name[0]) {
+		return "", 0
+	}
+	end := strings.IndexByte(s, '>')
+	if end == -1 || end > 128 || strings.ContainsAny(s[1:end], "<\n") {
+		return "", 0
+	}
+	n := 0
+	for n < len(name) && isLetter(name[n]) {
+		n++
+	}
+	if lineBreakTags[strings.ToLower(name[:n])] {
+		return "\n", end + 1
+	}
+	return "", end + 1
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,136 @@
+package castore
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrNotInitialized 尚未创建测试 CA
+var ErrNotInitialized = errors.New("test ca is not initialized")
+
+// Issued 签发日志中的一条记录
+type Issued struct {
+	SerialNumber string    `json:"serialNumber"`
+	SerialHex    string    `json:"serialHex"`
+	Subject      string    `json:"subject"`
+	SANs         []string  `json:"sans"`
+	NotBefore    time.Time `json:"notBefore"`
+	NotAfter     time.Time `json:"notAfter"`
+	IssuedAt     time.Time `json:"issuedAt"`
+	PEM          string    `json:"pem"`
+
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+}
+
+// State 测试 CA 的全部状态：CA 证书和私钥、CRL 编号以及签发日志
+type State struct {
+	RootCert         string    `json:"rootCert"`
+	RootKey          string    `json:"rootKey"`
+	IntermediateCert string    `json:"intermediateCert"`
+	IntermediateKey  string    `json:"intermediateKey"`
+	CreatedAt        time.Time `json:"createdAt"`
+	// CRLNumber 上一次生成的 CRL 编号
+	CRLNumber int64 `json:"crlNumber"`
+	// CRL 上一次生成的 CRL（DER），吊销记录变化后清空
+	CRL    []byte   `json:"crl,omitempty"`
+	Issued []Issued `json:"issued"`
+}
+
+// Store 以单个 JSON 文件保存测试 CA；私钥不加密，文件权限为 0600，只应用于测试环境
+type Store struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func NewStore() *Store {
+	path := os.Getenv("MYTOOLS_CA_FILE")
+	if path == "" {
+		path = filepath.Join("data", "test_ca.json")
+	}
+	return &Store{Path: path}
+}
+
+// Load 读取 CA 状态；尚未创建 CA 时返回 nil
+func (s *Store) Load() (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Save 覆盖保存 CA 状态
+func (s *Store) Save(st State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.save(st)
+}
+
+// Update 在锁内读取、修改并保存 CA 状态；尚未创建 CA 时返回错误
+func (s *Store) Update(fn func(st *State) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st, err := s.load()
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		return ErrNotInitialized
+	}
+	if err := fn(st); err != nil {
+		return err
+	}
+	return s.save(*st)
+}
+
+func (s *Store) load() (*State, error) {
+	if s.Path == "" {
+		return nil, errors.New("ca store path is empty")
+	}
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var st State
+	if err := json.Unmarshal(b, &st); err != nil {
+		return nil, errors.New("ca file is corrupted: " + err.Error())
+	}
+	if st.Issued == nil {
+		st.Issued = []Issued{}
+	}
+	return &st, nil
+}
+
+func (s *Store) save(st State) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// CreateTemp 创建的文件权限为 0600
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
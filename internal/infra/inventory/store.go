@@ -0,0 +1,124 @@
+package inventory
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Record 清单文件中的一条记录
+type Record struct {
+	ID      string    `json:"id"`
+	Label   string    `json:"label"`
+	PEM     string    `json:"pem"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+// Store 以单个 JSON 文件保存证书清单，写入时先写临时文件再重命名，避免中途崩溃损坏文件
+type Store struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+func NewStore() *Store {
+	path := os.Getenv("MYTOOLS_INVENTORY_FILE")
+	if path == "" {
+		path = filepath.Join("data", "cert_inventory.json")
+	}
+	return &Store{Path: path}
+}
+
+// List 返回全部记录，按添加时间排序；文件不存在时返回空列表
+func (s *Store) List() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.load()
+}
+
+// Put 新增记录，ID 已存在时保留原添加时间，标签为空时也保留原标签
+func (s *Store) Put(r Record) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return Record{}, err
+	}
+	for i, old := range records {
+		if old.ID == r.ID {
+			r.AddedAt = old.AddedAt
+			if r.Label == "" {
+				r.Label = old.Label
+			}
+			records[i] = r
+			return r, s.save(records)
+		}
+	}
+	return r, s.save(append(records, r))
+}
+
+// Delete 删除记录，返回记录是否存在
+func (s *Store) Delete(id string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.load()
+	if err != nil {
+		return false, err
+	}
+	for i, r := range records {
+		if r.ID == id {
+			return true, s.save(append(records[:i], records[i+1:]...))
+		}
+	}
+	return false, nil
+}
+
+func (s *Store) load() ([]Record, error) {
+	if s.Path == "" {
+		return nil, errors.New("inventory path is empty")
+	}
+	b, err := os.ReadFile(s.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := []Record{}
+	if err := json.Unmarshal(b, &records); err != nil {
+		return nil, errors.New("inventory file is corrupted: " + err.Error())
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].AddedAt.Before(records[j].AddedAt) })
+	return records, nil
+}
+
+func (s *Store) save(records []Record) error {
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0o755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.Path), filepath.Base(s.Path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.Path)
+}
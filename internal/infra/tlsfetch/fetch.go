@@ -0,0 +1,166 @@
+package tlsfetch
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 支持的 STARTTLS 协议
+const (
+	StartTLSNone = ""
+	StartTLSSMTP = "smtp"
+	StartTLSIMAP = "imap"
+	StartTLSPOP3 = "pop3"
+)
+
+const (
+	DefaultTimeout = 10 * time.Second
+	MaxTimeout     = 30 * time.Second
+)
+
+// ErrInvalidOptions 地址、STARTTLS 协议等参数不正确
+var ErrInvalidOptions = errors.New("invalid fetch options")
+
+// defaultPorts 地址不带端口时按 STARTTLS 协议选择默认端口
+var defaultPorts = map[string]string{
+	StartTLSNone: "443",
+	StartTLSSMTP: "25",
+	StartTLSIMAP: "143",
+	StartTLSPOP3: "110",
+}
+
+// tlsVersionNames crypto/tls 在 Go 1.21 之前没有 VersionName
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS 1.0",
+	tls.VersionTLS11: "TLS 1.1",
+	tls.VersionTLS12: "TLS 1.2",
+	tls.VersionTLS13: "TLS 1.3",
+}
+
+// Options 获取证书链的参数
+type Options struct {
+	// Address host 或 host:port，IPv6 地址需要加方括号
+	Address string
+	// ServerName SNI，为空时使用 Address 中的主机名（IP 地址不发送 SNI）
+	ServerName string
+	// StartTLS 取值 smtp / imap / pop3，为空时直接进行 TLS 握手
+	StartTLS string
+	// Timeout 连接、STARTTLS 协商和握手的总超时，零值使用 DefaultTimeout
+	Timeout time.Duration
+}
+
+// Result TLS 握手结果
+type Result struct {
+	// Address 实际连接的 host:port
+	Address    string
+	ServerName string
+	StartTLS   string
+	// Protocol 协商的协议版本，例如 TLS 1.3
+	Protocol    string
+	CipherSuite string
+	// ALPN 协商的应用层协议，未协商时为空
+	ALPN string
+	// Certificates 服务端发送的证书，保持原始顺序
+	Certificates []*x509.Certificate
+	// OCSPResponse 服务端装订的 OCSP 响应（DER），没有时为 nil
+	OCSPResponse []byte
+}
+
+// Fetch 连接 Address 并完成 TLS 握手，返回服务端发送的证书链；不校验证书，校验交给调用方
+func Fetch(ctx context.Context, opts Options) (Result, error) {
+	opts.StartTLS = strings.ToLower(strings.TrimSpace(opts.StartTLS))
+	if _, ok := defaultPorts[opts.StartTLS]; !ok {
+		return Result{}, fmt.Errorf("%w: unsupported starttls protocol %q", ErrInvalidOptions, opts.StartTLS)
+	}
+	addr, host, err := normalizeAddress(opts.Address, defaultPorts[opts.StartTLS])
+	if err != nil {
+		return Result{}, err
+	}
+	serverName := strings.TrimSpace(opts.ServerName)
+	if serverName == "" && net.ParseIP(host) == nil {
+		serverName = host
+	}
+
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if timeout > MaxTimeout {
+		timeout = MaxTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return Result{}, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if err := startTLS(conn, opts.StartTLS); err != nil {
+		return Result{}, fmt.Errorf("starttls %s: %w", opts.StartTLS, err)
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName: serverName,
+		// 只获取证书，不在握手阶段校验，过期或自签名的证书同样需要展示
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return Result{}, fmt.Errorf("tls handshake: %w", err)
+	}
+	state := tlsConn.ConnectionState()
+
+	return Result{
+		Address:      addr,
+		ServerName:   serverName,
+		StartTLS:     opts.StartTLS,
+		Protocol:     versionName(state.Version),
+		CipherSuite:  tls.CipherSuiteName(state.CipherSuite),
+		ALPN:         state.NegotiatedProtocol,
+		Certificates: state.PeerCertificates,
+		OCSPResponse: state.OCSPResponse,
+	}, nil
+}
+
+// normalizeAddress 补全默认端口，返回 host:port 和主机名
+func normalizeAddress(address, defaultPort string) (string, string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", "", fmt.Errorf("%w: address is required", ErrInvalidOptions)
+	}
+
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		// 没有端口：example.com、[::1]、::1
+		host, port = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]"), defaultPort
+	}
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", "", fmt.Errorf("%w: invalid address %q", ErrInvalidOptions, address)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", "", fmt.Errorf("%w: invalid port %q", ErrInvalidOptions, port)
+	}
+	return net.JoinHostPort(host, port), host, nil
+}
+
+func versionName(v uint16) string {
+	if name, ok := tlsVersionNames[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%04x", v)
+}
@@ -0,0 +1,248 @@
+package tlsfetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestCertificate(t *testing.T) tls.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "fetch.example.test"},
+		DNSNames:     []string{"fetch.example.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("create certificate: %v", err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		OCSPStaple:  []byte("test ocsp staple"),
+	}
+}
+
+func TestFetch(t *testing.T) {
+	cert := newTestCertificate(t)
+	var gotSNI string
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   []string{"http/1.1"},
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			gotSNI = hello.ServerName
+			return nil, nil
+		},
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	addr := srv.Listener.Addr().String()
+
+	t.Run("server name", func(t *testing.T) {
+		res, err := Fetch(context.Background(), Options{Address: addr, ServerName: "fetch.example.test"})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if gotSNI != "fetch.example.test" || res.ServerName != "fetch.example.test" {
+			t.Errorf("sni = %q, result server name = %q", gotSNI, res.ServerName)
+		}
+		if len(res.Certificates) != 1 || !bytes.Equal(res.Certificates[0].Raw, cert.Certificate[0]) {
+			t.Fatalf("certificates = %d", len(res.Certificates))
+		}
+		if res.Protocol != "TLS 1.3" || res.CipherSuite == "" || res.Address != addr {
+			t.Errorf("result = %+v", res)
+		}
+		if string(res.OCSPResponse) != "test ocsp staple" {
+			t.Errorf("ocsp staple = %q", res.OCSPResponse)
+		}
+	})
+
+	t.Run("ip address sends no sni", func(t *testing.T) {
+		gotSNI = "unset"
+		res, err := Fetch(context.Background(), Options{Address: addr})
+		if err != nil {
+			t.Fatalf("Fetch() error = %v", err)
+		}
+		if gotSNI != "" || res.ServerName != "" {
+			t.Errorf("sni = %q, result server name = %q", gotSNI, res.ServerName)
+		}
+	})
+}
+
+// serveStartTLS 在本地监听一次连接：执行 script 描述的明文对话后升级为 TLS
+func serveStartTLS(t *testing.T, cert tls.Certificate, script func(w io.Writer, r *bufio.Reader) bool) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.SetDeadline(time.Now().Add(5 * time.Second))
+		if !script(conn, bufio.NewReader(conn)) {
+			return
+		}
+		tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{cert}}).Handshake()
+	}()
+	return ln.Addr().String()
+}
+
+// expectLine 读取一行客户端命令并与 want 比较
+func expectLine(r *bufio.Reader, want string) bool {
+	line, err := r.ReadString('\n')
+	return err == nil && strings.TrimRight(line, "\r\n") == want
+}
+
+func TestFetchStartTLS(t *testing.T) {
+	cert := newTestCertificate(t)
+
+	tests := []struct {
+		name     string
+		protocol string
+		script   func(w io.Writer, r *bufio.Reader) bool
+	}{
+		{
+			name:     "smtp",
+			protocol: StartTLSSMTP,
+			script: func(w io.Writer, r *bufio.Reader) bool {
+				io.WriteString(w, "220-mail.example.test ESMTP\r\n220 ready\r\n")
+				if !expectLine(r, "EHLO mytools.local") {
+					return false
+				}
+				io.WriteString(w, "250-mail.example.test\r\n250-PIPELINING\r\n250 STARTTLS\r\n")
+				if !expectLine(r, "STARTTLS") {
+					return false
+				}
+				io.WriteString(w, "220 go ahead\r\n")
+				return true
+			},
+		},
+		{
+			name:     "imap",
+			protocol: StartTLSIMAP,
+			script: func(w io.Writer, r *bufio.Reader) bool {
+				io.WriteString(w, "* OK [CAPABILITY IMAP4rev1 STARTTLS] ready\r\n")
+				if !expectLine(r, "a001 STARTTLS") {
+					return false
+				}
+				io.WriteString(w, "* CAPABILITY IMAP4rev1\r\na001 OK Begin TLS negotiation now\r\n")
+				return true
+			},
+		},
+		{
+			name:     "pop3",
+			protocol: StartTLSPOP3,
+			script: func(w io.Writer, r *bufio.Reader) bool {
+				io.WriteString(w, "+OK POP3 ready\r\n")
+				if !expectLine(r, "STLS") {
+					return false
+				}
+				io.WriteString(w, "+OK Begin TLS negotiation\r\n")
+				return true
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := serveStartTLS(t, cert, tt.script)
+			res, err := Fetch(context.Background(), Options{Address: addr, StartTLS: strings.ToUpper(tt.protocol)})
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if res.StartTLS != tt.protocol || len(res.Certificates) != 1 || !bytes.Equal(res.Certificates[0].Raw, cert.Certificate[0]) {
+				t.Errorf("result = %+v", res)
+			}
+		})
+	}
+
+	t.Run("starttls refused", func(t *testing.T) {
+		addr := serveStartTLS(t, cert, func(w io.Writer, r *bufio.Reader) bool {
+			io.WriteString(w, "220 ready\r\n")
+			expectLine(r, "EHLO mytools.local")
+			io.WriteString(w, "250 mail.example.test\r\n")
+			expectLine(r, "STARTTLS")
+			io.WriteString(w, "454 TLS not available\r\n")
+			return false
+		})
+		_, err := Fetch(context.Background(), Options{Address: addr, StartTLS: StartTLSSMTP})
+		if err == nil || !strings.Contains(err.Error(), "454") {
+			t.Errorf("Fetch() error = %v, want 454 reply", err)
+		}
+	})
+}
+
+func TestFetchInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"empty address", Options{}},
+		{"bad port", Options{Address: "example.test:99999"}},
+		{"url", Options{Address: "https://example.test/path"}},
+		{"unsupported starttls", Options{Address: "example.test", StartTLS: "ftp"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Fetch(context.Background(), tt.opts)
+			if !errors.Is(err, ErrInvalidOptions) {
+				t.Errorf("Fetch() error = %v, want ErrInvalidOptions", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	tests := []struct {
+		in       string
+		wantAddr string
+		wantHost string
+	}{
+		{"example.test", "example.test:443", "example.test"},
+		{" example.test:8443 ", "example.test:8443", "example.test"},
+		{"[::1]:993", "[::1]:993", "::1"},
+		{"[::1]", "[::1]:443", "::1"},
+		{"::1", "[::1]:443", "::1"},
+	}
+	for _, tt := range tests {
+		addr, host, err := normalizeAddress(tt.in, "443")
+		if err != nil {
+			t.Errorf("normalizeAddress(%q) error = %v", tt.in, err)
+			continue
+		}
+		if addr != tt.wantAddr || host != tt.wantHost {
+			t.Errorf("normalizeAddress(%q) = %q, %q, want %q, %q", tt.in, addr, host, tt.wantAddr, tt.wantHost)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package tlsfetch
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+)
+
+// maxResponseLines 单个响应最多读取的行数，防止服务端无限输出
+const maxResponseLines = 100
+
+// startTLS 在明文连接上协商升级为 TLS；protocol 为空时什么都不做
+func startTLS(conn net.Conn, protocol string) error {
+	// 升级前服务端不会发送多余的数据，握手前丢弃 bufio 缓冲区是安全的
+	r := bufio.NewReader(conn)
+	switch protocol {
+	case StartTLSNone:
+		return nil
+	case StartTLSSMTP:
+		return startTLSSMTP(conn, r)
+	case StartTLSIMAP:
+		return startTLSIMAP(conn, r)
+	case StartTLSPOP3:
+		return startTLSPOP3(conn, r)
+	}
+	return fmt.Errorf("unsupported protocol %q", protocol)
+}
+
+// startTLSSMTP RFC 3207
+func startTLSSMTP(w io.Writer, r *bufio.Reader) error {
+	if err := readSMTPReply(r, "220"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "EHLO mytools.local\r\n"); err != nil {
+		return err
+	}
+	if err := readSMTPReply(r, "250"); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	return readSMTPReply(r, "220")
+}
+
+// readSMTPReply 读取一个（可能多行的）SMTP 响应，并检查响应码
+func readSMTPReply(r *bufio.Reader, code string) error {
+	for i := 0; i < maxResponseLines; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return err
+		}
+		if len(line) < 3 || line[:3] != code {
+			return fmt.Errorf("unexpected reply %q", line)
+		}
+		// 多行响应的中间行为 "250-..."，最后一行为 "250 ..."
+		if len(line) == 3 || line[3] != '-' {
+			return nil
+		}
+	}
+	return errors.New("reply is too long")
+}
+
+// startTLSIMAP RFC 3501
+func startTLSIMAP(w io.Writer, r *bufio.Reader) error {
+	line, err := readLine(r)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "* OK") {
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+	if _, err := io.WriteString(w, "a001 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	for i := 0; i < maxResponseLines; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return err
+		}
+		// 跳过 untagged 响应
+		if strings.HasPrefix(line, "* ") {
+			continue
+		}
+		if strings.HasPrefix(line, "a001 OK") {
+			return nil
+		}
+		return fmt.Errorf("unexpected reply %q", line)
+	}
+	return errors.New("reply is too long")
+}
+
+// startTLSPOP3 RFC 2595
+func startTLSPOP3(w io.Writer, r *bufio.Reader) error {
+	line, err := readLine(r)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("unexpected greeting %q", line)
+	}
+	if _, err := io.WriteString(w, "STLS\r\n"); err != nil {
+		return err
+	}
+	line, err = readLine(r)
+	if err != nil {
+		return err
+	}
+	if !strings.HasPrefix(line, "+OK") {
+		return fmt.Errorf("unexpected reply %q", line)
+	}
+	return nil
+}
+
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
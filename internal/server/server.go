@@ -31,6 +31,18 @@ func New() *Server {
 	e.GET("/cert", func(c *gin.Context) {
 		c.File(filepath.Join(staticDir, "cert.html"))
 	})
+	// 测试 CA 页面
+	e.GET("/ca", func(c *gin.Context) {
+		c.File(filepath.Join(staticDir, "ca.html"))
+	})
+	// 密钥工具页面
+	e.GET("/key", func(c *gin.Context) {
+		c.File(filepath.Join(staticDir, "key.html"))
+	})
+	// ASN.1 查看器页面
+	e.GET("/asn1", func(c *gin.Context) {
+		c.File(filepath.Join(staticDir, "asn1.html"))
+	})
 	// JSON 格式化页面
 	e.GET("/json", func(c *gin.Context) {
 		c.File(filepath.Join(staticDir, "json.html"))
@@ -7,7 +7,10 @@ function renderNav(activePage) {
     { href: "/", label: "首页", page: "home" },
     { href: "/csr", label: "CSR 格式化", page: "csr" },
     { href: "/cert", label: "证书格式化", page: "cert" },
-    { href: "/json", label: "JSON 格式化", page: "json" }
+    { href: "/asn1", label: "ASN.1 查看", page: "asn1" },
+    { href: "/json", label: "JSON 格式化", page: "json" },
+    { href: "/key", label: "密钥工具", page: "key" },
+    { href: "/ca", label: "测试 CA", page: "ca" }
   ];
 
   const navEl = document.querySelector(".nav");
@@ -32,6 +35,19 @@ function setStatus(msg, type) {
   el.textContent = msg || "";
 }
 
+// appendASN1Link 在状态栏后追加"用 ASN.1 查看器打开"链接，解析失败时可以直接查看原始结构
+function appendASN1Link(statusId, text) {
+  const el = $(statusId);
+  if (!el || !text) return;
+  const a = document.createElement("a");
+  a.href = "/asn1";
+  a.target = "_blank";
+  a.textContent = "用 ASN.1 查看器打开";
+  a.style.marginLeft = "8px";
+  a.addEventListener("click", () => localStorage.setItem(asn1InputKey, text));
+  el.appendChild(a);
+}
+
 async function copyToClipboard(text) {
     if (!text) return false;
 
@@ -133,14 +149,302 @@ async function formatCSR() {
   }
 }
 
+function renderCSRInfo(info) {
+  const container = $("csrInfoContainer");
+  const el = $("csrInfo");
+
+  if (!container || !el) return;
+
+  if (!info) {
+    container.style.display = "none";
+    el.innerHTML = "";
+    return;
+  }
+
+  const f = info.fields || {};
+  const sections = [
+    {
+      title: "主题 (Subject)",
+      content: [
+        labeledList("CN", f.commonName ? [f.commonName] : []),
+        labeledList("O", f.organization),
+        labeledList("OU", f.organizationalUnit),
+        labeledList("L", f.locality),
+        labeledList("ST", f.province),
+        labeledList("C", f.country)
+      ].join("") || escapeHTML(info.subject || "N/A")
+    },
+    {
+      title: "使用者可选名称 (SAN)",
+      content: [
+        labeledList("DNS", info.dnsNames),
+        labeledList("IP", info.ipAddresses),
+        labeledList("Email", info.emailAddresses),
+        labeledList("URI", info.uris)
+      ].join("") || "N/A"
+    },
+    {
+      title: "密钥与签名",
+      content: `<div><span class="cert-info-label">公钥:</span>${escapeHTML(info.publicKeyAlgorithm || "N/A")} ${info.publicKeySize || ""}${info.publicKeyCurve ? " (" + escapeHTML(info.publicKeyCurve) + ")" : ""}</div><div><span class="cert-info-label">签名算法:</span>${escapeHTML(info.signatureAlgorithm || "N/A")}</div><div><span class="cert-info-label">签名校验:</span>${info.signatureValid ? "有效" : "无效 " + escapeHTML(info.signatureError || "")}</div>`
+    },
+    {
+      title: "密钥用途",
+      content: [
+        labeledList("KeyUsage", info.keyUsage),
+        labeledList("ExtKeyUsage", info.extKeyUsage)
+      ].join("") || "N/A"
+    },
+    {
+      title: "请求的扩展",
+      content: (info.extensions || [])
+        .map(e => `<div><span class="cert-info-label">${escapeHTML(e.name)}:</span>${escapeHTML(e.oid)}${e.critical ? "（critical）" : ""}</div>`)
+        .join("") || "N/A"
+    },
+    {
+      title: "属性",
+      content: (info.attributes || [])
+        .map(a => `<div><span class="cert-info-label">${escapeHTML(a.name)}:</span>${escapeHTML((a.values || []).join(", "))}</div>`)
+        .join("") || "N/A"
+    }
+  ];
+
+  el.innerHTML = sections
+    .map(s => `<div class="cert-info-section"><div class="cert-info-title">${s.title}</div><div class="cert-info-content">${s.content}</div></div>`)
+    .join("");
+  container.style.display = "block";
+}
+
+async function parseCSR() {
+  const btn = $("btnParse");
+  const inEl = $("input");
+
+  if (!inEl) return;
+
+  setStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  renderCSRInfo(null);
+
+  const parsed = parseCSRFromInput(inEl.value);
+  if (parsed.err) {
+    setStatus(parsed.err, "err");
+    if (btn) btn.disabled = false;
+    return;
+  }
+
+  try {
+    const resp = await fetch("/api/v1/csr/parse", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ csr: parsed.csr })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setStatus(msg, "err");
+      appendASN1Link("status", inEl.value);
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderCSRInfo(data.data);
+    setStatus(data.data.signatureValid ? "解析完成" : "解析完成，但签名校验失败", data.data.signatureValid ? "ok" : "err");
+  } catch (e) {
+    setStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+const lintSeverityLabels = { error: "错误", warning: "警告", info: "提示" };
+
+function renderLintReports(res) {
+  const container = $("lintContainer");
+  const el = $("lintResult");
+  if (!container || !el) return;
+
+  if (!res) {
+    container.style.display = "none";
+    el.innerHTML = "";
+    return;
+  }
+
+  const reports = (res.certs || []).map((r, i) => ({ title: `证书 ${i + 1}${r.isCA ? "（CA）" : ""}`, report: r }));
+  if (res.csr) reports.push({ title: "CSR", report: res.csr });
+
+  el.innerHTML = reports.map(x => {
+    const r = x.report;
+    const findings = (r.findings || []).length === 0
+      ? `<div>未发现问题</div>`
+      : r.findings.map(f => `<div><span class="cert-info-label ${f.severity === "error" ? "expiry-expired" : f.severity === "warning" ? "expiry-warning" : ""}">[${lintSeverityLabels[f.severity] || escapeHTML(f.severity)}]</span>${escapeHTML(f.message)} <span class="small">(${escapeHTML(f.code)})</span></div>`).join("");
+    return `<div class="cert-info-section"><div class="cert-info-title">${x.title}：${escapeHTML(r.subject)}</div><div class="cert-info-content">${findings}</div></div>`;
+  }).join("");
+  container.style.display = "block";
+}
+
+async function lintInput(body) {
+  const btn = $("btnLint");
+
+  setStatus("检查中...", "");
+  if (btn) btn.disabled = true;
+  renderLintReports(null);
+
+  try {
+    const resp = await fetch("/api/v1/cert/lint", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(body)
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderLintReports(data.data);
+    setStatus(data.data.passed ? "合规检查通过" : "存在不符合 Baseline Requirements 的问题", data.data.passed ? "ok" : "err");
+  } catch (e) {
+    setStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function lintCSR() {
+  const inEl = $("input");
+  if (!inEl) return;
+  const parsed = parseCSRFromInput(inEl.value);
+  if (parsed.err) {
+    setStatus(parsed.err, "err");
+    return;
+  }
+  lintInput({ csr: parsed.csr });
+}
+
+function lintCertChain() {
+  const certChain = valueOf("input");
+  if (!certChain) {
+    setStatus("输入为空", "err");
+    return;
+  }
+  lintInput({ cert: certChain });
+}
+
+function setGenStatus(msg, type) {
+  const el = $("genStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function linesOf(id) {
+  const el = $(id);
+  if (!el) return [];
+  return (el.value || "").split(/[\r\n,]+/).map(s => s.trim()).filter(Boolean);
+}
+
+function valueOf(id) {
+  const el = $(id);
+  return el ? (el.value || "").trim() : "";
+}
+
+async function generateCSR() {
+  const btn = $("btnGenerate");
+  const btnCopyCSR = $("btnCopyGenCSR");
+  const btnCopyKey = $("btnCopyGenKey");
+  const csrOut = $("genCSROut");
+  const keyOut = $("genKeyOut");
+
+  if (!csrOut || !keyOut) return;
+
+  const commonName = valueOf("genCN");
+  if (!commonName) {
+    setGenStatus("CN 不能为空", "err");
+    return;
+  }
+
+  setGenStatus("生成中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopyCSR) btnCopyCSR.disabled = true;
+  if (btnCopyKey) btnCopyKey.disabled = true;
+
+  try {
+    const resp = await fetch("/api/v1/csr/generate", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        commonName,
+        organization: valueOf("genO"),
+        organizationalUnit: valueOf("genOU"),
+        locality: valueOf("genL"),
+        province: valueOf("genST"),
+        country: valueOf("genC"),
+        dnsNames: linesOf("genDNS"),
+        ipAddresses: linesOf("genIP"),
+        emailAddresses: linesOf("genEmail"),
+        keySpec: valueOf("genKeySpec"),
+        passphrase: $("genPassphrase") ? $("genPassphrase").value : ""
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setGenStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data || typeof data.data.csr !== "string") {
+      setGenStatus("响应格式不正确", "err");
+      return;
+    }
+
+    csrOut.value = data.data.csr;
+    keyOut.value = data.data.privateKey || "";
+    setGenStatus(data.data.encrypted ? "生成完成（私钥已加密）" : "生成完成", "ok");
+    if (btnCopyCSR) btnCopyCSR.disabled = false;
+    if (btnCopyKey) btnCopyKey.disabled = false;
+  } catch (e) {
+    setGenStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
 function wireCSRPage() {
   const btn = $("btnFormat");
+  const btnParse = $("btnParse");
   const btnCopy = $("btnCopy");
   const btnClear = $("btnClear");
   const inEl = $("input");
   const outEl = $("output");
 
   if (btn) btn.addEventListener("click", formatCSR);
+  if (btnParse) btnParse.addEventListener("click", parseCSR);
+  if ($("btnLint")) $("btnLint").addEventListener("click", lintCSR);
+
+  const btnGenerate = $("btnGenerate");
+  const btnCopyGenCSR = $("btnCopyGenCSR");
+  const btnCopyGenKey = $("btnCopyGenKey");
+  if (btnGenerate) btnGenerate.addEventListener("click", generateCSR);
+  if (btnCopyGenCSR) {
+    btnCopyGenCSR.addEventListener("click", () => copyCertToClipboard($("genCSROut").value, "btnCopyGenCSR"));
+  }
+  if (btnCopyGenKey) {
+    btnCopyGenKey.addEventListener("click", () => copyCertToClipboard($("genKeyOut").value, "btnCopyGenKey"));
+  }
 
   if (btnCopy && outEl) {
     btnCopy.addEventListener("click", async () => {
@@ -153,6 +457,8 @@ function wireCSRPage() {
     btnClear.addEventListener("click", () => {
       if (inEl) inEl.value = "";
       if (outEl) outEl.value = "";
+      renderCSRInfo(null);
+      renderLintReports(null);
       setStatus("", "");
       if (btnCopy) btnCopy.disabled = true;
     });
@@ -198,6 +504,21 @@ function formatDate(dateStr) {
   }
 }
 
+function escapeHTML(s) {
+  return String(s == null ? "" : s)
+    .replace(/&/g, "&amp;")
+    .replace(/</g, "&lt;")
+    .replace(/>/g, "&gt;")
+    .replace(/"/g, "&quot;");
+}
+
+function labeledList(label, items) {
+  if (!items || items.length === 0) return "";
+  return items
+    .map(item => `<div><span class="cert-info-label">${escapeHTML(label)}:</span>${escapeHTML(item)}</div>`)
+    .join("");
+}
+
 function renderCertList(certs) {
   const container = $("outputContainer");
   const certList = $("certList");
@@ -231,6 +552,14 @@ function renderCertList(certs) {
     
     toolbar.appendChild(label);
     toolbar.appendChild(copyBtn);
+
+    if ($("inventoryList")) {
+      const saveBtn = document.createElement("button");
+      saveBtn.className = "btn";
+      saveBtn.textContent = "保存到清单";
+      saveBtn.addEventListener("click", () => saveToInventory(cert.pem));
+      toolbar.appendChild(saveBtn);
+    }
     
     const textarea = document.createElement("textarea");
     textarea.className = "textarea";
@@ -262,9 +591,57 @@ function renderCertList(certs) {
         title: "序列号",
         content: cert.serialNumber || "N/A"
       },
+      {
+        title: "使用者可选名称 (SAN)",
+        content: [
+          labeledList("DNS", cert.dnsNames),
+          labeledList("IP", cert.ipAddresses),
+          labeledList("Email", cert.emailAddresses),
+          labeledList("URI", cert.uris)
+        ].join("") || "N/A"
+      },
+      {
+        title: "密钥与签名",
+        content: `<div><span class="cert-info-label">公钥:</span>${escapeHTML(cert.publicKeyAlgorithm || "N/A")} ${cert.publicKeySize || ""}${cert.publicKeyCurve ? " (" + escapeHTML(cert.publicKeyCurve) + ")" : ""}</div><div><span class="cert-info-label">签名算法:</span>${escapeHTML(cert.signatureAlgorithm || "N/A")}</div>`
+      },
+      {
+        title: "密钥用途",
+        content: [
+          labeledList("KeyUsage", cert.keyUsage),
+          labeledList("ExtKeyUsage", cert.extKeyUsage)
+        ].join("") || "N/A"
+      },
+      {
+        title: "指纹与标识",
+        content: [
+          labeledList("SHA-1", cert.fingerprintSha1 ? [cert.fingerprintSha1] : []),
+          labeledList("SHA-256", cert.fingerprintSha256 ? [cert.fingerprintSha256] : []),
+          labeledList("SKI", cert.subjectKeyId ? [cert.subjectKeyId] : []),
+          labeledList("AKI", cert.authorityKeyId ? [cert.authorityKeyId] : [])
+        ].join("") || "N/A"
+      },
+      {
+        title: "吊销与颁发者信息",
+        content: [
+          labeledList("CRL", cert.crlDistributionPoints),
+          labeledList("OCSP", cert.ocspServers),
+          labeledList("CA Issuers", cert.issuingCertificateUrl)
+        ].join("") || "N/A"
+      },
+      {
+        title: "证书策略",
+        content: [
+          labeledList("验证级别", cert.validationLevel ? [cert.validationLevel] : []),
+          labeledList("策略 OID", cert.policyOids)
+        ].join("") || "N/A"
+      },
+      {
+        title: "证书透明度 (CT)",
+        content: renderSCTs(cert)
+      },
       {
         title: "其他信息",
-        content: `<div><span class="cert-info-label">版本:</span>${cert.version || "N/A"}</div><div><span class="cert-info-label">是否CA:</span>${cert.isCA ? "是" : "否"}</div>`
+        content: `<div><span class="cert-info-label">版本:</span>${cert.version || "N/A"}</div><div><span class="cert-info-label">是否CA:</span>${cert.isCA ? "是" : "否"}</div>${cert.pathLenConstraint != null ? `<div><span class="cert-info-label">路径长度限制:</span>${cert.pathLenConstraint}</div>` : ""}`
       }
     ];
     
@@ -293,16 +670,110 @@ function renderCertList(certs) {
   container.style.display = "block";
 }
 
+function renderSCTs(cert) {
+  const parts = [];
+  if (cert.precertificate) parts.push(labeledList("预证书", ["带有 CT poison 扩展，不能用于 TLS"]));
+  if (cert.sctError) parts.push(labeledList("SCT 解析失败", [cert.sctError]));
+  (cert.scts || []).forEach((sct, i) => {
+    const log = sct.logName ? `${sct.logName} (${sct.logOperator})` : "未知日志";
+    parts.push(labeledList(`SCT #${i + 1}`, [
+      log,
+      "Log ID: " + sct.logId,
+      "时间: " + formatDate(sct.timestamp),
+      "签名: " + sct.hashAlgorithm + " / " + sct.signatureAlgorithm
+    ]));
+  });
+  return parts.join("") || "无嵌入 SCT";
+}
+
+function renderVerification(v) {
+  const container = $("verifyContainer");
+  const result = $("verifyResult");
+
+  if (!container || !result) return;
+
+  if (!v) {
+    container.style.display = "none";
+    result.innerHTML = "";
+    return;
+  }
+
+  const sourceLabels = {
+    chain: "证书链",
+    trust_store: "信任库",
+    self: "自签名"
+  };
+
+  let html = "";
+  html += `<div class="status ${v.verified ? "ok" : "err"}">${v.verified ? "验证通过" : "验证失败：" + escapeHTML(v.verifyError || "")}</div>`;
+  if (v.outOfOrder) {
+    html += `<div class="status err">证书顺序不正确，已按签发路径重新排序：${(v.order || []).map(i => i + 1).join(" -> ")}</div>`;
+  }
+  if (v.missingIntermediates) {
+    html += `<div class="status err">缺少中间证书，未找到签发者：${escapeHTML(v.missingIssuer || "")}</div>`;
+  }
+  if (v.unused && v.unused.length > 0) {
+    html += `<div class="status">未使用的证书：${v.unused.map(i => "证书 " + (i + 1)).join("、")}</div>`;
+  }
+
+  (v.links || []).forEach((link, index) => {
+    const source = sourceLabels[link.issuerSource] || "未找到";
+    const sig = link.signatureValid ? "签名有效" : ("签名无效" + (link.error ? "：" + escapeHTML(link.error) : ""));
+    html += `<div class="cert-info-section">
+      <div class="cert-info-title">第 ${index + 1} 环（签发者来源：${source}，${sig}）</div>
+      <div class="cert-info-content"><div><span class="cert-info-label">主题:</span>${escapeHTML(link.subject)}</div><div><span class="cert-info-label">签发者:</span>${escapeHTML(link.issuer)}</div></div>
+    </div>`;
+  });
+
+  result.innerHTML = html;
+
+  if (v.fullChainPem) {
+    const toolbar = document.createElement("div");
+    toolbar.className = "toolbar";
+    toolbar.style.marginTop = "8px";
+
+    const label = document.createElement("span");
+    label.textContent = "重新排序后的证书链（不含根证书）";
+    label.style.fontWeight = "bold";
+
+    const copyBtn = document.createElement("button");
+    copyBtn.className = "btn";
+    copyBtn.textContent = "复制证书链";
+    copyBtn.id = "btnCopyFullChain";
+    copyBtn.addEventListener("click", () => copyCertToClipboard(v.fullChainPem, "btnCopyFullChain"));
+
+    toolbar.appendChild(label);
+    toolbar.appendChild(copyBtn);
+
+    const textarea = document.createElement("textarea");
+    textarea.className = "textarea";
+    textarea.readOnly = true;
+    textarea.value = v.fullChainPem;
+    textarea.style.height = "200px";
+    textarea.style.fontSize = "12px";
+
+    result.appendChild(toolbar);
+    result.appendChild(textarea);
+  }
+
+  container.style.display = "block";
+}
+
 async function splitCertChain() {
   const btn = $("btnSplit");
   const inEl = $("input");
   const container = $("outputContainer");
+  const verifyEl = $("verifyChain");
+  const trustStoreEl = $("trustStore");
+  const rootsEl = $("rootsInput");
 
   if (!inEl) return;
 
   setStatus("处理中...", "");
   if (btn) btn.disabled = true;
   if (container) container.style.display = "none";
+  renderVerification(null);
+  renderFetchInfo(null);
 
   const certChain = (inEl.value || "").trim();
   if (!certChain) {
@@ -312,16 +783,21 @@ async function splitCertChain() {
   }
 
   try {
+    const verify = verifyEl ? verifyEl.checked : false;
+    const trustStore = trustStoreEl ? trustStoreEl.value : "system";
+    const rootsPem = rootsEl ? rootsEl.value : "";
+
     const resp = await fetch("/api/v1/cert/split", {
       method: "POST",
       headers: { "Content-Type": "application/json" },
-      body: JSON.stringify({ certChain })
+      body: JSON.stringify({ certChain, verify, trustStore, rootsPem })
     });
 
     const data = await resp.json().catch(() => null);
     if (!resp.ok) {
       const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
       setStatus(msg, "err");
+      appendASN1Link("status", certChain);
       return;
     }
 
@@ -334,6 +810,7 @@ async function splitCertChain() {
     const count = data.data.count || certs.length;
     
     renderCertList(certs);
+    renderVerification(data.data.verification);
     setStatus(`完成，共拆分出 ${count} 个证书`, "ok");
   } catch (e) {
     setStatus("请求失败：" + e.message, "err");
@@ -342,59 +819,951 @@ async function splitCertChain() {
   }
 }
 
-function wireCertPage() {
-  const btn = $("btnSplit");
-  const btnClear = $("btnClear");
-  const inEl = $("input");
-  const container = $("outputContainer");
+function setMatchStatus(msg, type) {
+  const el = $("matchStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
 
-  if (btn) {
-    btn.addEventListener("click", () => {
-      splitCertChain();
-    });
-  }
+function renderMatchResult(res) {
+  const el = $("matchResult");
+  if (!el) return;
 
-  if (btnClear) {
-    btnClear.addEventListener("click", () => {
-      if (inEl) inEl.value = "";
-      if (container) container.style.display = "none";
-      const certList = $("certList");
-      if (certList) certList.innerHTML = "";
-      setStatus("", "");
-    });
+  if (!res) {
+    el.innerHTML = "";
+    return;
   }
 
-  if (inEl) {
-    inEl.addEventListener("keydown", (e) => {
-      if ((e.ctrlKey || e.metaKey) && e.key === "Enter") {
-        e.preventDefault();
-        splitCertChain();
-      }
-    });
+  const pairLabels = {
+    "cert-key": "证书 ↔ 私钥",
+    "cert-csr": "证书 ↔ CSR",
+    "csr-key": "CSR ↔ 私钥"
+  };
+
+  const items = [
+    { title: "证书", item: res.cert },
+    { title: "CSR", item: res.csr },
+    { title: "私钥", item: res.key }
+  ].filter(x => x.item);
+
+  let html = "";
+  html += `<div class="cert-info-section"><div class="cert-info-title">匹配结果</div><div class="cert-info-content">${
+    (res.pairs || []).map(p => `<div><span class="cert-info-label">${pairLabels[p.pair] || escapeHTML(p.pair)}:</span>${p.match ? "匹配" : "不匹配"}</div>`).join("")
+  }</div></div>`;
+
+  items.forEach(x => {
+    const it = x.item;
+    html += `<div class="cert-info-section"><div class="cert-info-title">${x.title}</div><div class="cert-info-content">${
+      [
+        labeledList("主题", it.subject ? [it.subject] : []),
+        labeledList("公钥", [`${it.publicKeyAlgorithm} ${it.publicKeySize || ""}`]),
+        labeledList("公钥 SHA-256", [it.publicKeySha256]),
+        labeledList("格式", it.keyFormat ? [it.keyFormat + (it.encrypted ? "（已加密）" : "")] : [])
+      ].join("")
+    }</div></div>`;
+  });
+
+  if ((res.subjectDiffs || []).length > 0 || (res.sansMissingInCert || []).length > 0 || (res.sansAddedInCert || []).length > 0) {
+    html += `<div class="cert-info-section"><div class="cert-info-title">CSR 与证书差异</div><div class="cert-info-content">${
+      [
+        (res.subjectDiffs || []).map(d => `<div><span class="cert-info-label">${escapeHTML(d.field)}:</span>CSR=${escapeHTML(d.csr || "(空)")} / 证书=${escapeHTML(d.cert || "(空)")}</div>`).join(""),
+        labeledList("证书缺少的 SAN", res.sansMissingInCert),
+        labeledList("证书新增的 SAN", res.sansAddedInCert)
+      ].join("")
+    }</div></div>`;
   }
-}
 
-async function formatJSON() {
-  const btn = $("btnFormat");
-  const btnCopy = $("btnCopy");
-  const btnSave = $("btnSave");
-  const inEl = $("input");
-  const outEl = $("output");
-  const indentSelect = $("indentSelect");
+  el.innerHTML = html;
+}
 
-  if (!inEl || !outEl) return;
+async function matchCertKey() {
+  const btn = $("btnMatch");
 
-  setStatus("处理中...", "");
+  setMatchStatus("处理中...", "");
   if (btn) btn.disabled = true;
-  if (btnCopy) btnCopy.disabled = true;
-  if (btnSave) btnSave.disabled = true;
-
-  const jsonText = (inEl.value || "").trim();
-  if (!jsonText) {
-    setStatus("输入为空", "err");
-    if (btn) btn.disabled = false;
-    return;
-  }
+  renderMatchResult(null);
+
+  try {
+    const resp = await fetch("/api/v1/cert/match", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        cert: $("matchCert") ? $("matchCert").value : "",
+        csr: $("matchCSR") ? $("matchCSR").value : "",
+        privateKey: $("matchKey") ? $("matchKey").value : "",
+        passphrase: $("matchPassphrase") ? $("matchPassphrase").value : ""
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setMatchStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setMatchStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderMatchResult(data.data);
+    setMatchStatus(data.data.allMatch ? "全部匹配" : "存在不匹配项", data.data.allMatch ? "ok" : "err");
+  } catch (e) {
+    setMatchStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setDiffStatus(msg, type) {
+  const el = $("diffStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderDiffValue(f, side) {
+  if (f.added || f.removed) {
+    const items = side === "left" ? (f.removed || []) : (f.added || []);
+    const cls = side === "left" ? "diff-removed" : "diff-added";
+    const sign = side === "left" ? "- " : "+ ";
+    return items.map(v => `<div class="${cls}">${sign}${escapeHTML(v)}</div>`).join("");
+  }
+  const v = side === "left" ? f.left : f.right;
+  return v ? escapeHTML(v) : "(空)";
+}
+
+function renderDiffResult(res) {
+  const el = $("diffResult");
+  if (!el) return;
+
+  if (!res) {
+    el.innerHTML = "";
+    return;
+  }
+
+  const kindLabels = { cert: "证书", csr: "CSR" };
+  el.innerHTML = (res.pairs || []).map(p => {
+    const title = `#${p.index + 1} ${escapeHTML(p.leftSubject || "-")} ↔ ${escapeHTML(p.rightSubject || "-")}`;
+    let body;
+    if (p.change === "added") {
+      body = `<div class="diff-added">仅右侧存在（${kindLabels[p.rightKind] || escapeHTML(p.rightKind)}）</div>`;
+    } else if (p.change === "removed") {
+      body = `<div class="diff-removed">仅左侧存在（${kindLabels[p.leftKind] || escapeHTML(p.leftKind)}）</div>`;
+    } else if ((p.fields || []).length === 0) {
+      body = `<div class="diff-added">所有字段一致</div>`;
+    } else {
+      const rows = p.fields.map(f => `<tr class="${f.expected ? "diff-expected" : ""}"><td>${escapeHTML(f.field)}${f.expected ? "（预期变化）" : ""}</td><td>${renderDiffValue(f, "left")}</td><td>${renderDiffValue(f, "right")}</td></tr>`).join("");
+      body = `<table class="table"><thead><tr><th>字段</th><th>左侧</th><th>右侧</th></tr></thead><tbody>${rows}</tbody></table>`;
+    }
+    return `<div class="cert-info-section"><div class="cert-info-title">${title}</div>${body}</div>`;
+  }).join("");
+}
+
+async function diffCerts() {
+  const btn = $("btnDiff");
+
+  setDiffStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  renderDiffResult(null);
+
+  try {
+    const resp = await fetch("/api/v1/cert/diff", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ left: valueOf("diffLeft"), right: valueOf("diffRight") })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setDiffStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setDiffStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderDiffResult(data.data);
+    setDiffStatus(data.data.match ? "除有效期和序列号外一致" : "存在差异", data.data.match ? "ok" : "err");
+  } catch (e) {
+    setDiffStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+let pemScanBlocks = [];
+
+function setPEMScanStatus(msg, type) {
+  const el = $("pemScanStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderPEMScanResult(res) {
+  const el = $("pemScanResult");
+  const countEl = $("pemScanCount");
+  if (!el) return;
+
+  pemScanBlocks = res ? (res.blocks || []) : [];
+  if (countEl) countEl.textContent = res ? `(共 ${res.count} 个)` : "";
+  if (!res) {
+    el.innerHTML = "";
+    return;
+  }
+
+  const warnings = (res.warnings || []).map(w => `<div class="diff-removed">第 ${w.line} 行：${escapeHTML(w.message)}</div>`).join("");
+  el.innerHTML = warnings + pemScanBlocks.map((b, i) => {
+    const headers = Object.keys(b.headers || {}).map(k => `${k}: ${b.headers[k]}`);
+    const rows = [
+      labeledList("位置", [`第 ${b.line} 行第 ${b.column} 列（字节 ${b.offset}-${b.end}）`]),
+      labeledList("内容", b.summary ? [b.summary] : []),
+      labeledList("大小", [`${b.size} 字节`]),
+      labeledList("头部", headers),
+      b.error ? `<div class="diff-removed">${escapeHTML(b.error)}</div>` : ""
+    ];
+    return `<div class="cert-info-section"><div class="cert-info-title">#${i + 1} ${escapeHTML(b.type)} <button class="btn" id="btnCopyPEMBlock${i}" data-pem-index="${i}">复制</button> <a href="/asn1" target="_blank" data-asn1-index="${i}">ASN.1 结构</a></div><div class="cert-info-content">${rows.join("")}<textarea class="textarea" readonly style="height: 120px; font-size: 12px;">${escapeHTML(b.pem)}</textarea></div></div>`;
+  }).join("");
+
+  el.querySelectorAll("button[data-pem-index]").forEach(btn => {
+    btn.addEventListener("click", () => copyCertToClipboard(pemScanBlocks[btn.getAttribute("data-pem-index")].pem, btn.id));
+  });
+  el.querySelectorAll("a[data-asn1-index]").forEach(a => {
+    a.addEventListener("click", () => localStorage.setItem(asn1InputKey, pemScanBlocks[a.getAttribute("data-asn1-index")].pem));
+  });
+}
+
+async function scanPEM() {
+  const btn = $("btnPEMScan");
+  const btnCopy = $("btnCopyPEMScan");
+
+  setPEMScanStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  renderPEMScanResult(null);
+
+  try {
+    const resp = await fetch("/api/v1/pem/scan", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ input: valueOf("pemScanInput") })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setPEMScanStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setPEMScanStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderPEMScanResult(data.data);
+    if (btnCopy) btnCopy.disabled = pemScanBlocks.length === 0;
+    const warned = (data.data.warnings || []).length > 0;
+    setPEMScanStatus(`找到 ${data.data.count} 个 PEM 块` + (warned ? "，部分内容不完整" : ""), warned ? "err" : "ok");
+  } catch (e) {
+    setPEMScanStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setJWKSStatus(msg, type) {
+  const el = $("jwksStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderJWKSKeys(keys) {
+  const el = $("jwksResult");
+  if (!el) return;
+
+  el.innerHTML = (keys || []).map((k, i) => {
+    const rows = [
+      labeledList("kid", k.kid ? [k.kid] : []),
+      labeledList("kty", [k.kty + (k.use ? " / " + k.use : "") + (k.alg ? " / " + k.alg : "")]),
+      labeledList("公钥", [`${k.publicKeyAlgorithm} ${k.publicKeySize}${k.publicKeyCurve ? " (" + k.publicKeyCurve + ")" : ""}`]),
+      labeledList("RFC 7638 指纹", [k.thumbprint]),
+      labeledList("公钥 SHA-256", [k.publicKeySha256]),
+      labeledList("x5c", (k.certs || []).map(c => `${c.subject}（到期 ${formatDate(c.notAfter)}）`)),
+      (k.warnings || []).map(w => `<div class="diff-removed">${escapeHTML(w)}</div>`).join("")
+    ];
+    return `<div class="cert-info-section"><div class="cert-info-title">密钥 ${i + 1}</div><div class="cert-info-content">${rows.join("")}</div></div>`;
+  }).join("");
+}
+
+async function convertJWKS() {
+  const btn = $("btnJWKS");
+  const btnCopy = $("btnCopyJWKS");
+  const outEl = $("jwksOutput");
+  const decode = valueOf("jwksDirection") === "decode";
+
+  if (!outEl) return;
+  setJWKSStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  outEl.value = "";
+  renderJWKSKeys(null);
+
+  try {
+    const resp = await fetch(decode ? "/api/v1/cert/jwks/decode" : "/api/v1/cert/jwks", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify(decode
+        ? { jwks: valueOf("jwksInput") }
+        : { cert: valueOf("jwksInput"), use: valueOf("jwksUse"), alg: valueOf("jwksAlg") })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setJWKSStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setJWKSStatus("响应格式不正确", "err");
+      return;
+    }
+
+    if (decode) {
+      outEl.value = (data.data.keys || []).map(k => k.publicKeyPem).join("");
+      renderJWKSKeys(data.data.keys);
+      const warned = (data.data.keys || []).some(k => (k.warnings || []).length > 0);
+      setJWKSStatus(`解析出 ${data.data.count} 个密钥${warned ? "，存在警告" : ""}`, warned ? "err" : "ok");
+    } else {
+      outEl.value = JSON.stringify(data.data.jwks, null, 2);
+      setJWKSStatus(`生成 ${data.data.count} 个 JWK`, "ok");
+    }
+    if (btnCopy) btnCopy.disabled = false;
+  } catch (e) {
+    setJWKSStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setConvertStatus(msg, type) {
+  const el = $("convertStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+let convertDownload = null;
+
+function downloadContent(fileName, output, encoding) {
+  let blob;
+  if (encoding === "base64") {
+    const bin = atob(output);
+    const bytes = new Uint8Array(bin.length);
+    for (let i = 0; i < bin.length; i++) bytes[i] = bin.charCodeAt(i);
+    blob = new Blob([bytes], { type: "application/octet-stream" });
+  } else {
+    blob = new Blob([output], { type: "application/x-pem-file" });
+  }
+
+  const url = URL.createObjectURL(blob);
+  const a = document.createElement("a");
+  a.href = url;
+  a.download = fileName || "cert";
+  document.body.appendChild(a);
+  a.click();
+  document.body.removeChild(a);
+  URL.revokeObjectURL(url);
+}
+
+function downloadConvertResult() {
+  if (!convertDownload) return;
+  downloadContent(convertDownload.fileName, convertDownload.output, convertDownload.encoding);
+}
+
+let bundleDownload = null;
+
+function setBundleStatus(msg, type) {
+  const el = $("bundleStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderBundleResult(res) {
+  const el = $("bundleResult");
+  const downloads = $("bundleDownloads");
+  if (!el) return;
+
+  if (!res) {
+    el.innerHTML = "";
+    if (downloads) downloads.style.display = "none";
+    return;
+  }
+
+  const rootSourceLabels = { pool: "候选证书", trust_store: "信任库" };
+  const order = [res.leaf].concat(res.chain).map((c, i) => `${i + 1}. ${c.subject}（到期 ${formatDate(c.notAfter)}）`);
+  const rows = [
+    labeledList("顺序", order),
+    labeledList("完整性", [res.complete ? "已到达根证书" : "缺少签发者：" + (res.missingIssuer || "")]),
+    labeledList("根证书", res.rootSource ? [(rootSourceLabels[res.rootSource] || res.rootSource) + (res.rootIncluded ? "，已附加" : "，未附加")] : []),
+    labeledList("信任校验", [res.verified ? "通过" : "未通过：" + (res.verifyError || "")]),
+    labeledList("未使用", (res.unused || []).map(c => c.subject)),
+    labeledList("重复", res.duplicates ? [String(res.duplicates)] : []),
+    labeledList("警告", res.warnings || [])
+  ];
+  el.innerHTML = `<div class="cert-info-section"><div class="cert-info-content">${rows.join("")}</div></div>`;
+  if (downloads) downloads.style.display = "flex";
+}
+
+async function buildBundle() {
+  const btn = $("btnBundle");
+  const fileEl = $("bundleFiles");
+
+  setBundleStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  bundleDownload = null;
+  renderBundleResult(null);
+
+  const form = new FormData();
+  form.append("leaf", valueOf("bundleLeaf"));
+  form.append("candidates", valueOf("bundleCandidates"));
+  form.append("includeRoot", $("bundleIncludeRoot") && $("bundleIncludeRoot").checked ? "true" : "false");
+  form.append("trustStore", $("trustStore") ? $("trustStore").value : "system");
+  form.append("rootsPem", $("rootsInput") ? $("rootsInput").value : "");
+  if (fileEl) Array.from(fileEl.files).forEach(f => form.append("files", f));
+
+  try {
+    const resp = await fetch("/api/v1/cert/bundle", { method: "POST", body: form });
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setBundleStatus(msg, "err");
+      return;
+    }
+    if (!data || !data.ok || !data.data) {
+      setBundleStatus("响应格式不正确", "err");
+      return;
+    }
+
+    const res = data.data;
+    bundleDownload = {
+      "fullchain.pem": { output: res.fullChainPem, encoding: "text" },
+      "cert.pem": { output: res.certPem, encoding: "text" },
+      "chain.pem": { output: res.chainPem, encoding: "text" },
+      "bundle.p7b": { output: res.pkcs7, encoding: "base64" }
+    };
+    renderBundleResult(res);
+    setBundleStatus(`完成，证书链共 ${res.chain.length + 1} 个证书`, res.complete ? "ok" : "err");
+  } catch (e) {
+    setBundleStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+async function convertCert() {
+  const btn = $("btnConvert");
+  const fileEl = $("convertFile");
+  const btnDownload = $("btnDownloadConvert");
+  const keyOut = $("convertKeyOut");
+
+  setConvertStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnDownload) btnDownload.style.display = "none";
+  if (keyOut) {
+    keyOut.value = "";
+    keyOut.style.display = "none";
+  }
+  convertDownload = null;
+
+  const form = new FormData();
+  const file = fileEl && fileEl.files.length > 0 ? fileEl.files[0] : null;
+  if (file) form.append("file", file);
+  form.append("data", $("convertInput") ? $("convertInput").value : "");
+  form.append("target", $("convertTarget") ? $("convertTarget").value : "");
+  form.append("password", $("convertPassword") ? $("convertPassword").value : "");
+  form.append("outputPassword", $("convertOutputPassword") ? $("convertOutputPassword").value : "");
+  form.append("privateKey", $("convertKey") ? $("convertKey").value : "");
+  form.append("keyPassphrase", $("convertKeyPassphrase") ? $("convertKeyPassphrase").value : "");
+  form.append("legacyPkcs12", $("convertLegacy") && $("convertLegacy").checked ? "true" : "false");
+
+  try {
+    const resp = await fetch("/api/v1/cert/convert", { method: "POST", body: form });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setConvertStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data || !Array.isArray(data.data.certs)) {
+      setConvertStatus("响应格式不正确", "err");
+      return;
+    }
+
+    const res = data.data;
+    renderCertList(res.certs);
+    renderVerification(null);
+
+    if (res.output) {
+      convertDownload = { output: res.output, encoding: res.outputEncoding, fileName: res.fileName };
+      if (btnDownload) btnDownload.style.display = "";
+    }
+    if (res.privateKey && keyOut) {
+      keyOut.value = res.privateKey;
+      keyOut.style.display = "block";
+    }
+
+    let msg = `完成，输入格式 ${res.inputFormat.toUpperCase()}，共 ${res.count} 个证书`;
+    if (res.hasKey) msg += "，含私钥";
+    if (res.outputFormat) msg += `，已转换为 ${res.outputFormat.toUpperCase()}`;
+    setConvertStatus(msg, "ok");
+  } catch (e) {
+    setConvertStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setRevocationStatus(msg, type) {
+  const el = $("revocationStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderSignatureCheck(res) {
+  if (!res.signatureChecked) return labeledList("签名", ["未校验（未提供签发者证书）"]);
+  return labeledList("签名", [res.signatureValid ? "有效" : "无效：" + (res.signatureError || "")]);
+}
+
+function renderOCSPResult(res) {
+  const certStatusLabels = { good: "正常", revoked: "已吊销", unknown: "未知" };
+  const rows = [
+    labeledList("响应状态", [res.responseStatus]),
+    labeledList("证书状态", res.certStatus ? [certStatusLabels[res.certStatus] || res.certStatus] : []),
+    labeledList("序列号", res.serialHex ? [`${res.serialHex} (${res.serialNumber})`] : []),
+    labeledList("吊销时间", res.revokedAt ? [formatDate(res.revokedAt)] : []),
+    labeledList("吊销原因", res.revocationReason ? [res.revocationReason] : []),
+    labeledList("生成时间", res.producedAt ? [formatDate(res.producedAt)] : []),
+    labeledList("本次更新", res.thisUpdate ? [formatDate(res.thisUpdate)] : []),
+    labeledList("下次更新", res.nextUpdate ? [formatDate(res.nextUpdate) + (res.expired ? "（已过期）" : "")] : []),
+    labeledList("响应者", [res.responderName || res.responderKeyHash].filter(Boolean)),
+    labeledList("响应者证书", res.responderCert ? [res.responderCert.subject] : []),
+    labeledList("签名算法", res.signatureAlgorithm ? [res.signatureAlgorithm] : []),
+    res.responseStatus === "successful" ? renderSignatureCheck(res) : ""
+  ];
+  return `<div class="cert-info-section"><div class="cert-info-title">OCSP 响应</div><div class="cert-info-content">${rows.join("")}</div></div>`;
+}
+
+function renderCRLResult(res) {
+  let html = `<div class="cert-info-section"><div class="cert-info-title">CRL</div><div class="cert-info-content">${[
+    labeledList("签发者", [res.issuer]),
+    labeledList("CRL 编号", res.number ? [res.number] : []),
+    labeledList("本次更新", [formatDate(res.thisUpdate)]),
+    labeledList("下次更新", res.nextUpdate ? [formatDate(res.nextUpdate) + (res.expired ? "（已过期）" : "")] : []),
+    labeledList("签名算法", [res.signatureAlgorithm]),
+    labeledList("吊销数量", [String(res.count)]),
+    renderSignatureCheck(res)
+  ].join("")}</div></div>`;
+
+  if (res.lookup) {
+    const l = res.lookup;
+    const detail = l.issuerMismatch ? "证书不是此 CRL 的签发者签发的，无法判断"
+      : l.revoked ? `已吊销，时间 ${formatDate(l.entry.revocationTime)}${l.entry.reason ? "，原因 " + l.entry.reason : ""}` : "不在 CRL 中";
+    html += `<div class="cert-info-section"><div class="cert-info-title">序列号查询</div><div class="cert-info-content">${
+      labeledList(l.serialHex, [detail])
+    }</div></div>`;
+  }
+
+  if ((res.revoked || []).length > 0) {
+    const rows = res.revoked.map(e => `<tr><td>${escapeHTML(e.serialHex)}</td><td>${escapeHTML(formatDate(e.revocationTime))}</td><td>${escapeHTML(e.reason || "-")}</td></tr>`).join("");
+    html += `<table class="table"><thead><tr><th>序列号</th><th>吊销时间</th><th>原因</th></tr></thead><tbody>${rows}</tbody></table>`;
+  }
+  return html;
+}
+
+async function decodeRevocation() {
+  const btn = $("btnRevocation");
+  const resultEl = $("revocationResult");
+  const type = valueOf("revocationType") || "ocsp";
+  const fileEl = $("revocationFile");
+
+  setRevocationStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (resultEl) resultEl.innerHTML = "";
+
+  const form = new FormData();
+  if (fileEl && fileEl.files.length > 0) form.append("file", fileEl.files[0]);
+  form.append("data", valueOf("revocationInput"));
+  form.append("issuer", valueOf("revocationIssuer"));
+  if (type === "crl") {
+    const target = valueOf("revocationCert");
+    form.append(target.includes("-----BEGIN") ? "cert" : "serial", target);
+  }
+
+  try {
+    const resp = await fetch(`/api/v1/cert/${type}/decode`, { method: "POST", body: form });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setRevocationStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setRevocationStatus("响应格式不正确", "err");
+      return;
+    }
+
+    if (resultEl) resultEl.innerHTML = type === "crl" ? renderCRLResult(data.data) : renderOCSPResult(data.data);
+    setRevocationStatus("解析完成", "ok");
+  } catch (e) {
+    setRevocationStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setInventoryStatus(msg, type) {
+  const el = $("inventoryStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function inventoryQuery() {
+  const params = new URLSearchParams();
+  const warn = valueOf("inventoryWarningDays");
+  const crit = valueOf("inventoryCriticalDays");
+  if (warn) params.set("warningDays", warn);
+  if (crit) params.set("criticalDays", crit);
+  return params;
+}
+
+function renderInventory(res) {
+  const el = $("inventoryList");
+  const countEl = $("inventoryCount");
+  if (!el) return;
+
+  if (countEl) {
+    const s = res.summary || {};
+    countEl.textContent = `(共 ${res.count} 个，过期 ${s.expired || 0}，紧急 ${s.critical || 0}，告警 ${s.warning || 0})`;
+  }
+  if (!res.items || res.items.length === 0) {
+    el.innerHTML = `<div class="small">清单为空，可在拆分结果中点击“保存到清单”</div>`;
+    return;
+  }
+
+  const statusLabels = { ok: "正常", warning: "告警", critical: "紧急", expired: "已过期" };
+  const rows = res.items.map(it => `<tr>
+      <td>${escapeHTML(it.label)}</td>
+      <td>${escapeHTML(it.cert.subject)}</td>
+      <td>${escapeHTML((it.cert.dnsNames || []).join(", "))}</td>
+      <td>${escapeHTML(formatDate(it.cert.notAfter))}</td>
+      <td class="expiry-${escapeHTML(it.status)}">${it.daysToExpiry} 天 / ${statusLabels[it.status] || escapeHTML(it.status)}</td>
+      <td><button class="btn" data-inventory-id="${escapeHTML(it.id)}">删除</button></td>
+    </tr>`).join("");
+
+  el.innerHTML = `<table class="table">
+    <thead><tr><th>标签</th><th>主题</th><th>域名</th><th>到期时间</th><th>剩余</th><th></th></tr></thead>
+    <tbody>${rows}</tbody>
+  </table>`;
+
+  el.querySelectorAll("button[data-inventory-id]").forEach(btn => {
+    btn.addEventListener("click", () => deleteInventory(btn.getAttribute("data-inventory-id")));
+  });
+}
+
+async function loadInventory() {
+  setInventoryStatus("加载中...", "");
+  try {
+    const resp = await fetch("/api/v1/cert/inventory?" + inventoryQuery().toString());
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setInventoryStatus(msg, "err");
+      return;
+    }
+    if (!data || !data.ok || !data.data) {
+      setInventoryStatus("响应格式不正确", "err");
+      return;
+    }
+    renderInventory(data.data);
+    setInventoryStatus("", "");
+  } catch (e) {
+    setInventoryStatus("请求失败：" + e.message, "err");
+  }
+}
+
+async function saveToInventory(pem) {
+  try {
+    const resp = await fetch("/api/v1/cert/inventory", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ cert: pem, label: valueOf("inventoryLabel") })
+    });
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok || !data || !data.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setInventoryStatus("保存失败：" + msg, "err");
+      return;
+    }
+    await loadInventory();
+    setInventoryStatus(`已保存：${data.data.label}`, "ok");
+  } catch (e) {
+    setInventoryStatus("请求失败：" + e.message, "err");
+  }
+}
+
+async function deleteInventory(id) {
+  try {
+    const resp = await fetch("/api/v1/cert/inventory/" + encodeURIComponent(id), { method: "DELETE" });
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setInventoryStatus(msg, "err");
+      return;
+    }
+    await loadInventory();
+  } catch (e) {
+    setInventoryStatus("请求失败：" + e.message, "err");
+  }
+}
+
+function exportInventory(format) {
+  const params = inventoryQuery();
+  params.set("format", format);
+  window.location.href = "/api/v1/cert/inventory?" + params.toString();
+}
+
+function renderFetchInfo(res) {
+  const container = $("fetchContainer");
+  const result = $("fetchResult");
+  if (!container || !result) return;
+
+  if (!res) {
+    container.style.display = "none";
+    result.innerHTML = "";
+    return;
+  }
+
+  let hostname = [];
+  if (res.hostnameMatch != null) {
+    hostname = [res.hostnameMatch ? `匹配 ${res.serverName}` : "不匹配：" + (res.hostnameError || "")];
+  }
+  const rows = [
+    labeledList("地址", [res.address]),
+    labeledList("SNI", [res.serverName || "（未发送）"]),
+    labeledList("STARTTLS", res.startTls ? [res.startTls] : []),
+    labeledList("协议", [res.protocol]),
+    labeledList("加密套件", [res.cipherSuite]),
+    labeledList("ALPN", res.alpn ? [res.alpn] : []),
+    labeledList("主机名", hostname),
+    labeledList("OCSP 装订", res.ocspStaple ? [] : [res.ocspStapleError ? "解析失败：" + res.ocspStapleError : "无"])
+  ];
+  let html = `<div class="cert-info-section"><div class="cert-info-title">连接</div><div class="cert-info-content">${rows.join("")}</div></div>`;
+  if (res.ocspStaple) html += renderOCSPResult(res.ocspStaple);
+  result.innerHTML = html;
+  container.style.display = "block";
+}
+
+async function fetchCertChain() {
+  const btn = $("btnFetch");
+  const inEl = $("input");
+  const container = $("outputContainer");
+  const verifyEl = $("verifyChain");
+  const trustStoreEl = $("trustStore");
+  const rootsEl = $("rootsInput");
+
+  const address = valueOf("fetchAddress");
+  if (!address) {
+    setStatus("请输入服务器地址", "err");
+    return;
+  }
+
+  setStatus("连接中...", "");
+  if (btn) btn.disabled = true;
+  if (container) container.style.display = "none";
+  renderVerification(null);
+  renderFetchInfo(null);
+
+  try {
+    const resp = await fetch("/api/v1/cert/fetch", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        address,
+        serverName: valueOf("fetchServerName"),
+        startTls: valueOf("fetchStartTLS"),
+        verify: verifyEl ? verifyEl.checked : false,
+        trustStore: trustStoreEl ? trustStoreEl.value : "system",
+        rootsPem: rootsEl ? rootsEl.value : ""
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setStatus(msg, "err");
+      return;
+    }
+    if (!data || !data.ok || !data.data || !Array.isArray(data.data.certs)) {
+      setStatus("响应格式不正确", "err");
+      return;
+    }
+
+    if (inEl) inEl.value = data.data.chainPem;
+    renderFetchInfo(data.data);
+    renderCertList(data.data.certs);
+    renderVerification(data.data.verification);
+    setStatus(`完成，服务器返回 ${data.data.count} 个证书`, "ok");
+  } catch (e) {
+    setStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function wireCertPage() {
+  const btn = $("btnSplit");
+  const btnClear = $("btnClear");
+  const inEl = $("input");
+  const container = $("outputContainer");
+  const trustStoreEl = $("trustStore");
+  const rootsEl = $("rootsInput");
+  const btnMatch = $("btnMatch");
+
+  if (btnMatch) btnMatch.addEventListener("click", matchCertKey);
+  if ($("btnFetch")) $("btnFetch").addEventListener("click", fetchCertChain);
+  if ($("btnLint")) $("btnLint").addEventListener("click", lintCertChain);
+
+  const btnConvert = $("btnConvert");
+  const btnDownloadConvert = $("btnDownloadConvert");
+  if (btnConvert) btnConvert.addEventListener("click", convertCert);
+  if (btnDownloadConvert) btnDownloadConvert.addEventListener("click", downloadConvertResult);
+
+  if ($("btnBundle")) $("btnBundle").addEventListener("click", buildBundle);
+  document.querySelectorAll("button[data-bundle-file]").forEach(b => {
+    b.addEventListener("click", () => {
+      const name = b.getAttribute("data-bundle-file");
+      const file = bundleDownload && bundleDownload[name];
+      if (file) downloadContent(name, file.output, file.encoding);
+    });
+  });
+
+  const btnDiff = $("btnDiff");
+  if (btnDiff) btnDiff.addEventListener("click", diffCerts);
+
+  if ($("btnPEMScan")) $("btnPEMScan").addEventListener("click", scanPEM);
+  if ($("btnCopyPEMScan")) {
+    $("btnCopyPEMScan").addEventListener("click", () => copyCertToClipboard(pemScanBlocks.map(b => b.pem).join(""), "btnCopyPEMScan"));
+  }
+
+  const btnJWKS = $("btnJWKS");
+  const btnCopyJWKS = $("btnCopyJWKS");
+  if (btnJWKS) btnJWKS.addEventListener("click", convertJWKS);
+  if (btnCopyJWKS) btnCopyJWKS.addEventListener("click", () => copyCertToClipboard(valueOf("jwksOutput"), "btnCopyJWKS"));
+
+  const btnRevocation = $("btnRevocation");
+  if (btnRevocation) btnRevocation.addEventListener("click", decodeRevocation);
+
+  const btnInventoryRefresh = $("btnInventoryRefresh");
+  const btnInventoryCSV = $("btnInventoryCSV");
+  const btnInventoryJSON = $("btnInventoryJSON");
+  if (btnInventoryRefresh) btnInventoryRefresh.addEventListener("click", loadInventory);
+  if (btnInventoryCSV) btnInventoryCSV.addEventListener("click", () => exportInventory("csv"));
+  if (btnInventoryJSON) btnInventoryJSON.addEventListener("click", () => exportInventory("json"));
+  if ($("inventoryList")) loadInventory();
+
+  if (trustStoreEl && rootsEl) {
+    trustStoreEl.addEventListener("change", () => {
+      rootsEl.style.display = trustStoreEl.value === "custom" ? "block" : "none";
+    });
+  }
+
+  if (btn) {
+    btn.addEventListener("click", () => {
+      splitCertChain();
+    });
+  }
+
+  if (btnClear) {
+    btnClear.addEventListener("click", () => {
+      if (inEl) inEl.value = "";
+      if (container) container.style.display = "none";
+      const certList = $("certList");
+      if (certList) certList.innerHTML = "";
+      renderVerification(null);
+      renderFetchInfo(null);
+      renderLintReports(null);
+      setStatus("", "");
+    });
+  }
+
+  if (inEl) {
+    inEl.addEventListener("keydown", (e) => {
+      if ((e.ctrlKey || e.metaKey) && e.key === "Enter") {
+        e.preventDefault();
+        splitCertChain();
+      }
+    });
+  }
+}
+
+function renderJSONRepairs(repairs) {
+  if (!repairs.length) return "";
+  const rows = repairs.map(r => `<tr data-line="${r.line}" data-column="${r.column}" style="cursor: pointer;">
+      <td>${r.line}:${r.column}</td>
+      <td>${escapeHTML(r.kind)}</td>
+      <td>${escapeHTML(r.message)}</td>
+    </tr>`).join("");
+  return `<table class="table"><tr><th>位置</th><th>种类</th><th>修复</th></tr>${rows}</table>`;
+}
+
+async function formatJSON() {
+  const btn = $("btnFormat");
+  const btnCopy = $("btnCopy");
+  const btnSave = $("btnSave");
+  const inEl = $("input");
+  const outEl = $("output");
+  const indentSelect = $("indentSelect");
+  const repairEl = $("jsonRepairResult");
+  const repair = $("jsonRepair") ? $("jsonRepair").checked : false;
+
+  if (!inEl || !outEl) return;
+
+  setStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (btnSave) btnSave.disabled = true;
+  if (repairEl) repairEl.innerHTML = "";
+
+  const jsonText = (inEl.value || "").trim();
+  if (!jsonText) {
+    setStatus("输入为空", "err");
+    if (btn) btn.disabled = false;
+    return;
+  }
 
   const indent = indentSelect ? parseInt(indentSelect.value, 10) : 2;
 
@@ -402,13 +1771,13 @@ async function formatJSON() {
     const resp = await fetch("/api/v1/json/format", {
       method: "POST",
       headers: { "Content-Type": "application/json" },
-      body: JSON.stringify({ json: jsonText, indent })
+      body: JSON.stringify({ json: jsonText, indent, repair })
     });
 
     const data = await resp.json().catch(() => null);
     if (!resp.ok) {
       const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
-      setStatus(msg, "err");
+      setStatus(repair ? msg : msg + "（可勾选“修复”宽松解析）", "err");
       outEl.value = "";
       return;
     }
@@ -420,7 +1789,10 @@ async function formatJSON() {
     }
 
     outEl.value = data.data.formatted;
-    setStatus("格式化完成", "ok");
+    delete outEl.dataset.format;
+    const repairs = data.data.repairs || [];
+    if (repairEl) repairEl.innerHTML = renderJSONRepairs(repairs);
+    setStatus(repairs.length ? `格式化完成，修复 ${repairs.length} 处（点击定位到输入中的位置）` : "格式化完成", "ok");
     if (btnCopy) btnCopy.disabled = false;
     if (btnSave) btnSave.disabled = false;
   } catch (e) {
@@ -474,6 +1846,7 @@ async function minifyJSON() {
     }
 
     outEl.value = data.data.minified;
+    delete outEl.dataset.format;
     setStatus("压缩完成", "ok");
     if (btnCopy) btnCopy.disabled = false;
     if (btnSave) btnSave.disabled = false;
@@ -493,10 +1866,13 @@ function saveJSONToFile() {
     return;
   }
 
-  const fileName = "output.json";
+  // 转换结果按目标格式保存
+  const format = outEl.dataset.format || "json";
+  const fileName = "output." + format;
+  const mimeTypes = { json: "application/json", yaml: "application/yaml", toml: "application/toml", xml: "application/xml", csv: "text/csv" };
 
   try {
-    const blob = new Blob([outEl.value], { type: "application/json" });
+    const blob = new Blob([outEl.value], { type: mimeTypes[format] || "text/plain" });
     const url = URL.createObjectURL(blob);
     const a = document.createElement("a");
     a.href = url;
@@ -507,7 +1883,417 @@ function saveJSONToFile() {
     URL.revokeObjectURL(url);
     setStatus("文件已保存", "ok");
   } catch (e) {
-    setStatus("保存文件失败：" + e.message, "err");
+    setStatus("保存文件失败：" + e.message, "err");
+  }
+}
+
+// splitSamples 多个样本：有单独一行 --- 时按其分隔，否则每个非空行一个样本（JSON Lines）
+function splitSamples(text) {
+  const parts = /^---\s*$/m.test(text) ? text.split(/^---\s*$/m) : text.split("\n");
+  return parts.map(s => s.trim()).filter(Boolean);
+}
+
+async function inferSchema() {
+  const btn = $("btnInferSchema");
+  const btnCopy = $("btnCopy");
+  const btnSave = $("btnSave");
+  const outEl = $("output");
+  const jsonText = valueOf("input");
+
+  if (!outEl) return;
+  if (!jsonText) {
+    setStatus("输入为空", "err");
+    return;
+  }
+
+  const multi = $("inferMulti") ? $("inferMulti").checked : false;
+  const samples = multi ? splitSamples(jsonText) : [jsonText];
+  const indentSelect = $("indentSelect");
+
+  setStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (btnSave) btnSave.disabled = true;
+
+  try {
+    const resp = await fetch("/api/v1/json/infer", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ samples, indent: indentSelect ? parseInt(indentSelect.value, 10) : 2 })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setStatus(msg, "err");
+      outEl.value = "";
+      return;
+    }
+
+    if (!data || !data.ok || !data.data || typeof data.data.schema !== "string") {
+      setStatus("响应格式不正确", "err");
+      outEl.value = "";
+      return;
+    }
+
+    outEl.value = data.data.schema;
+    delete outEl.dataset.format;
+    setStatus(`已根据 ${data.data.samples} 个样本推断 Schema`, "ok");
+    if (btnCopy) btnCopy.disabled = false;
+    if (btnSave) btnSave.disabled = false;
+  } catch (e) {
+    setStatus("请求失败：" + e.message, "err");
+    outEl.value = "";
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+async function convertInput() {
+  const btn = $("btnConvert");
+  const btnCopy = $("btnCopy");
+  const btnSave = $("btnSave");
+  const outEl = $("output");
+  const input = ($("input") ? $("input").value : "");
+  const from = $("convertFrom") ? $("convertFrom").value : "json";
+  const to = $("convertTo") ? $("convertTo").value : "yaml";
+  const indentSelect = $("indentSelect");
+
+  if (!outEl) return;
+  if (!input.trim()) {
+    setStatus("输入为空", "err");
+    return;
+  }
+
+  setStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (btnSave) btnSave.disabled = true;
+
+  try {
+    const resp = await fetch("/api/v1/convert", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ input, from, to, indent: indentSelect ? parseInt(indentSelect.value, 10) : 2 })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setStatus(msg, "err");
+      outEl.value = "";
+      return;
+    }
+
+    if (!data || !data.ok || !data.data || typeof data.data.output !== "string") {
+      setStatus("响应格式不正确", "err");
+      outEl.value = "";
+      return;
+    }
+
+    outEl.value = data.data.output;
+    outEl.dataset.format = data.data.to;
+    setStatus(`已从 ${data.data.from.toUpperCase()} 转换为 ${data.data.to.toUpperCase()}`, "ok");
+    if (btnCopy) btnCopy.disabled = false;
+    if (btnSave) btnSave.disabled = false;
+  } catch (e) {
+    setStatus("请求失败：" + e.message, "err");
+    outEl.value = "";
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setJSONQueryStatus(msg, type) {
+  const el = $("jsonQueryStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+async function queryJSON() {
+  const btn = $("btnJSONQuery");
+  const btnCopy = $("btnCopyJSONQuery");
+  const outEl = $("jsonQueryOutput");
+  const pathsEl = $("jsonQueryPaths");
+  const jsonText = valueOf("input");
+  const query = valueOf("jsonQuery");
+
+  if (!outEl) return;
+  if (!jsonText) {
+    setJSONQueryStatus("输入为空", "err");
+    return;
+  }
+  if (!query) {
+    setJSONQueryStatus("请输入查询表达式", "err");
+    return;
+  }
+
+  setJSONQueryStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (pathsEl) pathsEl.innerHTML = "";
+  outEl.value = "";
+
+  try {
+    const indentSelect = $("indentSelect");
+    const resp = await fetch("/api/v1/json/query", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        json: jsonText,
+        query,
+        language: valueOf("jsonQueryLang"),
+        indent: indentSelect ? parseInt(indentSelect.value, 10) : 2,
+        compact: $("jsonQueryCompact") ? $("jsonQueryCompact").checked : false
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setJSONQueryStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setJSONQueryStatus("响应格式不正确", "err");
+      return;
+    }
+
+    const res = data.data;
+    outEl.value = res.output;
+    if (btnCopy) btnCopy.disabled = !res.output;
+    if (pathsEl && res.paths && res.paths.length) {
+      pathsEl.innerHTML = labeledList("路径", res.paths);
+    }
+    setJSONQueryStatus(`${res.language === "jsonpath" ? "JSONPath" : "jq"}：共 ${res.count} 个结果`, "ok");
+  } catch (e) {
+    setJSONQueryStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setCodegenStatus(msg, type) {
+  const el = $("codegenStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+async function generateCode() {
+  const btn = $("btnCodegen");
+  const btnCopy = $("btnCopyCodegen");
+  const outEl = $("codegenOutput");
+  const jsonText = valueOf("input");
+
+  if (!outEl) return;
+  if (!jsonText) {
+    setCodegenStatus("输入为空", "err");
+    return;
+  }
+
+  setCodegenStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  outEl.value = "";
+
+  try {
+    const resp = await fetch("/api/v1/json/codegen", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ json: jsonText, language: valueOf("codegenLang"), rootName: valueOf("codegenRoot") })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setCodegenStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data || typeof data.data.code !== "string") {
+      setCodegenStatus("响应格式不正确", "err");
+      return;
+    }
+
+    outEl.value = data.data.code;
+    if (btnCopy) btnCopy.disabled = false;
+    setCodegenStatus(`已生成 ${data.data.types.length} 个类型`, "ok");
+  } catch (e) {
+    setCodegenStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setJSONValidateStatus(msg, type) {
+  const el = $("jsonValidateStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+// 在输入框中选中第 line 行第 column 列开始的一行；行列相对于去掉首尾空白后提交的内容
+function selectInputPosition(line, column) {
+  const inEl = $("input");
+  if (!inEl || !line) return;
+  const trimmed = inEl.value.trimStart();
+  const lines = trimmed.split("\n");
+  let start = inEl.value.length - trimmed.length;
+  for (let i = 0; i < line - 1 && i < lines.length; i++) start += lines[i].length + 1;
+  start += Math.max(column - 1, 0);
+  const end = start + (lines[line - 1] || "").length - Math.max(column - 1, 0);
+  inEl.focus();
+  inEl.setSelectionRange(start, end);
+}
+
+async function validateJSON() {
+  const btn = $("btnJSONValidate");
+  const resultEl = $("jsonValidateResult");
+  const jsonText = valueOf("input");
+  const schema = valueOf("jsonSchema");
+
+  if (!resultEl) return;
+  if (!jsonText) {
+    setJSONValidateStatus("输入为空", "err");
+    return;
+  }
+  if (!schema) {
+    setJSONValidateStatus("请填写 JSON Schema", "err");
+    return;
+  }
+
+  setJSONValidateStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  resultEl.innerHTML = "";
+
+  try {
+    const resp = await fetch("/api/v1/json/validate", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ json: jsonText, schema, draft: valueOf("jsonSchemaDraft") })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setJSONValidateStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setJSONValidateStatus("响应格式不正确", "err");
+      return;
+    }
+
+    const res = data.data;
+    if (res.valid) {
+      setJSONValidateStatus(`校验通过（${res.draft}）`, "ok");
+      return;
+    }
+    const rows = res.violations.map(v => `<tr class="diff-removed" data-line="${v.line}" data-column="${v.column}" style="cursor: pointer;">
+      <td>${v.line ? `${v.line}:${v.column}` : ""}</td>
+      <td><code>${escapeHTML(v.path || "/")}</code></td>
+      <td>${escapeHTML(v.keyword)}</td>
+      <td>${escapeHTML(v.message)}<div class="small">${escapeHTML(v.schemaPath)}</div></td>
+    </tr>`).join("");
+    resultEl.innerHTML = `<table class="table"><tr><th>位置</th><th>路径</th><th>关键字</th><th>说明</th></tr>${rows}</table>`;
+    resultEl.querySelectorAll("tr[data-line]").forEach(tr => {
+      tr.addEventListener("click", () => selectInputPosition(parseInt(tr.dataset.line, 10), parseInt(tr.dataset.column, 10)));
+    });
+    setJSONValidateStatus(`校验未通过（${res.draft}）：${res.count} 处违规${res.truncated ? "，只显示前面的部分" : ""}`, "err");
+  } catch (e) {
+    setJSONValidateStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function setJSONDiffStatus(msg, type) {
+  const el = $("jsonDiffStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function renderJSONDiffChanges(changes) {
+  const labels = { added: "新增", removed: "删除", changed: "修改" };
+  const classes = { added: "diff-added", removed: "diff-removed", changed: "" };
+  const show = (v) => `<code>${escapeHTML(JSON.stringify(v))}</code>`;
+  const rows = changes.map(c => `<tr class="${classes[c.type] || ""}">
+    <td>${labels[c.type] || escapeHTML(c.type)}</td>
+    <td><code>${escapeHTML(c.path || "/")}</code></td>
+    <td>${c.type === "added" ? "" : show(c.old)}</td>
+    <td>${c.type === "removed" ? "" : show(c.new)}</td>
+  </tr>`).join("");
+  return `<table class="table"><tr><th>类型</th><th>路径</th><th>原值</th><th>新值</th></tr>${rows}</table>`;
+}
+
+async function diffJSON() {
+  const btn = $("btnJSONDiff");
+  const btnCopy = $("btnCopyJSONPatch");
+  const changesEl = $("jsonDiffChanges");
+  const patchEl = $("jsonDiffPatch");
+  const left = valueOf("jsonDiffLeft");
+  const right = valueOf("jsonDiffRight");
+
+  if (!patchEl) return;
+  if (!left || !right) {
+    setJSONDiffStatus("请填写左右两侧的 JSON", "err");
+    return;
+  }
+
+  setJSONDiffStatus("处理中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (changesEl) changesEl.innerHTML = "";
+  patchEl.value = "";
+
+  try {
+    const ignoreKeys = valueOf("jsonDiffIgnoreKeys").split(",").map(s => s.trim()).filter(Boolean);
+    const resp = await fetch("/api/v1/json/diff", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        left,
+        right,
+        ignoreArrayOrder: $("jsonDiffIgnoreOrder") ? $("jsonDiffIgnoreOrder").checked : false,
+        ignoreKeys
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setJSONDiffStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setJSONDiffStatus("响应格式不正确", "err");
+      return;
+    }
+
+    const res = data.data;
+    if (res.equal) {
+      setJSONDiffStatus("两侧 JSON 结构一致", "ok");
+      return;
+    }
+    patchEl.value = JSON.stringify(res.patch, null, 2);
+    if (btnCopy) btnCopy.disabled = false;
+    if (changesEl) changesEl.innerHTML = renderJSONDiffChanges(res.changes);
+    const s = res.summary;
+    setJSONDiffStatus(`新增 ${s.added} 处，删除 ${s.removed} 处，修改 ${s.changed} 处`, "ok");
+  } catch (e) {
+    setJSONDiffStatus("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
   }
 }
 
@@ -530,6 +2316,17 @@ function wireJSONPage() {
     btnMinify.addEventListener("click", minifyJSON);
   }
 
+  if ($("btnInferSchema")) $("btnInferSchema").addEventListener("click", inferSchema);
+
+  if ($("jsonRepairResult")) {
+    $("jsonRepairResult").addEventListener("click", (e) => {
+      const tr = e.target.closest("tr[data-line]");
+      if (tr) selectInputPosition(parseInt(tr.dataset.line, 10), parseInt(tr.dataset.column, 10));
+    });
+  }
+
+  if ($("btnConvert")) $("btnConvert").addEventListener("click", convertInput);
+
   if (btnCopy && outEl) {
     btnCopy.addEventListener("click", async () => {
       const ok = await copyToClipboard(outEl.value);
@@ -541,6 +2338,59 @@ function wireJSONPage() {
     btnSave.addEventListener("click", saveJSONToFile);
   }
 
+  if ($("btnJSONQuery")) $("btnJSONQuery").addEventListener("click", queryJSON);
+
+  if ($("btnCopyJSONQuery")) {
+    $("btnCopyJSONQuery").addEventListener("click", async () => {
+      const ok = await copyToClipboard(valueOf("jsonQueryOutput"));
+      setJSONQueryStatus(ok ? "已复制到剪贴板" : "复制失败（浏览器不支持或无权限）", ok ? "ok" : "err");
+    });
+  }
+
+  if ($("btnCodegen")) $("btnCodegen").addEventListener("click", generateCode);
+
+  if ($("btnCopyCodegen")) {
+    $("btnCopyCodegen").addEventListener("click", async () => {
+      const ok = await copyToClipboard(valueOf("codegenOutput"));
+      setCodegenStatus(ok ? "已复制到剪贴板" : "复制失败（浏览器不支持或无权限）", ok ? "ok" : "err");
+    });
+  }
+
+  if ($("btnJSONValidate")) $("btnJSONValidate").addEventListener("click", validateJSON);
+
+  if ($("btnJSONDiff")) $("btnJSONDiff").addEventListener("click", diffJSON);
+
+  if ($("btnJSONDiffFromInput")) {
+    $("btnJSONDiffFromInput").addEventListener("click", () => {
+      if ($("jsonDiffLeft") && inEl) $("jsonDiffLeft").value = inEl.value;
+    });
+  }
+
+  if ($("btnJSONDiffSwap")) {
+    $("btnJSONDiffSwap").addEventListener("click", () => {
+      const l = $("jsonDiffLeft");
+      const r = $("jsonDiffRight");
+      if (!l || !r) return;
+      [l.value, r.value] = [r.value, l.value];
+    });
+  }
+
+  if ($("btnCopyJSONPatch")) {
+    $("btnCopyJSONPatch").addEventListener("click", async () => {
+      const ok = await copyToClipboard(valueOf("jsonDiffPatch"));
+      setJSONDiffStatus(ok ? "已复制到剪贴板" : "复制失败（浏览器不支持或无权限）", ok ? "ok" : "err");
+    });
+  }
+
+  if ($("jsonQuery")) {
+    $("jsonQuery").addEventListener("keydown", (e) => {
+      if (e.key === "Enter") {
+        e.preventDefault();
+        queryJSON();
+      }
+    });
+  }
+
   if (btnClear) {
     btnClear.addEventListener("click", () => {
       if (inEl) inEl.value = "";
@@ -589,6 +2439,489 @@ function wireJSONPage() {
   }
 }
 
+function setKeyStatus(msg, type) {
+  const el = $("keyStatus");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+const keyTypeLabels = { private: "私钥", public: "公钥" };
+
+function renderKeyInfo(info) {
+  const container = $("keyInfoContainer");
+  const el = $("keyInfo");
+  if (!container || !el) return;
+
+  if (!info) {
+    container.style.display = "none";
+    el.innerHTML = "";
+    return;
+  }
+
+  const row = (label, value) => `<div><span class="cert-info-label">${label}:</span>${escapeHTML(value)}</div>`;
+  const state = info.locked ? "已加密（未提供口令）" : (info.encrypted ? "已加密（已解密）" : "未加密");
+  const sections = [
+    {
+      title: "格式",
+      content: row("类型", keyTypeLabels[info.type] || info.type) + row("格式", info.format) + (info.type === "private" ? row("加密", state) : "")
+    },
+    {
+      title: "算法",
+      content: info.algorithm
+        ? row("算法", info.algorithm) + row("长度", info.size + " bit") + (info.curve ? row("曲线", info.curve) : "")
+        : "N/A"
+    },
+    {
+      title: "指纹",
+      content: [
+        info.publicKeySha256 ? row("公钥 SHA-256", info.publicKeySha256) : "",
+        info.sshFingerprint ? row("SSH", info.sshFingerprint) : ""
+      ].join("") || "N/A"
+    },
+    {
+      title: "公钥",
+      content: [
+        info.sshPublicKey ? `<div class="key-block">${escapeHTML(info.sshPublicKey)}</div>` : "",
+        info.publicKeyPem ? `<div class="key-block">${escapeHTML(info.publicKeyPem)}</div>` : ""
+      ].join("") || "N/A"
+    }
+  ];
+
+  el.innerHTML = sections
+    .map(s => `<div class="cert-info-section"><div class="cert-info-title">${s.title}</div><div class="cert-info-content">${s.content}</div></div>`)
+    .join("");
+  container.style.display = "block";
+}
+
+async function inspectKey(convert) {
+  const inEl = $("keyInput");
+  const outEl = $("keyOutput");
+  const btnCopy = $("btnKeyCopy");
+  if (!inEl || !outEl) return;
+
+  if (!inEl.value.trim()) {
+    setKeyStatus("请输入密钥", "err");
+    return;
+  }
+
+  setKeyStatus("处理中...", "");
+  if (btnCopy) btnCopy.disabled = true;
+  outEl.value = "";
+
+  try {
+    const resp = await fetch("/api/v1/key/inspect", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        key: inEl.value,
+        passphrase: $("keyPassphrase") ? $("keyPassphrase").value : "",
+        target: convert ? valueOf("keyTarget") : "",
+        outputPassphrase: convert && $("keyOutputPassphrase") ? $("keyOutputPassphrase").value : ""
+      })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setKeyStatus(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setKeyStatus("响应格式不正确", "err");
+      return;
+    }
+
+    renderKeyInfo(data.data);
+    if (data.data.output) {
+      outEl.value = data.data.output;
+      if (btnCopy) btnCopy.disabled = false;
+    }
+    if (data.data.locked) {
+      setKeyStatus("私钥已加密，填写口令后可查看完整信息", "err");
+    } else {
+      setKeyStatus(convert ? "转换完成" : "解析完成", "ok");
+    }
+  } catch (e) {
+    setKeyStatus("请求失败：" + e.message, "err");
+  }
+}
+
+function wireKeyPage() {
+  const inEl = $("keyInput");
+  const outEl = $("keyOutput");
+  const btnCopy = $("btnKeyCopy");
+
+  if ($("btnKeyInspect")) $("btnKeyInspect").addEventListener("click", () => inspectKey(false));
+  if ($("btnKeyConvert")) $("btnKeyConvert").addEventListener("click", () => inspectKey(true));
+
+  if (btnCopy && outEl) {
+    btnCopy.addEventListener("click", async () => {
+      const ok = await copyToClipboard(outEl.value);
+      setKeyStatus(ok ? "已复制到剪贴板" : "复制失败（浏览器不支持或无权限）", ok ? "ok" : "err");
+    });
+  }
+
+  if ($("btnKeyClear")) {
+    $("btnKeyClear").addEventListener("click", () => {
+      if (inEl) inEl.value = "";
+      if (outEl) outEl.value = "";
+      renderKeyInfo(null);
+      setKeyStatus("", "");
+      if (btnCopy) btnCopy.disabled = true;
+    });
+  }
+
+  if (inEl) {
+    inEl.addEventListener("keydown", (e) => {
+      if ((e.ctrlKey || e.metaKey) && e.key === "Enter") {
+        e.preventDefault();
+        inspectKey(false);
+      }
+    });
+  }
+}
+
+// asn1InputKey 其他页面跳转到 ASN.1 查看器时通过 localStorage 传递输入
+const asn1InputKey = "mytools.asn1Input";
+
+function setASN1Status(msg, type) {
+  const el = $("asn1Status");
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+function asn1NodeLabel(n) {
+  const meta = `@${n.offset} hl=${n.headerLength} l=${n.indefinite ? "inf" : n.length}`;
+  let value = "";
+  if (n.oidName) value = `${n.oidName} (${n.value})`;
+  else if (n.time) value = `${n.value} (${n.time})`;
+  else if (n.value) value = n.value;
+  if (n.hex && !n.encapsulated && (!n.value || n.type === "BIT STRING")) {
+    value += (value ? " " : "") + n.hex + (n.truncated ? "..." : "");
+  }
+  return `<span class="asn1-type">${escapeHTML(n.type)}</span> <span class="asn1-meta">${meta}${n.encapsulated ? " encapsulates" : ""}</span>` +
+    (value ? " " + escapeHTML(value) : "") +
+    (n.error ? ` <span class="asn1-error">${escapeHTML(n.error)}</span>` : "");
+}
+
+function renderASN1Nodes(nodes) {
+  return (nodes || []).map(n => {
+    if (n.children && n.children.length) {
+      return `<details open><summary>${asn1NodeLabel(n)}</summary>${renderASN1Nodes(n.children)}</details>`;
+    }
+    return `<div class="asn1-leaf">${asn1NodeLabel(n)}</div>`;
+  }).join("");
+}
+
+function renderASN1Result(res) {
+  const container = $("asn1TreeContainer");
+  const treeEl = $("asn1Tree");
+  const summaryEl = $("asn1Summary");
+  const textEl = $("asn1Text");
+  const btnCopy = $("btnASN1CopyText");
+  if (!container || !treeEl) return;
+
+  if (textEl) textEl.value = res ? res.text : "";
+  if (btnCopy) btnCopy.disabled = !res;
+  if (!res) {
+    container.style.display = "none";
+    treeEl.innerHTML = "";
+    return;
+  }
+
+  const parts = [`格式：${res.format}`, `${res.length} 字节`];
+  if (res.pemType) parts.push(`PEM 类型：${res.pemType}（共 ${res.pemBlocks} 个块）`);
+  if (summaryEl) summaryEl.textContent = parts.join("，");
+  treeEl.innerHTML = renderASN1Nodes(res.nodes);
+  container.style.display = "block";
+}
+
+async function dumpASN1() {
+  const btn = $("btnASN1Parse");
+  const input = valueOf("asn1Input");
+
+  if (!input.trim()) {
+    setASN1Status("请输入 PEM、base64 或十六进制数据", "err");
+    return;
+  }
+
+  setASN1Status("处理中...", "");
+  if (btn) btn.disabled = true;
+  renderASN1Result(null);
+
+  try {
+    const resp = await fetch("/api/v1/asn1/dump", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ input, block: parseInt(valueOf("asn1Block"), 10) || 0 })
+    });
+
+    const data = await resp.json().catch(() => null);
+    if (!resp.ok) {
+      const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+      setASN1Status(msg, "err");
+      return;
+    }
+
+    if (!data || !data.ok || !data.data) {
+      setASN1Status("响应格式不正确", "err");
+      return;
+    }
+
+    renderASN1Result(data.data);
+    if (data.data.error) {
+      setASN1Status("数据有误，已显示可解析的部分：" + data.data.error, "err");
+    } else {
+      setASN1Status("解析完成", "ok");
+    }
+  } catch (e) {
+    setASN1Status("请求失败：" + e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function wireASN1Page() {
+  const inEl = $("asn1Input");
+
+  if ($("btnASN1Parse")) $("btnASN1Parse").addEventListener("click", dumpASN1);
+
+  if ($("btnASN1CopyText")) {
+    $("btnASN1CopyText").addEventListener("click", async () => {
+      const ok = await copyToClipboard(valueOf("asn1Text"));
+      setASN1Status(ok ? "已复制到剪贴板" : "复制失败（浏览器不支持或无权限）", ok ? "ok" : "err");
+    });
+  }
+
+  if ($("btnASN1Clear")) {
+    $("btnASN1Clear").addEventListener("click", () => {
+      if (inEl) inEl.value = "";
+      if ($("asn1Block")) $("asn1Block").value = "0";
+      renderASN1Result(null);
+      setASN1Status("", "");
+    });
+  }
+
+  if (inEl) {
+    inEl.addEventListener("keydown", (e) => {
+      if ((e.ctrlKey || e.metaKey) && e.key === "Enter") {
+        e.preventDefault();
+        dumpASN1();
+      }
+    });
+
+    // 从证书 / CSR 页面跳转过来时自动解析
+    const pending = localStorage.getItem(asn1InputKey);
+    if (pending) {
+      localStorage.removeItem(asn1InputKey);
+      inEl.value = pending;
+      dumpASN1();
+    }
+  }
+}
+
+function setCAStatus(id, msg, type) {
+  const el = $(id);
+  if (!el) return;
+  el.classList.remove("ok", "err");
+  if (type) el.classList.add(type);
+  el.textContent = msg || "";
+}
+
+async function caRequest(url, options) {
+  const resp = await fetch(url, options);
+  const data = await resp.json().catch(() => null);
+  if (!resp.ok || !data || !data.ok) {
+    const msg = data && data.error && data.error.message ? data.error.message : ("HTTP " + resp.status);
+    throw new Error(msg);
+  }
+  return data.data;
+}
+
+let caRootPEM = "";
+
+function renderCAInfo(res) {
+  const el = $("caInfo");
+  const btnCopy = $("btnCopyCARoot");
+  if (!el) return;
+
+  caRootPEM = res && res.root ? res.root.pem : "";
+  if (btnCopy) btnCopy.disabled = !caRootPEM;
+  if (!res || !res.initialized) {
+    el.innerHTML = `<div class="small">尚未创建测试 CA</div>`;
+    return;
+  }
+
+  const row = (label, value) => `<div><span class="cert-info-label">${escapeHTML(label)}:</span>${escapeHTML(value)}</div>`;
+  el.innerHTML = `<div class="cert-info-content">
+    ${row("根证书", res.root.subject)}
+    ${row("根证书到期", formatDate(res.root.notAfter))}
+    ${row("中间证书", res.intermediate.subject)}
+    ${row("中间证书到期", formatDate(res.intermediate.notAfter))}
+    ${row("创建时间", formatDate(res.createdAt))}
+    ${row("已签发 / 已吊销", `${res.issued} / ${res.revoked}`)}
+    ${row("CRL 编号", String(res.crlNumber))}
+  </div>`;
+}
+
+async function loadCAStatus() {
+  try {
+    renderCAInfo(await caRequest("/api/v1/ca"));
+  } catch (e) {
+    setCAStatus("caStatus", "请求失败：" + e.message, "err");
+  }
+}
+
+async function initCA() {
+  const btn = $("btnCAInit");
+  const force = $("caForce") ? $("caForce").checked : false;
+  if (force && !confirm("重新创建会替换现有 CA 并清空签发记录，确定继续？")) return;
+
+  setCAStatus("caStatus", "创建中...", "");
+  if (btn) btn.disabled = true;
+  try {
+    const res = await caRequest("/api/v1/ca/init", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        name: valueOf("caName"),
+        organization: valueOf("caOrg"),
+        keySpec: valueOf("caKeySpec"),
+        rootValidityDays: parseInt(valueOf("caRootDays"), 10) || 0,
+        intermediateValidityDays: parseInt(valueOf("caIntermediateDays"), 10) || 0,
+        force
+      })
+    });
+    renderCAInfo(res);
+    if ($("caForce")) $("caForce").checked = false;
+    setCAStatus("caStatus", "测试 CA 已创建", "ok");
+    loadCAIssued();
+  } catch (e) {
+    setCAStatus("caStatus", e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+async function signWithCA() {
+  const btn = $("btnCASign");
+  const outEl = $("caChainOut");
+  const btnCopy = $("btnCopyCAChain");
+  const container = $("outputContainer");
+
+  const csr = valueOf("caCSR");
+  if (!csr) {
+    setCAStatus("caSignStatus", "CSR 为空", "err");
+    return;
+  }
+
+  setCAStatus("caSignStatus", "签发中...", "");
+  if (btn) btn.disabled = true;
+  if (btnCopy) btnCopy.disabled = true;
+  if (outEl) outEl.value = "";
+  if (container) container.style.display = "none";
+  try {
+    const extKeyUsages = Array.from(document.querySelectorAll("input[name=caEKU]:checked")).map(el => el.value);
+    const res = await caRequest("/api/v1/ca/sign", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({
+        csr,
+        validityDays: parseInt(valueOf("caValidityDays"), 10) || 0,
+        dnsNames: linesOf("caDNS"),
+        ipAddresses: linesOf("caIP"),
+        emailAddresses: linesOf("caEmail"),
+        extKeyUsages
+      })
+    });
+    if (outEl) outEl.value = res.chainPem;
+    if (btnCopy) btnCopy.disabled = false;
+    renderCertList(res.certs);
+    setCAStatus("caSignStatus", `已签发，序列号 ${res.certs[0].serialNumber}`, "ok");
+    loadCAStatus();
+    loadCAIssued();
+  } catch (e) {
+    setCAStatus("caSignStatus", e.message, "err");
+  } finally {
+    if (btn) btn.disabled = false;
+  }
+}
+
+function renderCAIssued(res) {
+  const el = $("caIssuedList");
+  const countEl = $("caIssuedCount");
+  if (!el) return;
+
+  if (countEl) countEl.textContent = `(共 ${res.count} 个)`;
+  if (!res.items || res.items.length === 0) {
+    el.innerHTML = `<div class="small">暂无签发记录</div>`;
+    return;
+  }
+
+  const rows = res.items.map(it => `<tr>
+      <td>${escapeHTML(it.serialHex)}</td>
+      <td>${escapeHTML(it.subject)}</td>
+      <td>${escapeHTML((it.sans || []).join(", "))}</td>
+      <td>${escapeHTML(formatDate(it.notAfter))}</td>
+      <td>${it.revoked ? `<span class="expiry-expired">已吊销${it.revocationReason ? "（" + escapeHTML(it.revocationReason) + "）" : ""}</span>` : "有效"}</td>
+      <td><button class="btn" data-ca-serial="${escapeHTML(it.serialNumber)}">${it.revoked ? "更新原因" : "吊销"}</button></td>
+    </tr>`).join("");
+
+  el.innerHTML = `<table class="table">
+    <thead><tr><th>序列号</th><th>主题</th><th>SAN</th><th>到期时间</th><th>状态</th><th></th></tr></thead>
+    <tbody>${rows}</tbody>
+  </table>`;
+
+  el.querySelectorAll("button[data-ca-serial]").forEach(btn => {
+    btn.addEventListener("click", () => revokeCACert(btn.getAttribute("data-ca-serial")));
+  });
+}
+
+async function loadCAIssued() {
+  try {
+    renderCAIssued(await caRequest("/api/v1/ca/issued"));
+    setCAStatus("caIssuedStatus", "", "");
+  } catch (e) {
+    setCAStatus("caIssuedStatus", e.message, "err");
+  }
+}
+
+async function revokeCACert(serialNumber) {
+  try {
+    const res = await caRequest("/api/v1/ca/revoke", {
+      method: "POST",
+      headers: { "Content-Type": "application/json" },
+      body: JSON.stringify({ serialNumber, reason: valueOf("caRevokeReason") })
+    });
+    await loadCAIssued();
+    loadCAStatus();
+    setCAStatus("caIssuedStatus", `已吊销：${res.serialHex}`, "ok");
+  } catch (e) {
+    setCAStatus("caIssuedStatus", "吊销失败：" + e.message, "err");
+  }
+}
+
+function wireCAPage() {
+  if ($("btnCAInit")) $("btnCAInit").addEventListener("click", initCA);
+  if ($("btnCASign")) $("btnCASign").addEventListener("click", signWithCA);
+  if ($("btnCAIssuedRefresh")) $("btnCAIssuedRefresh").addEventListener("click", loadCAIssued);
+
+  if ($("btnCopyCARoot")) {
+    $("btnCopyCARoot").addEventListener("click", () => copyCertToClipboard(caRootPEM, "btnCopyCARoot"));
+  }
+  if ($("btnCopyCAChain")) {
+    $("btnCopyCAChain").addEventListener("click", () => copyCertToClipboard(valueOf("caChainOut"), "btnCopyCAChain"));
+  }
+
+  loadCAStatus();
+  loadCAIssued();
+}
+
 document.addEventListener("DOMContentLoaded", () => {
   const page = document.body ? document.body.dataset.page : null;
   
@@ -602,9 +2935,18 @@ document.addEventListener("DOMContentLoaded", () => {
   if (page === "cert") {
     wireCertPage();
   }
+  if (page === "asn1") {
+    wireASN1Page();
+  }
   if (page === "json") {
     wireJSONPage();
   }
+  if (page === "key") {
+    wireKeyPage();
+  }
+  if (page === "ca") {
+    wireCAPage();
+  }
   if (page === "sectigo") {
     wireSectigoPage();
   }